@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshpolicy "github.com/cloudfoundry/bosh-cli/policy"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+type CheckManifestCmd struct {
+	ui         boshui.UI
+	deployment boshdir.Deployment
+}
+
+func NewCheckManifestCmd(ui boshui.UI, deployment boshdir.Deployment) CheckManifestCmd {
+	return CheckManifestCmd{ui: ui, deployment: deployment}
+}
+
+func (c CheckManifestCmd) Run(opts CheckManifestOpts) error {
+	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
+
+	bytes, err := tpl.Evaluate(opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp(), boshtpl.EvaluateOpts{})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Evaluating manifest")
+	}
+
+	deploymentDiff, err := c.deployment.Diff(bytes, false)
+	if err != nil {
+		return err
+	}
+
+	return PolicyCheck{opts.Policy.Policy, c.ui}.Run(bytes, deploymentDiff, true)
+}
+
+// PolicyCheck reports policy violations and fails if any of them is an error
+type PolicyCheck struct {
+	policy boshpolicy.Policy
+	ui     boshui.UI
+}
+
+func (c PolicyCheck) Run(manifest []byte, diff boshdir.DeploymentDiff, alwaysPrint bool) error {
+	violations, err := c.policy.Check(manifest, diff.Changes())
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Checking manifest policy")
+	}
+
+	if len(violations) > 0 || alwaysPrint {
+		table := boshtbl.Table{
+			Content: "policy violations",
+			Header: []boshtbl.Header{
+				boshtbl.NewHeader("Rule"),
+				boshtbl.NewHeader("Severity"),
+				boshtbl.NewHeader("Path"),
+				boshtbl.NewHeader("Message"),
+			},
+			FillFirstColumn: true,
+		}
+
+		for _, v := range violations {
+			table.Rows = append(table.Rows, []boshtbl.Value{
+				boshtbl.NewValueString(v.Rule),
+				boshtbl.NewValueString(v.Severity),
+				boshtbl.NewValueString(v.Path),
+				boshtbl.NewValueString(v.Message),
+			})
+		}
+
+		c.ui.PrintTable(table)
+	}
+
+	errs := violations.Errors()
+	if len(errs) > 0 {
+		return bosherr.Errorf("Expected manifest to satisfy policy but found %d violation(s)", len(errs))
+	}
+
+	return nil
+}
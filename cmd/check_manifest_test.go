@@ -0,0 +1,147 @@
+package cmd_test
+
+import (
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("CheckManifestCmd", func() {
+	var (
+		ui         *fakeui.FakeUI
+		deployment *fakedir.FakeDeployment
+		command    CheckManifestCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		deployment = &fakedir.FakeDeployment{}
+		command = NewCheckManifestCmd(ui, deployment)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts CheckManifestOpts
+		)
+
+		BeforeEach(func() {
+			fs := fakesys.NewFakeFileSystem()
+			fs.SetGlob("/policy/*.yml", []string{"/policy/rules.yml"})
+			fs.WriteFileString("/policy/rules.yml", `
+rules:
+- {name: instances, path: /instance_groups/*/instances, max: 2}
+- {name: disk, path: /instance_groups/*/persistent_disk, no_decrease: true, severity: warning}
+`)
+
+			opts = CheckManifestOpts{
+				Args: CheckManifestArgs{
+					Manifest: FileBytesArg{Bytes: []byte("instance_groups:\n- name: ig1\n  instances: ((count))\n")},
+				},
+				Policy: PolicyDirArg{FS: fs},
+			}
+
+			opts.VarKVs = []boshtpl.VarKV{{Name: "count", Value: 1}}
+
+			err := (&opts.Policy).UnmarshalFlag("/policy")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("prints empty report when interpolated manifest satisfies policy", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.DiffCallCount()).To(Equal(1))
+			bytes, noRedact := deployment.DiffArgsForCall(0)
+			Expect(string(bytes)).To(Equal("instance_groups:\n- instances: 1\n  name: ig1\n"))
+			Expect(noRedact).To(BeFalse())
+
+			Expect(ui.Tables).To(HaveLen(1))
+			Expect(ui.Tables[0].Rows).To(BeEmpty())
+		})
+
+		It("returns error and prints violations", func() {
+			opts.VarKVs = []boshtpl.VarKV{{Name: "count", Value: 3}}
+
+			deployment.DiffReturns(boshdir.NewDeploymentDiff([][]interface{}{
+				{"instance_groups:", nil},
+				{"- name: ig1", nil},
+				{"  persistent_disk: 2048", "removed"},
+				{"  persistent_disk: 1024", "added"},
+			}, nil), nil)
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to satisfy policy but found 1 violation(s)"))
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "policy violations",
+				Header: []boshtbl.Header{
+					boshtbl.NewHeader("Rule"),
+					boshtbl.NewHeader("Severity"),
+					boshtbl.NewHeader("Path"),
+					boshtbl.NewHeader("Message"),
+				},
+				Rows: [][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("instances"),
+						boshtbl.NewValueString("error"),
+						boshtbl.NewValueString("/instance_groups/name=ig1/instances"),
+						boshtbl.NewValueString("Expected value '3' to be at most '2'"),
+					},
+					{
+						boshtbl.NewValueString("disk"),
+						boshtbl.NewValueString("warning"),
+						boshtbl.NewValueString("/instance_groups/name=ig1/persistent_disk"),
+						boshtbl.NewValueString("Expected value to not decrease from '2048' to '1024'"),
+					},
+				},
+				FillFirstColumn: true,
+			}))
+		})
+
+		It("does not fail when there are only warnings", func() {
+			deployment.DiffReturns(boshdir.NewDeploymentDiff([][]interface{}{
+				{"instance_groups:", nil},
+				{"- name: ig1", nil},
+				{"  persistent_disk: 2048", "removed"},
+				{"  persistent_disk: 1024", "added"},
+			}, nil), nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.Table.Rows).To(HaveLen(1))
+		})
+
+		It("returns error if manifest cannot be interpolated", func() {
+			opts.OpsFiles = []OpsFileArg{
+				{Ops: patch.Ops{patch.RemoveOp{Path: patch.MustNewPointerFromString("/missing")}}},
+			}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Evaluating manifest"))
+
+			Expect(deployment.DiffCallCount()).To(Equal(0))
+		})
+
+		It("returns error if diffing fails", func() {
+			deployment.DiffReturns(boshdir.DeploymentDiff{}, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os/user"
 	"path/filepath"
 
 	"github.com/cppforlife/go-patch/patch"
@@ -14,6 +15,7 @@ import (
 	boshreldir "github.com/cloudfoundry/bosh-cli/releasedir"
 	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
 	bistemcell "github.com/cloudfoundry/bosh-cli/stemcell"
+	bitemplate "github.com/cloudfoundry/bosh-cli/templatescompiler"
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
 	boshuit "github.com/cloudfoundry/bosh-cli/ui/task"
 
@@ -67,7 +69,7 @@ func (c Cmd) Execute() (cmdErr error) {
 
 	case *CreateEnvOpts:
 		envProvider := func(manifestPath string, statePath string, vars boshtpl.Variables, op patch.Op) DeploymentPreparer {
-			return NewEnvFactory(deps, manifestPath, statePath, vars, op, opts.RecreatePersistentDisks).Preparer()
+			return NewEnvFactory(deps, manifestPath, statePath, vars, op, opts.RecreatePersistentDisks, opts.NativeERB, opts.CacheRenderedTemplates).Preparer()
 		}
 
 		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
@@ -75,7 +77,7 @@ func (c Cmd) Execute() (cmdErr error) {
 
 	case *DeleteEnvOpts:
 		envProvider := func(manifestPath string, statePath string, vars boshtpl.Variables, op patch.Op) DeploymentDeleter {
-			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false).Deleter()
+			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, false, false).Deleter()
 		}
 
 		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
@@ -238,6 +240,24 @@ func (c Cmd) Execute() (cmdErr error) {
 	case *InterpolateOpts:
 		return NewInterpolateCmd(deps.UI).Run(*opts)
 
+	case *TestOpsOpts:
+		return NewTestOpsCmd(deps.FS, deps.UI).Run(*opts)
+
+	case *VarsStoreEncryptOpts:
+		return NewVarsStoreCmd(deps.FS, deps.UI, deps.Time).Encrypt(*opts)
+
+	case *VarsStoreDecryptOpts:
+		return NewVarsStoreCmd(deps.FS, deps.UI, deps.Time).Decrypt(*opts)
+
+	case *VarsStoreRekeyOpts:
+		return NewVarsStoreCmd(deps.FS, deps.UI, deps.Time).Rekey(*opts)
+
+	case *VarsStoreCheckOpts:
+		return NewVarsStoreCmd(deps.FS, deps.UI, deps.Time).Check(*opts)
+
+	case *VarsStoreRotateOpts:
+		return NewVarsStoreCmd(deps.FS, deps.UI, deps.Time).Rotate(*opts)
+
 	case *ConfigOpts:
 		return NewConfigCmd(deps.UI, c.director()).Run(*opts)
 
@@ -306,7 +326,22 @@ func (c Cmd) Execute() (cmdErr error) {
 	case *DeployOpts:
 		director, deployment := c.directorAndDeployment()
 		releaseManager := c.releaseManager(director)
-		return NewDeployCmd(deps.UI, deployment, releaseManager).Run(*opts)
+		return NewDeployCmd(deps.UI, deployment, releaseManager, c.releaseJobsReader()).Run(*opts)
+
+	case *CheckManifestOpts:
+		return NewCheckManifestCmd(deps.UI, c.deployment()).Run(*opts)
+
+	case *ValidateManifestOpts:
+		return NewValidateManifestCmd(deps.UI).Run(*opts)
+
+	case *ValidatePropertiesOpts:
+		return NewValidatePropertiesCmd(c.releaseJobsReader(), deps.UI).Run(*opts)
+
+	case *DiffDeploymentOpts:
+		return NewDiffDeploymentCmd(deps.UI, c.deployment()).Run(*opts)
+
+	case *DiffManifestsOpts:
+		return NewDiffManifestsCmd(deps.UI).Run(*opts)
 
 	case *StartOpts:
 		return NewStartCmd(deps.UI, c.deployment()).Run(*opts)
@@ -331,19 +366,50 @@ func (c Cmd) Execute() (cmdErr error) {
 		downloader := NewUIDownloader(director, deps.Time, deps.FS, deps.UI)
 		sshProvider := boshssh.NewProvider(deps.CmdRunner, deps.FS, deps.UI, deps.Logger)
 		nonIntSSHRunner := sshProvider.NewSSHRunner(false)
-		return NewLogsCmd(deployment, downloader, deps.UUIDGen, nonIntSSHRunner).Run(*opts)
+		logsFollower := NewDirectorLogsFollower(deployment, director, deps.Time, deps.UI)
+		logsSearcher := NewDirectorLogsSearcher(deployment, director, deps.FS, deps.UI)
+		opts.GatewayFlags.KnownHostsPath = c.knownHostsPath()
+		return NewLogsCmd(deployment, downloader, deps.UUIDGen, nonIntSSHRunner, logsFollower, logsSearcher).Run(*opts)
 
 	case *SSHOpts:
 		sshProvider := boshssh.NewProvider(deps.CmdRunner, deps.FS, deps.UI, deps.Logger)
 		intSSHRunner := sshProvider.NewSSHRunner(true)
 		nonIntSSHRunner := sshProvider.NewSSHRunner(false)
 		resultsSSHRunner := sshProvider.NewResultsSSHRunner(false)
-		return NewSSHCmd(c.deployment(), deps.UUIDGen, intSSHRunner, nonIntSSHRunner, resultsSSHRunner, deps.UI).Run(*opts)
+		if c.BoshOpts.JSONOpt {
+			resultsSSHRunner = sshProvider.NewStructuredResultsSSHRunner()
+		}
+		deployment, sessions := c.deploymentAndSSHSessions()
+		opts.GatewayFlags.KnownHostsPath = c.knownHostsPath()
+		opts.Record, opts.Recording = c.sshRecording(opts.Record)
+		return NewSSHCmd(deployment, sessions, deps.UUIDGen, intSSHRunner, nonIntSSHRunner, resultsSSHRunner, deps.UI).Run(*opts)
 
 	case *SCPOpts:
 		sshProvider := boshssh.NewProvider(deps.CmdRunner, deps.FS, deps.UI, deps.Logger)
 		scpRunner := sshProvider.NewSCPRunner()
-		return NewSCPCmd(c.deployment(), deps.UUIDGen, scpRunner, deps.UI).Run(*opts)
+		deployment, sessions := c.deploymentAndSSHSessions()
+		opts.GatewayFlags.KnownHostsPath = c.knownHostsPath()
+		return NewSCPCmd(deployment, sessions, deps.UUIDGen, scpRunner, deps.UI).Run(*opts)
+
+	case *PortForwardOpts:
+		sshProvider := boshssh.NewProvider(deps.CmdRunner, deps.FS, deps.UI, deps.Logger)
+		portForwarder := sshProvider.NewPortForwarder()
+		deployment, sessions := c.deploymentAndSSHSessions()
+		opts.GatewayFlags.KnownHostsPath = c.knownHostsPath()
+		return NewPortForwardCmd(deployment, sessions, portForwarder).Run(*opts)
+
+	case *SSHSessionStartOpts:
+		deployment, sessions := c.deploymentAndSSHSessions()
+		return NewSSHSessionCmd(deployment, sessions, deps.UUIDGen, deps.Time, deps.UI).Start(*opts)
+
+	case *SSHSessionStopOpts:
+		deployment, sessions := c.deploymentAndSSHSessions()
+		return NewSSHSessionCmd(deployment, sessions, deps.UUIDGen, deps.Time, deps.UI).Stop(*opts)
+
+	case *SSHConfigOpts:
+		deployment, sessions := c.deploymentAndSSHSessions()
+		opts.GatewayFlags.KnownHostsPath = c.knownHostsPath()
+		return NewSSHConfigCmd(deployment, sessions, deps.UUIDGen, deps.Time, deps.FS, deps.UI).Run(*opts)
 
 	case *ExportReleaseOpts:
 		director, deployment := c.directorAndDeployment()
@@ -365,6 +431,11 @@ func (c Cmd) Execute() (cmdErr error) {
 	case *VendorPackageOpts:
 		return NewVendorPackageCmd(c.releaseDir, deps.UI).Run(*opts)
 
+	case *RenderJobOpts:
+		erbRenderer := newERBRenderer(deps, opts.NativeERB)
+		jobRenderer := bitemplate.NewInstanceJobRenderer(erbRenderer, deps.FS, deps.UUIDGen, deps.Logger)
+		return NewRenderJobCmd(c.releaseJobsReader(), jobRenderer, deps.UI).Run(*opts)
+
 	case *FinalizeReleaseOpts:
 		_, relDirProv := c.releaseProviders()
 		releaseReader := relDirProv.NewReleaseReader(opts.Directory.Path, c.BoshOpts.Parallel)
@@ -511,6 +582,41 @@ func (c Cmd) directorAndDeployment() (boshdir.Director, boshdir.Deployment) {
 	return director, deployment
 }
 
+func (c Cmd) deploymentAndSSHSessions() (boshdir.Deployment, SSHSessions) {
+	sess := c.session()
+
+	deployment, err := sess.Deployment()
+	c.panicIfErr(err)
+
+	return deployment, NewFSSSHSessions(sess.Environment(), c.deps.FS, c.deps.Time)
+}
+
+func (c Cmd) knownHostsPath() string {
+	return KnownHostsPath(c.session().Environment())
+}
+
+func (c Cmd) sshRecording(dir string) (string, boshssh.RecordingOpts) {
+	environment := c.session().Environment()
+
+	if len(dir) == 0 {
+		dir = c.config().SSHRecordDir(environment)
+	}
+
+	recording := boshssh.RecordingOpts{Environment: environment}
+
+	if u, err := user.Current(); err == nil {
+		recording.LocalUser = u.Username
+	}
+
+	return dir, recording
+}
+
+func (c Cmd) releaseJobsReader() ReleaseJobsReader {
+	relProv, _ := c.releaseProviders()
+	return NewFSReleaseJobsReader(
+		relProv.NewExtractingJobArchiveReader(), relProv.NewJobDirReader, c.deps.Compressor, c.deps.FS)
+}
+
 func (c Cmd) releaseProviders() (boshrel.Provider, boshreldir.Provider) {
 	indexReporter := boshui.NewIndexReporter(c.deps.UI)
 	blobsReporter := boshui.NewBlobsReporter(c.deps.UI)
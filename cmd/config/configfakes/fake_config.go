@@ -54,6 +54,17 @@ type FakeConfig struct {
 	cACertReturnsOnCall map[int]struct {
 		result1 string
 	}
+	SSHRecordDirStub        func(url string) string
+	sSHRecordDirMutex       sync.RWMutex
+	sSHRecordDirArgsForCall []struct {
+		url string
+	}
+	sSHRecordDirReturns struct {
+		result1 string
+	}
+	sSHRecordDirReturnsOnCall map[int]struct {
+		result1 string
+	}
 	CredentialsStub        func(url string) config.Creds
 	credentialsMutex       sync.RWMutex
 	credentialsArgsForCall []struct {
@@ -262,12 +273,16 @@ func (fake *FakeConfig) CACert(url string) string {
 func (fake *FakeConfig) CACertCallCount() int {
 	fake.cACertMutex.RLock()
 	defer fake.cACertMutex.RUnlock()
+	fake.sSHRecordDirMutex.RLock()
+	defer fake.sSHRecordDirMutex.RUnlock()
 	return len(fake.cACertArgsForCall)
 }
 
 func (fake *FakeConfig) CACertArgsForCall(i int) string {
 	fake.cACertMutex.RLock()
 	defer fake.cACertMutex.RUnlock()
+	fake.sSHRecordDirMutex.RLock()
+	defer fake.sSHRecordDirMutex.RUnlock()
 	return fake.cACertArgsForCall[i].url
 }
 
@@ -290,6 +305,54 @@ func (fake *FakeConfig) CACertReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeConfig) SSHRecordDir(url string) string {
+	fake.sSHRecordDirMutex.Lock()
+	ret, specificReturn := fake.sSHRecordDirReturnsOnCall[len(fake.sSHRecordDirArgsForCall)]
+	fake.sSHRecordDirArgsForCall = append(fake.sSHRecordDirArgsForCall, struct {
+		url string
+	}{url})
+	fake.recordInvocation("SSHRecordDir", []interface{}{url})
+	fake.sSHRecordDirMutex.Unlock()
+	if fake.SSHRecordDirStub != nil {
+		return fake.SSHRecordDirStub(url)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.sSHRecordDirReturns.result1
+}
+
+func (fake *FakeConfig) SSHRecordDirCallCount() int {
+	fake.sSHRecordDirMutex.RLock()
+	defer fake.sSHRecordDirMutex.RUnlock()
+	return len(fake.sSHRecordDirArgsForCall)
+}
+
+func (fake *FakeConfig) SSHRecordDirArgsForCall(i int) string {
+	fake.sSHRecordDirMutex.RLock()
+	defer fake.sSHRecordDirMutex.RUnlock()
+	return fake.sSHRecordDirArgsForCall[i].url
+}
+
+func (fake *FakeConfig) SSHRecordDirReturns(result1 string) {
+	fake.SSHRecordDirStub = nil
+	fake.sSHRecordDirReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeConfig) SSHRecordDirReturnsOnCall(i int, result1 string) {
+	fake.SSHRecordDirStub = nil
+	if fake.sSHRecordDirReturnsOnCall == nil {
+		fake.sSHRecordDirReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.sSHRecordDirReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeConfig) Credentials(url string) config.Creds {
 	fake.credentialsMutex.Lock()
 	ret, specificReturn := fake.credentialsReturnsOnCall[len(fake.credentialsArgsForCall)]
@@ -486,6 +549,8 @@ func (fake *FakeConfig) Invocations() map[string][][]interface{} {
 	defer fake.aliasEnvironmentMutex.RUnlock()
 	fake.cACertMutex.RLock()
 	defer fake.cACertMutex.RUnlock()
+	fake.sSHRecordDirMutex.RLock()
+	defer fake.sSHRecordDirMutex.RUnlock()
 	fake.credentialsMutex.RLock()
 	defer fake.credentialsMutex.RUnlock()
 	fake.setCredentialsMutex.RLock()
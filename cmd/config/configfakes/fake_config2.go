@@ -52,6 +52,10 @@ func (f *FakeConfig2) CACert(environment string) string {
 	return f.Existing.EnvironmentCACert
 }
 
+func (f *FakeConfig2) SSHRecordDir(environment string) string {
+	panic("Not implemented")
+}
+
 func (f *FakeConfig2) Credentials(environment string) config.Creds {
 	panic("Not implemented")
 }
@@ -14,6 +14,7 @@ environments:
   ca_cert: |...
   username: admin
   password: admin
+  ssh_record_dir: ~/bosh-ssh-recordings
 */
 
 type FSConfig struct {
@@ -37,6 +38,9 @@ type fsConfigSchema_Environment struct {
 	Username     string `yaml:"username,omitempty"`
 	Password     string `yaml:"password,omitempty"`
 	RefreshToken string `yaml:"refresh_token,omitempty"`
+
+	// Default directory for recording SSH sessions
+	SSHRecordDir string `yaml:"ssh_record_dir,omitempty"`
 }
 
 func NewFSConfigFromPath(path string, fs boshsys.FileSystem) (FSConfig, error) {
@@ -104,6 +108,12 @@ func (c FSConfig) CACert(urlOrAlias string) string {
 	return tg.CACert
 }
 
+func (c FSConfig) SSHRecordDir(urlOrAlias string) string {
+	_, tg := c.findOrCreateEnvironment(urlOrAlias)
+
+	return tg.SSHRecordDir
+}
+
 func (c FSConfig) Credentials(urlOrAlias string) Creds {
 	_, tg := c.findOrCreateEnvironment(urlOrAlias)
 
@@ -112,6 +112,27 @@ var _ = Describe("FSConfig", func() {
 		})
 	})
 
+	Describe("SSHRecordDir", func() {
+		It("returns empty if file does not exist", func() {
+			Expect(config.SSHRecordDir("url")).To(Equal(""))
+		})
+
+		It("returns directory configured for environment by url or alias", func() {
+			fs.WriteFileString("/dir/sub-dir/config", `
+environments:
+- url: url
+  alias: alias
+  ssh_record_dir: /recordings
+- url: other-url
+`)
+
+			config = readConfig()
+			Expect(config.SSHRecordDir("url")).To(Equal("/recordings"))
+			Expect(config.SSHRecordDir("alias")).To(Equal("/recordings"))
+			Expect(config.SSHRecordDir("other-url")).To(Equal(""))
+		})
+	})
+
 	Describe("AliasEnvironment/CACert", func() {
 		It("returns empty if file does not exist", func() {
 			Expect(config.CACert("url")).To(Equal(""))
@@ -9,6 +9,8 @@ type Config interface {
 
 	CACert(url string) string
 
+	SSHRecordDir(url string) string
+
 	Credentials(url string) Creds
 	SetCredentials(url string, creds Creds) Config
 	UnsetCredentials(url string) Config
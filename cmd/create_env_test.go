@@ -3,6 +3,7 @@ package cmd_test
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 
@@ -54,6 +55,7 @@ import (
 	bistemcell "github.com/cloudfoundry/bosh-cli/stemcell"
 	mock_stemcell "github.com/cloudfoundry/bosh-cli/stemcell/mocks"
 	fakebistemcell "github.com/cloudfoundry/bosh-cli/stemcell/stemcellfakes"
+	bitemplateerb "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
 	biui "github.com/cloudfoundry/bosh-cli/ui"
 	fakebiui "github.com/cloudfoundry/bosh-cli/ui/fakes"
 )
@@ -137,6 +139,8 @@ var _ = Describe("CreateEnvCmd", func() {
 
 			expectedSkipDrain bool
 
+			templatesCache bitemplateerb.CachingERBRenderer
+
 			expectLegacyMigrate        *gomock.Call
 			expectStemcellUpload       *gomock.Call
 			expectStemcellDeleteUnused *gomock.Call
@@ -146,6 +150,7 @@ var _ = Describe("CreateEnvCmd", func() {
 
 		BeforeEach(func() {
 			expectedSkipDrain = false
+			templatesCache = nil
 			logger = boshlog.NewLogger(boshlog.LevelNone)
 			stdOut = gbytes.NewBuffer()
 			stdErr = gbytes.NewBuffer()
@@ -378,6 +383,7 @@ var _ = Describe("CreateEnvCmd", func() {
 					deploymentManifestParser,
 					tempRootConfigurator,
 					targetProvider,
+					templatesCache,
 				)
 			}
 
@@ -508,6 +514,32 @@ var _ = Describe("CreateEnvCmd", func() {
 			})
 		})
 
+		Context("when rendered templates cache is used", func() {
+			var cachePath string
+
+			BeforeEach(func() {
+				templatesCache = bitemplateerb.NewCachingERBRenderer(
+					bitemplateerb.NewNativeERBRenderer(fs, logger), "native", fs, logger)
+				cachePath = filepath.Join("fake-install-dir", "fake-installation-id", "rendered_templates")
+			})
+
+			It("keeps rendered templates in installation directory only readable by current user", func() {
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fs.GetFileTestStat(cachePath).FileMode).To(Equal(os.FileMode(0700)))
+			})
+
+			It("deletes rendered templates that were not used after deploying", func() {
+				stalePath := filepath.Join(cachePath, "fake-stale-template")
+				fs.WriteFileString(stalePath, "fake-rendered")
+				fs.SetGlob(filepath.Join(cachePath, "*"), []string{stalePath})
+
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fs.FileExists(stalePath)).To(BeFalse())
+			})
+		})
+
 		It("parses the installation manifest", func() {
 			err := command.Run(fakeStage, defaultCreateEnvOpts)
 			Expect(err).NotTo(HaveOccurred())
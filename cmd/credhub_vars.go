@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+// CredHubVars loads variables from CredHub credentials under configured path prefix.
+type CredHubVars struct {
+	client *http.Client
+
+	baseURL string
+	prefix  string
+	token   string
+}
+
+var _ boshtpl.Variables = CredHubVars{}
+
+func NewCredHubVars(client *http.Client, baseURL, prefix, token string) CredHubVars {
+	prefix = strings.Trim(prefix, "/")
+	if len(prefix) > 0 {
+		prefix = "/" + prefix
+	}
+
+	return CredHubVars{
+		client: client,
+
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		prefix:  prefix,
+		token:   token,
+	}
+}
+
+type credHubDataResp struct {
+	Data []struct {
+		Value json.RawMessage `json:"value"`
+	} `json:"data"`
+}
+
+type credHubFindResp struct {
+	Credentials []struct {
+		Name string `json:"name"`
+	} `json:"credentials"`
+}
+
+func (v CredHubVars) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	query := url.Values{}
+	query.Set("name", v.prefix+"/"+strings.TrimPrefix(varDef.Name, "/"))
+	query.Set("current", "true")
+
+	req, err := v.request("/api/v1/data?" + query.Encode())
+	if err != nil {
+		return nil, false, err
+	}
+
+	var resp credHubDataResp
+
+	found, err := varsSourceGet(v.client, req, &resp)
+	if err != nil || !found || len(resp.Data) == 0 {
+		return nil, false, err
+	}
+
+	val, err := varsSourceValue(resp.Data[0].Value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+func (v CredHubVars) List() ([]boshtpl.VariableDefinition, error) {
+	query := url.Values{}
+	query.Set("path", v.prefix+"/")
+
+	req, err := v.request("/api/v1/data?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var resp credHubFindResp
+
+	_, err = varsSourceGet(v.client, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []boshtpl.VariableDefinition
+
+	for _, cred := range resp.Credentials {
+		defs = append(defs, boshtpl.VariableDefinition{
+			Name: strings.TrimPrefix(cred.Name, v.prefix+"/"),
+		})
+	}
+
+	return defs, nil
+}
+
+func (v CredHubVars) request(path string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", v.baseURL+path, nil)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Building CredHub request")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+v.token)
+
+	return req, nil
+}
@@ -0,0 +1,87 @@
+package cmd_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+var _ = Describe("CredHubVars", func() {
+	var (
+		server *ghttp.Server
+		vars   CredHubVars
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		vars = NewCredHubVars(http.DefaultClient, server.URL(), "/bosh-lite/", "token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Get", func() {
+		It("returns current value of a credential under prefix", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Fbosh-lite%2Fadmin_password"),
+					ghttp.VerifyHeader(http.Header{"Authorization": []string{"Bearer token"}}),
+					ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"password","value":"secret"}]}`),
+				),
+			)
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("secret"))
+		})
+
+		It("returns structured values", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"certificate","value":{"ca":"ca","certificate":"cert"}}]}`),
+			)
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{Name: "cert"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal(map[interface{}]interface{}{"ca": "ca", "certificate": "cert"}))
+		})
+
+		It("returns not found if credential does not exist", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"error":"not found"}`))
+
+			_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "missing"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns error if response cannot be unmarshaled", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `-`))
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unmarshaling response for '/api/v1/data'"))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists credentials under prefix", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "path=%2Fbosh-lite%2F"),
+					ghttp.RespondWith(http.StatusOK, `{"credentials":[{"name":"/bosh-lite/admin_password"}]}`),
+				),
+			)
+
+			defs, err := vars.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defs).To(Equal([]boshtpl.VariableDefinition{{Name: "admin_password"}}))
+		})
+	})
+})
@@ -2,16 +2,19 @@ package cmd
 
 import (
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshschema "github.com/cloudfoundry/bosh-cli/schema"
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
 )
 
 type DeployCmd struct {
-	ui              boshui.UI
-	deployment      boshdir.Deployment
-	releaseUploader ReleaseUploader
+	ui                boshui.UI
+	deployment        boshdir.Deployment
+	releaseUploader   ReleaseUploader
+	releaseJobsReader ReleaseJobsReader
 }
 
 type ReleaseUploader interface {
@@ -22,8 +25,9 @@ func NewDeployCmd(
 	ui boshui.UI,
 	deployment boshdir.Deployment,
 	releaseUploader ReleaseUploader,
+	releaseJobsReader ReleaseJobsReader,
 ) DeployCmd {
-	return DeployCmd{ui, deployment, releaseUploader}
+	return DeployCmd{ui, deployment, releaseUploader, releaseJobsReader}
 }
 
 func (c DeployCmd) Run(opts DeployOpts) error {
@@ -34,7 +38,35 @@ func (c DeployCmd) Run(opts DeployOpts) error {
 		return bosherr.WrapErrorf(err, "Evaluating manifest")
 	}
 
-	err = c.checkDeploymentName(bytes)
+	schemaCheck := SchemaCheck{
+		manifestType: boshschema.TypeDeployment,
+		custom:       opts.Schema,
+		strict:       opts.StrictSchema,
+		advisory:     true,
+		skipBuiltin:  opts.SkipSchema,
+		ui:           c.ui,
+	}
+
+	err = schemaCheck.Run(bytes, false)
+	if err != nil {
+		return err
+	}
+
+	if opts.ValidateProperties {
+		err = c.checkProperties(bytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = checkDeploymentName(c.deployment, bytes)
+	if err != nil {
+		return err
+	}
+
+	overrides := UpdateOverrides{Canaries: opts.Canaries, MaxInFlight: opts.MaxInFlight}
+
+	bytes, err = overrides.Apply(bytes)
 	if err != nil {
 		return err
 	}
@@ -49,8 +81,24 @@ func (c DeployCmd) Run(opts DeployOpts) error {
 		return err
 	}
 
-	diff := NewDiff(deploymentDiff.Diff)
-	diff.Print(c.ui)
+	if opts.DryRun && opts.JSON {
+		diffBytes, err := NewDiffChanges(deploymentDiff.Changes()).Marshal(DiffFormatJSON)
+		if err != nil {
+			return err
+		}
+
+		c.ui.PrintBlock(diffBytes)
+	} else {
+		diff := NewDiff(deploymentDiff.Diff)
+		diff.Print(c.ui)
+	}
+
+	if opts.Policy.IsSet() {
+		err = PolicyCheck{opts.Policy.Policy, c.ui}.Run(bytes, deploymentDiff, false)
+		if err != nil {
+			return err
+		}
+	}
 
 	err = c.ui.AskForConfirmation()
 	if err != nil {
@@ -63,24 +111,60 @@ func (c DeployCmd) Run(opts DeployOpts) error {
 		Fix:                     opts.Fix,
 		SkipDrain:               opts.SkipDrain,
 		DryRun:                  opts.DryRun,
-		Canaries:                opts.Canaries,
-		MaxInFlight:             opts.MaxInFlight,
+		Canaries:                overrides.GlobalCanaries(),
+		MaxInFlight:             overrides.GlobalMaxInFlight(),
 		Diff:                    deploymentDiff,
 	}
 
 	return c.deployment.Update(bytes, updateOpts)
 }
 
-func (c DeployCmd) checkDeploymentName(bytes []byte) error {
+func checkDeploymentName(deployment boshdir.Deployment, bytes []byte) error {
 	manifest, err := boshdir.NewManifestFromBytes(bytes)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Parsing manifest")
 	}
 
-	if manifest.Name != c.deployment.Name() {
+	if manifest.Name != deployment.Name() {
 		errMsg := "Expected manifest to specify deployment name '%s' but was '%s'"
-		return bosherr.Errorf(errMsg, c.deployment.Name(), manifest.Name)
+		return bosherr.Errorf(errMsg, deployment.Name(), manifest.Name)
 	}
 
 	return nil
 }
+
+// checkProperties checks job properties against job specs of releases
+// that manifest references via local paths; other releases are not available
+// until they are uploaded and therefore are skipped
+func (c DeployCmd) checkProperties(manifest []byte) error {
+	var typedManifest struct {
+		Releases []struct {
+			Name string `yaml:"name"`
+			URL  URLArg `yaml:"url"`
+		} `yaml:"releases"`
+	}
+
+	err := yaml.Unmarshal(manifest, &typedManifest)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Deserializing manifest")
+	}
+
+	var releases []ReleaseJobs
+
+	for _, rel := range typedManifest.Releases {
+		if rel.URL.IsEmpty() || rel.URL.IsRemote() || rel.URL.IsGit() {
+			continue
+		}
+
+		relJobs, err := c.releaseJobsReader.Read(rel.URL.FilePath())
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading jobs of release '%s'", rel.Name)
+		}
+
+		defer relJobs.CleanUp()
+
+		releases = append(releases, relJobs)
+	}
+
+	return PropertiesCheck{releases, c.ui}.Run(manifest, false)
+}
@@ -3,6 +3,8 @@ package cmd_test
 import (
 	"errors"
 
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	"github.com/cppforlife/go-patch/patch"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -12,15 +14,19 @@ import (
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshjob "github.com/cloudfoundry/bosh-cli/release/job"
+	boshres "github.com/cloudfoundry/bosh-cli/release/resource"
 	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 var _ = Describe("DeployCmd", func() {
 	var (
-		ui              *fakeui.FakeUI
-		deployment      *fakedir.FakeDeployment
-		releaseUploader *fakecmd.FakeReleaseUploader
-		command         DeployCmd
+		ui                *fakeui.FakeUI
+		deployment        *fakedir.FakeDeployment
+		releaseUploader   *fakecmd.FakeReleaseUploader
+		releaseJobsReader *fakecmd.FakeReleaseJobsReader
+		command           DeployCmd
 	)
 
 	BeforeEach(func() {
@@ -33,7 +39,9 @@ var _ = Describe("DeployCmd", func() {
 			UploadReleasesStub: func(bytes []byte) ([]byte, error) { return bytes, nil },
 		}
 
-		command = NewDeployCmd(ui, deployment, releaseUploader)
+		releaseJobsReader = &fakecmd.FakeReleaseJobsReader{}
+
+		command = NewDeployCmd(ui, deployment, releaseUploader, releaseJobsReader)
 	})
 
 	Describe("Run", func() {
@@ -98,6 +106,269 @@ var _ = Describe("DeployCmd", func() {
 			}))
 		})
 
+		It("deploys manifest with deployment-wide canaries and max_in_flight", func() {
+			opts.Canaries = UpdateOverrideArgs{{Value: "1"}}
+			opts.MaxInFlight = UpdateOverrideArgs{{Value: "10%"}}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+
+			bytes, updateOpts := deployment.UpdateArgsForCall(0)
+			Expect(bytes).To(Equal([]byte("name: dep\n")))
+			Expect(updateOpts).To(Equal(boshdir.UpdateOpts{
+				Canaries:    "1",
+				MaxInFlight: "10%",
+			}))
+		})
+
+		It("deploys manifest with instance group specific canaries and max_in_flight injected", func() {
+			opts.Args.Manifest = FileBytesArg{
+				Bytes: []byte("name: dep\ninstance_groups:\n- name: ig1\n  update:\n    serial: true\n- name: ig2\n"),
+			}
+
+			opts.Canaries = UpdateOverrideArgs{{InstanceGroup: "ig1", Value: "1"}}
+			opts.MaxInFlight = UpdateOverrideArgs{{Value: "10%"}, {InstanceGroup: "ig2", Value: "20%"}}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+
+			bytes, updateOpts := deployment.UpdateArgsForCall(0)
+			Expect(string(bytes)).To(Equal(`instance_groups:
+- name: ig1
+  update:
+    canaries: "1"
+    max_in_flight: 10%
+    serial: true
+- name: ig2
+  update:
+    max_in_flight: 20%
+name: dep
+`))
+			Expect(updateOpts).To(Equal(boshdir.UpdateOpts{}))
+
+			Expect(deployment.DiffCallCount()).To(Equal(1))
+			diffBytes, _ := deployment.DiffArgsForCall(0)
+			Expect(diffBytes).To(Equal(bytes))
+		})
+
+		It("returns error if instance group specific value refers to unknown instance group", func() {
+			opts.Canaries = UpdateOverrideArgs{{InstanceGroup: "unknown", Value: "1"}}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Overriding instance group update values"))
+
+			Expect(deployment.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("does not deploy manifest if it violates policy", func() {
+			fs := fakesys.NewFakeFileSystem()
+			fs.SetGlob("/policy/*.yml", []string{"/policy/rules.yml"})
+			fs.WriteFileString("/policy/rules.yml", "rules: [{name: stemcells, path: /stemcells, required: true}]")
+
+			opts.Policy = PolicyDirArg{FS: fs}
+			err := (&opts.Policy).UnmarshalFlag("/policy")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to satisfy policy but found 1 violation(s)"))
+
+			Expect(ui.Table.Rows).To(HaveLen(1))
+			Expect(ui.AskedConfirmationCalled).To(BeFalse())
+			Expect(deployment.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("deploys manifest if it satisfies policy", func() {
+			fs := fakesys.NewFakeFileSystem()
+			fs.SetGlob("/policy/*.yml", []string{"/policy/rules.yml"})
+			fs.WriteFileString("/policy/rules.yml", "rules: [{name: name, path: /name, one_of: [dep]}]")
+
+			opts.Policy = PolicyDirArg{FS: fs}
+			err := (&opts.Policy).UnmarshalFlag("/policy")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Tables).To(BeEmpty())
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("warns about manifest not matching built-in schema but still deploys it", func() {
+			opts.Args.Manifest = FileBytesArg{
+				Bytes: []byte("name: dep\ninstance_group:\n- name: ig1\ninstance_groups: {}\n"),
+			}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table.Content).To(Equal("schema violations"))
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/instance_group"),
+					boshtbl.NewValueString("warning"),
+					boshtbl.NewValueString("Expected key to be known (did you mean 'instance_groups'?)"),
+				},
+				{
+					boshtbl.NewValueString("/instance_groups"),
+					boshtbl.NewValueString("warning"),
+					boshtbl.NewValueString("Expected value to be of type 'array' but was 'object'"),
+				},
+			}))
+
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("does not deploy manifest if it does not match built-in schema when schema check is strict", func() {
+			opts.StrictSchema = true
+			opts.Args.Manifest = FileBytesArg{
+				Bytes: []byte("name: dep\ninstance_group:\n- name: ig1\n"),
+			}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'deployment' schema but found 1 violation(s)"))
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/instance_group"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected key to be known (did you mean 'instance_groups'?)"),
+				},
+			}))
+
+			Expect(deployment.DiffCallCount()).To(Equal(0))
+			Expect(deployment.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("does not check manifest against built-in schema if schema check is skipped", func() {
+			opts.SkipSchema = true
+			opts.Args.Manifest = FileBytesArg{
+				Bytes: []byte("name: dep\ninstance_group:\n- name: ig1\n"),
+			}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Tables).To(BeEmpty())
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+		})
+
+		It("does not deploy manifest if it does not match custom schema", func() {
+			fs := fakesys.NewFakeFileSystem()
+			fs.WriteFileString("/schema.yml", "properties: {name: {enum: [other-dep]}}")
+
+			opts.Schema = SchemaFileArg{FS: fs}
+			err := (&opts.Schema).UnmarshalFlag("/schema.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'deployment' schema but found 1 violation(s)"))
+
+			Expect(ui.Table.Rows).To(HaveLen(1))
+			Expect(deployment.UpdateCallCount()).To(Equal(0))
+		})
+
+		Context("when validating job properties", func() {
+			BeforeEach(func() {
+				opts.ValidateProperties = true
+				opts.Args.Manifest = FileBytesArg{Bytes: []byte(`
+name: dep
+releases:
+- {name: capi, url: file:///capi.tgz}
+- {name: remote, url: https://remote-url}
+instance_groups:
+- name: web
+  jobs:
+  - name: api
+    release: capi
+    properties: {ports: 8080}
+  - name: other
+    release: remote
+    properties: {any: value}
+`)}
+
+				job := boshjob.NewJob(boshres.NewResource("api", "api-fp", nil))
+				job.Properties = map[string]boshjob.PropertyDefinition{
+					"port": {Default: biproperty.Property(80)},
+				}
+
+				releaseJobsReader.ReadReturns(ReleaseJobs{Name: "capi", Jobs: []*boshjob.Job{job}}, nil)
+			})
+
+			It("does not deploy manifest if job properties do not match specs of local releases", func() {
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected job properties to match job specs but found 1 violation(s)"))
+
+				Expect(releaseJobsReader.ReadCallCount()).To(Equal(1))
+				Expect(releaseJobsReader.ReadArgsForCall(0)).To(Equal("/capi.tgz"))
+
+				Expect(ui.Said).To(ContainElement(
+					"Skipped checking properties of jobs from releases that were not provided: remote"))
+
+				Expect(ui.Table.Content).To(Equal("property violations"))
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("/instance_groups/name=web/jobs/name=api"),
+						boshtbl.NewValueString("ports"),
+						boshtbl.NewValueString("error"),
+						boshtbl.NewValueString("Expected property to be defined in job spec (did you mean 'port'?)"),
+					},
+				}))
+
+				Expect(releaseUploader.UploadReleasesCallCount()).To(Equal(0))
+				Expect(deployment.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("returns error if release jobs cannot be read", func() {
+				releaseJobsReader.ReadReturns(ReleaseJobs{}, errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+				Expect(deployment.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("does not check job properties unless requested", func() {
+				opts.ValidateProperties = false
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(releaseJobsReader.ReadCallCount()).To(Equal(0))
+				Expect(deployment.UpdateCallCount()).To(Equal(1))
+			})
+		})
+
+		It("prints structured diff document when doing a dry run with JSON output", func() {
+			opts.DryRun = true
+			opts.JSON = true
+
+			deployment.DiffReturns(boshdir.NewDeploymentDiff([][]interface{}{
+				{"instance_groups:", nil},
+				{"- name: ig1", nil},
+				{"  instances: 1", "removed"},
+				{"  instances: 2", "added"},
+			}, nil), nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(BeEmpty())
+			Expect(ui.Tables).To(BeEmpty())
+			Expect(ui.Blocks).To(HaveLen(1))
+			Expect(ui.Blocks[0]).To(ContainSubstring(`"instance_groups": {`))
+			Expect(ui.Blocks[0]).To(ContainSubstring(`"path": "/instance_groups/name=ig1/instances"`))
+
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+		})
+
 		It("deploys templated manifest", func() {
 			opts.Args.Manifest = FileBytesArg{
 				Bytes: []byte("name: dep\nname1: ((name1))\nname2: ((name2))\n"),
@@ -20,6 +20,7 @@ import (
 	biinstallmanifest "github.com/cloudfoundry/bosh-cli/installation/manifest"
 	birelsetmanifest "github.com/cloudfoundry/bosh-cli/release/set/manifest"
 	bistemcell "github.com/cloudfoundry/bosh-cli/stemcell"
+	bitemplateerb "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
 	biui "github.com/cloudfoundry/bosh-cli/ui"
 )
 
@@ -47,6 +48,7 @@ func NewDeploymentPreparer(
 	deploymentManifestParser DeploymentManifestParser,
 	tempRootConfigurator TempRootConfigurator,
 	targetProvider biinstall.TargetProvider,
+	templatesCache bitemplateerb.CachingERBRenderer,
 ) DeploymentPreparer {
 	return DeploymentPreparer{
 		ui:                                      ui,
@@ -72,6 +74,7 @@ func NewDeploymentPreparer(
 		deploymentManifestParser:                deploymentManifestParser,
 		tempRootConfigurator:                    tempRootConfigurator,
 		targetProvider:                          targetProvider,
+		templatesCache:                          templatesCache,
 	}
 }
 
@@ -99,6 +102,7 @@ type DeploymentPreparer struct {
 	deploymentManifestParser                DeploymentManifestParser
 	tempRootConfigurator                    TempRootConfigurator
 	targetProvider                          biinstall.TargetProvider
+	templatesCache                          bitemplateerb.CachingERBRenderer
 }
 
 func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage, recreate bool, recreatePersistentDisks bool, skipDrain bool) (err error) {
@@ -129,6 +133,14 @@ func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage, recreate bool,
 		return bosherr.WrapError(err, "Setting temp root")
 	}
 
+	// Rendered templates cache is optional since it keeps rendered credentials
+	if c.templatesCache != nil {
+		err = c.templatesCache.UseCacheDir(target.RenderedTemplatesCachePath())
+		if err != nil {
+			return bosherr.WrapError(err, "Setting rendered templates cache")
+		}
+	}
+
 	defer func() {
 		err := c.releaseManager.DeleteAll()
 		if err != nil {
@@ -202,9 +214,18 @@ func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage, recreate bool,
 				stage)
 		})
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	if c.templatesCache != nil {
+		err = c.templatesCache.PruneUnused()
+		if err != nil {
+			c.logger.Warn(c.logTag, "Failed to prune rendered templates cache: %s", err.Error())
+		}
+	}
 
+	return nil
 }
 
 func (c *DeploymentPreparer) deploy(
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+const (
+	DiffFormatText = "text"
+	DiffFormatJSON = "json"
+	DiffFormatYAML = "yaml"
+)
+
+// DiffChanges presents deployment diff in a machine readable form
+type DiffChanges struct {
+	changes []boshdir.DiffChange
+}
+
+type DiffChangesDocument struct {
+	Deployment     DiffChangesGroup            `json:"deployment"      yaml:"deployment"`
+	InstanceGroups map[string]DiffChangesGroup `json:"instance_groups" yaml:"instance_groups"`
+}
+
+type DiffChangesGroup struct {
+	Added   []DiffChangesEntry `json:"added"   yaml:"added"`
+	Removed []DiffChangesEntry `json:"removed" yaml:"removed"`
+	Changed []DiffChangesEntry `json:"changed" yaml:"changed"`
+}
+
+type DiffChangesEntry struct {
+	Path     string `json:"path"                yaml:"path"`
+	OldValue string `json:"old_value,omitempty" yaml:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty" yaml:"new_value,omitempty"`
+	Redacted bool   `json:"redacted"            yaml:"redacted"`
+}
+
+func NewDiffChanges(changes []boshdir.DiffChange) DiffChanges {
+	return DiffChanges{changes: changes}
+}
+
+func (d DiffChanges) Document() DiffChangesDocument {
+	doc := DiffChangesDocument{
+		Deployment:     newDiffChangesGroup(),
+		InstanceGroups: map[string]DiffChangesGroup{},
+	}
+
+	for _, change := range d.changes {
+		group := doc.Deployment
+
+		if len(change.InstanceGroup) > 0 {
+			var found bool
+
+			group, found = doc.InstanceGroups[change.InstanceGroup]
+			if !found {
+				group = newDiffChangesGroup()
+			}
+		}
+
+		entry := DiffChangesEntry{
+			Path:     change.Path,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+			Redacted: change.Redacted,
+		}
+
+		switch change.Type {
+		case boshdir.DiffChangeAdded:
+			group.Added = append(group.Added, entry)
+		case boshdir.DiffChangeRemoved:
+			group.Removed = append(group.Removed, entry)
+		default:
+			group.Changed = append(group.Changed, entry)
+		}
+
+		if len(change.InstanceGroup) > 0 {
+			doc.InstanceGroups[change.InstanceGroup] = group
+		} else {
+			doc.Deployment = group
+		}
+	}
+
+	return doc
+}
+
+func (d DiffChanges) Marshal(format string) ([]byte, error) {
+	switch format {
+	case DiffFormatJSON:
+		var buf bytes.Buffer
+
+		// Avoid escaping of '<redacted>' values
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+
+		err := enc.Encode(d.Document())
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Marshaling diff")
+		}
+		return buf.Bytes(), nil
+
+	case DiffFormatYAML:
+		bytes, err := yaml.Marshal(d.Document())
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Marshaling diff")
+		}
+		return bytes, nil
+
+	default:
+		return nil, bosherr.Errorf("Expected diff format to be either '%s' or '%s' but was '%s'",
+			DiffFormatJSON, DiffFormatYAML, format)
+	}
+}
+
+func newDiffChangesGroup() DiffChangesGroup {
+	return DiffChangesGroup{
+		Added:   []DiffChangesEntry{},
+		Removed: []DiffChangesEntry{},
+		Changed: []DiffChangesEntry{},
+	}
+}
@@ -0,0 +1,110 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+var _ = Describe("DiffChanges", func() {
+	var (
+		changes DiffChanges
+	)
+
+	BeforeEach(func() {
+		changes = NewDiffChanges([]boshdir.DiffChange{
+			{Path: "/update/canaries", Type: "changed", OldValue: "1", NewValue: "2"},
+			{Path: "/instance_groups/name=ig1/instances", InstanceGroup: "ig1", Type: "added", NewValue: "3"},
+			{Path: "/instance_groups/name=ig1/properties/pass", InstanceGroup: "ig1", Type: "removed", OldValue: "<redacted>", Redacted: true},
+		})
+	})
+
+	Describe("Document", func() {
+		It("groups changes by instance group and type", func() {
+			Expect(changes.Document()).To(Equal(DiffChangesDocument{
+				Deployment: DiffChangesGroup{
+					Added:   []DiffChangesEntry{},
+					Removed: []DiffChangesEntry{},
+					Changed: []DiffChangesEntry{{Path: "/update/canaries", OldValue: "1", NewValue: "2"}},
+				},
+				InstanceGroups: map[string]DiffChangesGroup{
+					"ig1": {
+						Added:   []DiffChangesEntry{{Path: "/instance_groups/name=ig1/instances", NewValue: "3"}},
+						Removed: []DiffChangesEntry{{Path: "/instance_groups/name=ig1/properties/pass", OldValue: "<redacted>", Redacted: true}},
+						Changed: []DiffChangesEntry{},
+					},
+				},
+			}))
+		})
+	})
+
+	Describe("Marshal", func() {
+		It("returns JSON", func() {
+			bytes, err := changes.Marshal("json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(bytes)).To(Equal(`{
+  "deployment": {
+    "added": [],
+    "removed": [],
+    "changed": [
+      {
+        "path": "/update/canaries",
+        "old_value": "1",
+        "new_value": "2",
+        "redacted": false
+      }
+    ]
+  },
+  "instance_groups": {
+    "ig1": {
+      "added": [
+        {
+          "path": "/instance_groups/name=ig1/instances",
+          "new_value": "3",
+          "redacted": false
+        }
+      ],
+      "removed": [
+        {
+          "path": "/instance_groups/name=ig1/properties/pass",
+          "old_value": "<redacted>",
+          "redacted": true
+        }
+      ],
+      "changed": []
+    }
+  }
+}
+`))
+		})
+
+		It("returns YAML", func() {
+			bytes, err := NewDiffChanges([]boshdir.DiffChange{
+				{Path: "/instance_groups/name=ig1/instances", InstanceGroup: "ig1", Type: "changed", OldValue: "1", NewValue: "3"},
+			}).Marshal("yaml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(bytes)).To(Equal(`deployment:
+  added: []
+  removed: []
+  changed: []
+instance_groups:
+  ig1:
+    added: []
+    removed: []
+    changed:
+    - path: /instance_groups/name=ig1/instances
+      old_value: "1"
+      new_value: "3"
+      redacted: false
+`))
+		})
+
+		It("returns error for unknown format", func() {
+			_, err := changes.Marshal("xml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected diff format to be either 'json' or 'yaml' but was 'xml'"))
+		})
+	})
+})
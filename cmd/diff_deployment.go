@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+type DiffDeploymentCmd struct {
+	ui         boshui.UI
+	deployment boshdir.Deployment
+}
+
+func NewDiffDeploymentCmd(ui boshui.UI, deployment boshdir.Deployment) DiffDeploymentCmd {
+	return DiffDeploymentCmd{ui: ui, deployment: deployment}
+}
+
+func (c DiffDeploymentCmd) Run(opts DiffDeploymentOpts) error {
+	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
+
+	bytes, err := tpl.Evaluate(opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp(), boshtpl.EvaluateOpts{})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Evaluating manifest")
+	}
+
+	err = checkDeploymentName(c.deployment, bytes)
+	if err != nil {
+		return err
+	}
+
+	deploymentDiff, err := c.deployment.Diff(bytes, opts.NoRedact)
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == DiffFormatText {
+		NewDiff(deploymentDiff.Diff).Print(c.ui)
+		return nil
+	}
+
+	diffBytes, err := NewDiffChanges(deploymentDiff.Changes()).Marshal(opts.Format)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintBlock(diffBytes)
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("DiffDeploymentCmd", func() {
+	var (
+		ui         *fakeui.FakeUI
+		deployment *fakedir.FakeDeployment
+		command    DiffDeploymentCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		deployment = &fakedir.FakeDeployment{
+			NameStub: func() string { return "dep" },
+		}
+		command = NewDiffDeploymentCmd(ui, deployment)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts DiffDeploymentOpts
+		)
+
+		BeforeEach(func() {
+			opts = DiffDeploymentOpts{
+				Args: DiffDeploymentArgs{
+					Manifest: FileBytesArg{Bytes: []byte("name: dep\ninstances: ((count))\n")},
+				},
+				Format: "text",
+			}
+
+			opts.VarKVs = []boshtpl.VarKV{{Name: "count", Value: 2}}
+
+			deployment.DiffReturns(boshdir.NewDeploymentDiff([][]interface{}{
+				{"instances: 1", "removed"},
+				{"instances: 2", "added"},
+			}, nil), nil)
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("prints text diff for interpolated manifest", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.DiffCallCount()).To(Equal(1))
+			bytes, noRedact := deployment.DiffArgsForCall(0)
+			Expect(string(bytes)).To(Equal("instances: 2\nname: dep\n"))
+			Expect(noRedact).To(BeFalse())
+
+			Expect(ui.Said).To(Equal([]string{"- instances: 1\n", "+ instances: 2\n"}))
+		})
+
+		It("prints structured diff in YAML", func() {
+			opts.Format = "yaml"
+			opts.NoRedact = true
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, noRedact := deployment.DiffArgsForCall(0)
+			Expect(noRedact).To(BeTrue())
+
+			Expect(ui.Blocks).To(Equal([]string{`deployment:
+  added: []
+  removed: []
+  changed:
+  - path: /instances
+    old_value: "1"
+    new_value: "2"
+    redacted: false
+instance_groups: {}
+`}))
+		})
+
+		It("prints structured diff in JSON", func() {
+			opts.Format = "json"
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(HaveLen(1))
+			Expect(ui.Blocks[0]).To(ContainSubstring(`"path": "/instances"`))
+		})
+
+		It("returns error if format is unknown", func() {
+			opts.Format = "xml"
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected diff format to be either 'json' or 'yaml' but was 'xml'"))
+		})
+
+		It("returns error if manifest name does not match deployment name", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte("name: other-dep")}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to specify deployment name 'dep' but was 'other-dep'"))
+		})
+
+		It("returns error if diffing fails", func() {
+			deployment.DiffReturns(boshdir.DeploymentDiff{}, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
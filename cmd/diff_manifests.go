@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+type DiffManifestsCmd struct {
+	ui boshui.UI
+}
+
+func NewDiffManifestsCmd(ui boshui.UI) DiffManifestsCmd {
+	return DiffManifestsCmd{ui: ui}
+}
+
+func (c DiffManifestsCmd) Run(opts DiffManifestsOpts) error {
+	if opts.Format != DiffFormatText && opts.Format != DiffFormatJSON && opts.Format != DiffFormatYAML {
+		return bosherr.Errorf("Expected diff format to be either '%s', '%s' or '%s' but was '%s'",
+			DiffFormatText, DiffFormatJSON, DiffFormatYAML, opts.Format)
+	}
+
+	oldBytes, err := c.evaluate(opts.Args.OldManifest.Bytes, opts)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Evaluating old manifest")
+	}
+
+	newBytes, err := c.evaluate(opts.Args.NewManifest.Bytes, opts)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Evaluating new manifest")
+	}
+
+	manifestDiff, err := boshdir.NewManifestDiff(oldBytes, newBytes)
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == DiffFormatText {
+		NewDiff(manifestDiff.Diff).Print(c.ui)
+		return nil
+	}
+
+	diffBytes, err := NewDiffChanges(manifestDiff.Changes()).Marshal(opts.Format)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintBlock(diffBytes)
+
+	return nil
+}
+
+func (c DiffManifestsCmd) evaluate(bytes []byte, opts DiffManifestsOpts) ([]byte, error) {
+	tpl := boshtpl.NewTemplate(bytes)
+
+	return tpl.Evaluate(opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp(), boshtpl.EvaluateOpts{})
+}
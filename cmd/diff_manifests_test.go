@@ -0,0 +1,136 @@
+package cmd_test
+
+import (
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("DiffManifestsCmd", func() {
+	var (
+		ui      *fakeui.FakeUI
+		command DiffManifestsCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		command = NewDiffManifestsCmd(ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts DiffManifestsOpts
+		)
+
+		BeforeEach(func() {
+			opts = DiffManifestsOpts{
+				Args: DiffManifestsArgs{
+					OldManifest: FileBytesArg{Bytes: []byte(`
+instance_groups:
+- name: router
+  instances: 1
+- name: db
+  instances: ((db_count))
+`)},
+					NewManifest: FileBytesArg{Bytes: []byte(`
+instance_groups:
+- name: db
+  instances: ((db_count))
+- name: router
+  instances: ((router_count))
+`)},
+				},
+				Format: "text",
+			}
+
+			opts.VarKVs = []boshtpl.VarKV{
+				{Name: "db_count", Value: 1},
+				{Name: "router_count", Value: 2},
+			}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("prints text diff of both interpolated manifests keyed by name", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"  instance_groups:\n",
+				"  - name: router\n",
+				"-   instances: 1\n",
+				"+   instances: 2\n",
+			}))
+		})
+
+		It("applies ops files to both manifests", func() {
+			opts.OpsFiles = []OpsFileArg{
+				{
+					Ops: patch.Ops([]patch.Op{
+						patch.ReplaceOp{Path: patch.MustNewPointerFromString("/instance_groups/name=router/instances"), Value: 3},
+					}),
+				},
+			}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.Said).To(BeEmpty())
+		})
+
+		It("prints structured diff in JSON", func() {
+			opts.Format = "json"
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(Equal([]string{`{
+  "deployment": {
+    "added": [],
+    "removed": [],
+    "changed": []
+  },
+  "instance_groups": {
+    "router": {
+      "added": [],
+      "removed": [],
+      "changed": [
+        {
+          "path": "/instance_groups/name=router/instances",
+          "old_value": "1",
+          "new_value": "2",
+          "redacted": false
+        }
+      ]
+    }
+  }
+}
+`}))
+		})
+
+		It("returns error if format is unknown", func() {
+			opts.Format = "xml"
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected diff format to be either 'text', 'json' or 'yaml' but was 'xml'"))
+		})
+
+		It("returns error if manifest cannot be interpolated", func() {
+			opts.OpsFiles = []OpsFileArg{
+				{
+					Ops: patch.Ops([]patch.Op{
+						patch.RemoveOp{Path: patch.MustNewPointerFromString("/unknown")},
+					}),
+				},
+			}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Evaluating old manifest"))
+		})
+	})
+})
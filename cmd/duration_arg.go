@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// DurationArg is a duration that in addition to Go duration format (e.g. '12h')
+// accepts number of days (e.g. '30d').
+type DurationArg struct {
+	time.Duration
+}
+
+func (a *DurationArg) UnmarshalFlag(data string) error {
+	if strings.HasSuffix(data, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(data, "d"))
+		if err == nil && days >= 0 {
+			a.Duration = time.Duration(days) * 24 * time.Hour
+			return nil
+		}
+	}
+
+	duration, err := time.ParseDuration(data)
+	if err != nil || duration < 0 {
+		return bosherr.Errorf("Expected duration '%s' to be a number of days (e.g. '30d') or a duration (e.g. '12h')", data)
+	}
+
+	a.Duration = duration
+
+	return nil
+}
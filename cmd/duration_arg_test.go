@@ -0,0 +1,42 @@
+package cmd_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("DurationArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			arg *DurationArg
+		)
+
+		BeforeEach(func() {
+			arg = &DurationArg{}
+		})
+
+		It("parses number of days", func() {
+			err := arg.UnmarshalFlag("30d")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Duration).To(Equal(30 * 24 * time.Hour))
+		})
+
+		It("parses go durations", func() {
+			err := arg.UnmarshalFlag("1h30m")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Duration).To(Equal(90 * time.Minute))
+		})
+
+		It("returns error for invalid or negative durations", func() {
+			for _, data := range []string{"", "d", "-1d", "-1h", "1w", "1.5d"} {
+				err := arg.UnmarshalFlag(data)
+				Expect(err).To(HaveOccurred(), data)
+				Expect(err.Error()).To(ContainSubstring("Expected duration '" + data + "' to be a number of days"))
+			}
+		})
+	})
+})
@@ -51,6 +51,7 @@ type envFactory struct {
 
 	cpiInstaller   bicpirel.CpiInstaller
 	targetProvider boshinst.TargetProvider
+	templatesCache bitemplateerb.CachingERBRenderer
 	cloudFactory   bicloud.Factory
 
 	diskManagerFactory     bidisk.ManagerFactory
@@ -73,6 +74,8 @@ func NewEnvFactory(
 	manifestVars boshtpl.Variables,
 	manifestOp patch.Op,
 	recreatePersistentDisks bool,
+	nativeERB bool,
+	cacheTemplates bool,
 ) *envFactory {
 	f := envFactory{
 		deps:         deps,
@@ -112,13 +115,21 @@ func NewEnvFactory(
 		}
 	}
 
+	erbRenderer := newERBRenderer(deps, nativeERB)
+
+	if cacheTemplates {
+		f.templatesCache = bitemplateerb.NewCachingERBRenderer(
+			erbRenderer, erbRendererName(nativeERB), deps.FS, deps.Logger)
+		erbRenderer = f.templatesCache
+	}
+
 	f.deploymentStateService = biconfig.NewFileSystemDeploymentStateService(
 		deps.FS, deps.UUIDGen, deps.Logger, biconfig.DeploymentStatePath(manifestPath, statePath))
 
 	{
 		registryServer := biregistry.NewServerManager(deps.Logger)
 		installerFactory := boshinst.NewInstallerFactory(
-			deps.UI, deps.CmdRunner, deps.Compressor, releaseJobResolver,
+			deps.UI, deps.CmdRunner, erbRenderer, deps.Compressor, releaseJobResolver,
 			deps.UUIDGen, registryServer, deps.Logger, deps.FS, deps.DigestCreationAlgorithms)
 
 		f.cpiInstaller = bicpirel.CpiInstaller{
@@ -156,7 +167,6 @@ func NewEnvFactory(
 	}
 
 	{
-		erbRenderer := bitemplateerb.NewERBRenderer(deps.FS, deps.CmdRunner, deps.Logger)
 		jobRenderer := bitemplate.NewJobRenderer(erbRenderer, deps.FS, deps.UUIDGen, deps.Logger)
 
 		builderFactory := biinstancestate.NewBuilderFactory(
@@ -190,6 +200,28 @@ func NewEnvFactory(
 	return &f
 }
 
+// newERBRenderer renders templates with Ruby unless native renderer is requested;
+// native renderer still falls back to Ruby for templates it does not support
+func newERBRenderer(deps BasicDeps, native bool) bitemplateerb.ERBRenderer {
+	rubyRenderer := bitemplateerb.NewERBRenderer(deps.FS, deps.CmdRunner, deps.Logger)
+
+	if !native {
+		return rubyRenderer
+	}
+
+	return bitemplateerb.NewFallbackERBRenderer(
+		bitemplateerb.NewNativeERBRenderer(deps.FS, deps.Logger), rubyRenderer, deps.Logger)
+}
+
+// erbRendererName distinguishes rendered templates cache entries
+// since native renderer may not produce exactly same output as Ruby
+func erbRendererName(native bool) string {
+	if native {
+		return "native"
+	}
+	return "ruby"
+}
+
 func (f *envFactory) Preparer() DeploymentPreparer {
 	return NewDeploymentPreparer(
 		f.deps.UI,
@@ -230,6 +262,7 @@ func (f *envFactory) Preparer() DeploymentPreparer {
 		),
 		NewTempRootConfigurator(f.deps.FS),
 		f.targetProvider,
+		f.templatesCache,
 	)
 }
 
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+/*
+ExecVars loads variables from a plugin executable. Each operation
+runs plugin once with a JSON request on stdin and expects JSON response on stdout:
+
+	{"operation":"get","name":"db_password","type":"password"}
+	=> {"found":true,"value":"secret"}
+
+	{"operation":"list"}
+	=> {"names":["db_password"]}
+*/
+type ExecVars struct {
+	cmdRunner boshsys.CmdRunner
+
+	path string
+	args []string
+
+	logTag string
+	logger boshlog.Logger
+}
+
+var _ boshtpl.Variables = ExecVars{}
+
+func NewExecVars(cmdRunner boshsys.CmdRunner, path string, args []string, logger boshlog.Logger) ExecVars {
+	return ExecVars{
+		cmdRunner: cmdRunner,
+
+		path: path,
+		args: args,
+
+		logTag: "ExecVars",
+		logger: logger,
+	}
+}
+
+type execVarsReq struct {
+	Operation string `json:"operation"`
+
+	Name    string      `json:"name,omitempty"`
+	Type    string      `json:"type,omitempty"`
+	Options interface{} `json:"options,omitempty"`
+}
+
+type execVarsGetResp struct {
+	Found bool            `json:"found"`
+	Value json.RawMessage `json:"value"`
+}
+
+type execVarsListResp struct {
+	Names []string `json:"names"`
+}
+
+func (v ExecVars) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	req := execVarsReq{
+		Operation: "get",
+		Name:      varDef.Name,
+		Type:      varDef.Type,
+		Options:   varsSourceJSONValue(varDef.Options),
+	}
+
+	var resp execVarsGetResp
+
+	err := v.run(req, &resp)
+	if err != nil || !resp.Found {
+		return nil, false, err
+	}
+
+	val, err := varsSourceValue(resp.Value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+func (v ExecVars) List() ([]boshtpl.VariableDefinition, error) {
+	var resp execVarsListResp
+
+	err := v.run(execVarsReq{Operation: "list"}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []boshtpl.VariableDefinition
+
+	for _, name := range resp.Names {
+		defs = append(defs, boshtpl.VariableDefinition{Name: name})
+	}
+
+	return defs, nil
+}
+
+func (v ExecVars) run(req execVarsReq, resp interface{}) error {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Marshaling plugin request")
+	}
+
+	v.logger.Debug(v.logTag, "Running plugin '%s' for '%s' operation", v.path, req.Operation)
+
+	cmd := boshsys.Command{
+		Name:  v.path,
+		Args:  v.args,
+		Stdin: bytes.NewReader(reqBytes),
+	}
+
+	stdout, stderr, _, err := v.cmdRunner.RunComplexCommand(cmd)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Running plugin '%s': %s", v.path, strings.TrimSpace(stderr))
+	}
+
+	err = json.Unmarshal([]byte(stdout), resp)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Unmarshaling plugin '%s' response", v.path)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmd_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+var _ = Describe("ExecVars", func() {
+	var (
+		cmdRunner *fakesys.FakeCmdRunner
+		vars      ExecVars
+	)
+
+	BeforeEach(func() {
+		cmdRunner = fakesys.NewFakeCmdRunner()
+		vars = NewExecVars(cmdRunner, "/plugin", []string{"--profile", "prod"}, boshlog.NewLogger(boshlog.LevelNone))
+	})
+
+	Describe("Get", func() {
+		It("sends variable definition to plugin and returns found value", func() {
+			cmdRunner.AddCmdResult("/plugin --profile prod", fakesys.FakeCmdResult{
+				Stdout: `{"found":true,"value":{"ca":"ca"}}`,
+			})
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{
+				Name:    "cert",
+				Type:    "certificate",
+				Options: map[interface{}]interface{}{"common_name": "cert"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal(map[interface{}]interface{}{"ca": "ca"}))
+
+			Expect(cmdRunner.RunComplexCommands).To(HaveLen(1))
+
+			stdin, err := ioutil.ReadAll(cmdRunner.RunComplexCommands[0].Stdin)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(stdin)).To(Equal(
+				`{"operation":"get","name":"cert","type":"certificate","options":{"common_name":"cert"}}`))
+		})
+
+		It("returns not found if plugin did not find variable", func() {
+			cmdRunner.AddCmdResult("/plugin --profile prod", fakesys.FakeCmdResult{
+				Stdout: `{"found":false}`,
+			})
+
+			_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "missing"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns error with stderr if plugin fails", func() {
+			cmdRunner.AddCmdResult("/plugin --profile prod", fakesys.FakeCmdResult{
+				Stderr: "fake-stderr\n",
+				Error:  errors.New("fake-err"),
+			})
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "var"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Running plugin '/plugin': fake-stderr"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if plugin response cannot be unmarshaled", func() {
+			cmdRunner.AddCmdResult("/plugin --profile prod", fakesys.FakeCmdResult{Stdout: "-"})
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "var"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unmarshaling plugin '/plugin' response"))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns variable names listed by plugin", func() {
+			cmdRunner.AddCmdResult("/plugin --profile prod", fakesys.FakeCmdResult{
+				Stdout: `{"names":["var1","var2"]}`,
+			})
+
+			defs, err := vars.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defs).To(Equal([]boshtpl.VariableDefinition{{Name: "var1"}, {Name: "var2"}}))
+		})
+	})
+})
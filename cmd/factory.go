@@ -79,6 +79,14 @@ func (f Factory) New(args []string) (Cmd, error) {
 			opts.Deployment = boshOpts.DeploymentOpt
 		}
 
+		if opts, ok := command.(*DeployOpts); ok {
+			opts.JSON = boshOpts.JSONOpt
+		}
+
+		if opts, ok := command.(*SSHOpts); ok {
+			opts.JSON = boshOpts.JSONOpt
+		}
+
 		if len(extraArgs) > 0 {
 			errMsg := "Command '%T' does not support extra arguments: %s"
 			return fmt.Errorf(errMsg, command, strings.Join(extraArgs, ", "))
@@ -92,6 +100,7 @@ func (f Factory) New(args []string) (Cmd, error) {
 	boshOpts.SSH.GatewayFlags.UUIDGen = f.deps.UUIDGen
 	boshOpts.SCP.GatewayFlags.UUIDGen = f.deps.UUIDGen
 	boshOpts.Logs.GatewayFlags.UUIDGen = f.deps.UUIDGen
+	boshOpts.PortForward.GatewayFlags.UUIDGen = f.deps.UUIDGen
 
 	goflags.FactoryFunc = func(val interface{}) {
 		stype := reflect.Indirect(reflect.ValueOf(val))
@@ -100,6 +109,21 @@ func (f Factory) New(args []string) (Cmd, error) {
 			if field.IsValid() {
 				field.Set(reflect.ValueOf(f.deps.FS))
 			}
+
+			uiField := stype.FieldByName("UI")
+			if uiField.IsValid() && reflect.TypeOf(f.deps.UI).AssignableTo(uiField.Type()) {
+				uiField.Set(reflect.ValueOf(f.deps.UI))
+			}
+
+			cmdRunnerField := stype.FieldByName("CmdRunner")
+			if cmdRunnerField.IsValid() && reflect.TypeOf(f.deps.CmdRunner).AssignableTo(cmdRunnerField.Type()) {
+				cmdRunnerField.Set(reflect.ValueOf(f.deps.CmdRunner))
+			}
+
+			loggerField := stype.FieldByName("Logger")
+			if loggerField.IsValid() && reflect.TypeOf(f.deps.Logger).AssignableTo(loggerField.Type()) {
+				loggerField.Set(reflect.ValueOf(f.deps.Logger))
+			}
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"time"
 
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
@@ -18,6 +19,7 @@ import (
 var _ = Describe("Factory", func() {
 	var (
 		fs           *fakesys.FakeFileSystem
+		deps         BasicDeps
 		factory      Factory
 		fakeFilePath string
 	)
@@ -29,8 +31,9 @@ var _ = Describe("Factory", func() {
 		ui := boshui.NewConfUI(logger)
 		defer ui.Flush()
 
-		deps := NewBasicDeps(ui, logger)
+		deps = NewBasicDeps(ui, logger)
 		deps.FS = fs
+		deps.CmdRunner = fakesys.NewFakeCmdRunner()
 
 		factory = NewFactory(deps)
 		fakeFilePath = filepath.Join("/", "file")
@@ -192,6 +195,36 @@ var _ = Describe("Factory", func() {
 			_, _, err = cmd.Opts.(*LogsOpts).GatewayFlags.AsSSHOpts()
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("port-forward command has configured gateway flags", func() {
+			cmd, err := factory.New([]string{"port-forward", "group/0", "5432:5432"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = cmd.Opts.(*PortForwardOpts).GatewayFlags.AsSSHOpts()
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("ssh-session command", func() {
+		It("parses start subcommand with default TTL", func() {
+			cmd, err := factory.New([]string{"ssh-session", "start", "group/0"})
+			Expect(err).ToNot(HaveOccurred())
+
+			opts := cmd.Opts.(*SSHSessionStartOpts)
+			Expect(opts.Args.Slug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("group", "0")))
+			Expect(opts.TTL).To(Equal(time.Hour))
+		})
+
+		It("parses stop subcommand", func() {
+			cmd, err := factory.New([]string{"ssh-session", "stop"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cmd.Opts).To(BeAssignableToTypeOf(&SSHSessionStopOpts{}))
+		})
+
+		It("requires subcommand", func() {
+			_, err := factory.New([]string{"ssh-session"})
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Describe("deploy command", func() {
@@ -241,6 +274,22 @@ var _ = Describe("Factory", func() {
 		})
 	})
 
+	Describe("interpolate command (command that uses VarsSourceArg)", func() {
+		It("configures vars sources with command runner and logger", func() {
+			err := fs.WriteFileString(filepath.Join("/", "file"), "")
+			Expect(err).ToNot(HaveOccurred())
+
+			cmd, err := factory.New([]string{"interpolate", filepath.Join("/", "file"), "--vars-source", "plugin=exec:///plugin"})
+			Expect(err).ToNot(HaveOccurred())
+
+			opts := cmd.Opts.(*InterpolateOpts)
+			Expect(opts.VarsSources).To(HaveLen(1))
+			Expect(opts.VarsSources[0].CmdRunner).To(Equal(deps.CmdRunner))
+			Expect(opts.VarsSources[0].Logger).To(Equal(deps.Logger))
+			Expect(opts.VarsSources[0].Vars).To(Equal(NewExecVars(deps.CmdRunner, "/plugin", nil, deps.Logger)))
+		})
+	})
+
 	Describe("alias-env command", func() {
 		It("is passed global environment URL", func() {
 			cmd, err := factory.New([]string{"alias-env", "-e", "env", "alias"})
@@ -270,6 +319,28 @@ var _ = Describe("Factory", func() {
 		})
 	})
 
+	Describe("deploy command", func() {
+		It("is passed the global json flag", func() {
+			fs.WriteFileString("/manifest.yml", "")
+
+			cmd, err := factory.New([]string{"deploy", "--json", "/manifest.yml"})
+			Expect(err).ToNot(HaveOccurred())
+
+			opts := cmd.Opts.(*DeployOpts)
+			Expect(opts.JSON).To(BeTrue())
+		})
+	})
+
+	Describe("ssh command", func() {
+		It("is passed the global json flag", func() {
+			cmd, err := factory.New([]string{"ssh", "--json", "-c", "cmd"})
+			Expect(err).ToNot(HaveOccurred())
+
+			opts := cmd.Opts.(*SSHOpts)
+			Expect(opts.JSON).To(BeTrue())
+		})
+	})
+
 	Describe("vms command", func() {
 		It("is passed the deployment flag", func() {
 			cmd, err := factory.New([]string{"vms", "--deployment", "deployment"})
@@ -380,7 +451,13 @@ var _ = Describe("Factory", func() {
 			boshOpts.UploadBlobs = UploadBlobsOpts{}
 			boshOpts.SSH = SSHOpts{}
 			boshOpts.SCP = SCPOpts{}
+			boshOpts.PortForward = PortForwardOpts{}
+			boshOpts.SSHSession = SSHSessionOpts{}
+			boshOpts.SSHConfig = SSHConfigOpts{}
 			boshOpts.Deploy = DeployOpts{}
+			boshOpts.DiffDeployment = DiffDeploymentOpts{}
+			boshOpts.DiffManifests = DiffManifestsOpts{}
+			boshOpts.ValidateManifest = ValidateManifestOpts{}
 			boshOpts.UpdateRuntimeConfig = UpdateRuntimeConfigOpts{}
 			boshOpts.VMs = VMsOpts{}
 			boshOpts.Instances = InstancesOpts{}
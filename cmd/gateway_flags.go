@@ -1,19 +1,29 @@
 package cmd
 
 import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
 )
 
+var knownHostsPathInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9.\-]+`)
+
 func (f GatewayFlags) AsSSHOpts() (boshdir.SSHOpts, boshssh.ConnectionOpts, error) {
 	sshOpts, privKey, err := boshdir.NewSSHOpts(f.UUIDGen)
 	if err != nil {
 		return boshdir.SSHOpts{}, boshssh.ConnectionOpts{}, bosherr.WrapErrorf(err, "Generating SSH options")
 	}
 
-	connOpts := boshssh.ConnectionOpts{
+	return sshOpts, f.AsConnectionOpts(privKey), nil
+}
+
+func (f GatewayFlags) AsConnectionOpts(privKey string) boshssh.ConnectionOpts {
+	return boshssh.ConnectionOpts{
 		PrivateKey: privKey,
 
 		GatewayDisable: f.Disable,
@@ -23,7 +33,18 @@ func (f GatewayFlags) AsSSHOpts() (boshdir.SSHOpts, boshssh.ConnectionOpts, erro
 		GatewayPrivateKeyPath: f.PrivateKeyPath,
 
 		SOCKS5Proxy: f.SOCKS5Proxy,
+
+		KnownHostsPath:  f.KnownHostsPath,
+		TrustOnFirstUse: f.TrustOnFirstUse,
+
+		Native: f.Native,
 	}
+}
+
+// KnownHostsPath returns CLI managed known_hosts file used
+// for pinning instance host keys of a particular environment.
+func KnownHostsPath(environment string) string {
+	name := strings.Trim(knownHostsPathInvalidChars.ReplaceAllString(environment, "_"), "_")
 
-	return sshOpts, connOpts, nil
+	return filepath.Join("~", ".bosh", "known_hosts", name)
 }
@@ -0,0 +1,14 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("KnownHostsPath", func() {
+	It("returns known hosts path specific to the environment", func() {
+		Expect(KnownHostsPath("https://192.168.50.4:25555")).To(Equal("~/.bosh/known_hosts/https_192.168.50.4_25555"))
+	})
+})
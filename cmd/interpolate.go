@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/cppforlife/go-patch/patch"
 
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 type InterpolateCmd struct {
@@ -18,6 +22,10 @@ func NewInterpolateCmd(ui boshui.UI) InterpolateCmd {
 func (c InterpolateCmd) Run(opts InterpolateOpts) error {
 	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
 
+	if opts.Explain || opts.ExplainPath.IsSet() {
+		return c.explain(tpl, opts)
+	}
+
 	vars := opts.VarFlags.AsVariables()
 	op := opts.OpsFlags.AsOp()
 	evalOpts := boshtpl.EvaluateOpts{
@@ -41,3 +49,105 @@ func (c InterpolateCmd) Run(opts InterpolateOpts) error {
 
 	return nil
 }
+
+func (c InterpolateCmd) explain(tpl boshtpl.Template, opts InterpolateOpts) error {
+	varsExplanations, err := tpl.Explain(opts.VarFlags.AsNamedVariables(), opts.OpsFlags.AsOp())
+	if err != nil {
+		return err
+	}
+
+	varsTable := boshtbl.Table{
+		Content: "variables",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Variable"),
+			boshtbl.NewHeader("Source"),
+			boshtbl.NewHeader("Paths"),
+		},
+
+		SortBy: []boshtbl.ColumnSort{{Column: 0, Asc: true}},
+	}
+
+	for _, explanation := range varsExplanations {
+		var paths []string
+
+		for _, path := range explanation.Paths {
+			if c.pathMatches(path, opts.ExplainPath, false) {
+				paths = append(paths, path.String())
+			}
+		}
+
+		if opts.ExplainPath.IsSet() && len(paths) == 0 {
+			continue
+		}
+
+		source := boshtbl.NewValueFmt(boshtbl.NewValueString(explanation.Source), false)
+
+		if len(explanation.Source) == 0 {
+			source = boshtbl.NewValueFmt(boshtbl.NewValueString("(not found)"), true)
+		}
+
+		varsTable.Rows = append(varsTable.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(explanation.Name),
+			source,
+			boshtbl.NewValueStrings(paths),
+		})
+	}
+
+	opsTable := boshtbl.Table{
+		Content: "operations",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Path"),
+			boshtbl.NewHeader("Type"),
+			boshtbl.NewHeader("Ops File"),
+		},
+	}
+
+	for _, explanation := range opts.OpsFlags.Explain() {
+		if !c.pathMatches(explanation.Path, opts.ExplainPath, true) {
+			continue
+		}
+
+		opsTable.Rows = append(opsTable.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(explanation.Path.String()),
+			boshtbl.NewValueString(explanation.Type),
+			boshtbl.NewValueString(fmt.Sprintf("%s (op #%d)", explanation.OpsFile, explanation.Index)),
+		})
+	}
+
+	c.ui.PrintTable(varsTable)
+	c.ui.PrintTable(opsTable)
+
+	return nil
+}
+
+// pathMatches checks if path is at or below filter path;
+// ancestors of filter path also match when includeAncestors is true
+// since operations on ancestors replace everything below them.
+func (c InterpolateCmd) pathMatches(path, filter patch.Pointer, includeAncestors bool) bool {
+	if !filter.IsSet() {
+		return true
+	}
+
+	pathSegments := c.pathSegments(path)
+	filterSegments := c.pathSegments(filter)
+
+	for i := 0; i < len(pathSegments) && i < len(filterSegments); i++ {
+		if pathSegments[i] != filterSegments[i] {
+			return false
+		}
+	}
+
+	return len(pathSegments) >= len(filterSegments) || includeAncestors
+}
+
+func (InterpolateCmd) pathSegments(path patch.Pointer) []string {
+	segments := strings.Split(strings.TrimSuffix(path.String(), "/"), "/")
+
+	for i, segment := range segments {
+		segments[i] = strings.TrimSuffix(segment, "?")
+	}
+
+	return segments
+}
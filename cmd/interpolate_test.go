@@ -8,6 +8,7 @@ import (
 	. "github.com/cloudfoundry/bosh-cli/cmd"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
 	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 var _ = Describe("InterpolateCmd", func() {
@@ -148,5 +149,137 @@ var _ = Describe("InterpolateCmd", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Expected to use variables: name3"))
 		})
+
+		Context("when explaining", func() {
+			BeforeEach(func() {
+				opts.Args.Manifest = FileBytesArg{
+					Bytes: []byte(`
+instance_groups:
+- name: web
+  properties:
+    password: ((password))
+- name: db
+  properties:
+    password: ((password))
+    missing: ((missing))
+`),
+				}
+
+				opts.VarKVs = []boshtpl.VarKV{
+					{Name: "password", Value: "val-from-kv"},
+				}
+
+				opts.VarsFiles = []boshtpl.VarsFileArg{
+					{Path: "/vars.yml", Vars: boshtpl.StaticVariables{"password": "val-from-file", "port": 80}},
+				}
+
+				opts.OpsFiles = []OpsFileArg{
+					{
+						Path: "/ops.yml",
+						Ops: patch.Ops([]patch.Op{
+							patch.ReplaceOp{Path: patch.MustNewPointerFromString("/instance_groups/name=web/properties/port?"), Value: "((port))"},
+							patch.RemoveOp{Path: patch.MustNewPointerFromString("/instance_groups/name=db/properties/missing")},
+						}),
+					},
+				}
+
+				opts.Explain = true
+			})
+
+			It("shows sources of variables and operations instead of the manifest", func() {
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Blocks).To(BeEmpty())
+				Expect(ui.Tables).To(HaveLen(2))
+
+				Expect(ui.Tables[0].Content).To(Equal("variables"))
+				Expect(ui.Tables[0].Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("password"),
+						boshtbl.NewValueFmt(boshtbl.NewValueString("--var"), false),
+						boshtbl.NewValueStrings([]string{
+							"/instance_groups/name=db/properties/password",
+							"/instance_groups/name=web/properties/password",
+						}),
+					},
+					{
+						boshtbl.NewValueString("port"),
+						boshtbl.NewValueFmt(boshtbl.NewValueString("--vars-file /vars.yml"), false),
+						boshtbl.NewValueStrings([]string{"/instance_groups/name=web/properties/port"}),
+					},
+				}))
+
+				Expect(ui.Tables[1].Content).To(Equal("operations"))
+				Expect(ui.Tables[1].Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("/instance_groups/name=web/properties/port?"),
+						boshtbl.NewValueString("replace"),
+						boshtbl.NewValueString("/ops.yml (op #0)"),
+					},
+					{
+						boshtbl.NewValueString("/instance_groups/name=db/properties/missing"),
+						boshtbl.NewValueString("remove"),
+						boshtbl.NewValueString("/ops.yml (op #1)"),
+					},
+				}))
+			})
+
+			It("shows variables that are not found", func() {
+				opts.OpsFiles = nil
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Tables[0].Rows[0]).To(Equal([]boshtbl.Value{
+					boshtbl.NewValueString("missing"),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("(not found)"), true),
+					boshtbl.NewValueStrings([]string{"/instance_groups/name=db/properties/missing"}),
+				}))
+			})
+
+			It("only explains variables and operations at or below given path", func() {
+				opts.Explain = false
+				opts.ExplainPath = patch.MustNewPointerFromString("/instance_groups/name=web")
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Tables[0].Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("password"),
+						boshtbl.NewValueFmt(boshtbl.NewValueString("--var"), false),
+						boshtbl.NewValueStrings([]string{"/instance_groups/name=web/properties/password"}),
+					},
+					{
+						boshtbl.NewValueString("port"),
+						boshtbl.NewValueFmt(boshtbl.NewValueString("--vars-file /vars.yml"), false),
+						boshtbl.NewValueStrings([]string{"/instance_groups/name=web/properties/port"}),
+					},
+				}))
+
+				Expect(ui.Tables[1].Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("/instance_groups/name=web/properties/port?"),
+						boshtbl.NewValueString("replace"),
+						boshtbl.NewValueString("/ops.yml (op #0)"),
+					},
+				}))
+			})
+
+			It("includes operations on ancestors of given path", func() {
+				opts.ExplainPath = patch.MustNewPointerFromString("/instance_groups/name=db/properties/missing/nested")
+				opts.OpsFiles[0].Ops = append(opts.OpsFiles[0].Ops,
+					patch.ReplaceOp{Path: patch.MustNewPointerFromString("/instance_groups/name=db?"), Value: "val"})
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Tables[0].Rows).To(BeEmpty())
+				Expect(ui.Tables[1].Rows).To(HaveLen(2))
+				Expect(ui.Tables[1].Rows[0][0]).To(Equal(boshtbl.NewValueString("/instance_groups/name=db/properties/missing")))
+				Expect(ui.Tables[1].Rows[1][0]).To(Equal(boshtbl.NewValueString("/instance_groups/name=db?")))
+			})
+		})
 	})
 })
@@ -17,6 +17,8 @@ type LogsCmd struct {
 	downloader      Downloader
 	uuidGen         boshuuid.Generator
 	nonIntSSHRunner boshssh.Runner
+	logsFollower    LogsFollower
+	logsSearcher    LogsSearcher
 }
 
 func NewLogsCmd(
@@ -24,16 +26,37 @@ func NewLogsCmd(
 	downloader Downloader,
 	uuidGen boshuuid.Generator,
 	nonIntSSHRunner boshssh.Runner,
+	logsFollower LogsFollower,
+	logsSearcher LogsSearcher,
 ) LogsCmd {
 	return LogsCmd{
 		deployment:      deployment,
 		downloader:      downloader,
 		uuidGen:         uuidGen,
 		nonIntSSHRunner: nonIntSSHRunner,
+		logsFollower:    logsFollower,
+		logsSearcher:    logsSearcher,
 	}
 }
 
 func (c LogsCmd) Run(opts LogsOpts) error {
+	searching := len(opts.Grep) > 0 || opts.Extract
+
+	if (opts.Merge || !opts.Since.IsZero()) && len(opts.Grep) == 0 {
+		return bosherr.Error("Expected --since and --merge to be used together with --grep")
+	}
+	if opts.ViaDirector && !opts.Follow {
+		return bosherr.Error("Expected --via-director to be used together with --follow")
+	}
+	if searching && (opts.Follow || opts.Num > 0) {
+		return bosherr.Error("Expected --grep and --extract to not be used together with --follow or --num")
+	}
+	if searching {
+		return c.logsSearcher.Search(opts)
+	}
+	if opts.Follow && opts.ViaDirector {
+		return c.logsFollower.Follow(opts)
+	}
 	if opts.Follow || opts.Num > 0 {
 		return c.tail(opts)
 	}
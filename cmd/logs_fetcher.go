@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+// directorLogsFetcher fetches logs of individual instances via the Director
+// and extracts them in memory so that they do not have to be untarred by hand.
+type directorLogsFetcher struct {
+	deployment boshdir.Deployment
+	director   boshdir.Director
+}
+
+type logsInstance struct {
+	Group string
+	ID    string
+}
+
+func (i logsInstance) String() string { return i.Group + "/" + i.ID }
+
+func (f directorLogsFetcher) Instances(slug boshdir.AllOrInstanceGroupOrInstanceSlug) ([]logsInstance, error) {
+	infos, err := f.deployment.VMInfos()
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Listing instances")
+	}
+
+	var instances []logsInstance
+
+	for _, info := range infos {
+		if len(slug.Name()) > 0 && slug.Name() != info.JobName {
+			continue
+		}
+
+		if len(slug.IndexOrID()) > 0 {
+			matchesIndex := info.Index != nil && strconv.Itoa(*info.Index) == slug.IndexOrID()
+			if slug.IndexOrID() != info.ID && !matchesIndex {
+				continue
+			}
+		}
+
+		instances = append(instances, logsInstance{Group: info.JobName, ID: info.ID})
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].String() < instances[j].String()
+	})
+
+	return instances, nil
+}
+
+type logsFile struct {
+	Path     string
+	Contents []byte
+}
+
+func (f directorLogsFetcher) Fetch(inst logsInstance, opts LogsOpts) ([]logsFile, error) {
+	slug := boshdir.NewAllOrInstanceGroupOrInstanceSlug(inst.Group, inst.ID)
+
+	result, err := f.deployment.FetchLogs(slug, opts.Filters, opts.Agent)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	err = f.director.DownloadResourceUnchecked(result.BlobstoreID, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.extract(&buf, opts.Jobs)
+}
+
+func (f directorLogsFetcher) extract(reader io.Reader, jobs []string) ([]logsFile, error) {
+	gzReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading logs archive")
+	}
+
+	tarReader := tar.NewReader(gzReader)
+
+	var files []logsFile
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, bosherr.WrapError(err, "Reading logs archive")
+		}
+
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		filePath := strings.TrimPrefix(path.Clean(header.Name), "./")
+
+		if !f.matchesJobs(filePath, jobs) {
+			continue
+		}
+
+		contents, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Reading log file '%s'", filePath)
+		}
+
+		files = append(files, logsFile{Path: filePath, Contents: contents})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	return files, nil
+}
+
+func (f directorLogsFetcher) matchesJobs(filePath string, jobs []string) bool {
+	if len(jobs) == 0 {
+		return true
+	}
+
+	for _, job := range jobs {
+		if strings.HasPrefix(filePath, job+"/") {
+			return true
+		}
+	}
+
+	return false
+}
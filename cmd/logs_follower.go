@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	"code.cloudfoundry.org/clock"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+// Same as tail's default number of lines
+const logsFollowDefaultNum = 10
+
+//go:generate counterfeiter . LogsFollower
+
+type LogsFollower interface {
+	Follow(opts LogsOpts) error
+}
+
+// DirectorLogsFollower follows logs without SSH access by periodically
+// fetching logs of each matching instance via the Director and
+// printing lines that were appended since the previous fetch.
+type DirectorLogsFollower struct {
+	fetcher     directorLogsFetcher
+	timeService clock.Clock
+	ui          boshui.UI
+
+	// instance -> file path -> number of bytes already printed
+	offsets map[string]map[string]int
+}
+
+func NewDirectorLogsFollower(
+	deployment boshdir.Deployment,
+	director boshdir.Director,
+	timeService clock.Clock,
+	ui boshui.UI,
+) DirectorLogsFollower {
+	return DirectorLogsFollower{
+		fetcher:     directorLogsFetcher{deployment: deployment, director: director},
+		timeService: timeService,
+		ui:          ui,
+
+		offsets: map[string]map[string]int{},
+	}
+}
+
+func (f DirectorLogsFollower) Follow(opts LogsOpts) error {
+	for {
+		err := f.Poll(opts)
+		if err != nil {
+			return err
+		}
+
+		f.timeService.Sleep(opts.Interval)
+	}
+}
+
+// Poll fetches logs once from all matching instances. Instances that
+// were not seen before only print their last opts.Num lines (10 by default).
+func (f DirectorLogsFollower) Poll(opts LogsOpts) error {
+	instances, err := f.fetcher.Instances(opts.Args.Slug)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]struct{}{}
+
+	for _, inst := range instances {
+		seen[inst.String()] = struct{}{}
+
+		files, err := f.fetcher.Fetch(inst, opts)
+		if err != nil {
+			// Instance may be in the middle of an update; try again on next poll
+			f.ui.ErrorLinef("Failed to fetch logs from instance '%s': %s", inst, err)
+			continue
+		}
+
+		offsets, found := f.offsets[inst.String()]
+		if !found {
+			offsets = map[string]int{}
+			f.offsets[inst.String()] = offsets
+		}
+
+		for _, file := range files {
+			f.printFile(inst, file, offsets, !found, opts)
+		}
+	}
+
+	for name := range f.offsets {
+		if _, found := seen[name]; !found {
+			f.ui.PrintLinef("Instance '%s' is no longer present", name)
+			delete(f.offsets, name)
+		}
+	}
+
+	return nil
+}
+
+func (f DirectorLogsFollower) printFile(inst logsInstance, file logsFile, offsets map[string]int, newInst bool, opts LogsOpts) {
+	offset, found := offsets[file.Path]
+
+	if offset > len(file.Contents) {
+		// File was rotated or truncated since last fetch
+		offset = 0
+	}
+
+	// Only print complete lines; partial line will be printed on next poll
+	end := bytes.LastIndexByte(file.Contents, '\n') + 1
+	if end <= offset {
+		offsets[file.Path] = offset
+		return
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(file.Contents[offset:end]), "\n"), "\n")
+
+	if !found && newInst {
+		num := opts.Num
+		if num == 0 {
+			num = logsFollowDefaultNum
+		}
+
+		if num < len(lines) {
+			lines = lines[len(lines)-num:]
+		}
+	}
+
+	for _, line := range lines {
+		if opts.Quiet {
+			f.ui.PrintLinef("%s: %s", inst, line)
+		} else {
+			f.ui.PrintLinef("%s: %s: %s", inst, file.Path, line)
+		}
+	}
+
+	offsets[file.Path] = end
+}
@@ -0,0 +1,256 @@
+package cmd_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("DirectorLogsFollower", func() {
+	var (
+		deployment *fakedir.FakeDeployment
+		director   *fakedir.FakeDirector
+		ui         *fakeui.FakeUI
+		follower   DirectorLogsFollower
+
+		logs map[string]map[string]string
+	)
+
+	buildTarball := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+
+		gzWriter := gzip.NewWriter(&buf)
+		tarWriter := tar.NewWriter(gzWriter)
+
+		for name, contents := range files {
+			err := tarWriter.WriteHeader(&tar.Header{
+				Name:     "./" + name,
+				Mode:     0644,
+				Size:     int64(len(contents)),
+				Typeflag: tar.TypeReg,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = tarWriter.Write([]byte(contents))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(tarWriter.Close()).ToNot(HaveOccurred())
+		Expect(gzWriter.Close()).ToNot(HaveOccurred())
+
+		return buf.Bytes()
+	}
+
+	BeforeEach(func() {
+		index0 := 0
+		index1 := 1
+
+		deployment = &fakedir.FakeDeployment{}
+		deployment.VMInfosReturns([]boshdir.VMInfo{
+			{JobName: "router", ID: "router-id", Index: &index0},
+			{JobName: "db", ID: "db-id-1", Index: &index1},
+			{JobName: "db", ID: "db-id-0", Index: &index0},
+		}, nil)
+
+		logs = map[string]map[string]string{
+			"router/router-id": {"gorouter/gorouter.stdout.log": "r1\nr2\n"},
+			"db/db-id-0":       {"postgres/postgres.log": "p1\n", "syslog/syslog.log": "s1\n"},
+			"db/db-id-1":       {"postgres/postgres.log": "q1\n"},
+		}
+
+		deployment.FetchLogsStub = func(slug boshdir.AllOrInstanceGroupOrInstanceSlug, filters []string, agent bool) (boshdir.LogsResult, error) {
+			return boshdir.LogsResult{BlobstoreID: slug.String()}, nil
+		}
+
+		director = &fakedir.FakeDirector{}
+		director.DownloadResourceUncheckedStub = func(blobID string, out io.Writer) error {
+			files, found := logs[blobID]
+			if !found {
+				return errors.New("fake-download-err")
+			}
+			_, err := out.Write(buildTarball(files))
+			return err
+		}
+
+		ui = &fakeui.FakeUI{}
+		follower = NewDirectorLogsFollower(deployment, director, fakeclock.NewFakeClock(time.Now()), ui)
+	})
+
+	Describe("Poll", func() {
+		var (
+			opts LogsOpts
+		)
+
+		BeforeEach(func() {
+			opts = LogsOpts{Num: 10}
+		})
+
+		It("prints lines from all instances with per instance prefixes", func() {
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id-0: postgres/postgres.log: p1",
+				"db/db-id-0: syslog/syslog.log: s1",
+				"db/db-id-1: postgres/postgres.log: q1",
+				"router/router-id: gorouter/gorouter.stdout.log: r1",
+				"router/router-id: gorouter/gorouter.stdout.log: r2",
+			}))
+
+			Expect(deployment.FetchLogsCallCount()).To(Equal(3))
+
+			slug, filters, agent := deployment.FetchLogsArgsForCall(0)
+			Expect(slug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "db-id-0")))
+			Expect(filters).To(BeEmpty())
+			Expect(agent).To(BeFalse())
+		})
+
+		It("only prints lines appended since previous poll", func() {
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+
+			logs["router/router-id"]["gorouter/gorouter.stdout.log"] = "r1\nr2\nr3\npartial"
+			ui.Said = nil
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{
+				"router/router-id: gorouter/gorouter.stdout.log: r3",
+			}))
+
+			logs["router/router-id"]["gorouter/gorouter.stdout.log"] = "r1\nr2\nr3\npartial line\n"
+			ui.Said = nil
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{
+				"router/router-id: gorouter/gorouter.stdout.log: partial line",
+			}))
+		})
+
+		It("starts from the beginning of the file when it was rotated", func() {
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+
+			logs["router/router-id"]["gorouter/gorouter.stdout.log"] = "n1\n"
+			ui.Said = nil
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{
+				"router/router-id: gorouter/gorouter.stdout.log: n1",
+			}))
+		})
+
+		It("only prints last number of lines for newly seen instances", func() {
+			opts.Num = 1
+			opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("router", "")
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{
+				"router/router-id: gorouter/gorouter.stdout.log: r2",
+			}))
+		})
+
+		It("prints last 10 lines for newly seen instances by default", func() {
+			opts.Num = 0
+			opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("router", "")
+
+			var lines []string
+			for i := 1; i <= 12; i++ {
+				lines = append(lines, fmt.Sprintf("r%d", i))
+			}
+
+			logs["router/router-id"]["gorouter/gorouter.stdout.log"] = strings.Join(lines, "\n") + "\n"
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(HaveLen(10))
+			Expect(ui.Said[0]).To(Equal("router/router-id: gorouter/gorouter.stdout.log: r3"))
+			Expect(ui.Said[9]).To(Equal("router/router-id: gorouter/gorouter.stdout.log: r12"))
+		})
+
+		It("filters instances by instance group and index or ID", func() {
+			opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "1")
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id-1: postgres/postgres.log: q1",
+			}))
+
+			opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "db-id-0")
+			ui.Said = nil
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(ContainElement("db/db-id-0: postgres/postgres.log: p1"))
+			Expect(ui.Said).To(ContainElement("Instance 'db/db-id-1' is no longer present"))
+		})
+
+		It("filters files by jobs and passes filters to the Director", func() {
+			opts.Jobs = []string{"syslog"}
+			opts.Filters = []string{"syslog/*"}
+			opts.Agent = true
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id-0: syslog/syslog.log: s1",
+			}))
+
+			_, filters, agent := deployment.FetchLogsArgsForCall(0)
+			Expect(filters).To(Equal([]string{"syslog/*"}))
+			Expect(agent).To(BeTrue())
+		})
+
+		It("omits file names when quiet", func() {
+			opts.Quiet = true
+			opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "1")
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(Equal([]string{"db/db-id-1: q1"}))
+		})
+
+		It("picks up instances that join and notes instances that leave", func() {
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+
+			index0 := 0
+			deployment.VMInfosReturns([]boshdir.VMInfo{
+				{JobName: "router", ID: "router-id", Index: &index0},
+				{JobName: "web", ID: "web-id", Index: &index0},
+			}, nil)
+			logs["web/web-id"] = map[string]string{"nginx/access.log": "w1\n"}
+			ui.Said = nil
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Said).To(ConsistOf(
+				"web/web-id: nginx/access.log: w1",
+				"Instance 'db/db-id-0' is no longer present",
+				"Instance 'db/db-id-1' is no longer present",
+			))
+		})
+
+		It("continues with other instances if fetching logs fails", func() {
+			delete(logs, "db/db-id-0")
+
+			Expect(follower.Poll(opts)).ToNot(HaveOccurred())
+			Expect(ui.Errors).To(Equal([]string{
+				"Failed to fetch logs from instance 'db/db-id-0': fake-download-err",
+			}))
+			Expect(ui.Said).To(ContainElement("db/db-id-1: postgres/postgres.log: q1"))
+		})
+
+		It("returns an error if listing instances fails", func() {
+			deployment.VMInfosReturns(nil, errors.New("fake-err"))
+
+			err := follower.Poll(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+//go:generate counterfeiter . LogsSearcher
+
+type LogsSearcher interface {
+	Search(opts LogsOpts) error
+}
+
+// DirectorLogsSearcher fetches logs of each matching instance via the Director,
+// extracts them in memory and prints lines that match a regular expression.
+// Optionally logs are also unpacked into a per instance directory tree.
+type DirectorLogsSearcher struct {
+	fetcher directorLogsFetcher
+	fs      boshsys.FileSystem
+	ui      boshui.UI
+}
+
+func NewDirectorLogsSearcher(
+	deployment boshdir.Deployment,
+	director boshdir.Director,
+	fs boshsys.FileSystem,
+	ui boshui.UI,
+) DirectorLogsSearcher {
+	return DirectorLogsSearcher{
+		fetcher: directorLogsFetcher{deployment: deployment, director: director},
+		fs:      fs,
+		ui:      ui,
+	}
+}
+
+type logsLine struct {
+	Instance logsInstance
+	Path     string
+	Text     string
+
+	// Time of the line or of the closest preceding line that includes a timestamp
+	Time time.Time
+}
+
+func (s DirectorLogsSearcher) Search(opts LogsOpts) error {
+	var grepRegexp *regexp.Regexp
+
+	if len(opts.Grep) > 0 {
+		var err error
+
+		grepRegexp, err = regexp.Compile(opts.Grep)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Compiling regular expression '%s'", opts.Grep)
+		}
+	}
+
+	instances, err := s.fetcher.Instances(opts.Args.Slug)
+	if err != nil {
+		return err
+	}
+
+	var lines []logsLine
+
+	for _, inst := range instances {
+		files, err := s.fetcher.Fetch(inst, opts)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Fetching logs from instance '%s'", inst)
+		}
+
+		if opts.Extract {
+			err := s.extract(inst, files, opts.Directory.Path)
+			if err != nil {
+				return err
+			}
+		}
+
+		if grepRegexp != nil {
+			for _, file := range files {
+				lines = append(lines, s.matchingLines(inst, file, grepRegexp, opts.Since.Time)...)
+			}
+		}
+	}
+
+	if opts.Merge {
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	}
+
+	for _, line := range lines {
+		if opts.Quiet {
+			s.ui.PrintLinef("%s: %s", line.Instance, line.Text)
+		} else {
+			s.ui.PrintLinef("%s: %s: %s", line.Instance, line.Path, line.Text)
+		}
+	}
+
+	return nil
+}
+
+func (s DirectorLogsSearcher) extract(inst logsInstance, files []logsFile, dir string) error {
+	instDir := filepath.Join(dir, inst.Group, inst.ID)
+
+	for _, file := range files {
+		if path.IsAbs(file.Path) || strings.HasPrefix(file.Path, "../") {
+			return bosherr.Errorf("Expected log file path '%s' to be within logs directory", file.Path)
+		}
+
+		filePath := filepath.Join(instDir, filepath.FromSlash(file.Path))
+
+		err := s.fs.WriteFile(filePath, file.Contents)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Writing log file '%s'", filePath)
+		}
+	}
+
+	s.ui.PrintLinef("Extracted %d log file(s) from instance '%s' into '%s'", len(files), inst, instDir)
+
+	return nil
+}
+
+func (s DirectorLogsSearcher) matchingLines(inst logsInstance, file logsFile, grepRegexp *regexp.Regexp, since time.Time) []logsLine {
+	if len(file.Contents) == 0 {
+		return nil
+	}
+
+	var lines []logsLine
+	var lastTime time.Time
+
+	for _, text := range strings.Split(strings.TrimSuffix(string(file.Contents), "\n"), "\n") {
+		// Multi-line entries such as stack traces do not include timestamps on each line
+		if lineTime, found := logsLineTime(text); found {
+			lastTime = lineTime
+		}
+
+		if !since.IsZero() && !lastTime.IsZero() && lastTime.Before(since) {
+			continue
+		}
+
+		if grepRegexp.MatchString(text) {
+			lines = append(lines, logsLine{Instance: inst, Path: file.Path, Text: text, Time: lastTime})
+		}
+	}
+
+	return lines
+}
+
+var (
+	logsISO8601Regexp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	logsSlashedRegexp = regexp.MustCompile(`\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?`)
+	logsEpochRegexp   = regexp.MustCompile(`"timestamp":\s*"?(\d{10}(\.\d+)?)`)
+)
+
+// logsLineTime finds timestamp in commonly used formats: ISO 8601
+// (e.g. most job logs), 'yyyy/mm/dd hh:mm:ss' (e.g. Go log package)
+// and Unix epoch in JSON (e.g. lager logs). Timestamps without
+// time zone are assumed to be in UTC.
+func logsLineTime(text string) (time.Time, bool) {
+	if match := logsISO8601Regexp.FindString(text); len(match) > 0 {
+		match = strings.Replace(match, " ", "T", 1)
+
+		layouts := []string{
+			time.RFC3339Nano,
+			"2006-01-02T15:04:05.999999999Z0700",
+			"2006-01-02T15:04:05.999999999",
+		}
+
+		for _, layout := range layouts {
+			t, err := time.Parse(layout, match)
+			if err == nil {
+				return t, true
+			}
+		}
+	}
+
+	if match := logsSlashedRegexp.FindString(text); len(match) > 0 {
+		t, err := time.Parse("2006/01/02 15:04:05.999999999", match)
+		if err == nil {
+			return t, true
+		}
+	}
+
+	if matches := logsEpochRegexp.FindStringSubmatch(text); len(matches) > 1 {
+		epoch, err := strconv.ParseFloat(matches[1], 64)
+		if err == nil {
+			sec := int64(epoch)
+			return time.Unix(sec, int64((epoch-float64(sec))*1e9)).UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
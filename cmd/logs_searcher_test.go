@@ -0,0 +1,207 @@
+package cmd_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"path/filepath"
+	"time"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("DirectorLogsSearcher", func() {
+	var (
+		deployment *fakedir.FakeDeployment
+		director   *fakedir.FakeDirector
+		fs         *fakesys.FakeFileSystem
+		ui         *fakeui.FakeUI
+		searcher   DirectorLogsSearcher
+
+		logs map[string]map[string]string
+	)
+
+	buildTarball := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+
+		gzWriter := gzip.NewWriter(&buf)
+		tarWriter := tar.NewWriter(gzWriter)
+
+		for name, contents := range files {
+			err := tarWriter.WriteHeader(&tar.Header{
+				Name:     "./" + name,
+				Mode:     0644,
+				Size:     int64(len(contents)),
+				Typeflag: tar.TypeReg,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = tarWriter.Write([]byte(contents))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(tarWriter.Close()).ToNot(HaveOccurred())
+		Expect(gzWriter.Close()).ToNot(HaveOccurred())
+
+		return buf.Bytes()
+	}
+
+	BeforeEach(func() {
+		index0 := 0
+
+		deployment = &fakedir.FakeDeployment{}
+		deployment.VMInfosReturns([]boshdir.VMInfo{
+			{JobName: "router", ID: "router-id", Index: &index0},
+			{JobName: "db", ID: "db-id", Index: &index0},
+		}, nil)
+
+		logs = map[string]map[string]string{
+			"router/router-id": {
+				"gorouter/gorouter.log": `{"timestamp":"1483369440.5","message":"error: r1"}` + "\n" +
+					`{"timestamp":"1483369560.0","message":"ok: r2"}` + "\n",
+			},
+			"db/db-id": {
+				"postgres/postgres.log": "2017-01-02 15:05:00 UTC error: p1\n" +
+					"  at stack frame error\n" +
+					"2017-01-02T15:07:00Z error: p2\n",
+				"syslog/syslog.log": "2017/01/02 15:03:00 error: s1\n",
+			},
+		}
+
+		deployment.FetchLogsStub = func(slug boshdir.AllOrInstanceGroupOrInstanceSlug, filters []string, agent bool) (boshdir.LogsResult, error) {
+			return boshdir.LogsResult{BlobstoreID: slug.String()}, nil
+		}
+
+		director = &fakedir.FakeDirector{}
+		director.DownloadResourceUncheckedStub = func(blobID string, out io.Writer) error {
+			files, found := logs[blobID]
+			if !found {
+				return errors.New("fake-download-err")
+			}
+			_, err := out.Write(buildTarball(files))
+			return err
+		}
+
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		searcher = NewDirectorLogsSearcher(deployment, director, fs, ui)
+	})
+
+	Describe("Search", func() {
+		var (
+			opts LogsOpts
+		)
+
+		BeforeEach(func() {
+			opts = LogsOpts{Grep: "error"}
+		})
+
+		It("prints matching lines from all instances with per instance and file prefixes", func() {
+			Expect(searcher.Search(opts)).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id: postgres/postgres.log: 2017-01-02 15:05:00 UTC error: p1",
+				"db/db-id: postgres/postgres.log:   at stack frame error",
+				"db/db-id: postgres/postgres.log: 2017-01-02T15:07:00Z error: p2",
+				"db/db-id: syslog/syslog.log: 2017/01/02 15:03:00 error: s1",
+				`router/router-id: gorouter/gorouter.log: {"timestamp":"1483369440.5","message":"error: r1"}`,
+			}))
+
+			Expect(deployment.FetchLogsCallCount()).To(Equal(2))
+
+			slug, _, _ := deployment.FetchLogsArgsForCall(0)
+			Expect(slug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "db-id")))
+		})
+
+		It("merges matching lines chronologically", func() {
+			opts.Merge = true
+			opts.Quiet = true
+
+			Expect(searcher.Search(opts)).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id: 2017/01/02 15:03:00 error: s1",
+				`router/router-id: {"timestamp":"1483369440.5","message":"error: r1"}`,
+				"db/db-id: 2017-01-02 15:05:00 UTC error: p1",
+				"db/db-id:   at stack frame error",
+				"db/db-id: 2017-01-02T15:07:00Z error: p2",
+			}))
+		})
+
+		It("only prints lines logged after since timestamp", func() {
+			opts.Since = LogsSinceArg{Time: time.Date(2017, 1, 2, 15, 4, 30, 0, time.UTC)}
+
+			Expect(searcher.Search(opts)).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id: postgres/postgres.log: 2017-01-02 15:05:00 UTC error: p1",
+				"db/db-id: postgres/postgres.log:   at stack frame error",
+				"db/db-id: postgres/postgres.log: 2017-01-02T15:07:00Z error: p2",
+			}))
+		})
+
+		It("only searches logs of specified jobs", func() {
+			opts.Jobs = []string{"syslog"}
+
+			Expect(searcher.Search(opts)).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"db/db-id: syslog/syslog.log: 2017/01/02 15:03:00 error: s1",
+			}))
+		})
+
+		It("extracts logs into per instance directories", func() {
+			opts.Grep = ""
+			opts.Extract = true
+			opts.Directory = DirOrCWDArg{Path: "/fake-dir"}
+
+			Expect(searcher.Search(opts)).ToNot(HaveOccurred())
+
+			contents, err := fs.ReadFileString(filepath.Join("/fake-dir", "db", "db-id", "syslog", "syslog.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal("2017/01/02 15:03:00 error: s1\n"))
+
+			Expect(fs.FileExists(filepath.Join("/fake-dir", "router", "router-id", "gorouter", "gorouter.log"))).To(BeTrue())
+
+			Expect(ui.Said).To(Equal([]string{
+				"Extracted 2 log file(s) from instance 'db/db-id' into '/fake-dir/db/db-id'",
+				"Extracted 1 log file(s) from instance 'router/router-id' into '/fake-dir/router/router-id'",
+			}))
+		})
+
+		It("returns error if writing extracted log file fails", func() {
+			opts.Extract = true
+			fs.WriteFileError = errors.New("fake-err")
+
+			err := searcher.Search(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if regular expression is invalid", func() {
+			opts.Grep = "("
+
+			err := searcher.Search(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Compiling regular expression '('"))
+		})
+
+		It("returns error if fetching logs fails", func() {
+			delete(logs, "router/router-id")
+
+			err := searcher.Search(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Fetching logs from instance 'router/router-id'"))
+			Expect(err.Error()).To(ContainSubstring("fake-download-err"))
+		})
+	})
+})
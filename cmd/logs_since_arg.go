@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// LogsSinceArg is a point in time (e.g. '2017-01-02T15:04:05Z' or '2017-01-02').
+type LogsSinceArg struct {
+	time.Time
+}
+
+var logsSinceArgLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func (a *LogsSinceArg) UnmarshalFlag(data string) error {
+	for _, layout := range logsSinceArgLayouts {
+		t, err := time.Parse(layout, data)
+		if err == nil {
+			a.Time = t
+			return nil
+		}
+	}
+
+	return bosherr.Errorf("Expected since '%s' to be a timestamp (e.g. '2017-01-02T15:04:05Z')", data)
+}
@@ -0,0 +1,46 @@
+package cmd_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("LogsSinceArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			arg *LogsSinceArg
+		)
+
+		BeforeEach(func() {
+			arg = &LogsSinceArg{}
+		})
+
+		It("sets time from RFC3339 timestamp", func() {
+			err := arg.UnmarshalFlag("2017-01-02T15:04:05+01:00")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Time.Equal(time.Date(2017, 1, 2, 14, 4, 5, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("sets time in UTC from timestamp without time zone", func() {
+			err := arg.UnmarshalFlag("2017-01-02 15:04:05")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Time).To(Equal(time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)))
+		})
+
+		It("sets time to the start of the day from date", func() {
+			err := arg.UnmarshalFlag("2017-01-02")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Time).To(Equal(time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("returns error if timestamp cannot be parsed", func() {
+			err := arg.UnmarshalFlag("yesterday")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected since 'yesterday' to be a timestamp (e.g. '2017-01-02T15:04:05Z')"))
+		})
+	})
+})
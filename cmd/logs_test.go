@@ -24,6 +24,8 @@ var _ = Describe("LogsCmd", func() {
 		downloader      *fakecmd.FakeDownloader
 		uuidGen         *fakeuuid.FakeGenerator
 		nonIntSSHRunner *fakessh.FakeRunner
+		logsFollower    *fakecmd.FakeLogsFollower
+		logsSearcher    *fakecmd.FakeLogsSearcher
 		command         LogsCmd
 	)
 
@@ -34,7 +36,9 @@ var _ = Describe("LogsCmd", func() {
 		downloader = &fakecmd.FakeDownloader{}
 		uuidGen = &fakeuuid.FakeGenerator{}
 		nonIntSSHRunner = &fakessh.FakeRunner{}
-		command = NewLogsCmd(deployment, downloader, uuidGen, nonIntSSHRunner)
+		logsFollower = &fakecmd.FakeLogsFollower{}
+		logsSearcher = &fakecmd.FakeLogsSearcher{}
+		command = NewLogsCmd(deployment, downloader, uuidGen, nonIntSSHRunner, logsFollower, logsSearcher)
 	})
 
 	Describe("Run", func() {
@@ -139,6 +143,98 @@ var _ = Describe("LogsCmd", func() {
 			})
 		})
 
+		Context("when following logs via the Director", func() {
+			BeforeEach(func() {
+				opts.Follow = true
+				opts.ViaDirector = true
+			})
+
+			It("follows logs without setting up SSH access", func() {
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(logsFollower.FollowCallCount()).To(Equal(1))
+				Expect(logsFollower.FollowArgsForCall(0)).To(Equal(opts))
+
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+				Expect(nonIntSSHRunner.RunCallCount()).To(Equal(0))
+			})
+
+			It("returns an error if following fails", func() {
+				logsFollower.FollowReturns(errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+			})
+
+			It("returns an error without tailing logs over SSH if --follow is not used", func() {
+				opts.Follow = false
+				opts.Num = 10
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected --via-director to be used together with --follow"))
+
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+				Expect(nonIntSSHRunner.RunCallCount()).To(Equal(0))
+				Expect(logsFollower.FollowCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when searching logs", func() {
+			BeforeEach(func() {
+				opts.Grep = "error"
+			})
+
+			It("searches logs without downloading tarball or setting up SSH access", func() {
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(logsSearcher.SearchCallCount()).To(Equal(1))
+				Expect(logsSearcher.SearchArgsForCall(0)).To(Equal(opts))
+
+				Expect(deployment.FetchLogsCallCount()).To(Equal(0))
+				Expect(downloader.DownloadCallCount()).To(Equal(0))
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+			})
+
+			It("extracts logs without searching if only extract is specified", func() {
+				opts.Grep = ""
+				opts.Extract = true
+
+				Expect(act()).ToNot(HaveOccurred())
+				Expect(logsSearcher.SearchCallCount()).To(Equal(1))
+				Expect(downloader.DownloadCallCount()).To(Equal(0))
+			})
+
+			It("returns an error if searching fails", func() {
+				logsSearcher.SearchReturns(errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+			})
+
+			It("returns an error if following is requested", func() {
+				opts.Follow = true
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected --grep and --extract to not be used together with --follow or --num"))
+				Expect(logsSearcher.SearchCallCount()).To(Equal(0))
+			})
+
+			It("returns an error if merging is requested without grep", func() {
+				opts.Grep = ""
+				opts.Extract = true
+				opts.Merge = true
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected --since and --merge to be used together with --grep"))
+				Expect(logsSearcher.SearchCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when tailing logs (or specifying number of lines)", func() {
 
 			BeforeEach(func() {
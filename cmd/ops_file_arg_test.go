@@ -34,6 +34,7 @@ var _ = Describe("OpsFileArg", func() {
 			err := (&arg).UnmarshalFlag("/some/path")
 			Expect(err).ToNot(HaveOccurred())
 
+			Expect(arg.Path).To(Equal("/some/path"))
 			Expect(arg.Ops).To(Equal(patch.Ops{
 				patch.RemoveOp{Path: patch.MustNewPointerFromString("/a")},
 				patch.RemoveOp{Path: patch.MustNewPointerFromString("/b")},
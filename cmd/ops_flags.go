@@ -18,3 +18,40 @@ func (f OpsFlags) AsOp() patch.Op {
 
 	return ops
 }
+
+// OpExplanation describes where an operation came from and what path it touches.
+type OpExplanation struct {
+	OpsFile string
+	Index   int
+	Type    string
+	Path    patch.Pointer
+}
+
+func (f OpsFlags) Explain() []OpExplanation {
+	var explanations []OpExplanation
+
+	for _, opsFile := range f.OpsFiles {
+		for i, op := range opsFile.Ops {
+			explanation := OpExplanation{OpsFile: opsFile.Path, Index: i}
+
+			if descriptiveOp, ok := op.(patch.DescriptiveOp); ok {
+				op = descriptiveOp.Op
+			}
+
+			switch typedOp := op.(type) {
+			case patch.ReplaceOp:
+				explanation.Type = "replace"
+				explanation.Path = typedOp.Path
+			case patch.RemoveOp:
+				explanation.Type = "remove"
+				explanation.Path = typedOp.Path
+			default:
+				continue
+			}
+
+			explanations = append(explanations, explanation)
+		}
+	}
+
+	return explanations
+}
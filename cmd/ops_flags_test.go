@@ -34,4 +34,35 @@ var _ = Describe("OpsFlags", func() {
 			}))
 		})
 	})
+
+	Describe("Explain", func() {
+		It("returns paths touched by each operation with their ops files", func() {
+			flags := OpsFlags{
+				OpsFiles: []OpsFileArg{
+					{
+						Path: "/ops1.yml",
+						Ops: patch.Ops([]patch.Op{
+							patch.RemoveOp{Path: patch.MustNewPointerFromString("/a")},
+							patch.DescriptiveOp{
+								Op:       patch.ReplaceOp{Path: patch.MustNewPointerFromString("/b?"), Value: "val"},
+								ErrorMsg: "msg",
+							},
+						}),
+					},
+					{
+						Path: "/ops2.yml",
+						Ops: patch.Ops([]patch.Op{
+							patch.ReplaceOp{Path: patch.MustNewPointerFromString("/x"), Value: "val"},
+						}),
+					},
+				},
+			}
+
+			Expect(flags.Explain()).To(Equal([]OpExplanation{
+				{OpsFile: "/ops1.yml", Index: 0, Type: "remove", Path: patch.MustNewPointerFromString("/a")},
+				{OpsFile: "/ops1.yml", Index: 1, Type: "replace", Path: patch.MustNewPointerFromString("/b?")},
+				{OpsFile: "/ops2.yml", Index: 0, Type: "replace", Path: patch.MustNewPointerFromString("/x")},
+			}))
+		})
+	})
 })
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"time"
+
 	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
 	"github.com/cppforlife/go-patch/patch"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	boshrel "github.com/cloudfoundry/bosh-cli/release"
+	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
 )
 
 type BoshOpts struct {
@@ -88,7 +91,15 @@ type BoshOpts struct {
 	Deploy   DeployOpts   `command:"deploy"   alias:"d"   description:"Update deployment"`
 	Manifest ManifestOpts `command:"manifest" alias:"man" description:"Show deployment manifest"`
 
+	CheckManifest      CheckManifestOpts      `command:"check-manifest"      description:"Check deployment manifest against policy rules"`
+	ValidateManifest   ValidateManifestOpts   `command:"validate-manifest"   description:"Check manifest structure against built-in and custom schemas"`
+	ValidateProperties ValidatePropertiesOpts `command:"validate-properties" description:"Check job properties in manifest against job specs of releases"`
+	DiffDeployment     DiffDeploymentOpts     `command:"diff-deployment"     description:"Show differences between given and deployed manifests"`
+	DiffManifests      DiffManifestsOpts      `command:"diff-manifests"      description:"Show differences between two manifests without contacting the Director"`
+
 	Interpolate InterpolateOpts `command:"interpolate" alias:"int" description:"Interpolates variables into a manifest"`
+	VarsStore   VarsStoreOpts   `command:"vars-store"               description:"Manage encryption and certificates of vars store files"`
+	TestOps     TestOpsOpts     `command:"test-ops"                 description:"Check that ops files and their combinations apply to a base manifest"`
 
 	// Events
 	Events EventsOpts `command:"events" description:"List events"`
@@ -149,6 +160,10 @@ type BoshOpts struct {
 	SSH SSHOpts `command:"ssh" description:"SSH into instance(s)"`
 	SCP SCPOpts `command:"scp" description:"SCP to/from instance(s)"`
 
+	PortForward PortForwardOpts `command:"port-forward" description:"Forward local ports to ports on an instance"`
+	SSHSession  SSHSessionOpts  `command:"ssh-session"  description:"Set up SSH access once for reuse by ssh, scp and port-forward"`
+	SSHConfig   SSHConfigOpts   `command:"ssh-config"   description:"Generate ssh_config for accessing instances with standard SSH tooling"`
+
 	// -----> Release authoring
 
 	// Release creation
@@ -158,6 +173,7 @@ type BoshOpts struct {
 	GeneratePackage GeneratePackageOpts `command:"generate-package"            description:"Generate package"`
 	CreateRelease   CreateReleaseOpts   `command:"create-release"   alias:"cr" description:"Create release"`
 	VendorPackage   VendorPackageOpts   `command:"vendor-package"              description:"Vendor package"`
+	RenderJob       RenderJobOpts       `command:"render-job"                  description:"Render job templates locally for a synthetic instance"`
 
 	// Hidden
 	Sha1ifyRelease  Sha1ifyReleaseOpts  `command:"sha1ify-release"  hidden:"true" description:"Convert release tarball to use SHA1"`
@@ -188,6 +204,8 @@ type CreateEnvOpts struct {
 	StatePath               string `long:"state" value-name:"PATH" description:"State file path"`
 	Recreate                bool   `long:"recreate" description:"Recreate VM in deployment"`
 	RecreatePersistentDisks bool   `long:"recreate-persistent-disks" description:"Recreate persistent disks in the deployment"`
+	NativeERB               bool   `long:"native-erb" description:"Render job templates without Ruby, falling back to Ruby for unsupported templates"`
+	CacheRenderedTemplates  bool   `long:"cache-rendered-templates" description:"Keep rendered job templates in installation directory to skip rendering unchanged templates (includes credentials)"`
 	cmd
 }
 
@@ -307,6 +325,9 @@ type InterpolateOpts struct {
 	VarErrors       bool          `long:"var-errs"                  description:"Expect all variables to be found, otherwise error"`
 	VarErrorsUnused bool          `long:"var-errs-unused"           description:"Expect all variables to be used, otherwise error"`
 
+	Explain     bool          `long:"explain"                           description:"Show which sources supplied variables and which ops files touched paths instead of the result"`
+	ExplainPath patch.Pointer `long:"explain-path" value-name:"OP-PATH" description:"Explain only variables and operations at path (e.g.: /instance_groups/name=web)"`
+
 	cmd
 }
 
@@ -314,6 +335,76 @@ type InterpolateArgs struct {
 	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a template that will be interpolated"`
 }
 
+type TestOpsOpts struct {
+	Base   FileBytesArg `long:"base"    value-name:"PATH" description:"Path to a base manifest" required:"true"`
+	OpsDir DirOrCWDArg  `long:"ops-dir" value-name:"DIR"  description:"Directory with ops files to test" required:"true"`
+	Matrix FileBytesArg `long:"matrix"  value-name:"PATH" description:"Path to a file with combinations of ops files and expected values"`
+
+	VarFlags
+
+	cmd
+}
+
+type VarsStoreOpts struct {
+	Encrypt VarsStoreEncryptOpts `command:"encrypt" description:"Encrypt vars store file"`
+	Decrypt VarsStoreDecryptOpts `command:"decrypt" description:"Decrypt vars store file"`
+	Rekey   VarsStoreRekeyOpts   `command:"rekey"   description:"Re-encrypt vars store file with a new key or passphrase"`
+	Check   VarsStoreCheckOpts   `command:"check"   description:"Show expiry, CA chain and alternative names of certificates in vars store file"`
+	Rotate  VarsStoreRotateOpts  `command:"rotate"  description:"Regenerate certificates in vars store file"`
+}
+
+type VarsStoreEncryptOpts struct {
+	Args VarsStoreArgs `positional-args:"true" required:"true"`
+
+	KeyFile string `long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`
+
+	cmd
+}
+
+type VarsStoreDecryptOpts struct {
+	Args VarsStoreArgs `positional-args:"true" required:"true"`
+
+	KeyFile string `long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`
+
+	cmd
+}
+
+type VarsStoreRekeyOpts struct {
+	Args VarsStoreArgs `positional-args:"true" required:"true"`
+
+	KeyFile    string `long:"key-file"     value-name:"PATH" description:"Path to file with current key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`
+	NewKeyFile string `long:"new-key-file" value-name:"PATH" description:"Path to file with new key (default: BOSH_VARS_STORE_NEW_KEY, BOSH_VARS_STORE_NEW_KEY_FILE or BOSH_VARS_STORE_NEW_PASSPHRASE)"`
+
+	cmd
+}
+
+type VarsStoreArgs struct {
+	Path FileArg `positional-arg-name:"PATH" description:"Path to vars store file"`
+}
+
+type VarsStoreCheckOpts struct {
+	Args VarsStoreVarsArgs `positional-args:"true" required:"true"`
+
+	KeyFile string `long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`
+
+	cmd
+}
+
+type VarsStoreRotateOpts struct {
+	Args VarsStoreVarsArgs `positional-args:"true" required:"true"`
+
+	KeyFile        string      `long:"key-file"        value-name:"PATH"     description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`
+	Names          []string    `long:"name"            value-name:"NAME"     description:"Rotate certificate variable (certificates issued by it are rotated as well)"`
+	ExpiringWithin DurationArg `long:"expiring-within" value-name:"DURATION" description:"Rotate certificates expiring within duration (e.g. '30d')"`
+	KeepOldCA      bool        `long:"keep-old-ca"                           description:"Keep old CA certificate next to new one in 'ca' values of rotated certificates for a transition period"`
+
+	cmd
+}
+
+type VarsStoreVarsArgs struct {
+	VarsStore VarsFSStore `positional-arg-name:"PATH" description:"Path to vars store file"`
+}
+
 // Config
 
 type ConfigOpts struct {
@@ -380,6 +471,11 @@ type UpdateCloudConfigOpts struct {
 	Args UpdateCloudConfigArgs `positional-args:"true" required:"true"`
 	VarFlags
 	OpsFlags
+
+	Schema       SchemaFileArg `long:"schema"        value-name:"PATH" description:"Additionally check cloud config against schema from a file"`
+	StrictSchema bool          `long:"strict-schema"                   description:"Fail if cloud config does not match built-in schema"`
+	SkipSchema   bool          `long:"skip-schema"                     description:"Skip checking cloud config against built-in schema"`
+
 	cmd
 }
 
@@ -420,6 +516,10 @@ type UpdateRuntimeConfigOpts struct {
 	NoRedact bool   `long:"no-redact" description:"Show non-redacted manifest diff"`
 	Name     string `long:"name" description:"Runtime-Config name (default: '')" default:""`
 
+	Schema       SchemaFileArg `long:"schema"        value-name:"PATH" description:"Additionally check runtime config against schema from a file"`
+	StrictSchema bool          `long:"strict-schema"                   description:"Fail if runtime config does not match built-in schema"`
+	SkipSchema   bool          `long:"skip-schema"                     description:"Skip checking runtime config against built-in schema"`
+
 	cmd
 }
 
@@ -450,11 +550,21 @@ type DeployOpts struct {
 	Fix                     bool                `long:"fix"                                     description:"Recreate an instance with an unresponsive agent instead of erroring"`
 	SkipDrain               []boshdir.SkipDrain `long:"skip-drain" value-name:"INSTANCE-GROUP"  description:"Skip running drain scripts for specific instance groups" optional:"true" optional-value:"*"`
 
-	Canaries    string `long:"canaries" description:"Override manifest values for canaries"`
-	MaxInFlight string `long:"max-in-flight" description:"Override manifest values for max_in_flight"`
+	Canaries    UpdateOverrideArgs `long:"canaries"      value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`
+	MaxInFlight UpdateOverrideArgs `long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`
 
 	DryRun bool `long:"dry-run" description:"Renders job templates without altering deployment"`
 
+	Policy PolicyDirArg `long:"policy" value-name:"DIR" description:"Check manifest against policy rules from a directory before deploying"`
+
+	Schema       SchemaFileArg `long:"schema"        value-name:"PATH" description:"Additionally check manifest against schema from a file before deploying"`
+	StrictSchema bool          `long:"strict-schema"                   description:"Fail if manifest does not match built-in schema"`
+	SkipSchema   bool          `long:"skip-schema"                     description:"Skip checking manifest against built-in schema"`
+
+	ValidateProperties bool `long:"validate-properties" description:"Check job properties against job specs of releases with local paths before deploying"`
+
+	JSON bool
+
 	cmd
 }
 
@@ -462,6 +572,85 @@ type DeployArgs struct {
 	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a manifest file"`
 }
 
+type CheckManifestOpts struct {
+	Args CheckManifestArgs `positional-args:"true" required:"true"`
+
+	VarFlags
+	OpsFlags
+
+	Policy PolicyDirArg `long:"policy" value-name:"DIR" description:"Directory with policy rules" required:"true"`
+
+	cmd
+}
+
+type CheckManifestArgs struct {
+	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a manifest file"`
+}
+
+type ValidateManifestOpts struct {
+	Args ValidateManifestArgs `positional-args:"true" required:"true"`
+
+	VarFlags
+	OpsFlags
+
+	Type         string        `long:"type"          value-name:"TYPE" description:"Manifest type (deployment, cloud, runtime or cpi)" default:"deployment"`
+	Schema       SchemaFileArg `long:"schema"        value-name:"PATH" description:"Additionally check manifest against schema from a file"`
+	StrictSchema bool          `long:"strict-schema"                   description:"Fail if manifest has keys that are not known to built-in schema"`
+
+	cmd
+}
+
+type ValidateManifestArgs struct {
+	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a manifest file"`
+}
+
+type ValidatePropertiesOpts struct {
+	Args ValidatePropertiesArgs `positional-args:"true" required:"true"`
+
+	VarFlags
+	OpsFlags
+
+	Releases []string `long:"release" value-name:"PATH" description:"Path to a release tarball or release directory (multiple)" required:"true"`
+
+	cmd
+}
+
+type ValidatePropertiesArgs struct {
+	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a manifest file"`
+}
+
+type DiffDeploymentOpts struct {
+	Args DiffDeploymentArgs `positional-args:"true" required:"true"`
+
+	VarFlags
+	OpsFlags
+
+	NoRedact bool   `long:"no-redact"                   description:"Show non-redacted manifest diff"`
+	Format   string `long:"format" value-name:"FORMAT" description:"Diff format: text, json or yaml" default:"text"`
+
+	cmd
+}
+
+type DiffDeploymentArgs struct {
+	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a manifest file"`
+}
+
+type DiffManifestsOpts struct {
+	Args DiffManifestsArgs `positional-args:"true" required:"true"`
+
+	VarFlags
+	OpsFlags
+
+	Format string `long:"format" value-name:"FORMAT" description:"Diff format: text, json or yaml" default:"text"`
+
+	cmd
+}
+
+type DiffManifestsArgs struct {
+	OldManifest FileBytesArg `positional-arg-name:"OLD-PATH" description:"Path to an old manifest file"`
+	NewManifest FileBytesArg `positional-arg-name:"NEW-PATH" description:"Path to a new manifest file"`
+}
+
 type ManifestOpts struct {
 	cmd
 }
@@ -791,6 +980,14 @@ type LogsOpts struct {
 	Num    int  `long:"num"              description:"Last number of lines"`
 	Quiet  bool `long:"quiet"  short:"q" description:"Suppresses printing of headers when multiple files are being examined"`
 
+	ViaDirector bool          `long:"via-director"                 description:"Follow logs by periodically fetching them via the Director instead of SSH (requires --follow)"`
+	Interval    time.Duration `long:"interval" value-name:"DURATION" description:"Interval between fetches when following via the Director" default:"5s"`
+
+	Grep    string       `long:"grep"    value-name:"REGEX"     description:"Print lines matching regular expression instead of downloading logs"`
+	Since   LogsSinceArg `long:"since"   value-name:"TIMESTAMP" description:"Print only matching lines logged after timestamp"`
+	Merge   bool         `long:"merge"                          description:"Merge matching lines of all instances chronologically"`
+	Extract bool         `long:"extract"                        description:"Unpack logs into per instance directories instead of downloading tarball"`
+
 	Jobs    []string `long:"job"   description:"Limit to only specific jobs"`
 	Filters []string `long:"only"  description:"Filter logs (comma-separated)"`
 	Agent   bool     `long:"agent" description:"Include only agent logs"`
@@ -805,8 +1002,8 @@ type StartOpts struct {
 
 	Force bool `long:"force" description:"No-op for backwards compatibility"`
 
-	Canaries    string `long:"canaries" description:"Override manifest values for canaries"`
-	MaxInFlight string `long:"max-in-flight" description:"Override manifest values for max_in_flight"`
+	Canaries    UpdateOverrideArgs `long:"canaries"      value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`
+	MaxInFlight UpdateOverrideArgs `long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`
 
 	cmd
 }
@@ -820,8 +1017,8 @@ type StopOpts struct {
 	SkipDrain bool `long:"skip-drain" description:"Skip running drain scripts"`
 	Force     bool `long:"force"      description:"No-op for backwards compatibility"`
 
-	Canaries    string `long:"canaries" description:"Override manifest values for canaries"`
-	MaxInFlight string `long:"max-in-flight" description:"Override manifest values for max_in_flight"`
+	Canaries    UpdateOverrideArgs `long:"canaries"      value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`
+	MaxInFlight UpdateOverrideArgs `long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`
 
 	cmd
 }
@@ -832,8 +1029,8 @@ type RestartOpts struct {
 	SkipDrain bool `long:"skip-drain" description:"Skip running drain scripts"`
 	Force     bool `long:"force"      description:"No-op for backwards compatibility"`
 
-	Canaries    string `long:"canaries" description:"Override manifest values for canaries"`
-	MaxInFlight string `long:"max-in-flight" description:"Override manifest values for max_in_flight"`
+	Canaries    UpdateOverrideArgs `long:"canaries"      value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`
+	MaxInFlight UpdateOverrideArgs `long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`
 
 	cmd
 }
@@ -845,8 +1042,8 @@ type RecreateOpts struct {
 	Force     bool `long:"force"      description:"No-op for backwards compatibility"`
 	Fix       bool `long:"fix"        description:"Recreate an instance with an unresponsive agent instead of erroring"`
 
-	Canaries    string `long:"canaries" description:"Override manifest values for canaries"`
-	MaxInFlight string `long:"max-in-flight" description:"Override manifest values for max_in_flight"`
+	Canaries    UpdateOverrideArgs `long:"canaries"      value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`
+	MaxInFlight UpdateOverrideArgs `long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`
 
 	DryRun bool `long:"dry-run" description:"Renders job templates without altering deployment"`
 
@@ -867,8 +1064,19 @@ type SSHOpts struct {
 
 	Results bool `long:"results" short:"r" description:"Collect results into a table instead of streaming"`
 
+	MaxInFlight int           `long:"max-in-flight" description:"Number of instances to run command on at a time (default: all)"`
+	FailFast    bool          `long:"fail-fast"     description:"Do not run command on remaining instances after a failure"`
+	Timeout     time.Duration `long:"timeout"       description:"Time limit for running command on each instance" value-name:"DURATION"`
+
+	Record string `long:"record" value-name:"DIR" description:"Record sessions in asciicast format into directory (default: environment's ssh_record_dir config)"`
+
 	GatewayFlags
 
+	JSON bool
+
+	// Details about environment included into recordings
+	Recording boshssh.RecordingOpts
+
 	cmd
 }
 
@@ -877,6 +1085,9 @@ type SCPOpts struct {
 
 	Recursive bool `long:"recursive" short:"r" description:"Recursively copy entire directories. Note that symbolic links encountered are followed in the tree traversal"`
 
+	MaxInFlight int  `long:"max-in-flight" description:"Number of instances to copy files to or from at a time (default: all)"`
+	Sync        bool `long:"sync" description:"Skip files with matching checksums and verify copied files (requires --native-ssh)"`
+
 	GatewayFlags
 
 	cmd
@@ -886,9 +1097,53 @@ type SCPArgs struct {
 	Paths []string `positional-arg-name:"PATH"`
 }
 
+type SSHSessionOpts struct {
+	Start SSHSessionStartOpts `command:"start" description:"Set up SSH access to instances"`
+	Stop  SSHSessionStopOpts  `command:"stop"  description:"Clean up SSH access set up by ssh-session start"`
+}
+
+type SSHSessionStartOpts struct {
+	Args AllOrInstanceGroupOrInstanceSlugArgs `positional-args:"true"`
+
+	TTL time.Duration `long:"ttl" value-name:"DURATION" description:"Time after which SSH session expires" default:"1h"`
+
+	cmd
+}
+
+type SSHSessionStopOpts struct {
+	cmd
+}
+
+type SSHConfigOpts struct {
+	Args AllOrInstanceGroupOrInstanceSlugArgs `positional-args:"true"`
+
+	Directory DirOrCWDArg   `long:"dir"                       description:"Directory for ssh_config, identity and known hosts files" default:"."`
+	TTL       time.Duration `long:"ttl" value-name:"DURATION" description:"Time after which SSH session expires if it has to be started" default:"24h"`
+
+	GatewayFlags
+
+	cmd
+}
+
+type PortForwardOpts struct {
+	Args PortForwardArgs `positional-args:"true" required:"true"`
+
+	GatewayFlags
+
+	cmd
+}
+
+type PortForwardArgs struct {
+	Slug  boshdir.AllOrInstanceGroupOrInstanceSlug `positional-arg-name:"INSTANCE-GROUP[/INSTANCE-ID]"`
+	Ports []PortForwardArg                         `positional-arg-name:"LOCAL-PORT:REMOTE-PORT"`
+}
+
 type GatewayFlags struct {
 	UUIDGen boshuuid.Generator
 
+	// CLI managed known_hosts file for pinning host keys of current environment
+	KnownHostsPath string
+
 	Disable bool `long:"gw-disable" description:"Disable usage of gateway connection" env:"BOSH_GW_DISABLE"`
 
 	Username       string `long:"gw-user"        description:"Username for gateway connection" env:"BOSH_GW_USER"`
@@ -896,6 +1151,10 @@ type GatewayFlags struct {
 	PrivateKeyPath string `long:"gw-private-key" description:"Private key path for gateway connection" env:"BOSH_GW_PRIVATE_KEY"` // todo private file?
 
 	SOCKS5Proxy string `long:"gw-socks5" description:"SOCKS5 URL" env:"BOSH_ALL_PROXY"`
+
+	Native bool `long:"native-ssh" description:"Use built-in SSH client instead of ssh and scp binaries" env:"BOSH_NATIVE_SSH"`
+
+	TrustOnFirstUse bool `long:"trust-on-first-use" description:"Pin host keys of instances on first connection if Director does not provide them" env:"BOSH_SSH_TRUST_ON_FIRST_USE"`
 }
 
 // Release creation
@@ -951,6 +1210,21 @@ type VendorPackageArgs struct {
 	URL         DirOrCWDArg `positional-arg-name:"SRC-DIR" default:"."`
 }
 
+type RenderJobOpts struct {
+	Release string `long:"release" value-name:"PATH" description:"Path to a release tarball or release directory" required:"true"`
+	Job     string `long:"job"     value-name:"NAME" description:"Name of a job to render"                         required:"true"`
+
+	Properties FileBytesArg `long:"properties" value-name:"PATH" description:"Path to a YAML file with job properties"`
+	Links      FileBytesArg `long:"links"      value-name:"PATH" description:"Path to a YAML file with stubs of consumed links"`
+	Instance   FileBytesArg `long:"instance"   value-name:"PATH" description:"Path to a YAML file with instance fields (deployment, index, id, az, bootstrap, address, networks)"`
+
+	Output DirOrCWDArg `long:"output" short:"o" value-name:"DIR" description:"Directory to write rendered templates to" required:"true"`
+
+	NativeERB bool `long:"native-erb" description:"Render job templates without Ruby, falling back to Ruby for unsupported templates"`
+
+	cmd
+}
+
 type Sha1ifyReleaseOpts struct {
 	Args RedigestReleaseArgs `positional-args:"true"`
 
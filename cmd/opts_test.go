@@ -304,6 +304,22 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("VarsStore", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("VarsStore", opts)).To(Equal(
+					`command:"vars-store" description:"Manage encryption and certificates of vars store files"`,
+				))
+			})
+		})
+
+		Describe("TestOps", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("TestOps", opts)).To(Equal(
+					`command:"test-ops" description:"Check that ops files and their combinations apply to a base manifest"`,
+				))
+			})
+		})
+
 		Describe("Config", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Config", opts)).To(Equal(
@@ -432,6 +448,54 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("CheckManifest", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("CheckManifest", opts)).To(Equal(
+					`command:"check-manifest" description:"Check deployment manifest against policy rules"`,
+				))
+			})
+		})
+
+		Describe("ValidateManifest", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ValidateManifest", opts)).To(Equal(
+					`command:"validate-manifest" description:"Check manifest structure against built-in and custom schemas"`,
+				))
+			})
+		})
+
+		Describe("ValidateProperties", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ValidateProperties", opts)).To(Equal(
+					`command:"validate-properties" description:"Check job properties in manifest against job specs of releases"`,
+				))
+			})
+		})
+
+		Describe("RenderJob", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("RenderJob", opts)).To(Equal(
+					`command:"render-job" description:"Render job templates locally for a synthetic instance"`,
+				))
+			})
+		})
+
+		Describe("DiffDeployment", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DiffDeployment", opts)).To(Equal(
+					`command:"diff-deployment" description:"Show differences between given and deployed manifests"`,
+				))
+			})
+		})
+
+		Describe("DiffManifests", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DiffManifests", opts)).To(Equal(
+					`command:"diff-manifests" description:"Show differences between two manifests without contacting the Director"`,
+				))
+			})
+		})
+
 		Describe("Stemcells", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Stemcells", opts)).To(Equal(
@@ -688,6 +752,30 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("PortForward", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("PortForward", opts)).To(Equal(
+					`command:"port-forward" description:"Forward local ports to ports on an instance"`,
+				))
+			})
+		})
+
+		Describe("SSHSession", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SSHSession", opts)).To(Equal(
+					`command:"ssh-session" description:"Set up SSH access once for reuse by ssh, scp and port-forward"`,
+				))
+			})
+		})
+
+		Describe("SSHConfig", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SSHConfig", opts)).To(Equal(
+					`command:"ssh-config" description:"Generate ssh_config for accessing instances with standard SSH tooling"`,
+				))
+			})
+		})
+
 		Describe("InitRelease", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("InitRelease", opts)).To(Equal(
@@ -832,6 +920,18 @@ var _ = Describe("Opts", func() {
 			))
 		})
 
+		It("has --native-erb", func() {
+			Expect(getStructTagForName("NativeERB", opts)).To(Equal(
+				`long:"native-erb" description:"Render job templates without Ruby, falling back to Ruby for unsupported templates"`,
+			))
+		})
+
+		It("has --cache-rendered-templates", func() {
+			Expect(getStructTagForName("CacheRenderedTemplates", opts)).To(Equal(
+				`long:"cache-rendered-templates" description:"Keep rendered job templates in installation directory to skip rendering unchanged templates (includes credentials)"`,
+			))
+		})
+
 		It("has --skip-drain", func() {
 			Expect(getStructTagForName("SkipDrain", opts)).To(Equal(
 				`long:"skip-drain" description:"Skip running drain scripts"`,
@@ -1283,6 +1383,18 @@ var _ = Describe("Opts", func() {
 				`long:"var-errs-unused" description:"Expect all variables to be used, otherwise error"`,
 			))
 		})
+
+		It("has Explain", func() {
+			Expect(getStructTagForName("Explain", &opts)).To(Equal(
+				`long:"explain" description:"Show which sources supplied variables and which ops files touched paths instead of the result"`,
+			))
+		})
+
+		It("has ExplainPath", func() {
+			Expect(getStructTagForName("ExplainPath", &opts)).To(Equal(
+				`long:"explain-path" value-name:"OP-PATH" description:"Explain only variables and operations at path (e.g.: /instance_groups/name=web)"`,
+			))
+		})
 	})
 
 	Describe("InterpolateArgs", func() {
@@ -1301,6 +1413,254 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("VarsStoreOpts", func() {
+		var opts *VarsStoreOpts
+
+		BeforeEach(func() {
+			opts = &VarsStoreOpts{}
+		})
+
+		Describe("Encrypt", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Encrypt", opts)).To(Equal(
+					`command:"encrypt" description:"Encrypt vars store file"`,
+				))
+			})
+		})
+
+		Describe("Decrypt", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Decrypt", opts)).To(Equal(
+					`command:"decrypt" description:"Decrypt vars store file"`,
+				))
+			})
+		})
+
+		Describe("Rekey", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Rekey", opts)).To(Equal(
+					`command:"rekey" description:"Re-encrypt vars store file with a new key or passphrase"`,
+				))
+			})
+		})
+
+		Describe("Check", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Check", opts)).To(Equal(
+					`command:"check" description:"Show expiry, CA chain and alternative names of certificates in vars store file"`,
+				))
+			})
+		})
+
+		Describe("Rotate", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Rotate", opts)).To(Equal(
+					`command:"rotate" description:"Regenerate certificates in vars store file"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreEncryptOpts", func() {
+		var opts *VarsStoreEncryptOpts
+
+		BeforeEach(func() {
+			opts = &VarsStoreEncryptOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(
+					`positional-args:"true" required:"true"`,
+				))
+			})
+		})
+
+		Describe("KeyFile", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("KeyFile", opts)).To(Equal(
+					`long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreDecryptOpts", func() {
+		var opts *VarsStoreDecryptOpts
+
+		BeforeEach(func() {
+			opts = &VarsStoreDecryptOpts{}
+		})
+
+		Describe("KeyFile", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("KeyFile", opts)).To(Equal(
+					`long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreRekeyOpts", func() {
+		var opts *VarsStoreRekeyOpts
+
+		BeforeEach(func() {
+			opts = &VarsStoreRekeyOpts{}
+		})
+
+		Describe("KeyFile", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("KeyFile", opts)).To(Equal(
+					`long:"key-file" value-name:"PATH" description:"Path to file with current key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`,
+				))
+			})
+		})
+
+		Describe("NewKeyFile", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("NewKeyFile", opts)).To(Equal(
+					`long:"new-key-file" value-name:"PATH" description:"Path to file with new key (default: BOSH_VARS_STORE_NEW_KEY, BOSH_VARS_STORE_NEW_KEY_FILE or BOSH_VARS_STORE_NEW_PASSPHRASE)"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreCheckOpts", func() {
+		var opts *VarsStoreCheckOpts
+
+		BeforeEach(func() {
+			opts = &VarsStoreCheckOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(
+					`positional-args:"true" required:"true"`,
+				))
+			})
+		})
+
+		Describe("KeyFile", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("KeyFile", opts)).To(Equal(
+					`long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreRotateOpts", func() {
+		var opts *VarsStoreRotateOpts
+
+		BeforeEach(func() {
+			opts = &VarsStoreRotateOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(
+					`positional-args:"true" required:"true"`,
+				))
+			})
+		})
+
+		Describe("KeyFile", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("KeyFile", opts)).To(Equal(
+					`long:"key-file" value-name:"PATH" description:"Path to file with base64 encoded 32 byte key (default: BOSH_VARS_STORE_KEY, BOSH_VARS_STORE_KEY_FILE or BOSH_VARS_STORE_PASSPHRASE)"`,
+				))
+			})
+		})
+
+		Describe("Names", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Names", opts)).To(Equal(
+					`long:"name" value-name:"NAME" description:"Rotate certificate variable (certificates issued by it are rotated as well)"`,
+				))
+			})
+		})
+
+		Describe("ExpiringWithin", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ExpiringWithin", opts)).To(Equal(
+					`long:"expiring-within" value-name:"DURATION" description:"Rotate certificates expiring within duration (e.g. '30d')"`,
+				))
+			})
+		})
+
+		Describe("KeepOldCA", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("KeepOldCA", opts)).To(Equal(
+					`long:"keep-old-ca" description:"Keep old CA certificate next to new one in 'ca' values of rotated certificates for a transition period"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreVarsArgs", func() {
+		var opts *VarsStoreVarsArgs
+
+		BeforeEach(func() {
+			opts = &VarsStoreVarsArgs{}
+		})
+
+		Describe("VarsStore", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("VarsStore", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to vars store file"`,
+				))
+			})
+		})
+	})
+
+	Describe("VarsStoreArgs", func() {
+		var opts *VarsStoreArgs
+
+		BeforeEach(func() {
+			opts = &VarsStoreArgs{}
+		})
+
+		Describe("Path", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Path", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to vars store file"`,
+				))
+			})
+		})
+	})
+
+	Describe("TestOpsOpts", func() {
+		var opts *TestOpsOpts
+
+		BeforeEach(func() {
+			opts = &TestOpsOpts{}
+		})
+
+		Describe("Base", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Base", opts)).To(Equal(
+					`long:"base" value-name:"PATH" description:"Path to a base manifest" required:"true"`,
+				))
+			})
+		})
+
+		Describe("OpsDir", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("OpsDir", opts)).To(Equal(
+					`long:"ops-dir" value-name:"DIR" description:"Directory with ops files to test" required:"true"`,
+				))
+			})
+		})
+
+		Describe("Matrix", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Matrix", opts)).To(Equal(
+					`long:"matrix" value-name:"PATH" description:"Path to a file with combinations of ops files and expected values"`,
+				))
+			})
+		})
+	})
+
 	Describe("UpdateCloudConfigOpts", func() {
 		var opts *UpdateCloudConfigOpts
 
@@ -1313,6 +1673,30 @@ var _ = Describe("Opts", func() {
 				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
 			})
 		})
+
+		Describe("Schema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Schema", opts)).To(Equal(
+					`long:"schema" value-name:"PATH" description:"Additionally check cloud config against schema from a file"`,
+				))
+			})
+		})
+
+		Describe("StrictSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("StrictSchema", opts)).To(Equal(
+					`long:"strict-schema" description:"Fail if cloud config does not match built-in schema"`,
+				))
+			})
+		})
+
+		Describe("SkipSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SkipSchema", opts)).To(Equal(
+					`long:"skip-schema" description:"Skip checking cloud config against built-in schema"`,
+				))
+			})
+		})
 	})
 
 	Describe("UpdateCloudConfigArgs", func() {
@@ -1366,32 +1750,264 @@ var _ = Describe("Opts", func() {
 
 		Describe("NoRedact", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("NoRedact", opts)).To(Equal(`long:"no-redact" description:"Show non-redacted manifest diff"`))
+				Expect(getStructTagForName("NoRedact", opts)).To(Equal(`long:"no-redact" description:"Show non-redacted manifest diff"`))
+			})
+		})
+
+		Describe("Schema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Schema", opts)).To(Equal(
+					`long:"schema" value-name:"PATH" description:"Additionally check runtime config against schema from a file"`,
+				))
+			})
+		})
+
+		Describe("StrictSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("StrictSchema", opts)).To(Equal(
+					`long:"strict-schema" description:"Fail if runtime config does not match built-in schema"`,
+				))
+			})
+		})
+
+		Describe("SkipSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SkipSchema", opts)).To(Equal(
+					`long:"skip-schema" description:"Skip checking runtime config against built-in schema"`,
+				))
+			})
+		})
+	})
+
+	Describe("UpdateRuntimeConfigArgs", func() {
+		var opts *UpdateRuntimeConfigArgs
+
+		BeforeEach(func() {
+			opts = &UpdateRuntimeConfigArgs{}
+		})
+
+		Describe("RuntimeConfig", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("RuntimeConfig", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to a runtime config file"`,
+				))
+			})
+		})
+	})
+
+	Describe("DeployOpts", func() {
+		var opts *DeployOpts
+
+		BeforeEach(func() {
+			opts = &DeployOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("Recreate", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Recreate", opts)).To(Equal(
+					`long:"recreate" description:"Recreate all VMs in deployment"`,
+				))
+			})
+		})
+
+		Describe("RecreatePersistentDisks", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("RecreatePersistentDisks", opts)).To(Equal(
+					`long:"recreate-persistent-disks" description:"Recreate all persistent disks in deployment"`,
+				))
+			})
+		})
+
+		Describe("NoRedact", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("NoRedact", opts)).To(Equal(
+					`long:"no-redact" description:"Show non-redacted manifest diff"`,
+				))
+			})
+		})
+
+		Describe("SkipDrain", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SkipDrain", opts)).To(Equal(
+					`long:"skip-drain" value-name:"INSTANCE-GROUP" description:"Skip running drain scripts for specific instance groups" optional:"true" optional-value:"*"`,
+				))
+			})
+		})
+
+		Describe("Canaries", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Canaries", opts)).To(Equal(
+					`long:"canaries" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`,
+				))
+			})
+		})
+
+		Describe("MaxInFlight", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
+					`long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`,
+				))
+			})
+		})
+
+		Describe("DryRun", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DryRun", opts)).To(Equal(
+					`long:"dry-run" description:"Renders job templates without altering deployment"`,
+				))
+			})
+		})
+
+		Describe("Policy", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Policy", opts)).To(Equal(
+					`long:"policy" value-name:"DIR" description:"Check manifest against policy rules from a directory before deploying"`,
+				))
+			})
+		})
+
+		Describe("Schema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Schema", opts)).To(Equal(
+					`long:"schema" value-name:"PATH" description:"Additionally check manifest against schema from a file before deploying"`,
+				))
+			})
+		})
+
+		Describe("StrictSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("StrictSchema", opts)).To(Equal(
+					`long:"strict-schema" description:"Fail if manifest does not match built-in schema"`,
+				))
+			})
+		})
+
+		Describe("SkipSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SkipSchema", opts)).To(Equal(
+					`long:"skip-schema" description:"Skip checking manifest against built-in schema"`,
+				))
+			})
+		})
+
+		Describe("ValidateProperties", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ValidateProperties", opts)).To(Equal(
+					`long:"validate-properties" description:"Check job properties against job specs of releases with local paths before deploying"`,
+				))
+			})
+		})
+	})
+
+	Describe("DiffDeploymentOpts", func() {
+		var opts *DiffDeploymentOpts
+
+		BeforeEach(func() {
+			opts = &DiffDeploymentOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("NoRedact", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("NoRedact", opts)).To(Equal(
+					`long:"no-redact" description:"Show non-redacted manifest diff"`,
+				))
+			})
+		})
+
+		Describe("Format", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Format", opts)).To(Equal(
+					`long:"format" value-name:"FORMAT" description:"Diff format: text, json or yaml" default:"text"`,
+				))
+			})
+		})
+	})
+
+	Describe("DiffManifestsOpts", func() {
+		var opts *DiffManifestsOpts
+
+		BeforeEach(func() {
+			opts = &DiffManifestsOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("Format", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Format", opts)).To(Equal(
+					`long:"format" value-name:"FORMAT" description:"Diff format: text, json or yaml" default:"text"`,
+				))
+			})
+		})
+	})
+
+	Describe("DiffManifestsArgs", func() {
+		var opts *DiffManifestsArgs
+
+		BeforeEach(func() {
+			opts = &DiffManifestsArgs{}
+		})
+
+		Describe("OldManifest", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("OldManifest", opts)).To(Equal(
+					`positional-arg-name:"OLD-PATH" description:"Path to an old manifest file"`,
+				))
+			})
+		})
+
+		Describe("NewManifest", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("NewManifest", opts)).To(Equal(
+					`positional-arg-name:"NEW-PATH" description:"Path to a new manifest file"`,
+				))
 			})
 		})
 	})
 
-	Describe("UpdateRuntimeConfigArgs", func() {
-		var opts *UpdateRuntimeConfigArgs
+	Describe("CheckManifestOpts", func() {
+		var opts *CheckManifestOpts
 
 		BeforeEach(func() {
-			opts = &UpdateRuntimeConfigArgs{}
+			opts = &CheckManifestOpts{}
 		})
 
-		Describe("RuntimeConfig", func() {
+		Describe("Args", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("RuntimeConfig", opts)).To(Equal(
-					`positional-arg-name:"PATH" description:"Path to a runtime config file"`,
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("Policy", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Policy", opts)).To(Equal(
+					`long:"policy" value-name:"DIR" description:"Directory with policy rules" required:"true"`,
 				))
 			})
 		})
 	})
 
-	Describe("DeployOpts", func() {
-		var opts *DeployOpts
+	Describe("ValidateManifestOpts", func() {
+		var opts *ValidateManifestOpts
 
 		BeforeEach(func() {
-			opts = &DeployOpts{}
+			opts = &ValidateManifestOpts{}
 		})
 
 		Describe("Args", func() {
@@ -1400,58 +2016,80 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
-		Describe("Recreate", func() {
+		Describe("Type", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("Recreate", opts)).To(Equal(
-					`long:"recreate" description:"Recreate all VMs in deployment"`,
+				Expect(getStructTagForName("Type", opts)).To(Equal(
+					`long:"type" value-name:"TYPE" description:"Manifest type (deployment, cloud, runtime or cpi)" default:"deployment"`,
 				))
 			})
 		})
 
-		Describe("RecreatePersistentDisks", func() {
+		Describe("Schema", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("RecreatePersistentDisks", opts)).To(Equal(
-					`long:"recreate-persistent-disks" description:"Recreate all persistent disks in deployment"`,
+				Expect(getStructTagForName("Schema", opts)).To(Equal(
+					`long:"schema" value-name:"PATH" description:"Additionally check manifest against schema from a file"`,
 				))
 			})
 		})
 
-		Describe("NoRedact", func() {
+		Describe("StrictSchema", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("NoRedact", opts)).To(Equal(
-					`long:"no-redact" description:"Show non-redacted manifest diff"`,
+				Expect(getStructTagForName("StrictSchema", opts)).To(Equal(
+					`long:"strict-schema" description:"Fail if manifest has keys that are not known to built-in schema"`,
 				))
 			})
 		})
+	})
 
-		Describe("SkipDrain", func() {
+	Describe("ValidateManifestArgs", func() {
+		var opts *ValidateManifestArgs
+
+		BeforeEach(func() {
+			opts = &ValidateManifestArgs{}
+		})
+
+		Describe("Manifest", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("SkipDrain", opts)).To(Equal(
-					`long:"skip-drain" value-name:"INSTANCE-GROUP" description:"Skip running drain scripts for specific instance groups" optional:"true" optional-value:"*"`,
+				Expect(getStructTagForName("Manifest", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to a manifest file"`,
 				))
 			})
 		})
+	})
 
-		Describe("Canaries", func() {
+	Describe("ValidatePropertiesOpts", func() {
+		var opts *ValidatePropertiesOpts
+
+		BeforeEach(func() {
+			opts = &ValidatePropertiesOpts{}
+		})
+
+		Describe("Args", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("Canaries", opts)).To(Equal(
-					`long:"canaries" description:"Override manifest values for canaries"`,
-				))
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
 			})
 		})
 
-		Describe("MaxInFlight", func() {
+		Describe("Releases", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
-					`long:"max-in-flight" description:"Override manifest values for max_in_flight"`,
+				Expect(getStructTagForName("Releases", opts)).To(Equal(
+					`long:"release" value-name:"PATH" description:"Path to a release tarball or release directory (multiple)" required:"true"`,
 				))
 			})
 		})
+	})
 
-		Describe("DryRun", func() {
+	Describe("ValidatePropertiesArgs", func() {
+		var opts *ValidatePropertiesArgs
+
+		BeforeEach(func() {
+			opts = &ValidatePropertiesArgs{}
+		})
+
+		Describe("Manifest", func() {
 			It("contains desired values", func() {
-				Expect(getStructTagForName("DryRun", opts)).To(Equal(
-					`long:"dry-run" description:"Renders job templates without altering deployment"`,
+				Expect(getStructTagForName("Manifest", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to a manifest file"`,
 				))
 			})
 		})
@@ -2345,6 +2983,22 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("ViaDirector", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ViaDirector", opts)).To(Equal(
+					`long:"via-director" description:"Follow logs by periodically fetching them via the Director instead of SSH (requires --follow)"`,
+				))
+			})
+		})
+
+		Describe("Interval", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Interval", opts)).To(Equal(
+					`long:"interval" value-name:"DURATION" description:"Interval between fetches when following via the Director" default:"5s"`,
+				))
+			})
+		})
+
 		Describe("Jobs", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Jobs", opts)).To(Equal(
@@ -2368,6 +3022,38 @@ var _ = Describe("Opts", func() {
 				))
 			})
 		})
+
+		Describe("Grep", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Grep", opts)).To(Equal(
+					`long:"grep" value-name:"REGEX" description:"Print lines matching regular expression instead of downloading logs"`,
+				))
+			})
+		})
+
+		Describe("Since", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Since", opts)).To(Equal(
+					`long:"since" value-name:"TIMESTAMP" description:"Print only matching lines logged after timestamp"`,
+				))
+			})
+		})
+
+		Describe("Merge", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Merge", opts)).To(Equal(
+					`long:"merge" description:"Merge matching lines of all instances chronologically"`,
+				))
+			})
+		})
+
+		Describe("Extract", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Extract", opts)).To(Equal(
+					`long:"extract" description:"Unpack logs into per instance directories instead of downloading tarball"`,
+				))
+			})
+		})
 	})
 
 	Describe("StartOpts", func() {
@@ -2394,7 +3080,7 @@ var _ = Describe("Opts", func() {
 		Describe("Canaries", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Canaries", opts)).To(Equal(
-					`long:"canaries" description:"Override manifest values for canaries"`,
+					`long:"canaries" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2402,7 +3088,7 @@ var _ = Describe("Opts", func() {
 		Describe("MaxInFlight", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
-					`long:"max-in-flight" description:"Override manifest values for max_in_flight"`,
+					`long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2456,7 +3142,7 @@ var _ = Describe("Opts", func() {
 		Describe("Canaries", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Canaries", opts)).To(Equal(
-					`long:"canaries" description:"Override manifest values for canaries"`,
+					`long:"canaries" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2464,7 +3150,7 @@ var _ = Describe("Opts", func() {
 		Describe("MaxInFlight", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
-					`long:"max-in-flight" description:"Override manifest values for max_in_flight"`,
+					`long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2502,7 +3188,7 @@ var _ = Describe("Opts", func() {
 		Describe("Canaries", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Canaries", opts)).To(Equal(
-					`long:"canaries" description:"Override manifest values for canaries"`,
+					`long:"canaries" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2510,7 +3196,7 @@ var _ = Describe("Opts", func() {
 		Describe("MaxInFlight", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
-					`long:"max-in-flight" description:"Override manifest values for max_in_flight"`,
+					`long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2548,7 +3234,7 @@ var _ = Describe("Opts", func() {
 		Describe("Canaries", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Canaries", opts)).To(Equal(
-					`long:"canaries" description:"Override manifest values for canaries"`,
+					`long:"canaries" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for canaries (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2556,7 +3242,7 @@ var _ = Describe("Opts", func() {
 		Describe("MaxInFlight", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
-					`long:"max-in-flight" description:"Override manifest values for max_in_flight"`,
+					`long:"max-in-flight" value-name:"[INSTANCE-GROUP=]VALUE" description:"Override manifest values for max_in_flight (optionally per instance group)"`,
 				))
 			})
 		})
@@ -2614,6 +3300,38 @@ var _ = Describe("Opts", func() {
 				))
 			})
 		})
+
+		Describe("MaxInFlight", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
+					`long:"max-in-flight" description:"Number of instances to run command on at a time (default: all)"`,
+				))
+			})
+		})
+
+		Describe("FailFast", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("FailFast", opts)).To(Equal(
+					`long:"fail-fast" description:"Do not run command on remaining instances after a failure"`,
+				))
+			})
+		})
+
+		Describe("Timeout", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Timeout", opts)).To(Equal(
+					`long:"timeout" description:"Time limit for running command on each instance" value-name:"DURATION"`,
+				))
+			})
+		})
+
+		Describe("Record", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Record", opts)).To(Equal(
+					`long:"record" value-name:"DIR" description:"Record sessions in asciicast format into directory (default: environment's ssh_record_dir config)"`,
+				))
+			})
+		})
 	})
 
 	Describe("SCPOpts", func() {
@@ -2636,6 +3354,22 @@ var _ = Describe("Opts", func() {
 				))
 			})
 		})
+
+		Describe("MaxInFlight", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("MaxInFlight", opts)).To(Equal(
+					`long:"max-in-flight" description:"Number of instances to copy files to or from at a time (default: all)"`,
+				))
+			})
+		})
+
+		Describe("Sync", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Sync", opts)).To(Equal(
+					`long:"sync" description:"Skip files with matching checksums and verify copied files (requires --native-ssh)"`,
+				))
+			})
+		})
 	})
 
 	Describe("SCPArgs", func() {
@@ -2652,6 +3386,116 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("SSHSessionOpts", func() {
+		var opts *SSHSessionOpts
+
+		BeforeEach(func() {
+			opts = &SSHSessionOpts{}
+		})
+
+		Describe("Start", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Start", opts)).To(Equal(
+					`command:"start" description:"Set up SSH access to instances"`,
+				))
+			})
+		})
+
+		Describe("Stop", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Stop", opts)).To(Equal(
+					`command:"stop" description:"Clean up SSH access set up by ssh-session start"`,
+				))
+			})
+		})
+	})
+
+	Describe("SSHSessionStartOpts", func() {
+		var opts *SSHSessionStartOpts
+
+		BeforeEach(func() {
+			opts = &SSHSessionStartOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true"`))
+			})
+		})
+
+		Describe("TTL", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("TTL", opts)).To(Equal(
+					`long:"ttl" value-name:"DURATION" description:"Time after which SSH session expires" default:"1h"`,
+				))
+			})
+		})
+	})
+
+	Describe("SSHConfigOpts", func() {
+		var opts *SSHConfigOpts
+
+		BeforeEach(func() {
+			opts = &SSHConfigOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true"`))
+			})
+		})
+
+		Describe("Directory", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Directory", opts)).To(Equal(
+					`long:"dir" description:"Directory for ssh_config, identity and known hosts files" default:"."`,
+				))
+			})
+		})
+
+		Describe("TTL", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("TTL", opts)).To(Equal(
+					`long:"ttl" value-name:"DURATION" description:"Time after which SSH session expires if it has to be started" default:"24h"`,
+				))
+			})
+		})
+	})
+
+	Describe("PortForwardOpts", func() {
+		var opts *PortForwardOpts
+
+		BeforeEach(func() {
+			opts = &PortForwardOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+	})
+
+	Describe("PortForwardArgs", func() {
+		var opts *PortForwardArgs
+
+		BeforeEach(func() {
+			opts = &PortForwardArgs{}
+		})
+
+		Describe("Slug", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Slug", opts)).To(Equal(`positional-arg-name:"INSTANCE-GROUP[/INSTANCE-ID]"`))
+			})
+		})
+
+		Describe("Ports", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Ports", opts)).To(Equal(`positional-arg-name:"LOCAL-PORT:REMOTE-PORT"`))
+			})
+		})
+	})
+
 	Describe("GatewayFlags", func() {
 		var opts *GatewayFlags
 
@@ -2688,6 +3532,18 @@ var _ = Describe("Opts", func() {
 				`long:"gw-socks5" description:"SOCKS5 URL" env:"BOSH_ALL_PROXY"`,
 			))
 		})
+
+		It("Native contains desired values", func() {
+			Expect(getStructTagForName("Native", opts)).To(Equal(
+				`long:"native-ssh" description:"Use built-in SSH client instead of ssh and scp binaries" env:"BOSH_NATIVE_SSH"`,
+			))
+		})
+
+		It("TrustOnFirstUse contains desired values", func() {
+			Expect(getStructTagForName("TrustOnFirstUse", opts)).To(Equal(
+				`long:"trust-on-first-use" description:"Pin host keys of instances on first connection if Director does not provide them" env:"BOSH_SSH_TRUST_ON_FIRST_USE"`,
+			))
+		})
 	})
 
 	Describe("InitReleaseOpts", func() {
@@ -2852,6 +3708,70 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("RenderJobOpts", func() {
+		var opts *RenderJobOpts
+
+		BeforeEach(func() {
+			opts = &RenderJobOpts{}
+		})
+
+		Describe("Release", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Release", opts)).To(Equal(
+					`long:"release" value-name:"PATH" description:"Path to a release tarball or release directory" required:"true"`,
+				))
+			})
+		})
+
+		Describe("Job", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Job", opts)).To(Equal(
+					`long:"job" value-name:"NAME" description:"Name of a job to render" required:"true"`,
+				))
+			})
+		})
+
+		Describe("Properties", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Properties", opts)).To(Equal(
+					`long:"properties" value-name:"PATH" description:"Path to a YAML file with job properties"`,
+				))
+			})
+		})
+
+		Describe("Links", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Links", opts)).To(Equal(
+					`long:"links" value-name:"PATH" description:"Path to a YAML file with stubs of consumed links"`,
+				))
+			})
+		})
+
+		Describe("Instance", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Instance", opts)).To(Equal(
+					`long:"instance" value-name:"PATH" description:"Path to a YAML file with instance fields (deployment, index, id, az, bootstrap, address, networks)"`,
+				))
+			})
+		})
+
+		Describe("Output", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Output", opts)).To(Equal(
+					`long:"output" short:"o" value-name:"DIR" description:"Directory to write rendered templates to" required:"true"`,
+				))
+			})
+		})
+
+		Describe("NativeERB", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("NativeERB", opts)).To(Equal(
+					`long:"native-erb" description:"Render job templates without Ruby, falling back to Ruby for unsupported templates"`,
+				))
+			})
+		})
+	})
+
 	Describe("CreateReleaseOpts", func() {
 		var opts *CreateReleaseOpts
 
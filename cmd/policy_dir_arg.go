@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshpolicy "github.com/cloudfoundry/bosh-cli/policy"
+)
+
+type PolicyDirArg struct {
+	FS boshsys.FileSystem
+
+	Policy boshpolicy.Policy
+
+	path string
+}
+
+func (a PolicyDirArg) IsSet() bool { return len(a.path) > 0 }
+
+func (a *PolicyDirArg) UnmarshalFlag(data string) error {
+	if len(data) == 0 {
+		return bosherr.Errorf("Expected policy directory path to be non-empty")
+	}
+
+	absPath, err := a.FS.ExpandPath(data)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting absolute path '%s'", data)
+	}
+
+	policy, err := boshpolicy.NewPolicyFromDir(absPath, a.FS)
+	if err != nil {
+		return err
+	}
+
+	(*a).Policy = policy
+	(*a).path = absPath
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd_test
+
+import (
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("PolicyDirArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			fs  *fakesys.FakeFileSystem
+			arg PolicyDirArg
+		)
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+			arg = PolicyDirArg{FS: fs}
+		})
+
+		It("loads policy from directory", func() {
+			fs.ExpandPathExpanded = "/policy"
+			fs.SetGlob("/policy/*.yml", []string{"/policy/rules.yml"})
+			fs.WriteFileString("/policy/rules.yml", "rules: [{name: a, path: /a, required: true}]")
+
+			err := (&arg).UnmarshalFlag("~/policy")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.IsSet()).To(BeTrue())
+			Expect(arg.Policy.Rules).To(HaveLen(1))
+			Expect(arg.Policy.Rules[0].Name()).To(Equal("a"))
+		})
+
+		It("returns an error if policy cannot be loaded", func() {
+			err := (&arg).UnmarshalFlag("/policy")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find at least one policy file in '/policy'"))
+			Expect(arg.IsSet()).To(BeFalse())
+		})
+
+		It("returns an error when it's empty", func() {
+			err := (&arg).UnmarshalFlag("")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected policy directory path to be non-empty"))
+		})
+	})
+})
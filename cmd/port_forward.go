@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
+)
+
+type PortForwardCmd struct {
+	deployment    boshdir.Deployment
+	sessions      SSHSessions
+	portForwarder boshssh.PortForwarder
+}
+
+func NewPortForwardCmd(deployment boshdir.Deployment, sessions SSHSessions, portForwarder boshssh.PortForwarder) PortForwardCmd {
+	return PortForwardCmd{deployment: deployment, sessions: sessions, portForwarder: portForwarder}
+}
+
+func (c PortForwardCmd) Run(opts PortForwardOpts) error {
+	if len(opts.Args.Ports) == 0 {
+		return bosherr.Errorf("Expected at least one port to forward")
+	}
+
+	var forwards []boshssh.PortForward
+
+	for _, port := range opts.Args.Ports {
+		forwards = append(forwards, boshssh.PortForward{LocalPort: port.LocalPort, RemotePort: port.RemotePort})
+	}
+
+	result, connOpts, cleanUp, err := sshAccess{c.deployment, c.sessions}.SetUp(opts.Args.Slug, opts.GatewayFlags)
+	if err != nil {
+		return err
+	}
+
+	defer cleanUp()
+
+	err = c.portForwarder.Forward(connOpts, result, forwards)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Forwarding ports")
+	}
+
+	return nil
+}
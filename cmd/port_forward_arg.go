@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// PortForwardArg is a pair of local and remote ports (e.g. '5432:5432').
+type PortForwardArg struct {
+	LocalPort  int
+	RemotePort int
+}
+
+func (a *PortForwardArg) UnmarshalFlag(data string) error {
+	pieces := strings.Split(data, ":")
+
+	if len(pieces) != 2 {
+		return bosherr.Errorf("Expected port forward '%s' to be in format 'LOCAL-PORT:REMOTE-PORT'", data)
+	}
+
+	localPort, err := a.parsePort(pieces[0])
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing local port in '%s'", data)
+	}
+
+	remotePort, err := a.parsePort(pieces[1])
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing remote port in '%s'", data)
+	}
+
+	a.LocalPort = localPort
+	a.RemotePort = remotePort
+
+	return nil
+}
+
+func (a PortForwardArg) parsePort(str string) (int, error) {
+	port, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, err
+	}
+
+	if port < 1 || port > 65535 {
+		return 0, bosherr.Errorf("Expected port '%d' to be between 1 and 65535", port)
+	}
+
+	return port, nil
+}
@@ -0,0 +1,52 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("PortForwardArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			arg *PortForwardArg
+		)
+
+		BeforeEach(func() {
+			arg = &PortForwardArg{}
+		})
+
+		It("sets local and remote ports", func() {
+			err := arg.UnmarshalFlag("15432:5432")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*arg).To(Equal(PortForwardArg{LocalPort: 15432, RemotePort: 5432}))
+		})
+
+		It("returns error if remote port is missing", func() {
+			err := arg.UnmarshalFlag("5432")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected port forward '5432' to be in format 'LOCAL-PORT:REMOTE-PORT'"))
+		})
+
+		It("returns error if ports are not numbers", func() {
+			err := arg.UnmarshalFlag("a:5432")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Parsing local port in 'a:5432'"))
+
+			err = arg.UnmarshalFlag("5432:b")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Parsing remote port in '5432:b'"))
+		})
+
+		It("returns error if ports are out of range", func() {
+			err := arg.UnmarshalFlag("0:5432")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected port '0' to be between 1 and 65535"))
+
+			err = arg.UnmarshalFlag("5432:70000")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected port '70000' to be between 1 and 65535"))
+		})
+	})
+})
@@ -15,7 +15,14 @@ func NewRecreateCmd(ui boshui.UI, deployment boshdir.Deployment) RecreateCmd {
 }
 
 func (c RecreateCmd) Run(opts RecreateOpts) error {
-	err := c.ui.AskForConfirmation()
+	overrides := UpdateOverrides{Canaries: opts.Canaries, MaxInFlight: opts.MaxInFlight}
+
+	manifest, err := overrides.DeploymentManifest(c.deployment)
+	if err != nil {
+		return err
+	}
+
+	err = c.ui.AskForConfirmation()
 	if err != nil {
 		return err
 	}
@@ -25,8 +32,9 @@ func (c RecreateCmd) Run(opts RecreateOpts) error {
 		Force:       opts.Force,
 		Fix:         opts.Fix,
 		DryRun:      opts.DryRun,
-		Canaries:    opts.Canaries,
-		MaxInFlight: opts.MaxInFlight,
+		Canaries:    overrides.GlobalCanaries(),
+		MaxInFlight: overrides.GlobalMaxInFlight(),
+		Manifest:    manifest,
 	}
 
 	return c.deployment.Recreate(opts.Args.Slug, recreateOpts)
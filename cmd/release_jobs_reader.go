@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshcmd "github.com/cloudfoundry/bosh-utils/fileutil"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshjob "github.com/cloudfoundry/bosh-cli/release/job"
+	boshman "github.com/cloudfoundry/bosh-cli/release/manifest"
+	boshreldir "github.com/cloudfoundry/bosh-cli/releasedir"
+)
+
+// ReleaseJobs are jobs of a local release; only job specs and templates
+// are of interest so jobs do not have packages attached
+type ReleaseJobs struct {
+	Name string
+	Jobs []*boshjob.Job
+
+	// JobDirs maps job names to directories with job specs, monit files and templates
+	JobDirs map[string]string
+}
+
+// CleanUp removes jobs extracted from release tarballs
+func (r ReleaseJobs) CleanUp() error {
+	for _, job := range r.Jobs {
+		err := job.CleanUp()
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Cleaning up job '%s'", job.Name())
+		}
+	}
+
+	return nil
+}
+
+//go:generate counterfeiter . ReleaseJobsReader
+
+type ReleaseJobsReader interface {
+	Read(path string) (ReleaseJobs, error)
+}
+
+// FSReleaseJobsReader reads jobs from release tarballs and release directories
+// without reading packages, which are irrelevant for checking job specs
+// and rendering job templates. Jobs read from tarballs stay extracted until cleaned up.
+type FSReleaseJobsReader struct {
+	jobArchiveReader    boshjob.ArchiveReader
+	jobDirReaderFactory func(string) boshjob.DirReader
+	compressor          boshcmd.Compressor
+	fs                  boshsys.FileSystem
+}
+
+func NewFSReleaseJobsReader(
+	jobArchiveReader boshjob.ArchiveReader,
+	jobDirReaderFactory func(string) boshjob.DirReader,
+	compressor boshcmd.Compressor,
+	fs boshsys.FileSystem,
+) FSReleaseJobsReader {
+	return FSReleaseJobsReader{
+		jobArchiveReader:    jobArchiveReader,
+		jobDirReaderFactory: jobDirReaderFactory,
+		compressor:          compressor,
+		fs:                  fs,
+	}
+}
+
+func (r FSReleaseJobsReader) Read(path string) (ReleaseJobs, error) {
+	path, err := r.fs.ExpandPath(path)
+	if err != nil {
+		return ReleaseJobs{}, err
+	}
+
+	if !r.fs.FileExists(path) {
+		return ReleaseJobs{}, bosherr.Errorf("Expected release '%s' to exist", path)
+	}
+
+	stat, err := r.fs.Stat(path)
+	if err != nil {
+		return ReleaseJobs{}, bosherr.WrapErrorf(err, "Checking release '%s'", path)
+	}
+
+	if stat.IsDir() {
+		return r.readDir(path)
+	}
+
+	return r.readArchive(path)
+}
+
+func (r FSReleaseJobsReader) readDir(path string) (ReleaseJobs, error) {
+	config := boshreldir.NewFSConfig(
+		filepath.Join(path, "config", "final.yml"),
+		filepath.Join(path, "config", "private.yml"),
+		r.fs,
+	)
+
+	name, err := config.Name()
+	if err != nil {
+		return ReleaseJobs{}, bosherr.WrapErrorf(err, "Reading release name in '%s'", path)
+	}
+
+	jobPaths, err := r.fs.Glob(filepath.Join(path, "jobs", "*"))
+	if err != nil {
+		return ReleaseJobs{}, bosherr.WrapErrorf(err, "Globbing jobs in '%s'", path)
+	}
+
+	sort.Strings(jobPaths)
+
+	jobDirReader := r.jobDirReaderFactory(path)
+	relJobs := ReleaseJobs{Name: name, JobDirs: map[string]string{}}
+
+	for _, jobPath := range jobPaths {
+		job, err := jobDirReader.Read(jobPath)
+		if err != nil {
+			return ReleaseJobs{}, bosherr.WrapErrorf(err, "Reading job from '%s'", jobPath)
+		}
+
+		relJobs.Jobs = append(relJobs.Jobs, job)
+		relJobs.JobDirs[job.Name()] = jobPath
+	}
+
+	return relJobs, nil
+}
+
+func (r FSReleaseJobsReader) readArchive(path string) (ReleaseJobs, error) {
+	extractPath, err := r.fs.TempDir("bosh-release-jobs")
+	if err != nil {
+		return ReleaseJobs{}, bosherr.WrapErrorf(err, "Creating temp directory to extract release '%s'", path)
+	}
+
+	defer r.fs.RemoveAll(extractPath)
+
+	err = r.compressor.DecompressFileToDir(path, extractPath, boshcmd.CompressorOptions{})
+	if err != nil {
+		return ReleaseJobs{}, bosherr.WrapErrorf(err, "Extracting release '%s'", path)
+	}
+
+	manifest, err := boshman.NewManifestFromPath(filepath.Join(extractPath, "release.MF"), r.fs)
+	if err != nil {
+		return ReleaseJobs{}, err
+	}
+
+	relJobs := ReleaseJobs{Name: manifest.Name, JobDirs: map[string]string{}}
+
+	for _, ref := range manifest.Jobs {
+		job, err := r.jobArchiveReader.Read(ref, filepath.Join(extractPath, "jobs", ref.Name+".tgz"))
+		if err != nil {
+			relJobs.CleanUp()
+			return ReleaseJobs{}, bosherr.WrapErrorf(err, "Reading job '%s' from archive", ref.Name)
+		}
+
+		relJobs.Jobs = append(relJobs.Jobs, job)
+		relJobs.JobDirs[job.Name()] = job.ExtractedPath()
+	}
+
+	return relJobs, nil
+}
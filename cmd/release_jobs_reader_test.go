@@ -0,0 +1,161 @@
+package cmd_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	fakecmd "github.com/cloudfoundry/bosh-utils/fileutil/fakes"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshjob "github.com/cloudfoundry/bosh-cli/release/job"
+	fakejob "github.com/cloudfoundry/bosh-cli/release/job/jobfakes"
+	boshman "github.com/cloudfoundry/bosh-cli/release/manifest"
+	boshres "github.com/cloudfoundry/bosh-cli/release/resource"
+)
+
+var _ = Describe("FSReleaseJobsReader", func() {
+	var (
+		jobArchiveReader *fakejob.FakeArchiveReader
+		jobDirReader     *fakejob.FakeDirReader
+		jobDirReaderPath string
+		compressor       *fakecmd.FakeCompressor
+		fs               *fakesys.FakeFileSystem
+		reader           FSReleaseJobsReader
+	)
+
+	BeforeEach(func() {
+		jobArchiveReader = &fakejob.FakeArchiveReader{}
+		jobDirReader = &fakejob.FakeDirReader{}
+		compressor = fakecmd.NewFakeCompressor()
+		fs = fakesys.NewFakeFileSystem()
+
+		jobDirReaderFactory := func(path string) boshjob.DirReader {
+			jobDirReaderPath = path
+			return jobDirReader
+		}
+
+		reader = NewFSReleaseJobsReader(jobArchiveReader, jobDirReaderFactory, compressor, fs)
+	})
+
+	Describe("Read", func() {
+		Context("when path is a release tarball", func() {
+			BeforeEach(func() {
+				fs.WriteFileString("/release.tgz", "tarball")
+				fs.TempDirDir = "/extracted"
+
+				fs.WriteFileString("/extracted/release.MF", `
+name: capi
+version: 1.0
+jobs:
+- {name: api, version: api-v, fingerprint: api-fp, sha1: api-sha1}
+- {name: worker, version: worker-v, fingerprint: worker-fp, sha1: worker-sha1}
+`)
+			})
+
+			It("extracts release and reads only its jobs", func() {
+				jobArchiveReader.ReadStub = func(ref boshman.JobRef, path string) (*boshjob.Job, error) {
+					extractedPath := "/extracted-" + ref.Name
+					fs.MkdirAll(extractedPath, os.ModePerm)
+					return boshjob.NewExtractedJob(boshres.NewResource(ref.Name, ref.Fingerprint, nil), extractedPath, fs), nil
+				}
+
+				relJobs, err := reader.Read("/release.tgz")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(relJobs.Name).To(Equal("capi"))
+				Expect(relJobs.Jobs).To(HaveLen(2))
+				Expect(relJobs.Jobs[0].Name()).To(Equal("api"))
+				Expect(relJobs.Jobs[1].Name()).To(Equal("worker"))
+				Expect(relJobs.JobDirs).To(Equal(map[string]string{
+					"api":    "/extracted-api",
+					"worker": "/extracted-worker",
+				}))
+
+				Expect(compressor.DecompressFileToDirTarballPaths).To(Equal([]string{"/release.tgz"}))
+				Expect(compressor.DecompressFileToDirDirs).To(Equal([]string{"/extracted"}))
+
+				ref, path := jobArchiveReader.ReadArgsForCall(1)
+				Expect(ref.Name).To(Equal("worker"))
+				Expect(path).To(Equal("/extracted/jobs/worker.tgz"))
+
+				Expect(fs.FileExists("/extracted")).To(BeFalse())
+				Expect(fs.FileExists("/extracted-api")).To(BeTrue())
+
+				err = relJobs.CleanUp()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fs.FileExists("/extracted-api")).To(BeFalse())
+				Expect(fs.FileExists("/extracted-worker")).To(BeFalse())
+			})
+
+			It("returns error if job cannot be read", func() {
+				jobArchiveReader.ReadReturns(nil, errors.New("fake-err"))
+
+				_, err := reader.Read("/release.tgz")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Reading job 'api' from archive"))
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(fs.FileExists("/extracted")).To(BeFalse())
+			})
+
+			It("returns error if release cannot be extracted", func() {
+				compressor.DecompressFileToDirErr = errors.New("fake-err")
+
+				_, err := reader.Read("/release.tgz")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Extracting release '/release.tgz'"))
+			})
+		})
+
+		Context("when path is a release directory", func() {
+			BeforeEach(func() {
+				fs.WriteFileString("/release/config/final.yml", "name: capi")
+				fs.SetGlob("/release/jobs/*", []string{"/release/jobs/worker", "/release/jobs/api"})
+			})
+
+			It("reads release name from config and jobs from job directories", func() {
+				jobDirReader.ReadStub = func(path string) (*boshjob.Job, error) {
+					return boshjob.NewJob(boshres.NewResource(filepath.Base(path), "fp", nil)), nil
+				}
+
+				relJobs, err := reader.Read("/release")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(relJobs.Name).To(Equal("capi"))
+				Expect(relJobs.Jobs).To(HaveLen(2))
+				Expect(relJobs.Jobs[0].Name()).To(Equal("api"))
+				Expect(relJobs.Jobs[1].Name()).To(Equal("worker"))
+				Expect(relJobs.JobDirs).To(Equal(map[string]string{
+					"api":    "/release/jobs/api",
+					"worker": "/release/jobs/worker",
+				}))
+
+				Expect(jobDirReaderPath).To(Equal("/release"))
+			})
+
+			It("returns error if release name cannot be read", func() {
+				fs.WriteFileString("/release/config/final.yml", "")
+
+				_, err := reader.Read("/release")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Reading release name in '/release'"))
+			})
+
+			It("returns error if job cannot be read", func() {
+				jobDirReader.ReadReturns(nil, errors.New("fake-err"))
+
+				_, err := reader.Read("/release")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Reading job from '/release/jobs/api'"))
+			})
+		})
+
+		It("returns error if release does not exist", func() {
+			_, err := reader.Read("/missing.tgz")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected release '/missing.tgz' to exist"))
+		})
+	})
+})
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	"gopkg.in/yaml.v2"
+
+	bitemplate "github.com/cloudfoundry/bosh-cli/templatescompiler"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+type RenderJobCmd struct {
+	releaseJobsReader ReleaseJobsReader
+	jobRenderer       bitemplate.InstanceJobRenderer
+	ui                boshui.UI
+}
+
+/*
+# instance.yml
+---
+deployment: cf
+index: 1
+id: 7d6c5e2a-...
+az: z1
+bootstrap: false
+address: web-1.example.com
+networks:
+  default: {ip: 10.0.0.5, netmask: 255.255.255.0, gateway: 10.0.0.1}
+
+# links.yml
+---
+db:
+  address: db.example.com
+  properties: {port: 5432}
+  instances:
+  - {name: db, index: 0, id: 4f3a..., az: z1, address: 10.0.0.10, bootstrap: true}
+*/
+
+type renderJobInstance struct {
+	Deployment string                      `yaml:"deployment"`
+	Index      int                         `yaml:"index"`
+	ID         string                      `yaml:"id"`
+	AZ         string                      `yaml:"az"`
+	Bootstrap  bool                        `yaml:"bootstrap"`
+	Address    string                      `yaml:"address"`
+	Networks   map[string]renderJobNetwork `yaml:"networks"`
+}
+
+type renderJobNetwork struct {
+	IP      string `yaml:"ip"`
+	Netmask string `yaml:"netmask"`
+	Gateway string `yaml:"gateway"`
+}
+
+type renderJobLink struct {
+	Address    string                      `yaml:"address"`
+	Properties map[interface{}]interface{} `yaml:"properties"`
+	Instances  []renderJobLinkInstance     `yaml:"instances"`
+}
+
+type renderJobLinkInstance struct {
+	Name      string `yaml:"name"`
+	Index     int    `yaml:"index"`
+	ID        string `yaml:"id"`
+	AZ        string `yaml:"az"`
+	Address   string `yaml:"address"`
+	Bootstrap bool   `yaml:"bootstrap"`
+}
+
+func NewRenderJobCmd(
+	releaseJobsReader ReleaseJobsReader,
+	jobRenderer bitemplate.InstanceJobRenderer,
+	ui boshui.UI,
+) RenderJobCmd {
+	return RenderJobCmd{releaseJobsReader: releaseJobsReader, jobRenderer: jobRenderer, ui: ui}
+}
+
+func (c RenderJobCmd) Run(opts RenderJobOpts) error {
+	props, err := c.properties(opts.Properties.Bytes)
+	if err != nil {
+		return err
+	}
+
+	instance, deploymentName, err := c.instance(opts.Instance.Bytes)
+	if err != nil {
+		return err
+	}
+
+	links, err := c.links(opts.Links.Bytes)
+	if err != nil {
+		return err
+	}
+
+	relJobs, err := c.releaseJobsReader.Read(opts.Release)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading jobs of release '%s'", opts.Release)
+	}
+
+	defer relJobs.CleanUp()
+
+	job, found := relJobs.findJob(opts.Job)
+	if !found {
+		return bosherr.Errorf("Expected to find job '%s' in release '%s'", opts.Job, relJobs.Name)
+	}
+
+	err = c.jobRenderer.Render(
+		*job, relJobs.JobDirs[job.Name()], props, instance, links, deploymentName, opts.Output.Path)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Rendering templates for job '%s'", job.Name())
+	}
+
+	c.ui.PrintLinef("Rendered templates of job '%s' into '%s'", job.Name(), opts.Output.Path)
+
+	return nil
+}
+
+func (c RenderJobCmd) properties(bytes []byte) (biproperty.Map, error) {
+	var rawProps map[interface{}]interface{}
+
+	err := yaml.Unmarshal(bytes, &rawProps)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Deserializing job properties")
+	}
+
+	props, err := biproperty.BuildMap(rawProps)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Building job properties")
+	}
+
+	return props, nil
+}
+
+func (c RenderJobCmd) instance(bytes []byte) (bitemplate.InstanceSpec, string, error) {
+	defaultInstance := bitemplate.DefaultInstanceSpec()
+
+	rawInstance := renderJobInstance{
+		Index:     defaultInstance.Index,
+		AZ:        defaultInstance.AZ,
+		Bootstrap: defaultInstance.Bootstrap,
+	}
+
+	err := yaml.Unmarshal(bytes, &rawInstance)
+	if err != nil {
+		return bitemplate.InstanceSpec{}, "", bosherr.WrapErrorf(err, "Deserializing instance")
+	}
+
+	instance := bitemplate.InstanceSpec{
+		Index:     rawInstance.Index,
+		ID:        rawInstance.ID,
+		AZ:        rawInstance.AZ,
+		Bootstrap: rawInstance.Bootstrap,
+		Address:   rawInstance.Address,
+		Networks:  defaultInstance.Networks,
+	}
+
+	if len(rawInstance.Networks) > 0 {
+		instance.Networks = map[string]bitemplate.NetworkSpec{}
+
+		for name, network := range rawInstance.Networks {
+			instance.Networks[name] = bitemplate.NetworkSpec(network)
+		}
+	}
+
+	return instance, rawInstance.Deployment, nil
+}
+
+func (c RenderJobCmd) links(bytes []byte) (map[string]bitemplate.LinkSpec, error) {
+	var rawLinks map[string]renderJobLink
+
+	err := yaml.Unmarshal(bytes, &rawLinks)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Deserializing links")
+	}
+
+	links := map[string]bitemplate.LinkSpec{}
+
+	for name, rawLink := range rawLinks {
+		props, err := biproperty.BuildMap(rawLink.Properties)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Building properties of link '%s'", name)
+		}
+
+		link := bitemplate.LinkSpec{Address: rawLink.Address, Properties: props}
+
+		for _, rawInst := range rawLink.Instances {
+			link.Instances = append(link.Instances, bitemplate.LinkInstanceSpec(rawInst))
+		}
+
+		links[name] = link
+	}
+
+	return links, nil
+}
@@ -0,0 +1,180 @@
+package cmd_test
+
+import (
+	"errors"
+	"os"
+
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	fakecmd "github.com/cloudfoundry/bosh-cli/cmd/cmdfakes"
+	boshjob "github.com/cloudfoundry/bosh-cli/release/job"
+	boshres "github.com/cloudfoundry/bosh-cli/release/resource"
+	bitemplate "github.com/cloudfoundry/bosh-cli/templatescompiler"
+	mock_template "github.com/cloudfoundry/bosh-cli/templatescompiler/mocks"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("RenderJobCmd", func() {
+	var (
+		mockCtrl          *gomock.Controller
+		releaseJobsReader *fakecmd.FakeReleaseJobsReader
+		jobRenderer       *mock_template.MockInstanceJobRenderer
+		ui                *fakeui.FakeUI
+		command           RenderJobCmd
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		releaseJobsReader = &fakecmd.FakeReleaseJobsReader{}
+		jobRenderer = mock_template.NewMockInstanceJobRenderer(mockCtrl)
+		ui = &fakeui.FakeUI{}
+		command = NewRenderJobCmd(releaseJobsReader, jobRenderer, ui)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			opts RenderJobOpts
+			job  *boshjob.Job
+			fs   *fakesys.FakeFileSystem
+		)
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+			fs.MkdirAll("/extracted-api", os.ModePerm)
+
+			job = boshjob.NewExtractedJob(boshres.NewResource("api", "api-fp", nil), "/extracted-api", fs)
+
+			releaseJobsReader.ReadReturns(ReleaseJobs{
+				Name:    "capi",
+				Jobs:    []*boshjob.Job{job},
+				JobDirs: map[string]string{"api": "/extracted-api"},
+			}, nil)
+
+			opts = RenderJobOpts{
+				Release: "/capi.tgz",
+				Job:     "api",
+				Output:  DirOrCWDArg{Path: "/output"},
+			}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("renders job from release for given properties, instance and links", func() {
+			opts.Properties = FileBytesArg{Bytes: []byte("port: 8080\ntls: {enabled: true}\n")}
+			opts.Instance = FileBytesArg{Bytes: []byte(`
+deployment: cf
+index: 1
+id: fake-id
+az: z1
+bootstrap: false
+address: web-1.internal
+networks:
+  private: {ip: 10.0.0.5, netmask: 255.255.255.0, gateway: 10.0.0.1}
+`)}
+			opts.Links = FileBytesArg{Bytes: []byte(`
+db:
+  address: db.internal
+  properties: {port: 5432}
+  instances:
+  - {name: db, index: 0, id: db-id, az: z1, address: 10.0.0.10, bootstrap: true}
+`)}
+
+			expectedProps := biproperty.Map{
+				"port": 8080,
+				"tls":  biproperty.Map{"enabled": true},
+			}
+
+			expectedInstance := bitemplate.InstanceSpec{
+				Index:     1,
+				ID:        "fake-id",
+				AZ:        "z1",
+				Bootstrap: false,
+				Address:   "web-1.internal",
+				Networks: map[string]bitemplate.NetworkSpec{
+					"private": {IP: "10.0.0.5", Netmask: "255.255.255.0", Gateway: "10.0.0.1"},
+				},
+			}
+
+			expectedLinks := map[string]bitemplate.LinkSpec{
+				"db": {
+					Address:    "db.internal",
+					Properties: biproperty.Map{"port": 5432},
+					Instances: []bitemplate.LinkInstanceSpec{
+						{Name: "db", Index: 0, ID: "db-id", AZ: "z1", Address: "10.0.0.10", Bootstrap: true},
+					},
+				},
+			}
+
+			jobRenderer.EXPECT().Render(
+				gomock.Any(), "/extracted-api", expectedProps, expectedInstance, expectedLinks, "cf", "/output",
+			).Return(nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(releaseJobsReader.ReadArgsForCall(0)).To(Equal("/capi.tgz"))
+			Expect(ui.Said).To(Equal([]string{"Rendered templates of job 'api' into '/output'"}))
+
+			Expect(fs.FileExists("/extracted-api")).To(BeFalse())
+		})
+
+		It("renders job for default instance without properties and links", func() {
+			jobRenderer.EXPECT().Render(
+				gomock.Any(), "/extracted-api", biproperty.Map{}, bitemplate.DefaultInstanceSpec(),
+				map[string]bitemplate.LinkSpec{}, "", "/output",
+			).Return(nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns error if job is not found in release", func() {
+			opts.Job = "worker"
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find job 'worker' in release 'capi'"))
+		})
+
+		It("returns error if release cannot be read", func() {
+			releaseJobsReader.ReadReturns(ReleaseJobs{}, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading jobs of release '/capi.tgz'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if instance cannot be deserialized", func() {
+			opts.Instance = FileBytesArg{Bytes: []byte("-")}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Deserializing instance"))
+
+			Expect(releaseJobsReader.ReadCallCount()).To(Equal(0))
+		})
+
+		It("returns error if rendering fails", func() {
+			jobRenderer.EXPECT().Render(
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			).Return(errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Rendering templates for job 'api'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+			Expect(ui.Said).To(BeEmpty())
+		})
+	})
+})
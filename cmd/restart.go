@@ -15,7 +15,14 @@ func NewRestartCmd(ui boshui.UI, deployment boshdir.Deployment) RestartCmd {
 }
 
 func (c RestartCmd) Run(opts RestartOpts) error {
-	err := c.ui.AskForConfirmation()
+	overrides := UpdateOverrides{Canaries: opts.Canaries, MaxInFlight: opts.MaxInFlight}
+
+	manifest, err := overrides.DeploymentManifest(c.deployment)
+	if err != nil {
+		return err
+	}
+
+	err = c.ui.AskForConfirmation()
 	if err != nil {
 		return err
 	}
@@ -23,8 +30,9 @@ func (c RestartCmd) Run(opts RestartOpts) error {
 	restartOpts := boshdir.RestartOpts{
 		SkipDrain:   opts.SkipDrain,
 		Force:       opts.Force,
-		Canaries:    opts.Canaries,
-		MaxInFlight: opts.MaxInFlight,
+		Canaries:    overrides.GlobalCanaries(),
+		MaxInFlight: overrides.GlobalMaxInFlight(),
+		Manifest:    manifest,
 	}
 	return c.deployment.Restart(opts.Args.Slug, restartOpts)
 }
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshschema "github.com/cloudfoundry/bosh-cli/schema"
+)
+
+type SchemaFileArg struct {
+	FS boshsys.FileSystem
+
+	Schema boshschema.Schema
+
+	path string
+}
+
+func (a SchemaFileArg) IsSet() bool { return len(a.path) > 0 }
+
+func (a *SchemaFileArg) UnmarshalFlag(data string) error {
+	if len(data) == 0 {
+		return bosherr.Errorf("Expected schema file path to be non-empty")
+	}
+
+	absPath, err := a.FS.ExpandPath(data)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting absolute path '%s'", data)
+	}
+
+	bytes, err := a.FS.ReadFile(absPath)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading schema file '%s'", absPath)
+	}
+
+	schema, err := boshschema.NewSchemaFromBytes(bytes)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Building schema from file '%s'", absPath)
+	}
+
+	(*a).Schema = schema
+	(*a).path = absPath
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd_test
+
+import (
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("SchemaFileArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			fs  *fakesys.FakeFileSystem
+			arg SchemaFileArg
+		)
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+			arg = SchemaFileArg{FS: fs}
+		})
+
+		It("loads schema from file", func() {
+			fs.ExpandPathExpanded = "/schema.yml"
+			fs.WriteFileString("/schema.yml", "required: [name]")
+
+			err := (&arg).UnmarshalFlag("~/schema.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.IsSet()).To(BeTrue())
+
+			violations, err := arg.Schema.ValidateBytes([]byte("{}"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(HaveLen(1))
+		})
+
+		It("returns an error if schema cannot be read", func() {
+			err := (&arg).UnmarshalFlag("/schema.yml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading schema file '/schema.yml'"))
+			Expect(arg.IsSet()).To(BeFalse())
+		})
+
+		It("returns an error if schema is not valid", func() {
+			fs.WriteFileString("/schema.yml", "type: str")
+
+			err := (&arg).UnmarshalFlag("/schema.yml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Building schema from file '/schema.yml'"))
+			Expect(arg.IsSet()).To(BeFalse())
+		})
+
+		It("returns an error when it's empty", func() {
+			err := (&arg).UnmarshalFlag("")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected schema file path to be non-empty"))
+		})
+	})
+})
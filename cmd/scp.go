@@ -11,6 +11,7 @@ import (
 
 type SCPCmd struct {
 	deployment boshdir.Deployment
+	sessions   SSHSessions
 	uuidGen    boshuuid.Generator
 	scpRunner  boshssh.SCPRunner
 	ui         biui.UI
@@ -18,12 +19,14 @@ type SCPCmd struct {
 
 func NewSCPCmd(
 	deployment boshdir.Deployment,
+	sessions SSHSessions,
 	uuidGen boshuuid.Generator,
 	scpRunner boshssh.SCPRunner,
 	ui biui.UI,
 ) SCPCmd {
 	return SCPCmd{
 		deployment: deployment,
+		sessions:   sessions,
 		uuidGen:    uuidGen,
 		scpRunner:  scpRunner,
 		ui:         ui,
@@ -31,26 +34,25 @@ func NewSCPCmd(
 }
 
 func (c SCPCmd) Run(opts SCPOpts) error {
-	scpArgs := boshssh.NewSCPArgs(opts.Args.Paths, opts.Recursive)
+	if opts.MaxInFlight < 0 {
+		return bosherr.Error("Expected max in flight to be a positive number")
+	}
+
+	scpArgs := boshssh.NewSCPArgs(opts.Args.Paths, opts.Recursive).WithSync(opts.Sync)
 
 	slug, err := scpArgs.AllOrInstanceGroupOrInstanceSlug()
 	if err != nil {
 		return err
 	}
 
-	sshOpts, connOpts, err := opts.GatewayFlags.AsSSHOpts()
+	result, connOpts, cleanUp, err := sshAccess{c.deployment, c.sessions}.SetUp(slug, opts.GatewayFlags)
 	if err != nil {
 		return err
 	}
 
-	result, err := c.deployment.SetUpSSH(slug, sshOpts)
-	if err != nil {
-		return err
-	}
+	defer cleanUp()
 
-	defer func() {
-		_ = c.deployment.CleanUpSSH(slug, sshOpts)
-	}()
+	connOpts.MaxInFlight = opts.MaxInFlight
 
 	err = c.scpRunner.Run(connOpts, result, scpArgs)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	. "github.com/cloudfoundry/bosh-cli/cmd"
+	fakecmd "github.com/cloudfoundry/bosh-cli/cmd/cmdfakes"
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
 	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
@@ -22,17 +23,21 @@ var _ = Describe("SCPCmd", func() {
 	var (
 		deployment *fakedir.FakeDeployment
 		uuidGen    *fakeuuid.FakeGenerator
+		sessions   *fakecmd.FakeSSHSessions
 		scpRunner  *fakessh.FakeSCPRunner
 		ui         *fakeui.FakeUI
 		command    SCPCmd
 	)
 
 	BeforeEach(func() {
-		deployment = &fakedir.FakeDeployment{}
+		deployment = &fakedir.FakeDeployment{
+			NameStub: func() string { return "dep" },
+		}
+		sessions = &fakecmd.FakeSSHSessions{}
 		uuidGen = &fakeuuid.FakeGenerator{}
 		scpRunner = &fakessh.FakeSCPRunner{}
 		ui = &fakeui.FakeUI{}
-		command = NewSCPCmd(deployment, uuidGen, scpRunner, ui)
+		command = NewSCPCmd(deployment, sessions, uuidGen, scpRunner, ui)
 	})
 
 	Describe("Run", func() {
@@ -100,6 +105,7 @@ var _ = Describe("SCPCmd", func() {
 				opts.GatewayFlags.Host = "gw-host"
 				opts.GatewayFlags.PrivateKeyPath = "gw-private-key"
 				opts.GatewayFlags.SOCKS5Proxy = "some-proxy"
+				opts.GatewayFlags.Native = true
 
 				Expect(act()).ToNot(HaveOccurred())
 
@@ -112,6 +118,7 @@ var _ = Describe("SCPCmd", func() {
 				Expect(runConnOpts.GatewayHost).To(Equal("gw-host"))
 				Expect(runConnOpts.GatewayPrivateKeyPath).To(Equal("gw-private-key"))
 				Expect(runConnOpts.SOCKS5Proxy).To(Equal("some-proxy"))
+				Expect(runConnOpts.Native).To(BeTrue())
 				Expect(runResult).To(Equal(boshdir.SSHResult{Hosts: []boshdir.Host{{Host: "ip1"}}}))
 				Expect(runCommand).To(Equal(boshssh.NewSCPArgs([]string{"from:file", "/something"}, false)))
 			})
@@ -125,12 +132,87 @@ var _ = Describe("SCPCmd", func() {
 				Expect(runCommand).To(Equal(boshssh.NewSCPArgs([]string{"from:file", "/something"}, true)))
 			})
 
+			It("runs SCP on limited number of instances at a time if max in flight is set", func() {
+				opts.MaxInFlight = 2
+				Expect(act()).ToNot(HaveOccurred())
+				Expect(scpRunner.RunCallCount()).To(Equal(1))
+
+				runConnOpts, _, _ := scpRunner.RunArgsForCall(0)
+				Expect(runConnOpts.MaxInFlight).To(Equal(2))
+			})
+
+			It("returns error if max in flight is negative", func() {
+				opts.MaxInFlight = -1
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected max in flight to be a positive number"))
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+			})
+
+			It("sets up SCP to sync if sync flag is set", func() {
+				opts.Sync = true
+				Expect(act()).ToNot(HaveOccurred())
+				Expect(scpRunner.RunCallCount()).To(Equal(1))
+
+				_, _, runCommand := scpRunner.RunArgsForCall(0)
+				Expect(runCommand.Sync()).To(BeTrue())
+				Expect(runCommand).To(Equal(boshssh.NewSCPArgs([]string{"from:file", "/something"}, false).WithSync(true)))
+			})
+
 			It("returns error if SCP errors", func() {
 				scpRunner.RunReturns(errors.New("fake-err"))
 				err := act()
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-err"))
 			})
+
+			Context("when SSH session is active", func() {
+				BeforeEach(func() {
+					sessions.ActiveReturns(SSHSession{
+						Deployment: "dep",
+						Username:   "session-username",
+						PrivateKey: "session-private-key",
+						Result: boshdir.SSHResult{
+							Hosts: []boshdir.Host{
+								{Job: "from", IndexOrID: "id", Host: "ip1"},
+								{Job: "other", IndexOrID: "0", Host: "ip2"},
+							},
+						},
+					}, true, nil)
+				})
+
+				It("reuses SSH session for matching instances instead of setting up SSH access", func() {
+					Expect(act()).ToNot(HaveOccurred())
+
+					Expect(sessions.ActiveArgsForCall(0)).To(Equal(deployment))
+
+					Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+					Expect(deployment.CleanUpSSHCallCount()).To(Equal(0))
+
+					Expect(scpRunner.RunCallCount()).To(Equal(1))
+
+					connOpts, result, _ := scpRunner.RunArgsForCall(0)
+					Expect(connOpts.PrivateKey).To(Equal("session-private-key"))
+					Expect(result.Hosts).To(Equal([]boshdir.Host{{Job: "from", IndexOrID: "id", Host: "ip1"}}))
+				})
+
+				It("sets up SSH access if session does not include instances", func() {
+					sessions.ActiveReturns(SSHSession{InstanceGroup: "other"}, true, nil)
+
+					Expect(act()).ToNot(HaveOccurred())
+
+					Expect(deployment.SetUpSSHCallCount()).To(Equal(1))
+					Expect(deployment.CleanUpSSHCallCount()).To(Equal(1))
+				})
+
+				It("returns error if finding SSH session fails", func() {
+					sessions.ActiveReturns(SSHSession{}, false, errors.New("fake-err"))
+
+					err := act()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-err"))
+				})
+			})
 		})
 
 		Context("when valid SCP args are not provided", func() {
@@ -11,6 +11,7 @@ import (
 
 type SSHCmd struct {
 	deployment       boshdir.Deployment
+	sessions         SSHSessions
 	uuidGen          boshuuid.Generator
 	intSSHRunner     boshssh.Runner
 	nonIntSSHRunner  boshssh.Runner
@@ -20,6 +21,7 @@ type SSHCmd struct {
 
 func NewSSHCmd(
 	deployment boshdir.Deployment,
+	sessions SSHSessions,
 	uuidGen boshuuid.Generator,
 	intSSHRunner boshssh.Runner,
 	nonIntSSHRunner boshssh.Runner,
@@ -28,6 +30,7 @@ func NewSSHCmd(
 ) SSHCmd {
 	return SSHCmd{
 		deployment:       deployment,
+		sessions:         sessions,
 		uuidGen:          uuidGen,
 		intSSHRunner:     intSSHRunner,
 		nonIntSSHRunner:  nonIntSSHRunner,
@@ -37,27 +40,42 @@ func NewSSHCmd(
 }
 
 func (c SSHCmd) Run(opts SSHOpts) error {
+	// Structured output requires results to be collected per instance
+	if opts.JSON {
+		opts.Results = true
+	}
+
 	if opts.Results || !c.ui.IsInteractive() {
 		if len(opts.Command) == 0 {
 			return bosherr.Errorf("Non-interactive SSH requires non-empty command")
 		}
 	}
 
-	sshOpts, connOpts, err := opts.GatewayFlags.AsSSHOpts()
-	if err != nil {
-		return err
+	if opts.MaxInFlight < 0 {
+		return bosherr.Errorf("Expected max in flight to be a positive number")
 	}
 
-	connOpts.RawOpts = opts.RawOpts.AsStrings()
+	if (opts.MaxInFlight > 0 || opts.FailFast || opts.Timeout > 0) && len(opts.Command) == 0 {
+		return bosherr.Errorf("Max in flight, fail fast and timeout options require a command")
+	}
 
-	result, err := c.deployment.SetUpSSH(opts.Args.Slug, sshOpts)
+	result, connOpts, cleanUp, err := sshAccess{c.deployment, c.sessions}.SetUp(opts.Args.Slug, opts.GatewayFlags)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		_ = c.deployment.CleanUpSSH(opts.Args.Slug, sshOpts)
-	}()
+	defer cleanUp()
+
+	connOpts.RawOpts = opts.RawOpts.AsStrings()
+	connOpts.MaxInFlight = opts.MaxInFlight
+	connOpts.FailFast = opts.FailFast
+	connOpts.Timeout = opts.Timeout
+
+	if len(opts.Record) > 0 {
+		connOpts.Recording = opts.Recording
+		connOpts.Recording.Dir = opts.Record
+		connOpts.Recording.Deployment = c.deployment.Name()
+	}
 
 	var runner boshssh.Runner
 
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+type SSHConfigCmd struct {
+	deployment boshdir.Deployment
+	sessions   SSHSessions
+	sessionCmd SSHSessionCmd
+	fs         boshsys.FileSystem
+	ui         boshui.UI
+}
+
+func NewSSHConfigCmd(
+	deployment boshdir.Deployment,
+	sessions SSHSessions,
+	uuidGen boshuuid.Generator,
+	timeService clock.Clock,
+	fs boshsys.FileSystem,
+	ui boshui.UI,
+) SSHConfigCmd {
+	return SSHConfigCmd{
+		deployment: deployment,
+		sessions:   sessions,
+		sessionCmd: NewSSHSessionCmd(deployment, sessions, uuidGen, timeService, ui),
+		fs:         fs,
+		ui:         ui,
+	}
+}
+
+func (c SSHConfigCmd) Run(opts SSHConfigOpts) error {
+	slug := opts.Args.Slug
+	deploymentName := c.deployment.Name()
+
+	// Credentials have to outlive this command hence they are kept
+	// in an SSH session which could be cleaned up via 'ssh-session stop'
+	session, found, err := c.sessions.Active(c.deployment)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		session, err = c.sessionCmd.start(slug, opts.TTL)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, covered := session.ResultFor(slug)
+	if !covered {
+		return bosherr.Errorf("Expected active SSH session for deployment '%s' to include instances '%s'; stop it first", deploymentName, slug)
+	}
+
+	connOpts := opts.GatewayFlags.AsConnectionOpts(session.PrivateKey)
+
+	if len(connOpts.KnownHostsPath) > 0 {
+		result, err = boshssh.NewHostKeyPins(connOpts.KnownHostsPath, c.fs).Pin(result)
+		if err != nil {
+			return err
+		}
+	}
+
+	configPath := filepath.Join(opts.Directory.Path, deploymentName+"-ssh_config")
+	identityPath := filepath.Join(opts.Directory.Path, deploymentName+"-identity")
+	knownHostsPath := filepath.Join(opts.Directory.Path, deploymentName+"-known_hosts")
+
+	config := boshssh.SSHConfig{
+		Deployment: deploymentName,
+
+		ConnOpts: connOpts,
+		Result:   result,
+
+		IdentityFile:   identityPath,
+		KnownHostsFile: knownHostsPath,
+	}
+
+	configContents, err := config.String()
+	if err != nil {
+		return err
+	}
+
+	err = c.writeFile(identityPath, session.PrivateKey, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = c.writeFile(knownHostsPath, boshssh.KnownHosts(result), 0644)
+	if err != nil {
+		return err
+	}
+
+	err = c.writeFile(configPath, configContents, 0644)
+	if err != nil {
+		return err
+	}
+
+	c.printHosts(config)
+
+	c.ui.PrintLinef("Use 'ssh -F %s <alias>' to connect until '%s'",
+		configPath, session.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"))
+
+	return nil
+}
+
+// writeFile creates file with given permissions next to destination and renames it
+// so that identity file is never readable by others, even while it's being written
+func (c SSHConfigCmd) writeFile(path, contents string, mode os.FileMode) error {
+	tmpPath := path + ".tmp"
+
+	err := c.fs.RemoveAll(tmpPath)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Removing '%s'", tmpPath)
+	}
+
+	file, err := c.fs.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing '%s'", path)
+	}
+
+	_, err = file.Write([]byte(contents))
+	if err != nil {
+		_ = file.Close()
+		_ = c.fs.RemoveAll(tmpPath)
+		return bosherr.WrapErrorf(err, "Writing '%s'", path)
+	}
+
+	err = file.Close()
+	if err != nil {
+		_ = c.fs.RemoveAll(tmpPath)
+		return bosherr.WrapErrorf(err, "Writing '%s'", path)
+	}
+
+	err = c.fs.Rename(tmpPath, path)
+	if err != nil {
+		_ = c.fs.RemoveAll(tmpPath)
+		return bosherr.WrapErrorf(err, "Writing '%s'", path)
+	}
+
+	return nil
+}
+
+func (c SSHConfigCmd) printHosts(config boshssh.SSHConfig) {
+	table := boshtbl.Table{
+		Content: "hosts",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Alias"),
+			boshtbl.NewHeader("Instance"),
+			boshtbl.NewHeader("IP"),
+		},
+
+		SortBy: []boshtbl.ColumnSort{{Column: 0, Asc: true}},
+	}
+
+	for _, host := range config.Result.Hosts {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(config.Alias(host)),
+			boshtbl.NewValueString(host.Job + "/" + host.IndexOrID),
+			boshtbl.NewValueString(host.Host),
+		})
+	}
+
+	c.ui.PrintTable(table)
+}
@@ -0,0 +1,230 @@
+package cmd_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	fakecmd "github.com/cloudfoundry/bosh-cli/cmd/cmdfakes"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("SSHConfigCmd", func() {
+	var (
+		deployment *fakedir.FakeDeployment
+		sessions   *fakecmd.FakeSSHSessions
+		uuidGen    *fakeuuid.FakeGenerator
+		fs         *fakesys.FakeFileSystem
+		ui         *fakeui.FakeUI
+		command    SSHConfigCmd
+		now        time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+
+		deployment = &fakedir.FakeDeployment{
+			NameStub: func() string { return "dep" },
+		}
+		sessions = &fakecmd.FakeSSHSessions{}
+		uuidGen = &fakeuuid.FakeGenerator{GeneratedUUID: "8c5ff117-9572-45c5-8564-8bcf076ecafa"}
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		command = NewSSHConfigCmd(deployment, sessions, uuidGen, fakeclock.NewFakeClock(now), fs, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts SSHConfigOpts
+		)
+
+		BeforeEach(func() {
+			opts = SSHConfigOpts{
+				Args: AllOrInstanceGroupOrInstanceSlugArgs{
+					Slug: boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", ""),
+				},
+				Directory: DirOrCWDArg{Path: "/dir"},
+				TTL:       24 * time.Hour,
+			}
+
+			err := fs.MkdirAll("/dir", os.ModePerm)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		Context("when SSH session is active", func() {
+			BeforeEach(func() {
+				index := 0
+
+				sessions.ActiveReturns(SSHSession{
+					Deployment: "dep",
+					PrivateKey: "private-key",
+					Result: boshdir.SSHResult{
+						Hosts: []boshdir.Host{
+							{Job: "db", IndexOrID: "db-id", Index: &index, Username: "user", Host: "10.0.0.1", HostPublicKey: "ssh-rsa key"},
+							{Job: "web", IndexOrID: "web-id", Username: "user", Host: "10.0.0.2"},
+						},
+					},
+					ExpiresAt: now.Add(time.Hour),
+				}, true, nil)
+			})
+
+			It("writes ssh_config, identity and known hosts files for matching instances", func() {
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+
+				identity, err := fs.ReadFileString("/dir/dep-identity")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(identity).To(Equal("private-key"))
+				Expect(fs.GetFileTestStat("/dir/dep-identity").FileMode).To(Equal(os.FileMode(0600)))
+
+				knownHosts, err := fs.ReadFileString("/dir/dep-known_hosts")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(knownHosts).To(Equal("10.0.0.1 ssh-rsa key\n"))
+
+				config, err := fs.ReadFileString("/dir/dep-ssh_config")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config).To(ContainSubstring("Host dep-db-0\n  HostName 10.0.0.1\n  User user\n"))
+				Expect(config).To(ContainSubstring("  IdentityFile /dir/dep-identity\n"))
+				Expect(config).To(ContainSubstring("  UserKnownHostsFile /dir/dep-known_hosts\n"))
+				Expect(config).ToNot(ContainSubstring("dep-web"))
+
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{{
+					boshtbl.NewValueString("dep-db-0"),
+					boshtbl.NewValueString("db/db-id"),
+					boshtbl.NewValueString("10.0.0.1"),
+				}}))
+
+				Expect(ui.Said).To(Equal([]string{
+					"Use 'ssh -F /dir/dep-ssh_config <alias>' to connect until '2017-01-02T16:04:05Z'",
+				}))
+			})
+
+			It("uses gateway flags", func() {
+				opts.GatewayFlags.Username = "gw-user"
+				opts.GatewayFlags.Host = "gw-host"
+
+				Expect(act()).ToNot(HaveOccurred())
+
+				config, err := fs.ReadFileString("/dir/dep-ssh_config")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config).To(ContainSubstring("  ProxyJump gw-user@gw-host\n"))
+			})
+
+			It("writes known hosts with pinned host keys", func() {
+				opts.GatewayFlags.KnownHostsPath = "/home/.bosh/known_hosts/env"
+
+				Expect(act()).ToNot(HaveOccurred())
+
+				pinned, err := fs.ReadFileString("/home/.bosh/known_hosts/env")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pinned).To(Equal("10.0.0.1 ssh-rsa key\n"))
+			})
+
+			It("returns error if host key does not match pinned host key", func() {
+				opts.GatewayFlags.KnownHostsPath = "/home/.bosh/known_hosts/env"
+				fs.WriteFileString("/home/.bosh/known_hosts/env", "10.0.0.1 ssh-rsa other-key\n")
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("to match previously pinned host key"))
+
+				Expect(fs.FileExists("/dir/dep-ssh_config")).To(BeFalse())
+			})
+
+			It("returns error if session does not include instances", func() {
+				opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("other", "")
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected active SSH session for deployment 'dep' to include instances 'other'; stop it first"))
+			})
+
+			It("creates identity file only readable by current user before writing private key", func() {
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(fs.RenameOldPaths).To(ContainElement("/dir/dep-identity.tmp"))
+				Expect(fs.RenameNewPaths).To(ContainElement("/dir/dep-identity"))
+
+				stat := fs.GetFileTestStat("/dir/dep-identity")
+				Expect(stat.FileMode).To(Equal(os.FileMode(0600)))
+				Expect(stat.Flags).To(Equal(os.O_CREATE | os.O_EXCL | os.O_WRONLY))
+				Expect(fs.FileExists("/dir/dep-identity.tmp")).To(BeFalse())
+			})
+
+			It("returns error if writing files fails", func() {
+				fs.OpenFileErr = errors.New("fake-err")
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(fs.FileExists("/dir/dep-identity")).To(BeFalse())
+			})
+
+			It("removes partially written file if it cannot be renamed", func() {
+				fs.RenameError = errors.New("fake-err")
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(fs.FileExists("/dir/dep-identity.tmp")).To(BeFalse())
+				Expect(fs.FileExists("/dir/dep-identity")).To(BeFalse())
+			})
+		})
+
+		Context("when SSH session is not active", func() {
+			BeforeEach(func() {
+				deployment.SetUpSSHReturns(boshdir.SSHResult{
+					Hosts: []boshdir.Host{{Job: "db", IndexOrID: "db-id", Username: "user", Host: "10.0.0.1"}},
+				}, nil)
+			})
+
+			It("starts SSH session so that credentials are kept until it's stopped", func() {
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(1))
+				Expect(deployment.CleanUpSSHCallCount()).To(Equal(0))
+
+				slug, _ := deployment.SetUpSSHArgsForCall(0)
+				Expect(slug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "")))
+
+				Expect(sessions.SaveCallCount()).To(Equal(1))
+
+				session := sessions.SaveArgsForCall(0)
+				Expect(session.ExpiresAt).To(Equal(now.Add(24 * time.Hour)))
+
+				identity, err := fs.ReadFileString("/dir/dep-identity")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(identity).To(Equal(session.PrivateKey))
+
+				config, err := fs.ReadFileString("/dir/dep-ssh_config")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config).To(ContainSubstring("Host dep-db-db-id\n"))
+			})
+
+			It("returns error if setting up SSH access fails", func() {
+				deployment.SetUpSSHReturns(boshdir.SSHResult{}, errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(fs.FileExists("/dir/dep-ssh_config")).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+type SSHSessionCmd struct {
+	deployment  boshdir.Deployment
+	sessions    SSHSessions
+	uuidGen     boshuuid.Generator
+	timeService clock.Clock
+	ui          boshui.UI
+}
+
+func NewSSHSessionCmd(
+	deployment boshdir.Deployment,
+	sessions SSHSessions,
+	uuidGen boshuuid.Generator,
+	timeService clock.Clock,
+	ui boshui.UI,
+) SSHSessionCmd {
+	return SSHSessionCmd{
+		deployment:  deployment,
+		sessions:    sessions,
+		uuidGen:     uuidGen,
+		timeService: timeService,
+		ui:          ui,
+	}
+}
+
+func (c SSHSessionCmd) Start(opts SSHSessionStartOpts) error {
+	session, err := c.start(opts.Args.Slug, opts.TTL)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintLinef("Started SSH session for %d instance(s) until '%s'",
+		len(session.Result.Hosts), session.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"))
+
+	return nil
+}
+
+func (c SSHSessionCmd) start(slug boshdir.AllOrInstanceGroupOrInstanceSlug, ttl time.Duration) (SSHSession, error) {
+	if ttl <= 0 {
+		return SSHSession{}, bosherr.Error("Expected TTL to be a positive duration")
+	}
+
+	deploymentName := c.deployment.Name()
+
+	session, found, err := c.sessions.Find(deploymentName)
+	if err != nil {
+		return SSHSession{}, err
+	}
+
+	if found {
+		if !session.Expired(c.timeService.Now()) {
+			return SSHSession{}, bosherr.Errorf("Expected no active SSH session for deployment '%s'; stop it first", deploymentName)
+		}
+
+		err := c.cleanUp(session)
+		if err != nil {
+			return SSHSession{}, err
+		}
+	}
+
+	sshOpts, privKey, err := boshdir.NewSSHOpts(c.uuidGen)
+	if err != nil {
+		return SSHSession{}, bosherr.WrapErrorf(err, "Generating SSH options")
+	}
+
+	result, err := c.deployment.SetUpSSH(slug, sshOpts)
+	if err != nil {
+		return SSHSession{}, err
+	}
+
+	session = SSHSession{
+		Deployment: deploymentName,
+
+		InstanceGroup: slug.Name(),
+		IndexOrID:     slug.IndexOrID(),
+
+		Username:   sshOpts.Username,
+		PrivateKey: privKey,
+		Result:     result,
+
+		ExpiresAt: c.timeService.Now().Add(ttl),
+	}
+
+	err = c.sessions.Save(session)
+	if err != nil {
+		_ = c.deployment.CleanUpSSH(slug, sshOpts)
+		return SSHSession{}, err
+	}
+
+	return session, nil
+}
+
+func (c SSHSessionCmd) Stop(_ SSHSessionStopOpts) error {
+	deploymentName := c.deployment.Name()
+
+	session, found, err := c.sessions.Find(deploymentName)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		c.ui.PrintLinef("No SSH session found for deployment '%s'", deploymentName)
+		return nil
+	}
+
+	err = c.cleanUp(session)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintLinef("Stopped SSH session")
+
+	return nil
+}
+
+func (c SSHSessionCmd) cleanUp(session SSHSession) error {
+	err := c.deployment.CleanUpSSH(session.Slug(), session.SSHOpts())
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Cleaning up SSH access")
+	}
+
+	return c.sessions.Delete(session.Deployment)
+}
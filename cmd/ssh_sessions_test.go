@@ -0,0 +1,283 @@
+package cmd_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
+)
+
+var _ = Describe("SSHSession", func() {
+	Describe("ResultFor", func() {
+		var (
+			session SSHSession
+		)
+
+		BeforeEach(func() {
+			session = SSHSession{
+				Result: boshdir.SSHResult{
+					Hosts: []boshdir.Host{
+						{Job: "db", IndexOrID: "db-id", Host: "ip1"},
+						{Job: "web", IndexOrID: "web-id", Host: "ip2"},
+					},
+					GatewayHost: "gw-host",
+				},
+			}
+		})
+
+		It("returns all hosts for all instances", func() {
+			result, found := session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("", ""))
+			Expect(found).To(BeTrue())
+			Expect(result).To(Equal(session.Result))
+		})
+
+		It("returns only matching hosts when session includes all instances", func() {
+			result, found := session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("web", "web-id"))
+			Expect(found).To(BeTrue())
+			Expect(result).To(Equal(boshdir.SSHResult{
+				Hosts:       []boshdir.Host{{Job: "web", IndexOrID: "web-id", Host: "ip2"}},
+				GatewayHost: "gw-host",
+			}))
+		})
+
+		It("returns not found if session was started for other instance group", func() {
+			session.InstanceGroup = "db"
+
+			_, found := session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("", ""))
+			Expect(found).To(BeFalse())
+
+			_, found = session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("web", ""))
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns not found if session was started for other instance", func() {
+			session.InstanceGroup = "db"
+			session.IndexOrID = "db-id"
+
+			_, found := session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", ""))
+			Expect(found).To(BeFalse())
+
+			result, found := session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "db-id"))
+			Expect(found).To(BeTrue())
+			Expect(result.Hosts).To(HaveLen(1))
+		})
+
+		It("returns not found if no hosts match", func() {
+			_, found := session.ResultFor(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "0"))
+			Expect(found).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("FSSSHSessions", func() {
+	var (
+		fs          *fakesys.FakeFileSystem
+		timeService *fakeclock.FakeClock
+		sessions    FSSSHSessions
+		now         time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+
+		fs = fakesys.NewFakeFileSystem()
+		fs.ExpandPathExpanded = "/home/user/.bosh/ssh-sessions.json"
+		timeService = fakeclock.NewFakeClock(now)
+		sessions = NewFSSSHSessions("https://env", fs, timeService)
+	})
+
+	session := func(deployment string, expiresAt time.Time) SSHSession {
+		return SSHSession{
+			Deployment: deployment,
+			Username:   "username",
+			PrivateKey: "private-key",
+			Result:     boshdir.SSHResult{Hosts: []boshdir.Host{{Job: "db", IndexOrID: "id"}}},
+			ExpiresAt:  expiresAt,
+		}
+	}
+
+	It("returns not found if sessions file does not exist", func() {
+		_, found, err := sessions.Find("dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("saves sessions per environment and deployment with restricted permissions", func() {
+		Expect(sessions.Save(session("dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+
+		otherEnvSessions := NewFSSSHSessions("https://other-env", fs, timeService)
+		Expect(otherEnvSessions.Save(session("dep", now.Add(2*time.Hour)))).ToNot(HaveOccurred())
+
+		found, ok, err := sessions.Find("dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(found).To(Equal(session("dep", now.Add(time.Hour))))
+
+		_, ok, err = sessions.Find("other-dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		stat := fs.GetFileTestStat("/home/user/.bosh/ssh-sessions.json")
+		Expect(stat.FileMode).To(Equal(os.FileMode(0600)))
+	})
+
+	It("replaces existing session for the same deployment", func() {
+		Expect(sessions.Save(session("dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+		Expect(sessions.Save(session("dep", now.Add(2*time.Hour)))).ToNot(HaveOccurred())
+
+		found, _, err := sessions.Find("dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found.ExpiresAt).To(Equal(now.Add(2 * time.Hour)))
+	})
+
+	Describe("Active", func() {
+		var (
+			deployment *fakedir.FakeDeployment
+		)
+
+		BeforeEach(func() {
+			deployment = &fakedir.FakeDeployment{
+				NameStub: func() string { return "dep" },
+			}
+		})
+
+		It("returns session that has not expired", func() {
+			Expect(sessions.Save(session("dep", now.Add(time.Minute)))).ToNot(HaveOccurred())
+
+			active, found, err := sessions.Active(deployment)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(active).To(Equal(session("dep", now.Add(time.Minute))))
+
+			Expect(deployment.CleanUpSSHCallCount()).To(Equal(0))
+		})
+
+		It("cleans up and deletes expired session", func() {
+			expired := session("dep", now.Add(time.Minute))
+			expired.InstanceGroup = "db"
+
+			Expect(sessions.Save(expired)).ToNot(HaveOccurred())
+			Expect(sessions.Save(session("other-dep", now.Add(time.Minute)))).ToNot(HaveOccurred())
+
+			timeService.Increment(time.Minute)
+
+			_, found, err := sessions.Active(deployment)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(deployment.CleanUpSSHCallCount()).To(Equal(1))
+			slug, sshOpts := deployment.CleanUpSSHArgsForCall(0)
+			Expect(slug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("db", "")))
+			Expect(sshOpts).To(Equal(boshdir.SSHOpts{Username: "username"}))
+
+			_, found, err = sessions.Find("dep")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			_, found, err = sessions.Find("other-dep")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
+		It("keeps expired session if it cannot be cleaned up", func() {
+			Expect(sessions.Save(session("dep", now))).ToNot(HaveOccurred())
+
+			deployment.CleanUpSSHReturns(errors.New("fake-err"))
+
+			_, _, err := sessions.Active(deployment)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Cleaning up expired SSH session for deployment 'dep'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+			_, found, err := sessions.Find("dep")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
+		It("returns not found without cleaning up if there is no session", func() {
+			_, found, err := sessions.Active(deployment)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(deployment.CleanUpSSHCallCount()).To(Equal(0))
+		})
+	})
+
+	It("deletes session", func() {
+		Expect(sessions.Save(session("dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+		Expect(sessions.Save(session("other-dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+
+		Expect(sessions.Delete("dep")).ToNot(HaveOccurred())
+
+		_, found, err := sessions.Find("dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		_, found, err = sessions.Find("other-dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	It("returns error if sessions file cannot be parsed", func() {
+		Expect(fs.WriteFileString("/home/user/.bosh/ssh-sessions.json", "-")).ToNot(HaveOccurred())
+
+		_, _, err := sessions.Find("dep")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling SSH sessions"))
+	})
+
+	It("creates sessions file with restricted permissions instead of changing them after writing", func() {
+		Expect(sessions.Save(session("dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+
+		Expect(fs.ChmodCallCount).To(Equal(0))
+		Expect(fs.FileExists("/home/user/.bosh/ssh-sessions.json.tmp")).To(BeFalse())
+	})
+
+	It("returns error if writing sessions file fails", func() {
+		fs.OpenFileErr = errors.New("fake-err")
+
+		err := sessions.Save(session("dep", now))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("fake-err"))
+	})
+
+	It("keeps previous sessions file if closing written file fails", func() {
+		Expect(sessions.Save(session("dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+
+		file := fakesys.NewFakeFile("/home/user/.bosh/ssh-sessions.json.tmp", fs)
+		file.CloseErr = errors.New("fake-err")
+		fs.RegisterOpenFile("/home/user/.bosh/ssh-sessions.json.tmp", file)
+
+		err := sessions.Save(session("dep", now.Add(2*time.Hour)))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+		found, _, err := sessions.Find("dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found.ExpiresAt).To(Equal(now.Add(time.Hour)))
+		Expect(fs.FileExists("/home/user/.bosh/ssh-sessions.json.tmp")).To(BeFalse())
+	})
+
+	It("keeps previous sessions file if replacing it fails", func() {
+		Expect(sessions.Save(session("dep", now.Add(time.Hour)))).ToNot(HaveOccurred())
+
+		fs.RenameError = errors.New("fake-err")
+
+		err := sessions.Save(session("dep", now.Add(2*time.Hour)))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+		found, _, err := sessions.Find("dep")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found.ExpiresAt).To(Equal(now.Add(time.Hour)))
+		Expect(fs.FileExists("/home/user/.bosh/ssh-sessions.json.tmp")).To(BeFalse())
+	})
+})
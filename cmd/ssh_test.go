@@ -2,12 +2,14 @@ package cmd_test
 
 import (
 	"errors"
+	"time"
 
 	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	. "github.com/cloudfoundry/bosh-cli/cmd"
+	fakecmd "github.com/cloudfoundry/bosh-cli/cmd/cmdfakes"
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
 	boshssh "github.com/cloudfoundry/bosh-cli/ssh"
@@ -18,6 +20,7 @@ import (
 var _ = Describe("SSHCmd", func() {
 	var (
 		deployment       *fakedir.FakeDeployment
+		sessions         *fakecmd.FakeSSHSessions
 		uuidGen          *fakeuuid.FakeGenerator
 		intSSHRunner     *fakessh.FakeRunner
 		nonIntSSHRunner  *fakessh.FakeRunner
@@ -27,14 +30,17 @@ var _ = Describe("SSHCmd", func() {
 	)
 
 	BeforeEach(func() {
-		deployment = &fakedir.FakeDeployment{}
+		deployment = &fakedir.FakeDeployment{
+			NameStub: func() string { return "dep" },
+		}
+		sessions = &fakecmd.FakeSSHSessions{}
 		uuidGen = &fakeuuid.FakeGenerator{}
 		intSSHRunner = &fakessh.FakeRunner{}
 		nonIntSSHRunner = &fakessh.FakeRunner{}
 		resultsSSHRunner = &fakessh.FakeRunner{}
 		ui = &fakeui.FakeUI{}
 		command = NewSSHCmd(
-			deployment, uuidGen, intSSHRunner, nonIntSSHRunner, resultsSSHRunner, ui)
+			deployment, sessions, uuidGen, intSSHRunner, nonIntSSHRunner, resultsSSHRunner, ui)
 	})
 
 	Describe("Run", func() {
@@ -118,6 +124,7 @@ var _ = Describe("SSHCmd", func() {
 					opts.GatewayFlags.Host = "gw-host"
 					opts.GatewayFlags.PrivateKeyPath = "gw-private-key"
 					opts.GatewayFlags.SOCKS5Proxy = "socks5"
+					opts.GatewayFlags.Native = true
 
 					Expect(act()).ToNot(HaveOccurred())
 
@@ -131,10 +138,36 @@ var _ = Describe("SSHCmd", func() {
 					Expect(runConnOpts.GatewayHost).To(Equal("gw-host"))
 					Expect(runConnOpts.GatewayPrivateKeyPath).To(Equal("gw-private-key"))
 					Expect(runConnOpts.SOCKS5Proxy).To(Equal("socks5"))
+					Expect(runConnOpts.Native).To(BeTrue())
 					Expect(runResult).To(Equal(boshdir.SSHResult{Hosts: []boshdir.Host{{Host: "ip1"}}}))
 					Expect(runCommand).To(Equal([]string{"cmd", "arg1"}))
 				})
 
+				It("runs non-interactive SSH session with rolling execution options", func() {
+					opts.MaxInFlight = 5
+					opts.FailFast = true
+					opts.Timeout = 60 * time.Second
+
+					Expect(act()).ToNot(HaveOccurred())
+
+					Expect((*runner).RunCallCount()).To(Equal(1))
+
+					runConnOpts, _, _ := (*runner).RunArgsForCall(0)
+					Expect(runConnOpts.MaxInFlight).To(Equal(5))
+					Expect(runConnOpts.FailFast).To(BeTrue())
+					Expect(runConnOpts.Timeout).To(Equal(60 * time.Second))
+				})
+
+				It("returns error if max in flight is negative", func() {
+					opts.MaxInFlight = -1
+
+					err := act()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("Expected max in flight to be a positive number"))
+
+					Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+				})
+
 				It("returns error if non-interactive SSH session errors", func() {
 					(*runner).RunReturns(errors.New("fake-err"))
 					err := act()
@@ -173,6 +206,16 @@ var _ = Describe("SSHCmd", func() {
 					Expect(sshOpts).To(Equal(setupSSHOpts))
 				})
 
+				It("returns error if rolling execution options are given", func() {
+					opts.FailFast = true
+
+					err := act()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("require a command"))
+
+					Expect(intSSHRunner.RunCallCount()).To(Equal(0))
+				})
+
 				It("runs only interactive SSH", func() {
 					Expect(act()).ToNot(HaveOccurred())
 					Expect(nonIntSSHRunner.RunCallCount()).To(Equal(0))
@@ -212,6 +255,30 @@ var _ = Describe("SSHCmd", func() {
 					Expect(runCommand).To(BeNil())
 				})
 
+				It("records interactive SSH session if recording directory is given", func() {
+					opts.Record = "/recordings"
+					opts.Recording = boshssh.RecordingOpts{Environment: "https://director:25555", LocalUser: "local-user"}
+
+					Expect(act()).ToNot(HaveOccurred())
+
+					runConnOpts, _, _ := intSSHRunner.RunArgsForCall(0)
+					Expect(runConnOpts.Recording).To(Equal(boshssh.RecordingOpts{
+						Dir:         "/recordings",
+						Environment: "https://director:25555",
+						Deployment:  "dep",
+						LocalUser:   "local-user",
+					}))
+				})
+
+				It("does not record interactive SSH session without recording directory", func() {
+					opts.Recording = boshssh.RecordingOpts{Environment: "https://director:25555"}
+
+					Expect(act()).ToNot(HaveOccurred())
+
+					runConnOpts, _, _ := intSSHRunner.RunArgsForCall(0)
+					Expect(runConnOpts.Recording).To(Equal(boshssh.RecordingOpts{}))
+				})
+
 				It("returns error if interactive SSH session errors", func() {
 					intSSHRunner.RunReturns(errors.New("fake-err"))
 					err := act()
@@ -263,5 +330,64 @@ var _ = Describe("SSHCmd", func() {
 				})
 			})
 		})
+
+		Context("when JSON output is requested", func() {
+			BeforeEach(func() {
+				ui.Interactive = true
+				opts.JSON = true
+			})
+
+			itRunsNonInteractiveSSHWhenCommandIsGiven(&resultsSSHRunner)
+
+			Context("when command is not provided", func() {
+				It("returns an error since command is required", func() {
+					Expect(act()).To(Equal(errors.New("Non-interactive SSH requires non-empty command")))
+				})
+			})
+		})
+
+		Context("when SSH session is active", func() {
+			BeforeEach(func() {
+				ui.Interactive = true
+				opts.Command = []string{"cmd"}
+				opts.GatewayFlags.Host = "gw-host"
+
+				sessions.ActiveReturns(SSHSession{
+					Deployment:    "dep",
+					InstanceGroup: "job-name",
+					Username:      "session-username",
+					PrivateKey:    "session-private-key",
+					Result: boshdir.SSHResult{
+						Hosts: []boshdir.Host{{Job: "job-name", IndexOrID: "id", Host: "ip1"}},
+					},
+				}, true, nil)
+			})
+
+			It("reuses SSH session instead of setting up SSH access", func() {
+				// Key pair is not generated for reused session
+				uuidGen.GenerateError = errors.New("fake-err")
+
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(sessions.ActiveArgsForCall(0)).To(Equal(deployment))
+
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(0))
+				Expect(deployment.CleanUpSSHCallCount()).To(Equal(0))
+
+				connOpts, result, _ := nonIntSSHRunner.RunArgsForCall(0)
+				Expect(connOpts.PrivateKey).To(Equal("session-private-key"))
+				Expect(connOpts.GatewayHost).To(Equal("gw-host"))
+				Expect(result.Hosts).To(Equal([]boshdir.Host{{Job: "job-name", IndexOrID: "id", Host: "ip1"}}))
+			})
+
+			It("sets up SSH access if session was started for other instances", func() {
+				opts.Args.Slug = boshdir.NewAllOrInstanceGroupOrInstanceSlug("", "")
+
+				Expect(act()).ToNot(HaveOccurred())
+
+				Expect(deployment.SetUpSSHCallCount()).To(Equal(1))
+				Expect(deployment.CleanUpSSHCallCount()).To(Equal(1))
+			})
+		})
 	})
 })
@@ -15,14 +15,22 @@ func NewStartCmd(ui boshui.UI, deployment boshdir.Deployment) StartCmd {
 }
 
 func (c StartCmd) Run(opts StartOpts) error {
-	err := c.ui.AskForConfirmation()
+	overrides := UpdateOverrides{Canaries: opts.Canaries, MaxInFlight: opts.MaxInFlight}
+
+	manifest, err := overrides.DeploymentManifest(c.deployment)
+	if err != nil {
+		return err
+	}
+
+	err = c.ui.AskForConfirmation()
 	if err != nil {
 		return err
 	}
 
 	startOpts := boshdir.StartOpts{
-		Canaries:    opts.Canaries,
-		MaxInFlight: opts.MaxInFlight,
+		Canaries:    overrides.GlobalCanaries(),
+		MaxInFlight: overrides.GlobalMaxInFlight(),
+		Manifest:    manifest,
 	}
 	return c.deployment.Start(opts.Args.Slug, startOpts)
 }
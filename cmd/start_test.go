@@ -68,7 +68,7 @@ var _ = Describe("StartCmd", func() {
 		})
 
 		It("can set canaries", func() {
-			opts.Canaries = "100%"
+			opts.Canaries = UpdateOverrideArgs{{Value: "100%"}}
 
 			err := act()
 			Expect(err).ToNot(HaveOccurred())
@@ -80,7 +80,7 @@ var _ = Describe("StartCmd", func() {
 		})
 
 		It("can set max_in_flight", func() {
-			opts.MaxInFlight = "5"
+			opts.MaxInFlight = UpdateOverrideArgs{{Value: "5"}}
 
 			err := act()
 			Expect(err).ToNot(HaveOccurred())
@@ -90,5 +90,42 @@ var _ = Describe("StartCmd", func() {
 			_, opts := deployment.StartArgsForCall(0)
 			Expect(opts.MaxInFlight).To(Equal("5"))
 		})
+
+		It("injects instance group specific canaries and max_in_flight into current manifest", func() {
+			deployment.ManifestReturns("instance_groups:\n- name: ig1\n- name: ig2\n", nil)
+
+			opts.Canaries = UpdateOverrideArgs{{InstanceGroup: "ig1", Value: "1"}}
+			opts.MaxInFlight = UpdateOverrideArgs{{Value: "2"}, {InstanceGroup: "ig2", Value: "20%"}}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deployment.StartCallCount()).To(Equal(1))
+
+			_, opts := deployment.StartArgsForCall(0)
+			Expect(opts.Canaries).To(Equal(""))
+			Expect(opts.MaxInFlight).To(Equal(""))
+			Expect(string(opts.Manifest)).To(Equal(`instance_groups:
+- name: ig1
+  update:
+    canaries: "1"
+    max_in_flight: "2"
+- name: ig2
+  update:
+    max_in_flight: 20%
+`))
+		})
+
+		It("returns error if fetching manifest for instance group specific values fails", func() {
+			deployment.ManifestReturns("", errors.New("fake-err"))
+
+			opts.Canaries = UpdateOverrideArgs{{InstanceGroup: "ig1", Value: "1"}}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+			Expect(deployment.StartCallCount()).To(Equal(0))
+		})
 	})
 })
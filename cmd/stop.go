@@ -15,7 +15,14 @@ func NewStopCmd(ui boshui.UI, deployment boshdir.Deployment) StopCmd {
 }
 
 func (c StopCmd) Run(opts StopOpts) error {
-	err := c.ui.AskForConfirmation()
+	overrides := UpdateOverrides{Canaries: opts.Canaries, MaxInFlight: opts.MaxInFlight}
+
+	manifest, err := overrides.DeploymentManifest(c.deployment)
+	if err != nil {
+		return err
+	}
+
+	err = c.ui.AskForConfirmation()
 	if err != nil {
 		return err
 	}
@@ -23,8 +30,9 @@ func (c StopCmd) Run(opts StopOpts) error {
 	stopOpts := boshdir.StopOpts{
 		SkipDrain:   opts.SkipDrain,
 		Force:       opts.Force,
-		Canaries:    opts.Canaries,
-		MaxInFlight: opts.MaxInFlight,
+		Canaries:    overrides.GlobalCanaries(),
+		MaxInFlight: overrides.GlobalMaxInFlight(),
+		Manifest:    manifest,
 		Hard:        opts.Hard,
 	}
 	return c.deployment.Stop(opts.Args.Slug, stopOpts)
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+type TestOpsCmd struct {
+	fs boshsys.FileSystem
+	ui boshui.UI
+}
+
+// TestOpsMatrix declares combinations of ops files that are expected
+// to apply together and optionally values expected in the result
+type TestOpsMatrix struct {
+	Combinations []TestOpsCombination `yaml:"combinations"`
+}
+
+type TestOpsCombination struct {
+	Name     string               `yaml:"name"`
+	OpsFiles []string             `yaml:"ops_files"`
+	Expect   []TestOpsExpectation `yaml:"expect"`
+}
+
+type TestOpsExpectation struct {
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value"`
+}
+
+type testOpsResult struct {
+	Name     string
+	OpsFiles []string
+	Err      error
+}
+
+func NewTestOpsCmd(fs boshsys.FileSystem, ui boshui.UI) TestOpsCmd {
+	return TestOpsCmd{fs: fs, ui: ui}
+}
+
+func (c TestOpsCmd) Run(opts TestOpsOpts) error {
+	var matrix TestOpsMatrix
+
+	if len(opts.Matrix.Bytes) > 0 {
+		err := yaml.Unmarshal(opts.Matrix.Bytes, &matrix)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Deserializing ops files matrix")
+		}
+	}
+
+	paths, err := c.opsFilePaths(opts.OpsDir.Path)
+	if err != nil {
+		return err
+	}
+
+	tpl := boshtpl.NewTemplate(opts.Base.Bytes)
+	vars := opts.VarFlags.AsVariables()
+
+	var results []testOpsResult
+
+	for _, path := range paths {
+		name := c.relativeName(opts.OpsDir.Path, path)
+
+		ops, err := c.loadOps(path)
+		if err == nil {
+			_, err = tpl.Evaluate(vars, ops, boshtpl.EvaluateOpts{})
+		}
+
+		results = append(results, testOpsResult{Name: name, OpsFiles: []string{name}, Err: err})
+	}
+
+	for i, combination := range matrix.Combinations {
+		name := combination.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("combination #%d", i)
+		}
+
+		results = append(results, testOpsResult{
+			Name:     name,
+			OpsFiles: combination.OpsFiles,
+			Err:      c.runCombination(tpl, vars, opts.OpsDir.Path, combination),
+		})
+	}
+
+	return c.report(results)
+}
+
+func (c TestOpsCmd) runCombination(tpl boshtpl.Template, vars boshtpl.Variables, dir string, combination TestOpsCombination) error {
+	if len(combination.OpsFiles) == 0 {
+		return bosherr.Errorf("Expected combination to include at least one ops file")
+	}
+
+	var ops patch.Ops
+
+	for _, name := range combination.OpsFiles {
+		fileOps, err := c.loadOps(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		ops = append(ops, fileOps)
+	}
+
+	bytes, err := tpl.Evaluate(vars, ops, boshtpl.EvaluateOpts{})
+	if err != nil {
+		return err
+	}
+
+	var result interface{}
+
+	err = yaml.Unmarshal(bytes, &result)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Deserializing result")
+	}
+
+	for _, expectation := range combination.Expect {
+		err := c.checkExpectation(result, expectation)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c TestOpsCmd) checkExpectation(result interface{}, expectation TestOpsExpectation) error {
+	path, err := patch.NewPointerFromString(expectation.Path)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing expected path '%s'", expectation.Path)
+	}
+
+	actual, err := patch.FindOp{Path: path}.Apply(result)
+	if err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(actual, expectation.Value) {
+		return bosherr.Errorf("Expected path '%s' to have value '%s' but was '%s'",
+			expectation.Path, c.inlineYAML(expectation.Value), c.inlineYAML(actual))
+	}
+
+	return nil
+}
+
+func (c TestOpsCmd) report(results []testOpsResult) error {
+	table := boshtbl.Table{
+		Content: "ops file tests",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Test"),
+			boshtbl.NewHeader("Ops Files"),
+			boshtbl.NewHeader("Result"),
+		},
+	}
+
+	var failed int
+
+	for _, result := range results {
+		var value boshtbl.Value = boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false)
+
+		if result.Err != nil {
+			failed++
+			value = boshtbl.NewValueFmt(boshtbl.NewValueString(result.Err.Error()), true)
+		}
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(result.Name),
+			boshtbl.NewValueStrings(result.OpsFiles),
+			value,
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	if failed > 0 {
+		return bosherr.Errorf("Expected %d ops file test(s) to pass but %d failed", len(results), failed)
+	}
+
+	return nil
+}
+
+// opsFilePaths includes ops files in subdirectories (e.g. operations/experimental/)
+func (c TestOpsCmd) opsFilePaths(dir string) ([]string, error) {
+	var paths []string
+
+	err := c.fs.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && (filepath.Ext(path) == ".yml" || filepath.Ext(path) == ".yaml") {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Listing ops files in '%s'", dir)
+	}
+
+	if len(paths) == 0 {
+		return nil, bosherr.Errorf("Expected to find at least one ops file in '%s'", dir)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func (c TestOpsCmd) loadOps(path string) (patch.Ops, error) {
+	arg := OpsFileArg{FS: c.fs}
+
+	err := arg.UnmarshalFlag(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return arg.Ops, nil
+}
+
+func (c TestOpsCmd) relativeName(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+func (c TestOpsCmd) inlineYAML(val interface{}) string {
+	bytes, err := yaml.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+
+	return strings.TrimSpace(string(bytes))
+}
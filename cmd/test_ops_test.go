@@ -0,0 +1,247 @@
+package cmd_test
+
+import (
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("TestOpsCmd", func() {
+	var (
+		fs      *fakesys.FakeFileSystem
+		ui      *fakeui.FakeUI
+		command TestOpsCmd
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		command = NewTestOpsCmd(fs, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts TestOpsOpts
+		)
+
+		BeforeEach(func() {
+			fs.WriteFileString("/ops/scale.yml", `
+- type: replace
+  path: /instance_groups/name=web/instances
+  value: ((web_instances))
+`)
+			fs.WriteFileString("/ops/rename.yml", `
+- type: replace
+  path: /name
+  value: renamed
+`)
+			fs.WriteFileString("/ops/tls.yaml", `
+- type: replace
+  path: /instance_groups/name=web/properties?/tls
+  value: true
+`)
+
+			opts = TestOpsOpts{
+				Base:   FileBytesArg{Bytes: []byte("name: dep\ninstance_groups:\n- name: web\n  instances: 1\n")},
+				OpsDir: DirOrCWDArg{Path: "/ops"},
+			}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("applies each ops file in sorted order and reports results", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table.Content).To(Equal("ops file tests"))
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("rename.yml"),
+					boshtbl.NewValueStrings([]string{"rename.yml"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false),
+				},
+				{
+					boshtbl.NewValueString("scale.yml"),
+					boshtbl.NewValueStrings([]string{"scale.yml"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false),
+				},
+				{
+					boshtbl.NewValueString("tls.yaml"),
+					boshtbl.NewValueStrings([]string{"tls.yaml"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false),
+				},
+			}))
+		})
+
+		It("includes ops files in subdirectories", func() {
+			fs.WriteFileString("/ops/experimental/scale-down.yml", `
+- type: replace
+  path: /instance_groups/name=web/instances
+  value: 0
+`)
+			fs.WriteFileString("/ops/experimental/README.md", "not an ops file")
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table.Rows).To(HaveLen(4))
+			Expect(ui.Table.Rows[0]).To(Equal([]boshtbl.Value{
+				boshtbl.NewValueString("experimental/scale-down.yml"),
+				boshtbl.NewValueStrings([]string{"experimental/scale-down.yml"}),
+				boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false),
+			}))
+		})
+
+		It("reports nested ops files that refer to missing paths", func() {
+			fs.WriteFileString("/ops/experimental/missing.yml", `
+- type: replace
+  path: /instance_groups/name=missing/instances
+  value: 0
+`)
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected 4 ops file test(s) to pass but 1 failed"))
+
+			result := ui.Table.Rows[0][2].(boshtbl.ValueFmt)
+			Expect(result.Error).To(BeTrue())
+		})
+
+		It("returns error if ops directory cannot be listed", func() {
+			fs.WalkErr = errors.New("fake-err")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Listing ops files in '/ops'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("reports ops files that refer to missing paths and returns error", func() {
+			opts.Base = FileBytesArg{Bytes: []byte("name: dep\ninstance_groups:\n- name: db\n")}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected 3 ops file test(s) to pass but 2 failed"))
+
+			Expect(ui.Table.Rows).To(HaveLen(3))
+			Expect(ui.Table.Rows[0][2]).To(Equal(boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false)))
+
+			result := ui.Table.Rows[1][2].(boshtbl.ValueFmt)
+			Expect(result.Error).To(BeTrue())
+			Expect(result.V.String()).To(ContainSubstring("name=web"))
+		})
+
+		It("reports ops files that cannot be parsed", func() {
+			fs.WriteFileString("/ops/rename.yml", "- type: unknown\n")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+
+			result := ui.Table.Rows[0][2].(boshtbl.ValueFmt)
+			Expect(result.Error).To(BeTrue())
+			Expect(result.V.String()).To(ContainSubstring("Building ops"))
+		})
+
+		It("returns error if ops directory does not include any ops files", func() {
+			opts.OpsDir = DirOrCWDArg{Path: "/empty"}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find at least one ops file in '/empty'"))
+			Expect(ui.Tables).To(BeEmpty())
+		})
+
+		Context("when matrix is given", func() {
+			BeforeEach(func() {
+				opts.VarKVs = []boshtpl.VarKV{{Name: "web_instances", Value: 3}}
+			})
+
+			It("applies combinations of ops files and checks expected values", func() {
+				opts.Matrix = FileBytesArg{Bytes: []byte(`
+combinations:
+- name: scaled-tls
+  ops_files: [scale.yml, tls.yaml]
+  expect:
+  - path: /instance_groups/name=web/instances
+    value: 3
+  - path: /instance_groups/name=web/properties
+    value: {tls: true}
+- ops_files: [rename.yml]
+`)}
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Rows).To(HaveLen(5))
+				Expect(ui.Table.Rows[3]).To(Equal([]boshtbl.Value{
+					boshtbl.NewValueString("scaled-tls"),
+					boshtbl.NewValueStrings([]string{"scale.yml", "tls.yaml"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false),
+				}))
+				Expect(ui.Table.Rows[4]).To(Equal([]boshtbl.Value{
+					boshtbl.NewValueString("combination #1"),
+					boshtbl.NewValueStrings([]string{"rename.yml"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("ok"), false),
+				}))
+			})
+
+			It("reports combinations whose result does not have expected values", func() {
+				opts.Matrix = FileBytesArg{Bytes: []byte(`
+combinations:
+- name: scaled
+  ops_files: [scale.yml]
+  expect:
+  - path: /instance_groups/name=web/instances
+    value: 5
+`)}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected 4 ops file test(s) to pass but 1 failed"))
+
+				Expect(ui.Table.Rows[3][2]).To(Equal(boshtbl.NewValueFmt(boshtbl.NewValueString(
+					"Expected path '/instance_groups/name=web/instances' to have value '5' but was '3'"), true)))
+			})
+
+			It("reports combinations that refer to missing ops files", func() {
+				opts.Matrix = FileBytesArg{Bytes: []byte(`
+combinations:
+- name: missing
+  ops_files: [missing.yml]
+`)}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+
+				result := ui.Table.Rows[3][2].(boshtbl.ValueFmt)
+				Expect(result.Error).To(BeTrue())
+				Expect(result.V.String()).To(ContainSubstring("Reading ops file '/ops/missing.yml'"))
+			})
+
+			It("reports combinations without ops files", func() {
+				opts.Matrix = FileBytesArg{Bytes: []byte("combinations:\n- name: empty\n")}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+
+				Expect(ui.Table.Rows[3][2]).To(Equal(boshtbl.NewValueFmt(boshtbl.NewValueString(
+					"Expected combination to include at least one ops file"), true)))
+			})
+
+			It("returns error if matrix cannot be deserialized", func() {
+				opts.Matrix = FileBytesArg{Bytes: []byte("-")}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Deserializing ops files matrix"))
+			})
+		})
+	})
+})
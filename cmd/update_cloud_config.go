@@ -5,6 +5,7 @@ import (
 
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshschema "github.com/cloudfoundry/bosh-cli/schema"
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
 )
 
@@ -25,6 +26,20 @@ func (c UpdateCloudConfigCmd) Run(opts UpdateCloudConfigOpts) error {
 		return bosherr.WrapErrorf(err, "Evaluating cloud config")
 	}
 
+	schemaCheck := SchemaCheck{
+		manifestType: boshschema.TypeCloud,
+		custom:       opts.Schema,
+		strict:       opts.StrictSchema,
+		advisory:     true,
+		skipBuiltin:  opts.SkipSchema,
+		ui:           c.ui,
+	}
+
+	err = schemaCheck.Run(bytes, false)
+	if err != nil {
+		return err
+	}
+
 	cloudConfigDiff, err := c.director.DiffCloudConfig(bytes)
 	if err != nil {
 		return err
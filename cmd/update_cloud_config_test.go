@@ -12,6 +12,7 @@ import (
 	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
 	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 var _ = Describe("UpdateCloudConfigCmd", func() {
@@ -83,6 +84,41 @@ var _ = Describe("UpdateCloudConfigCmd", func() {
 			Expect(bytes).To(Equal([]byte("name1: val1-from-kv\nname2: val2-from-file\nxyz: val\n")))
 		})
 
+		It("warns about cloud config not matching built-in schema but still updates it", func() {
+			opts.Args.CloudConfig = FileBytesArg{Bytes: []byte("vm_type: []")}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table.Rows).To(HaveLen(1))
+			Expect(ui.Table.Rows[0][1]).To(Equal(boshtbl.NewValueString("warning")))
+			Expect(director.UpdateCloudConfigCallCount()).To(Equal(1))
+		})
+
+		It("does not update cloud config if it does not match built-in schema when schema check is strict", func() {
+			opts.StrictSchema = true
+			opts.Args.CloudConfig = FileBytesArg{Bytes: []byte("vm_type: []")}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'cloud' schema but found 1 violation(s)"))
+
+			Expect(ui.Table.Rows).To(HaveLen(1))
+			Expect(director.DiffCloudConfigCallCount()).To(Equal(0))
+			Expect(director.UpdateCloudConfigCallCount()).To(Equal(0))
+		})
+
+		It("does not check cloud config against built-in schema if schema check is skipped", func() {
+			opts.SkipSchema = true
+			opts.Args.CloudConfig = FileBytesArg{Bytes: []byte("vm_type: []")}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Tables).To(BeEmpty())
+			Expect(director.UpdateCloudConfigCallCount()).To(Equal(1))
+		})
+
 		It("returns an error if diffing failed", func() {
 			director.DiffCloudConfigReturns(boshdir.ConfigDiff{}, errors.New("Fetching diff result"))
 
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+type UpdateOverrideArgs []UpdateOverrideArg
+
+// UpdateOverrideArg is either a deployment-wide value (e.g. '2', '20%')
+// or a value scoped to a single instance group (e.g. 'ig1=2').
+type UpdateOverrideArg struct {
+	InstanceGroup string
+	Value         string
+}
+
+func (a *UpdateOverrideArg) UnmarshalFlag(data string) error {
+	pieces := strings.SplitN(data, "=", 2)
+
+	if len(pieces) == 1 {
+		if len(pieces[0]) == 0 {
+			return bosherr.Error("Expected value to be non-empty")
+		}
+		a.Value = pieces[0]
+		return nil
+	}
+
+	if len(pieces[0]) == 0 {
+		return bosherr.Errorf("Expected instance group name in '%s' to be non-empty", data)
+	}
+
+	if len(pieces[1]) == 0 {
+		return bosherr.Errorf("Expected value in '%s' to be non-empty", data)
+	}
+
+	a.InstanceGroup = pieces[0]
+	a.Value = pieces[1]
+
+	return nil
+}
+
+// Global returns last specified deployment-wide value
+func (a UpdateOverrideArgs) Global() string {
+	var val string
+
+	for _, arg := range a {
+		if len(arg.InstanceGroup) == 0 {
+			val = arg.Value
+		}
+	}
+
+	return val
+}
+
+func (a UpdateOverrideArgs) HasInstanceGroups() bool {
+	for _, arg := range a {
+		if len(arg.InstanceGroup) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateOverrides combines canaries and max_in_flight overrides.
+// Deployment-wide values are sent to the Director as-is; however
+// Director applies them to all instance groups, hence when any instance group
+// specific value is given all values are injected into manifest update blocks.
+type UpdateOverrides struct {
+	Canaries    UpdateOverrideArgs
+	MaxInFlight UpdateOverrideArgs
+}
+
+func (o UpdateOverrides) IsPerInstanceGroup() bool {
+	return o.Canaries.HasInstanceGroups() || o.MaxInFlight.HasInstanceGroups()
+}
+
+func (o UpdateOverrides) GlobalCanaries() string {
+	if o.Canaries.HasInstanceGroups() {
+		return ""
+	}
+	return o.Canaries.Global()
+}
+
+func (o UpdateOverrides) GlobalMaxInFlight() string {
+	if o.MaxInFlight.HasInstanceGroups() {
+		return ""
+	}
+	return o.MaxInFlight.Global()
+}
+
+func (o UpdateOverrides) Apply(manifestBytes []byte) ([]byte, error) {
+	if !o.IsPerInstanceGroup() {
+		return manifestBytes, nil
+	}
+
+	var manifest interface{}
+
+	err := yaml.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling manifest")
+	}
+
+	var igNames struct {
+		InstanceGroups []struct {
+			Name string
+		} `yaml:"instance_groups"`
+	}
+
+	err = yaml.Unmarshal(manifestBytes, &igNames)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling manifest")
+	}
+
+	var allNames []string
+
+	for _, ig := range igNames.InstanceGroups {
+		allNames = append(allNames, ig.Name)
+	}
+
+	var ops patch.Ops
+
+	ops = append(ops, o.ops("canaries", o.Canaries, allNames)...)
+	ops = append(ops, o.ops("max_in_flight", o.MaxInFlight, allNames)...)
+
+	manifest, err = ops.Apply(manifest)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Overriding instance group update values")
+	}
+
+	manifestBytes, err = yaml.Marshal(manifest)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshalling manifest")
+	}
+
+	return manifestBytes, nil
+}
+
+// DeploymentManifest returns current deployment manifest with injected values
+// or nil when there is nothing to inject so that Director uses its own copy.
+func (o UpdateOverrides) DeploymentManifest(deployment boshdir.Deployment) ([]byte, error) {
+	if !o.IsPerInstanceGroup() {
+		return nil, nil
+	}
+
+	manifest, err := deployment.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	return o.Apply([]byte(manifest))
+}
+
+func (UpdateOverrides) ops(key string, args UpdateOverrideArgs, allNames []string) patch.Ops {
+	var ops patch.Ops
+
+	if !args.HasInstanceGroups() {
+		return ops
+	}
+
+	if global := args.Global(); len(global) > 0 {
+		for _, name := range allNames {
+			ops = append(ops, updateOverrideOp(name, key, global))
+		}
+	}
+
+	for _, arg := range args {
+		if len(arg.InstanceGroup) > 0 {
+			ops = append(ops, updateOverrideOp(arg.InstanceGroup, key, arg.Value))
+		}
+	}
+
+	return ops
+}
+
+func updateOverrideOp(instanceGroup, key, value string) patch.Op {
+	tokens := []patch.Token{
+		patch.RootToken{},
+		patch.KeyToken{Key: "instance_groups"},
+		patch.MatchingIndexToken{Key: "name", Value: instanceGroup},
+		patch.KeyToken{Key: "update", Optional: true},
+		patch.KeyToken{Key: key, Optional: true},
+	}
+
+	return patch.ReplaceOp{Path: patch.NewPointer(tokens), Value: value}
+}
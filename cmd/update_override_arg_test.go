@@ -0,0 +1,106 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("UpdateOverrideArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			arg *UpdateOverrideArg
+		)
+
+		BeforeEach(func() {
+			arg = &UpdateOverrideArg{}
+		})
+
+		It("sets deployment-wide value", func() {
+			err := arg.UnmarshalFlag("20%")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*arg).To(Equal(UpdateOverrideArg{Value: "20%"}))
+		})
+
+		It("sets instance group specific value", func() {
+			err := arg.UnmarshalFlag("ig1=2")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*arg).To(Equal(UpdateOverrideArg{InstanceGroup: "ig1", Value: "2"}))
+		})
+
+		It("returns error if value is empty", func() {
+			err := arg.UnmarshalFlag("")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected value to be non-empty"))
+		})
+
+		It("returns error if instance group name is empty", func() {
+			err := arg.UnmarshalFlag("=2")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected instance group name in '=2' to be non-empty"))
+		})
+
+		It("returns error if instance group value is empty", func() {
+			err := arg.UnmarshalFlag("ig1=")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected value in 'ig1=' to be non-empty"))
+		})
+	})
+})
+
+var _ = Describe("UpdateOverrides", func() {
+	Describe("GlobalCanaries/GlobalMaxInFlight", func() {
+		It("returns last deployment-wide value", func() {
+			overrides := UpdateOverrides{
+				Canaries:    UpdateOverrideArgs{{Value: "1"}, {Value: "2"}},
+				MaxInFlight: UpdateOverrideArgs{{Value: "3"}},
+			}
+			Expect(overrides.GlobalCanaries()).To(Equal("2"))
+			Expect(overrides.GlobalMaxInFlight()).To(Equal("3"))
+		})
+
+		It("returns empty value when instance group specific values are given", func() {
+			overrides := UpdateOverrides{
+				Canaries:    UpdateOverrideArgs{{Value: "1"}, {InstanceGroup: "ig1", Value: "2"}},
+				MaxInFlight: UpdateOverrideArgs{{Value: "3"}},
+			}
+			Expect(overrides.GlobalCanaries()).To(Equal(""))
+			Expect(overrides.GlobalMaxInFlight()).To(Equal("3"))
+		})
+	})
+
+	Describe("Apply", func() {
+		It("returns manifest as is when there are no instance group specific values", func() {
+			overrides := UpdateOverrides{Canaries: UpdateOverrideArgs{{Value: "1"}}}
+
+			bytes, err := overrides.Apply([]byte("name: dep"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes).To(Equal([]byte("name: dep")))
+		})
+
+		It("injects deployment-wide values into all instance groups before specific ones", func() {
+			overrides := UpdateOverrides{
+				Canaries: UpdateOverrideArgs{{InstanceGroup: "ig2", Value: "3"}, {Value: "1"}},
+			}
+
+			bytes, err := overrides.Apply([]byte("instance_groups:\n- name: ig1\n- name: ig2\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(bytes)).To(Equal(`instance_groups:
+- name: ig1
+  update:
+    canaries: "1"
+- name: ig2
+  update:
+    canaries: "3"
+`))
+		})
+
+		It("returns error if manifest cannot be parsed", func() {
+			overrides := UpdateOverrides{Canaries: UpdateOverrideArgs{{InstanceGroup: "ig1", Value: "1"}}}
+
+			_, err := overrides.Apply([]byte("-"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
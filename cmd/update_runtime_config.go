@@ -5,6 +5,7 @@ import (
 
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshschema "github.com/cloudfoundry/bosh-cli/schema"
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
 )
 
@@ -26,6 +27,20 @@ func (c UpdateRuntimeConfigCmd) Run(opts UpdateRuntimeConfigOpts) error {
 		return bosherr.WrapErrorf(err, "Evaluating runtime config")
 	}
 
+	schemaCheck := SchemaCheck{
+		manifestType: boshschema.TypeRuntime,
+		custom:       opts.Schema,
+		strict:       opts.StrictSchema,
+		advisory:     true,
+		skipBuiltin:  opts.SkipSchema,
+		ui:           c.ui,
+	}
+
+	err = schemaCheck.Run(bytes, false)
+	if err != nil {
+		return err
+	}
+
 	configDiff, err := c.director.DiffRuntimeConfig(opts.Name, bytes, opts.NoRedact)
 	if err != nil {
 		return err
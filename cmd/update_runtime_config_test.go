@@ -13,6 +13,7 @@ import (
 	fakedir "github.com/cloudfoundry/bosh-cli/director/directorfakes"
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
 	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 var _ = Describe("UpdateRuntimeConfigCmd", func() {
@@ -153,6 +154,41 @@ releases:
 			Expect(err.Error()).To(ContainSubstring("fake-err"))
 		})
 
+		It("warns about runtime config not matching built-in schema but still updates it", func() {
+			opts.Args.RuntimeConfig = FileBytesArg{Bytes: []byte("name: runtime")}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table.Rows).To(HaveLen(1))
+			Expect(ui.Table.Rows[0][1]).To(Equal(boshtbl.NewValueString("warning")))
+			Expect(director.UpdateRuntimeConfigCallCount()).To(Equal(1))
+		})
+
+		It("does not update runtime config if it does not match built-in schema when schema check is strict", func() {
+			opts.StrictSchema = true
+			opts.Args.RuntimeConfig = FileBytesArg{Bytes: []byte("name: runtime")}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'runtime' schema but found 1 violation(s)"))
+
+			Expect(ui.Table.Rows).To(HaveLen(1))
+			Expect(director.DiffRuntimeConfigCallCount()).To(Equal(0))
+			Expect(director.UpdateRuntimeConfigCallCount()).To(Equal(0))
+		})
+
+		It("does not check runtime config against built-in schema if schema check is skipped", func() {
+			opts.SkipSchema = true
+			opts.Args.RuntimeConfig = FileBytesArg{Bytes: []byte("name: runtime")}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Tables).To(BeEmpty())
+			Expect(director.UpdateRuntimeConfigCallCount()).To(Equal(1))
+		})
+
 		It("returns an error if diffing failed", func() {
 			director.DiffRuntimeConfigReturns(boshdir.ConfigDiff{}, errors.New("Fetching diff result"))
 
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshpolicy "github.com/cloudfoundry/bosh-cli/policy"
+	boshschema "github.com/cloudfoundry/bosh-cli/schema"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+type ValidateManifestCmd struct {
+	ui boshui.UI
+}
+
+func NewValidateManifestCmd(ui boshui.UI) ValidateManifestCmd {
+	return ValidateManifestCmd{ui: ui}
+}
+
+func (c ValidateManifestCmd) Run(opts ValidateManifestOpts) error {
+	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
+
+	bytes, err := tpl.Evaluate(opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp(), boshtpl.EvaluateOpts{})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Evaluating manifest")
+	}
+
+	check := SchemaCheck{
+		manifestType: opts.Type,
+		custom:       opts.Schema,
+		strict:       opts.StrictSchema,
+		ui:           c.ui,
+	}
+
+	return check.Run(bytes, true)
+}
+
+// SchemaCheck reports violations of built-in schema for a manifest type
+// and of an optional custom schema, and fails if there are any errors.
+//
+// Built-in schemas may not know about keys supported by newer Directors,
+// hence unknown keys are only reported as warnings unless check is strict.
+// Advisory checks (e.g. before deploying, since the Director validates
+// manifests anyway) report all built-in schema violations as warnings.
+// Violations of custom schema are always errors.
+type SchemaCheck struct {
+	manifestType string
+	custom       SchemaFileArg
+
+	strict      bool
+	advisory    bool
+	skipBuiltin bool
+
+	ui boshui.UI
+}
+
+type schemaCheckViolation struct {
+	boshschema.Violation
+	Severity string
+}
+
+func (c SchemaCheck) Run(manifest []byte, alwaysPrint bool) error {
+	schema, err := boshschema.NewBuiltinSchema(c.manifestType)
+	if err != nil {
+		return err
+	}
+
+	var violations []schemaCheckViolation
+
+	if !c.skipBuiltin {
+		builtinViolations, err := schema.ValidateBytes(manifest)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Validating manifest")
+		}
+
+		for _, v := range builtinViolations {
+			violations = append(violations, schemaCheckViolation{v, c.builtinSeverity(v)})
+		}
+	}
+
+	if c.custom.IsSet() {
+		customViolations, err := c.custom.Schema.ValidateBytes(manifest)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Validating manifest")
+		}
+
+		for _, v := range customViolations {
+			violations = append(violations, schemaCheckViolation{v, boshpolicy.SeverityError})
+		}
+	}
+
+	if len(violations) > 0 || alwaysPrint {
+		table := boshtbl.Table{
+			Content: "schema violations",
+			Header: []boshtbl.Header{
+				boshtbl.NewHeader("Path"),
+				boshtbl.NewHeader("Severity"),
+				boshtbl.NewHeader("Message"),
+			},
+		}
+
+		for _, v := range violations {
+			table.Rows = append(table.Rows, []boshtbl.Value{
+				boshtbl.NewValueString(v.Path),
+				boshtbl.NewValueString(v.Severity),
+				boshtbl.NewValueString(v.Message),
+			})
+		}
+
+		c.ui.PrintTable(table)
+	}
+
+	var errs int
+
+	for _, v := range violations {
+		if v.Severity == boshpolicy.SeverityError {
+			errs++
+		}
+	}
+
+	if errs > 0 {
+		return bosherr.Errorf("Expected manifest to match '%s' schema but found %d violation(s)", c.manifestType, errs)
+	}
+
+	return nil
+}
+
+func (c SchemaCheck) builtinSeverity(v boshschema.Violation) string {
+	if c.strict {
+		return boshpolicy.SeverityError
+	}
+
+	if c.advisory || v.UnknownKey {
+		return boshpolicy.SeverityWarning
+	}
+
+	return boshpolicy.SeverityError
+}
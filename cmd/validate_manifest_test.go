@@ -0,0 +1,152 @@
+package cmd_test
+
+import (
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("ValidateManifestCmd", func() {
+	var (
+		ui      *fakeui.FakeUI
+		command ValidateManifestCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		command = NewValidateManifestCmd(ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts ValidateManifestOpts
+		)
+
+		BeforeEach(func() {
+			opts = ValidateManifestOpts{
+				Args: ValidateManifestArgs{
+					Manifest: FileBytesArg{Bytes: []byte("name: dep\ninstance_groups:\n- name: web\n  instances: ((count))\n")},
+				},
+				Type: "deployment",
+			}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("prints empty report when manifest matches schema", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Tables).To(HaveLen(1))
+			Expect(ui.Table.Content).To(Equal("schema violations"))
+			Expect(ui.Table.Rows).To(BeEmpty())
+		})
+
+		It("checks manifest after it has been interpolated", func() {
+			opts.VarKVs = []boshtpl.VarKV{{Name: "count", Value: "many"}}
+			opts.OpsFiles = []OpsFileArg{
+				{
+					Ops: patch.Ops([]patch.Op{
+						patch.ReplaceOp{Path: patch.MustNewPointerFromString("/instance_groups/name=web/azs?"), Value: "z1"},
+					}),
+				},
+			}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'deployment' schema but found 2 violation(s)"))
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/instance_groups/name=web/azs"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected value to be of type 'array' but was 'string'"),
+				},
+				{
+					boshtbl.NewValueString("/instance_groups/name=web/instances"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected value to be of type 'integer' but was 'string'"),
+				},
+			}))
+		})
+
+		It("checks manifest against schema for given type and only warns about unknown keys", func() {
+			opts.Type = "runtime"
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/instance_groups"),
+					boshtbl.NewValueString("warning"),
+					boshtbl.NewValueString("Expected key to be known"),
+				},
+				{
+					boshtbl.NewValueString("/name"),
+					boshtbl.NewValueString("warning"),
+					boshtbl.NewValueString("Expected key to be known"),
+				},
+			}))
+		})
+
+		It("fails on unknown keys when schema check is strict", func() {
+			opts.Type = "runtime"
+			opts.StrictSchema = true
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'runtime' schema but found 2 violation(s)"))
+		})
+
+		It("additionally checks manifest against custom schema", func() {
+			fs := fakesys.NewFakeFileSystem()
+			fs.WriteFileString("/schema.yml", "required: [stemcells]")
+
+			opts.Schema = SchemaFileArg{FS: fs}
+			err := (&opts.Schema).UnmarshalFlag("/schema.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest to match 'deployment' schema but found 1 violation(s)"))
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/stemcells"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected key to be present"),
+				},
+			}))
+		})
+
+		It("returns error if manifest type is unknown", func() {
+			opts.Type = "unknown"
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected manifest type 'unknown' to be one of: deployment, cloud, runtime, cpi"))
+		})
+
+		It("returns error if manifest cannot be interpolated", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte("-")}
+			opts.OpsFiles = []OpsFileArg{
+				{
+					Ops: patch.Ops([]patch.Op{
+						patch.ReplaceOp{Path: patch.MustNewPointerFromString("/missing"), Value: "val"},
+					}),
+				},
+			}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Evaluating manifest"))
+		})
+	})
+})
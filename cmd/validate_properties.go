@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+
+	boshutil "github.com/cloudfoundry/bosh-cli/common/util"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshpolicy "github.com/cloudfoundry/bosh-cli/policy"
+	boshjob "github.com/cloudfoundry/bosh-cli/release/job"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+type ValidatePropertiesCmd struct {
+	releaseJobsReader ReleaseJobsReader
+	ui                boshui.UI
+}
+
+func NewValidatePropertiesCmd(releaseJobsReader ReleaseJobsReader, ui boshui.UI) ValidatePropertiesCmd {
+	return ValidatePropertiesCmd{releaseJobsReader: releaseJobsReader, ui: ui}
+}
+
+func (c ValidatePropertiesCmd) Run(opts ValidatePropertiesOpts) error {
+	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
+
+	bytes, err := tpl.Evaluate(opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp(), boshtpl.EvaluateOpts{})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Evaluating manifest")
+	}
+
+	var releases []ReleaseJobs
+
+	for _, path := range opts.Releases {
+		relJobs, err := c.releaseJobsReader.Read(path)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading jobs of release '%s'", path)
+		}
+
+		defer relJobs.CleanUp()
+
+		releases = append(releases, relJobs)
+	}
+
+	return PropertiesCheck{releases, c.ui}.Run(bytes, true)
+}
+
+// PropertyViolation describes job property in a manifest that does not match job spec.
+// Severity is one of policy severities; only errors fail the check.
+type PropertyViolation struct {
+	Path     string
+	Property string
+	Severity string
+	Message  string
+}
+
+// PropertiesCheck checks job properties of instance groups and addons
+// against specs of jobs from given releases. Jobs from releases
+// that were not given are skipped.
+type PropertiesCheck struct {
+	releases []ReleaseJobs
+	ui       boshui.UI
+}
+
+type propertiesCheckManifest struct {
+	InstanceGroups []propertiesCheckGroup `yaml:"instance_groups"`
+	Addons         []propertiesCheckGroup `yaml:"addons"`
+	Properties     interface{}            `yaml:"properties"`
+}
+
+type propertiesCheckGroup struct {
+	Name       string               `yaml:"name"`
+	Jobs       []propertiesCheckJob `yaml:"jobs"`
+	Properties interface{}          `yaml:"properties"`
+}
+
+type propertiesCheckJob struct {
+	Name       string      `yaml:"name"`
+	Release    string      `yaml:"release"`
+	Properties interface{} `yaml:"properties"`
+}
+
+func (c PropertiesCheck) Run(manifest []byte, alwaysPrint bool) error {
+	var typedManifest propertiesCheckManifest
+
+	err := yaml.Unmarshal(manifest, &typedManifest)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Deserializing manifest")
+	}
+
+	var violations []PropertyViolation
+	var skippedReleases []string
+
+	sections := []struct {
+		name   string
+		groups []propertiesCheckGroup
+	}{
+		{"instance_groups", typedManifest.InstanceGroups},
+		{"addons", typedManifest.Addons},
+	}
+
+	for _, section := range sections {
+		for _, group := range section.groups {
+			for _, manifestJob := range group.Jobs {
+				path := fmt.Sprintf("/%s/name=%s/jobs/name=%s", section.name, group.Name, manifestJob.Name)
+
+				relJobs, found := c.findRelease(manifestJob.Release)
+				if !found {
+					if !containsStr(skippedReleases, manifestJob.Release) {
+						skippedReleases = append(skippedReleases, manifestJob.Release)
+					}
+					continue
+				}
+
+				job, found := relJobs.findJob(manifestJob.Name)
+				if !found {
+					violations = append(violations, PropertyViolation{
+						Path:     path,
+						Severity: boshpolicy.SeverityError,
+						Message:  fmt.Sprintf("Expected job to be in release '%s'", manifestJob.Release),
+					})
+					continue
+				}
+
+				// Similarly to the Director, job properties fall back
+				// to instance group or addon properties and then global properties
+				props := manifestJob.Properties
+				if props == nil {
+					props = group.Properties
+				}
+				if props == nil {
+					props = typedManifest.Properties
+				}
+
+				violations = append(violations, jobPropertiesCheck{job, path}.Run(props)...)
+			}
+		}
+	}
+
+	if len(skippedReleases) > 0 {
+		sort.Strings(skippedReleases)
+		c.ui.PrintLinef("Skipped checking properties of jobs from releases that were not provided: %s",
+			strings.Join(skippedReleases, ", "))
+	}
+
+	if len(violations) > 0 || alwaysPrint {
+		table := boshtbl.Table{
+			Content: "property violations",
+			Header: []boshtbl.Header{
+				boshtbl.NewHeader("Path"),
+				boshtbl.NewHeader("Property"),
+				boshtbl.NewHeader("Severity"),
+				boshtbl.NewHeader("Message"),
+			},
+			FillFirstColumn: true,
+		}
+
+		for _, v := range violations {
+			table.Rows = append(table.Rows, []boshtbl.Value{
+				boshtbl.NewValueString(v.Path),
+				boshtbl.NewValueString(v.Property),
+				boshtbl.NewValueString(v.Severity),
+				boshtbl.NewValueString(v.Message),
+			})
+		}
+
+		c.ui.PrintTable(table)
+	}
+
+	var errs int
+
+	for _, v := range violations {
+		if v.Severity == boshpolicy.SeverityError {
+			errs++
+		}
+	}
+
+	if errs > 0 {
+		return bosherr.Errorf("Expected job properties to match job specs but found %d violation(s)", errs)
+	}
+
+	return nil
+}
+
+func (c PropertiesCheck) findRelease(name string) (ReleaseJobs, bool) {
+	for _, rel := range c.releases {
+		if rel.Name == name {
+			return rel, true
+		}
+	}
+
+	return ReleaseJobs{}, false
+}
+
+func (r ReleaseJobs) findJob(name string) (*boshjob.Job, bool) {
+	for _, job := range r.Jobs {
+		if job.Name() == name {
+			return job, true
+		}
+	}
+
+	return nil, false
+}
+
+// jobPropertiesCheck walks nested manifest properties
+// since job specs define properties with dotted names (e.g. a.b.c)
+type jobPropertiesCheck struct {
+	job  *boshjob.Job
+	path string
+}
+
+func (c jobPropertiesCheck) Run(props interface{}) []PropertyViolation {
+	var names []string
+
+	for name := range c.job.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	if isUnresolvedVariable(props) {
+		return nil
+	}
+
+	set := map[string]bool{}
+	violations := c.walk(props, "", names, set)
+
+	for _, name := range names {
+		if c.job.Properties[name].Default == nil && !set[name] {
+			violations = append(violations, PropertyViolation{
+				Path:     c.path,
+				Property: name,
+				Severity: boshpolicy.SeverityWarning,
+				Message:  "Expected property to be set since job spec does not specify its default",
+			})
+		}
+	}
+
+	return violations
+}
+
+func (c jobPropertiesCheck) walk(props interface{}, prefix string, names []string, set map[string]bool) []PropertyViolation {
+	typedProps, ok := props.(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	vals := map[string]interface{}{}
+
+	for k, v := range typedProps {
+		key := fmt.Sprintf("%v", k)
+		keys = append(keys, key)
+		vals[key] = v
+	}
+
+	sort.Strings(keys)
+
+	var violations []PropertyViolation
+
+	for _, key := range keys {
+		name := prefix + key
+		val := vals[key]
+
+		if _, found := c.job.Properties[name]; found {
+			set[name] = true
+			continue
+		}
+
+		if !hasPropertyPrefix(names, name+".") {
+			msg := "Expected property to be defined in job spec"
+
+			if suggestion := boshutil.SuggestName(name, names); len(suggestion) > 0 {
+				msg += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+			}
+
+			violations = append(violations, PropertyViolation{
+				Path:     c.path,
+				Property: name,
+				Severity: boshpolicy.SeverityError,
+				Message:  msg,
+			})
+			continue
+		}
+
+		if typedVal, ok := val.(map[interface{}]interface{}); ok {
+			violations = append(violations, c.walk(typedVal, name+".", names, set)...)
+		} else if isUnresolvedVariable(val) {
+			// Unresolved variable may provide nested properties
+			markPropertiesSet(names, name+".", set)
+		} else {
+			violations = append(violations, PropertyViolation{
+				Path:     c.path,
+				Property: name,
+				Severity: boshpolicy.SeverityError,
+				Message:  "Expected property to be a map since job spec defines nested properties",
+			})
+		}
+	}
+
+	return violations
+}
+
+func isUnresolvedVariable(val interface{}) bool {
+	str, ok := val.(string)
+	return ok && strings.HasPrefix(str, "((") && strings.HasSuffix(str, "))")
+}
+
+func hasPropertyPrefix(names []string, prefix string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func markPropertiesSet(names []string, prefix string, set map[string]bool) {
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			set[name] = true
+		}
+	}
+}
+
+func containsStr(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,261 @@
+package cmd_test
+
+import (
+	"errors"
+
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	fakecmd "github.com/cloudfoundry/bosh-cli/cmd/cmdfakes"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshjob "github.com/cloudfoundry/bosh-cli/release/job"
+	boshres "github.com/cloudfoundry/bosh-cli/release/resource"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("ValidatePropertiesCmd", func() {
+	var (
+		releaseJobsReader *fakecmd.FakeReleaseJobsReader
+		ui                *fakeui.FakeUI
+		command           ValidatePropertiesCmd
+	)
+
+	BeforeEach(func() {
+		releaseJobsReader = &fakecmd.FakeReleaseJobsReader{}
+		ui = &fakeui.FakeUI{}
+		command = NewValidatePropertiesCmd(releaseJobsReader, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts ValidatePropertiesOpts
+		)
+
+		BeforeEach(func() {
+			apiJob := boshjob.NewJob(boshres.NewResource("api", "api-fp", nil))
+			apiJob.Properties = map[string]boshjob.PropertyDefinition{
+				"port":           {Default: biproperty.Property(8080)},
+				"tls.enabled":    {Default: biproperty.Property(false)},
+				"tls.cert":       {},
+				"admin.password": {},
+			}
+
+			dnsJob := boshjob.NewJob(boshres.NewResource("bosh-dns", "dns-fp", nil))
+			dnsJob.Properties = map[string]boshjob.PropertyDefinition{
+				"cache.enabled": {Default: biproperty.Property(true)},
+			}
+
+			releaseJobsReader.ReadStub = func(path string) (ReleaseJobs, error) {
+				switch path {
+				case "/capi.tgz":
+					return ReleaseJobs{Name: "capi", Jobs: []*boshjob.Job{apiJob}}, nil
+				case "/bosh-dns":
+					return ReleaseJobs{Name: "bosh-dns", Jobs: []*boshjob.Job{dnsJob}}, nil
+				default:
+					return ReleaseJobs{}, errors.New("fake-err")
+				}
+			}
+
+			opts = ValidatePropertiesOpts{
+				Args: ValidatePropertiesArgs{
+					Manifest: FileBytesArg{Bytes: []byte(`
+name: dep
+instance_groups:
+- name: web
+  jobs:
+  - name: api
+    release: capi
+    properties:
+      port: ((port))
+      tls: {enabled: true, cert: cert}
+      admin: {password: ((admin_password))}
+addons:
+- name: dns
+  jobs:
+  - name: bosh-dns
+    release: bosh-dns
+    properties: {cache: {enabled: false}}
+`)},
+				},
+				Releases: []string{"/capi.tgz", "/bosh-dns"},
+			}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("reads given releases and prints empty table if properties match job specs", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(releaseJobsReader.ReadCallCount()).To(Equal(2))
+			Expect(releaseJobsReader.ReadArgsForCall(0)).To(Equal("/capi.tgz"))
+			Expect(releaseJobsReader.ReadArgsForCall(1)).To(Equal("/bosh-dns"))
+
+			Expect(ui.Table.Content).To(Equal("property violations"))
+			Expect(ui.Table.Rows).To(BeEmpty())
+		})
+
+		It("reports unknown properties with suggestions and missing properties without defaults", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte(`
+name: dep
+instance_groups:
+- name: web
+  jobs:
+  - name: api
+    release: capi
+    properties:
+      ports: 8080
+      tls: {enabled: true, certs: cert}
+      admin: password
+addons:
+- name: dns
+  jobs:
+  - name: bosh-dns
+    release: bosh-dns
+    properties: {cache: {enabled: false}, logs: true}
+`)}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected job properties to match job specs but found 4 violation(s)"))
+
+			apiPath := boshtbl.NewValueString("/instance_groups/name=web/jobs/name=api")
+			dnsPath := boshtbl.NewValueString("/addons/name=dns/jobs/name=bosh-dns")
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					apiPath,
+					boshtbl.NewValueString("admin"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected property to be a map since job spec defines nested properties"),
+				},
+				{
+					apiPath,
+					boshtbl.NewValueString("ports"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected property to be defined in job spec (did you mean 'port'?)"),
+				},
+				{
+					apiPath,
+					boshtbl.NewValueString("tls.certs"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected property to be defined in job spec (did you mean 'tls.cert'?)"),
+				},
+				{
+					apiPath,
+					boshtbl.NewValueString("admin.password"),
+					boshtbl.NewValueString("warning"),
+					boshtbl.NewValueString("Expected property to be set since job spec does not specify its default"),
+				},
+				{
+					apiPath,
+					boshtbl.NewValueString("tls.cert"),
+					boshtbl.NewValueString("warning"),
+					boshtbl.NewValueString("Expected property to be set since job spec does not specify its default"),
+				},
+				{
+					dnsPath,
+					boshtbl.NewValueString("logs"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected property to be defined in job spec"),
+				},
+			}))
+		})
+
+		It("does not fail if there are only warnings", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte(`
+name: dep
+instance_groups:
+- name: web
+  jobs: [{name: api, release: capi}]
+`)}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.Table.Rows).To(HaveLen(2))
+		})
+
+		It("falls back to instance group and global properties if job does not specify properties", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte(`
+name: dep
+instance_groups:
+- name: web
+  jobs: [{name: api, release: capi}]
+  properties: {tls: {cert: cert}, admin: {password: pass}}
+addons:
+- name: dns
+  jobs: [{name: bosh-dns, release: bosh-dns}]
+properties: {unknown: true}
+`)}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/addons/name=dns/jobs/name=bosh-dns"),
+					boshtbl.NewValueString("unknown"),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected property to be defined in job spec"),
+				},
+			}))
+		})
+
+		It("reports jobs that are not found in their release", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte(`
+name: dep
+instance_groups:
+- name: web
+  jobs: [{name: worker, release: capi}]
+`)}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("/instance_groups/name=web/jobs/name=worker"),
+					boshtbl.NewValueString(""),
+					boshtbl.NewValueString("error"),
+					boshtbl.NewValueString("Expected job to be in release 'capi'"),
+				},
+			}))
+		})
+
+		It("skips jobs from releases that were not given", func() {
+			opts.Releases = []string{"/capi.tgz"}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Said).To(Equal([]string{
+				"Skipped checking properties of jobs from releases that were not provided: bosh-dns"}))
+		})
+
+		It("checks interpolated manifest", func() {
+			opts.Args.Manifest = FileBytesArg{Bytes: []byte(`
+name: dep
+instance_groups:
+- name: web
+  jobs: [{name: api, release: capi, properties: ((props))}]
+`)}
+			opts.VarKVs = []boshtpl.VarKV{{Name: "props", Value: map[interface{}]interface{}{"pot": 1}}}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(ui.Table.Rows[0][1]).To(Equal(boshtbl.NewValueString("pot")))
+		})
+
+		It("returns error if release cannot be read", func() {
+			opts.Releases = []string{"/unknown.tgz"}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading jobs of release '/unknown.tgz'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
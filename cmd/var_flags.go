@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	cfgtypes "github.com/cloudfoundry/config-server/types"
 
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
@@ -13,6 +15,8 @@ type VarFlags struct {
 	VarsFiles   []boshtpl.VarsFileArg `long:"vars-file"  short:"l" value-name:"PATH"      description:"Load variables from a YAML file"`
 	VarsEnvs    []boshtpl.VarsEnvArg  `long:"vars-env"             value-name:"PREFIX"    description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 	VarsFSStore VarsFSStore           `long:"vars-store"           value-name:"PATH"      description:"Load/save variables from/to a YAML file"`
+
+	VarsSources []VarsSourceArg `long:"vars-source" value-name:"NAME=URI" description:"Load variables from an external source (vault+https://, credhub+https:// or exec://)"`
 }
 
 func (f VarFlags) AsVariables() boshtpl.Variables {
@@ -44,6 +48,10 @@ func (f VarFlags) AsVariables() boshtpl.Variables {
 
 	firstToUse = append(firstToUse, staticVars)
 
+	for _, source := range f.VarsSources {
+		firstToUse = append(firstToUse, source)
+	}
+
 	store := &f.VarsFSStore
 
 	if f.VarsFSStore.IsSet() {
@@ -58,3 +66,65 @@ func (f VarFlags) AsVariables() boshtpl.Variables {
 
 	return vars
 }
+
+// AsNamedVariables is similar to AsVariables but keeps each source
+// of variables separate so that it's possible to tell which one supplied a value.
+func (f VarFlags) AsNamedVariables() boshtpl.MultiVars {
+	var varss []boshtpl.Variables
+
+	if len(f.VarKVs) > 0 {
+		kvVars := boshtpl.StaticVariables{}
+
+		for _, kv := range f.VarKVs {
+			kvVars[kv.Name] = kv.Value
+		}
+
+		varss = append(varss, boshtpl.NamedVars{Name: "--var", Vars: kvVars})
+	}
+
+	// Later flags take precedence over earlier ones
+	for i := len(f.VarFiles) - 1; i >= 0; i-- {
+		varss = append(varss, boshtpl.NamedVars{
+			Name: fmt.Sprintf("--var-file %s", f.VarFiles[i].Path),
+			Vars: f.VarFiles[i].Vars,
+		})
+	}
+
+	for i := len(f.VarsFiles) - 1; i >= 0; i-- {
+		varss = append(varss, boshtpl.NamedVars{
+			Name: fmt.Sprintf("--vars-file %s", f.VarsFiles[i].Path),
+			Vars: f.VarsFiles[i].Vars,
+		})
+	}
+
+	for i := len(f.VarsEnvs) - 1; i >= 0; i-- {
+		varss = append(varss, boshtpl.NamedVars{
+			Name: fmt.Sprintf("--vars-env %s", f.VarsEnvs[i].Prefix),
+			Vars: f.VarsEnvs[i].Vars,
+		})
+	}
+
+	for _, source := range f.VarsSources {
+		varss = append(varss, boshtpl.NamedVars{
+			Name: fmt.Sprintf("--vars-source %s", source.Name),
+			Vars: source,
+		})
+	}
+
+	store := &f.VarsFSStore
+
+	if f.VarsFSStore.IsSet() {
+		varss = append(varss, boshtpl.NamedVars{
+			Name: fmt.Sprintf("--vars-store %s", store.Path()),
+			Vars: store,
+		})
+	}
+
+	vars := boshtpl.NewMultiVars(varss)
+
+	if f.VarsFSStore.IsSet() {
+		store.ValueGeneratorFactory = cfgtypes.NewValueGeneratorConcrete(NewVarsCertLoader(vars))
+	}
+
+	return vars
+}
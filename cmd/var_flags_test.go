@@ -140,6 +140,46 @@ env: store
 			}
 		})
 
+		It("prefers explicitly given variables, to vars sources, to vars store", func() {
+			varsStore := &VarsFSStore{FS: fakesys.NewFakeFileSystem()}
+
+			err := varsStore.UnmarshalFlag("/file")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = varsStore.FS.WriteFileString("/file", `
+store: store
+source2: store
+`)
+			Expect(err).ToNot(HaveOccurred())
+
+			flags := VarFlags{
+				VarKVs: []VarKV{
+					{Name: "kv", Value: "kv"},
+				},
+				VarsSources: []VarsSourceArg{
+					{Name: "source1", Vars: StaticVariables{"kv": "source1", "source1": "source1"}},
+					{Name: "source2", Vars: StaticVariables{"source1": "source2", "source2": "source2"}},
+				},
+				VarsFSStore: *varsStore,
+			}
+
+			vars := flags.AsVariables()
+
+			expectedVals := map[string]string{
+				"kv":      "kv",
+				"source1": "source1",
+				"source2": "source2",
+				"store":   "store",
+			}
+
+			for key, expectedVal := range expectedVals {
+				val, found, err := vars.Get(VariableDefinition{Name: key})
+				Expect(val).To(Equal(expectedVal), fmt.Sprintf("Expecting key '%s' value to match", key))
+				Expect(found).To(BeTrue())
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
 		It("configures vars store to have ability to look up all variables for value generation", func() {
 			varsStore := &VarsFSStore{FS: fakesys.NewFakeFileSystem()}
 			varsStore.UnmarshalFlag("/file")
@@ -178,4 +218,58 @@ env: store
 			Expect(valRaw["ca"].(string)).To(Equal(caCert))
 		})
 	})
+
+	Describe("AsNamedVariables", func() {
+		It("returns sources with names in the same precedence order as AsVariables", func() {
+			varsStore := &VarsFSStore{FS: fakesys.NewFakeFileSystem()}
+
+			err := varsStore.UnmarshalFlag("/store")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = varsStore.FS.WriteFileString("/store", "all: store\nstore: store")
+			Expect(err).ToNot(HaveOccurred())
+
+			flags := VarFlags{
+				VarKVs: []VarKV{
+					{Name: "kv", Value: "kv1"},
+					{Name: "kv", Value: "kv2"},
+				},
+				VarFiles: []VarFileArg{
+					{Path: "/var-file1", Vars: StaticVariables{"kv": "var_file1", "var_file": "var_file1"}},
+					{Path: "/var-file2", Vars: StaticVariables{"var_file": "var_file2"}},
+				},
+				VarsFiles: []VarsFileArg{
+					{Path: "/vars-file1", Vars: StaticVariables{"vars_file": "vars_file1", "all": "vars_file1"}},
+					{Path: "/vars-file2", Vars: StaticVariables{"vars_file": "vars_file2"}},
+				},
+				VarsEnvs: []VarsEnvArg{
+					{Prefix: "ENV", Vars: StaticVariables{"env": "env", "all": "env"}},
+				},
+				VarsSources: []VarsSourceArg{
+					{Name: "source", Vars: StaticVariables{"source": "source", "env": "source"}},
+				},
+				VarsFSStore: *varsStore,
+			}
+
+			vars := flags.AsNamedVariables()
+
+			expectedVals := map[string][]string{
+				"kv":        {"kv2", "--var"},
+				"var_file":  {"var_file2", "--var-file /var-file2"},
+				"vars_file": {"vars_file2", "--vars-file /vars-file2"},
+				"all":       {"vars_file1", "--vars-file /vars-file1"},
+				"env":       {"env", "--vars-env ENV"},
+				"source":    {"source", "--vars-source source"},
+				"store":     {"store", "--vars-store /store"},
+			}
+
+			for key, expected := range expectedVals {
+				val, source, found, err := vars.GetWithSource(VariableDefinition{Name: key})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(val).To(Equal(expected[0]), fmt.Sprintf("Expecting key '%s' value to match", key))
+				Expect(source.(NamedVars).Name).To(Equal(expected[1]), fmt.Sprintf("Expecting key '%s' source to match", key))
+			}
+		})
+	})
 })
@@ -7,20 +7,38 @@ import (
 	"gopkg.in/yaml.v2"
 
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
 )
 
+// VarsFSStore keeps variables in a YAML file. File is transparently
+// encrypted if it was encrypted before (see vars-store encrypt command)
+// or if it's new and vars store key is configured (see VarsStoreKeySource).
 type VarsFSStore struct {
 	FS boshsys.FileSystem
+	UI boshui.UI
 
 	ValueGeneratorFactory cfgtypes.ValueGeneratorFactory
 
 	path string
+	keys *VarsStoreKeySource
 }
 
 var _ boshtpl.Variables = VarsFSStore{}
 
 func (s VarsFSStore) IsSet() bool { return len(s.path) > 0 }
 
+func (s VarsFSStore) Path() string { return s.path }
+
+// WithKeyFile returns store that reads its key from given key file
+// instead of environment variables (same as --key-file of vars-store encrypt)
+func (s VarsFSStore) WithKeyFile(keyFile string) VarsFSStore {
+	if len(keyFile) > 0 {
+		s.keys = NewVarsStoreKeySource(VarsStoreEnvPrefix, keyFile, s.FS, s.UI)
+	}
+
+	return s
+}
+
 func (s VarsFSStore) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
 	vars, err := s.load()
 	if err != nil {
@@ -73,43 +91,96 @@ func (s VarsFSStore) generateAndSet(varDef boshtpl.VariableDefinition) (interfac
 }
 
 func (s VarsFSStore) set(key string, val interface{}) error {
-	vars, err := s.load()
+	vars, cipher, err := s.loadWithCipher()
 	if err != nil {
 		return err
 	}
 
 	vars[key] = val
 
-	return s.save(vars)
+	return s.save(vars, cipher)
 }
 
 func (s VarsFSStore) load() (boshtpl.StaticVariables, error) {
+	vars, _, err := s.loadWithCipher()
+	return vars, err
+}
+
+// loadWithCipher returns cipher that should be used to save variables
+// or nil if variables should be saved in plaintext.
+func (s VarsFSStore) loadWithCipher() (boshtpl.StaticVariables, *VarsStoreCipher, error) {
 	vars := boshtpl.StaticVariables{}
 
-	if s.FS.FileExists(s.path) {
-		bytes, err := s.FS.ReadFile(s.path)
+	if !s.FS.FileExists(s.path) {
+		cipher, err := s.configuredCipher()
+		if err != nil {
+			return vars, nil, err
+		}
+
+		return vars, cipher, nil
+	}
+
+	bytes, err := s.FS.ReadFile(s.path)
+	if err != nil {
+		return vars, nil, err
+	}
+
+	var cipher *VarsStoreCipher
+
+	if IsEncryptedVarsStore(bytes) {
+		if s.keys == nil {
+			return vars, nil, bosherr.Errorf("Expected vars store key to be configured for encrypted variables file store '%s'", s.path)
+		}
+
+		cipher, err = s.keys.Cipher(false)
 		if err != nil {
-			return vars, err
+			return vars, nil, bosherr.WrapErrorf(err, "Decrypting variables file store '%s'", s.path)
 		}
 
-		err = yaml.Unmarshal(bytes, &vars)
+		bytes, err = cipher.Decrypt(bytes)
 		if err != nil {
-			return vars, bosherr.WrapErrorf(err, "Deserializing variables file store '%s'", s.path)
+			return vars, nil, bosherr.WrapErrorf(err, "Decrypting variables file store '%s'", s.path)
 		}
 	}
+
+	err = yaml.Unmarshal(bytes, &vars)
+	if err != nil {
+		return vars, nil, bosherr.WrapErrorf(err, "Deserializing variables file store '%s'", s.path)
+	}
+
 	if vars == nil {
-		return boshtpl.StaticVariables{}, nil
+		return boshtpl.StaticVariables{}, cipher, nil
 	}
 
-	return vars, nil
+	return vars, cipher, nil
 }
 
-func (s VarsFSStore) save(vars boshtpl.StaticVariables) error {
+func (s VarsFSStore) configuredCipher() (*VarsStoreCipher, error) {
+	if s.keys == nil {
+		return nil, nil
+	}
+
+	cipher, _, err := s.keys.ConfiguredCipher()
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Configuring encryption for variables file store '%s'", s.path)
+	}
+
+	return cipher, nil
+}
+
+func (s VarsFSStore) save(vars boshtpl.StaticVariables, cipher *VarsStoreCipher) error {
 	bytes, err := yaml.Marshal(vars)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Serializing variables")
 	}
 
+	if cipher != nil {
+		bytes, err = cipher.Encrypt(bytes)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Encrypting variables file store '%s'", s.path)
+		}
+	}
+
 	err = s.FS.WriteFile(s.path, bytes)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Writing variables to file store '%s'", s.path)
@@ -129,6 +200,7 @@ func (s *VarsFSStore) UnmarshalFlag(data string) error {
 	}
 
 	(*s).path = absPath
+	(*s).keys = NewVarsStoreKeySource(VarsStoreEnvPrefix, "", s.FS, s.UI)
 	(*s).ValueGeneratorFactory = cfgtypes.NewValueGeneratorConcrete(nil)
 
 	return nil
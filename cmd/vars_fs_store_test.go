@@ -1,8 +1,10 @@
 package cmd_test
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
 
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	fakecfgtypes "github.com/cloudfoundry/config-server/types/typesfakes"
@@ -132,6 +134,83 @@ var _ = Describe("VarsFSStore", func() {
 				Expect(fs.ReadFileString("/file")).To(MatchYAML(fmt.Sprintf("---\nkey:  %s\n", val.(string))))
 			})
 		})
+
+		Context("when vars store key is configured", func() {
+			var (
+				key []byte
+			)
+
+			BeforeEach(func() {
+				key = []byte("01234567890123456789012345678901")
+				os.Setenv("BOSH_VARS_STORE_KEY", base64.StdEncoding.EncodeToString(key))
+
+				err := (&store).UnmarshalFlag("/file")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.Unsetenv("BOSH_VARS_STORE_KEY")
+			})
+
+			It("returns value from encrypted file and keeps file encrypted when saving generated value", func() {
+				encrypted, err := NewVarsStoreCipher(VarsStoreKey{Key: key}).Encrypt([]byte("key: val"))
+				Expect(err).ToNot(HaveOccurred())
+
+				fs.WriteFile("/file", encrypted)
+
+				val, found, err := store.Get(boshtpl.VariableDefinition{Name: "key"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(val).To(Equal("val"))
+
+				val, found, err = store.Get(boshtpl.VariableDefinition{Name: "key2", Type: "password"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				contents, err := fs.ReadFile("/file")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(IsEncryptedVarsStore(contents)).To(BeTrue())
+
+				decrypted, err := NewVarsStoreCipher(VarsStoreKey{Key: key}).Decrypt(contents)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(decrypted)).To(Equal(fmt.Sprintf("key: val\nkey2: %s\n", val.(string))))
+			})
+
+			It("encrypts new file", func() {
+				val, found, err := store.Get(boshtpl.VariableDefinition{Name: "key", Type: "password"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				contents, err := fs.ReadFile("/file")
+				Expect(err).ToNot(HaveOccurred())
+
+				decrypted, err := NewVarsStoreCipher(VarsStoreKey{Key: key}).Decrypt(contents)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(decrypted)).To(Equal(fmt.Sprintf("key: %s\n", val.(string))))
+			})
+
+			It("keeps existing plaintext file in plaintext", func() {
+				fs.WriteFileString("/file", "key: val")
+
+				_, _, err := store.Get(boshtpl.VariableDefinition{Name: "key2", Type: "password"})
+				Expect(err).ToNot(HaveOccurred())
+
+				contents, err := fs.ReadFile("/file")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(IsEncryptedVarsStore(contents)).To(BeFalse())
+			})
+
+			It("returns error if file was encrypted with a different key", func() {
+				encrypted, err := NewVarsStoreCipher(VarsStoreKey{Key: []byte("10987654321098765432109876543210")}).Encrypt([]byte("key: val"))
+				Expect(err).ToNot(HaveOccurred())
+
+				fs.WriteFile("/file", encrypted)
+
+				_, _, err = store.Get(boshtpl.VariableDefinition{Name: "key"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Decrypting variables file store '/file'"))
+			})
+		})
 	})
 
 	Describe("List", func() {
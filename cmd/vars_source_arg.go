@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-utils/crypto"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshhttp "github.com/cloudfoundry/bosh-utils/httpclient"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"gopkg.in/yaml.v2"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+/*
+VarsSourceArg loads variables from an external secret store so that
+they do not have to be kept on disk. Supported sources:
+
+	vault=vault+https://vault.example.com:8200/secret/bosh?version=2
+	credhub=credhub+https://credhub.example.com:8844/bosh-lite
+	plugin=exec:///usr/local/bin/bosh-vars-plugin?arg=--profile&arg=prod
+
+Vault and CredHub tokens are taken from VAULT_TOKEN and CREDHUB_ACCESS_TOKEN
+environment variables unless 'token-env' query param names another variable.
+'ca-cert' query param may point to a CA certificate file.
+*/
+type VarsSourceArg struct {
+	FS        boshsys.FileSystem
+	CmdRunner boshsys.CmdRunner
+	Logger    boshlog.Logger
+
+	Name string
+	Vars boshtpl.Variables
+}
+
+var _ boshtpl.Variables = VarsSourceArg{}
+
+func (a VarsSourceArg) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	val, found, err := a.Vars.Get(varDef)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Getting variable '%s' from vars source '%s'", varDef.Name, a.Name)
+	}
+
+	return val, found, nil
+}
+
+func (a VarsSourceArg) List() ([]boshtpl.VariableDefinition, error) {
+	defs, err := a.Vars.List()
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Listing variables from vars source '%s'", a.Name)
+	}
+
+	return defs, nil
+}
+
+func (a *VarsSourceArg) UnmarshalFlag(data string) error {
+	pieces := strings.SplitN(data, "=", 2)
+	if len(pieces) != 2 || len(pieces[0]) == 0 || len(pieces[1]) == 0 {
+		return bosherr.Errorf("Expected vars source '%s' to be in format 'name=uri'", data)
+	}
+
+	uri, err := url.Parse(pieces[1])
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing vars source '%s' URI", pieces[0])
+	}
+
+	var vars boshtpl.Variables
+
+	switch uri.Scheme {
+	case "vault+http", "vault+https":
+		vars, err = a.vaultVars(uri)
+
+	case "credhub+http", "credhub+https":
+		vars, err = a.credHubVars(uri)
+
+	case "exec":
+		vars, err = a.execVars(uri)
+
+	default:
+		return bosherr.Errorf("Expected vars source '%s' URI scheme to be one of 'vault+https', 'credhub+https' or 'exec' but was '%s'", pieces[0], uri.Scheme)
+	}
+
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Configuring vars source '%s'", pieces[0])
+	}
+
+	(*a).Name = pieces[0]
+	(*a).Vars = vars
+
+	return nil
+}
+
+func (a VarsSourceArg) vaultVars(uri *url.URL) (boshtpl.Variables, error) {
+	client, err := a.httpClient(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.SplitN(strings.Trim(uri.Path, "/"), "/", 2)
+	if len(segments[0]) == 0 {
+		return nil, bosherr.Error("Expected Vault URI to include secrets engine mount path")
+	}
+
+	var path string
+
+	if len(segments) > 1 {
+		path = segments[1]
+	}
+
+	version := 1
+
+	switch uri.Query().Get("version") {
+	case "", "1":
+	case "2":
+		version = 2
+	default:
+		return nil, bosherr.Errorf("Expected Vault KV version to be '1' or '2'")
+	}
+
+	token, err := a.token(uri, "VAULT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVaultVars(client, a.baseURL(uri), segments[0], path, version, token), nil
+}
+
+func (a VarsSourceArg) credHubVars(uri *url.URL) (boshtpl.Variables, error) {
+	client, err := a.httpClient(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := a.token(uri, "CREDHUB_ACCESS_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCredHubVars(client, a.baseURL(uri), uri.Path, token), nil
+}
+
+func (a VarsSourceArg) execVars(uri *url.URL) (boshtpl.Variables, error) {
+	path := uri.Path
+	if len(path) == 0 {
+		path = uri.Opaque
+	}
+
+	if len(path) == 0 {
+		return nil, bosherr.Error("Expected exec URI to include plugin path")
+	}
+
+	return NewExecVars(a.CmdRunner, path, uri.Query()["arg"], a.Logger), nil
+}
+
+func (a VarsSourceArg) baseURL(uri *url.URL) string {
+	scheme := strings.SplitN(uri.Scheme, "+", 2)[1]
+	return scheme + "://" + uri.Host
+}
+
+func (a VarsSourceArg) token(uri *url.URL, defaultEnv string) (string, error) {
+	env := uri.Query().Get("token-env")
+	if len(env) == 0 {
+		env = defaultEnv
+	}
+
+	token := os.Getenv(env)
+	if len(token) == 0 {
+		return "", bosherr.Errorf("Expected environment variable '%s' to contain access token", env)
+	}
+
+	return token, nil
+}
+
+func (a VarsSourceArg) httpClient(uri *url.URL) (*http.Client, error) {
+	caCertPath := uri.Query().Get("ca-cert")
+	if len(caCertPath) == 0 {
+		return boshhttp.CreateDefaultClient(nil), nil
+	}
+
+	caCert, err := a.FS.ReadFile(caCertPath)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading CA certificate '%s'", caCertPath)
+	}
+
+	certPool, err := crypto.CertPoolFromPEM(caCert)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Parsing CA certificate '%s'", caCertPath)
+	}
+
+	return boshhttp.CreateDefaultClient(certPool), nil
+}
+
+// varsSourceGet makes a request to an HTTP based vars source
+// and returns false if requested resource does not exist.
+func varsSourceGet(client *http.Client, req *http.Request, result interface{}) (found bool, err error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Requesting '%s'", req.URL.Path)
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+			found, err = false, bosherr.WrapErrorf(closeErr, "Closing response for '%s'", req.URL.Path)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Reading response for '%s'", req.URL.Path)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, bosherr.Errorf("Expected response for '%s' to be successful but was '%d'", req.URL.Path, resp.StatusCode)
+	}
+
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Unmarshaling response for '%s'", req.URL.Path)
+	}
+
+	return true, nil
+}
+
+// varsSourceValue converts JSON value into a form
+// that is used for values loaded from YAML files.
+func varsSourceValue(raw json.RawMessage) (interface{}, error) {
+	var val interface{}
+
+	err := yaml.Unmarshal(raw, &val)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Unmarshaling value")
+	}
+
+	return val, nil
+}
+
+// varsSourceJSONValue converts value loaded from YAML into a form
+// that could be marshaled into JSON.
+func varsSourceJSONValue(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+
+		for k, v := range typedVal {
+			result[fmt.Sprintf("%v", k)] = varsSourceJSONValue(v)
+		}
+
+		return result
+
+	case []interface{}:
+		result := []interface{}{}
+
+		for _, v := range typedVal {
+			result = append(result, varsSourceJSONValue(v))
+		}
+
+		return result
+
+	default:
+		return val
+	}
+}
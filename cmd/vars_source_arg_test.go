@@ -0,0 +1,121 @@
+package cmd_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+var _ = Describe("VarsSourceArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			fs        *fakesys.FakeFileSystem
+			cmdRunner *fakesys.FakeCmdRunner
+			logger    boshlog.Logger
+			arg       VarsSourceArg
+		)
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+			cmdRunner = fakesys.NewFakeCmdRunner()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			arg = VarsSourceArg{FS: fs, CmdRunner: cmdRunner, Logger: logger}
+
+			os.Setenv("BOSH_TEST_VARS_SOURCE_TOKEN", "token")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("BOSH_TEST_VARS_SOURCE_TOKEN")
+		})
+
+		It("configures Vault source that reads secrets with token from environment", func() {
+			server := ghttp.NewServer()
+			defer server.Close()
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/secret/data/bosh/admin_password"),
+					ghttp.VerifyHeader(http.Header{"X-Vault-Token": []string{"token"}}),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"data":{"value":"secret"}}}`),
+				),
+			)
+
+			uri := "vault+" + server.URL() + "/secret/bosh?version=2&token-env=BOSH_TEST_VARS_SOURCE_TOKEN"
+
+			err := (&arg).UnmarshalFlag("vault=" + uri)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Name).To(Equal("vault"))
+
+			val, found, err := arg.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("secret"))
+		})
+
+		It("configures CredHub source", func() {
+			err := (&arg).UnmarshalFlag("credhub=credhub+https://credhub:8844/prefix?token-env=BOSH_TEST_VARS_SOURCE_TOKEN")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Vars).To(BeAssignableToTypeOf(CredHubVars{}))
+		})
+
+		It("configures exec source with given command runner", func() {
+			err := (&arg).UnmarshalFlag("plugin=exec:///usr/local/bin/plugin?arg=--profile")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Vars).To(Equal(NewExecVars(cmdRunner, "/usr/local/bin/plugin", []string{"--profile"}, logger)))
+		})
+
+		It("returns error if token environment variable is not set", func() {
+			err := (&arg).UnmarshalFlag("vault=vault+https://vault:8200/secret?token-env=BOSH_TEST_VARS_SOURCE_MISSING")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected environment variable 'BOSH_TEST_VARS_SOURCE_MISSING' to contain access token"))
+		})
+
+		It("returns error if Vault mount path is missing", func() {
+			err := (&arg).UnmarshalFlag("vault=vault+https://vault:8200?token-env=BOSH_TEST_VARS_SOURCE_TOKEN")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected Vault URI to include secrets engine mount path"))
+		})
+
+		It("returns error if CA certificate cannot be read", func() {
+			fs.RegisterReadFileError("/ca.pem", errors.New("fake-err"))
+
+			err := (&arg).UnmarshalFlag("credhub=credhub+https://credhub:8844/prefix?ca-cert=/ca.pem&token-env=BOSH_TEST_VARS_SOURCE_TOKEN")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading CA certificate '/ca.pem'"))
+		})
+
+		It("returns error if scheme is not supported", func() {
+			err := (&arg).UnmarshalFlag("other=https://other")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("URI scheme to be one of"))
+		})
+
+		It("returns error if format is not name=uri", func() {
+			err := (&arg).UnmarshalFlag("vault")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected vars source 'vault' to be in format 'name=uri'"))
+		})
+	})
+
+	Describe("Get", func() {
+		It("includes source name in errors", func() {
+			arg := VarsSourceArg{
+				Name: "vault",
+				Vars: NewExecVars(fakesys.NewFakeCmdRunner(), "/plugin", nil, boshlog.NewLogger(boshlog.LevelNone)),
+			}
+
+			_, _, err := arg.Get(boshtpl.VariableDefinition{Name: "var"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Getting variable 'var' from vars source 'vault'"))
+		})
+	})
+})
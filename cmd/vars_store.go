@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	cfgtypes "github.com/cloudfoundry/config-server/types"
+
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+type VarsStoreCmd struct {
+	fs          boshsys.FileSystem
+	ui          boshui.UI
+	timeService clock.Clock
+}
+
+func NewVarsStoreCmd(fs boshsys.FileSystem, ui boshui.UI, timeService clock.Clock) VarsStoreCmd {
+	return VarsStoreCmd{fs: fs, ui: ui, timeService: timeService}
+}
+
+func (c VarsStoreCmd) Encrypt(opts VarsStoreEncryptOpts) error {
+	path := opts.Args.Path.ExpandedPath
+
+	contents, err := c.read(path)
+	if err != nil {
+		return err
+	}
+
+	if IsEncryptedVarsStore(contents) {
+		return bosherr.Errorf("Expected vars store '%s' to not be already encrypted", path)
+	}
+
+	cipher, err := NewVarsStoreKeySource(VarsStoreEnvPrefix, opts.KeyFile, c.fs, c.ui).Cipher(true)
+	if err != nil {
+		return err
+	}
+
+	err = c.write(path, contents, cipher)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintLinef("Encrypted vars store '%s'", path)
+
+	return nil
+}
+
+func (c VarsStoreCmd) Decrypt(opts VarsStoreDecryptOpts) error {
+	path := opts.Args.Path.ExpandedPath
+
+	contents, err := c.decrypt(path, opts.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	err = c.write(path, contents, nil)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintLinef("Decrypted vars store '%s'", path)
+
+	return nil
+}
+
+func (c VarsStoreCmd) Rekey(opts VarsStoreRekeyOpts) error {
+	path := opts.Args.Path.ExpandedPath
+
+	contents, err := c.decrypt(path, opts.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	cipher, err := NewVarsStoreKeySource(VarsStoreNewEnvPrefix, opts.NewKeyFile, c.fs, c.ui).Cipher(true)
+	if err != nil {
+		return err
+	}
+
+	err = c.write(path, contents, cipher)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintLinef("Re-encrypted vars store '%s'", path)
+
+	return nil
+}
+
+func (c VarsStoreCmd) Check(opts VarsStoreCheckOpts) error {
+	vars, err := opts.Args.VarsStore.WithKeyFile(opts.KeyFile).load()
+	if err != nil {
+		return err
+	}
+
+	certs, err := FindVarsStoreCertificates(vars)
+	if err != nil {
+		return err
+	}
+
+	now := c.timeService.Now()
+
+	table := boshtbl.Table{
+		Content: "certificates",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Name"),
+			boshtbl.NewHeader("Common Name"),
+			boshtbl.NewHeader("CA Chain"),
+			boshtbl.NewHeader("Alternative Names"),
+			boshtbl.NewHeader("Expires"),
+			boshtbl.NewHeader("Expires In"),
+		},
+
+		SortBy: []boshtbl.ColumnSort{
+			{Column: 4, Asc: true},
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, cert := range certs {
+		chain := cert.Chain
+
+		if cert.IsSelfSigned() {
+			chain = []string{"(self-signed)"}
+		} else if !cert.IssuerFound {
+			chain = []string{fmt.Sprintf("(unknown: %s)", cert.Certificate.Issuer.CommonName)}
+		}
+
+		expiresIn := cert.Certificate.NotAfter.Sub(now)
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(cert.Name),
+			boshtbl.NewValueString(cert.Certificate.Subject.CommonName),
+			boshtbl.NewValueStrings(chain),
+			boshtbl.NewValueStrings(cert.AlternativeNames()),
+			boshtbl.NewValueTime(cert.Certificate.NotAfter.UTC()),
+			boshtbl.NewValueFmt(boshtbl.NewValueString(c.formatExpiresIn(expiresIn)), expiresIn <= 0),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
+
+func (c VarsStoreCmd) formatExpiresIn(d time.Duration) string {
+	if d <= 0 {
+		return "expired"
+	}
+
+	return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+}
+
+// Rotate regenerates selected certificates and all certificates
+// issued by them since those would not be trusted otherwise.
+func (c VarsStoreCmd) Rotate(opts VarsStoreRotateOpts) error {
+	if len(opts.Names) == 0 && opts.ExpiringWithin.Duration == 0 {
+		return bosherr.Error("Expected certificates to rotate to be selected with --name or --expiring-within")
+	}
+
+	store := opts.Args.VarsStore.WithKeyFile(opts.KeyFile)
+
+	vars, cipher, err := store.loadWithCipher()
+	if err != nil {
+		return err
+	}
+
+	certs, err := FindVarsStoreCertificates(vars)
+	if err != nil {
+		return err
+	}
+
+	certs, err = c.selectForRotation(certs, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(certs) == 0 {
+		c.ui.PrintLinef("No certificates to rotate")
+		return nil
+	}
+
+	generator, err := cfgtypes.NewValueGeneratorConcrete(NewVarsCertLoader(vars)).GetGenerator("certificate")
+	if err != nil {
+		return err
+	}
+
+	oldCAs := map[string]string{}
+
+	for _, cert := range certs {
+		genOpts, err := cert.GenerationOptions()
+		if err != nil {
+			return err
+		}
+
+		val, err := generator.Generate(genOpts)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Generating certificate '%s'", cert.Name)
+		}
+
+		certVal := val.(cfgtypes.CertResponse)
+
+		if opts.KeepOldCA {
+			if cert.Certificate.IsCA {
+				oldCAs[cert.Name] = cert.CertificatePEM
+			}
+
+			issuerName := cert.CAName
+			if len(issuerName) == 0 {
+				issuerName = cert.Name
+			}
+
+			if oldCA, found := oldCAs[issuerName]; found {
+				certVal.CA += oldCA
+			}
+		}
+
+		// Later certificates are issued by CAs rotated earlier
+		vars[cert.Name] = certVal
+
+		c.ui.PrintLinef("Rotated certificate '%s' (expiry changed from '%s' to '%s')",
+			cert.Name, cert.Certificate.NotAfter.UTC().Format(time.RFC3339), c.notAfter(certVal))
+	}
+
+	return store.save(vars, cipher)
+}
+
+// selectForRotation returns selected certificates and certificates issued by them
+// ordered so that issuing CAs come before certificates they issue.
+func (c VarsStoreCmd) selectForRotation(certs []VarsStoreCertificate, opts VarsStoreRotateOpts) ([]VarsStoreCertificate, error) {
+	selected := map[string]bool{}
+
+	for _, name := range opts.Names {
+		var found bool
+
+		for _, cert := range certs {
+			if cert.Name == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, bosherr.Errorf("Expected to find certificate variable '%s'", name)
+		}
+
+		selected[name] = true
+	}
+
+	if opts.ExpiringWithin.Duration > 0 {
+		threshold := c.timeService.Now().Add(opts.ExpiringWithin.Duration)
+
+		for _, cert := range certs {
+			if cert.Certificate.NotAfter.Before(threshold) {
+				selected[cert.Name] = true
+			}
+		}
+	}
+
+	var result []VarsStoreCertificate
+
+	for _, cert := range certs {
+		if selected[cert.Name] {
+			result = append(result, cert)
+			continue
+		}
+
+		for _, caName := range cert.Chain {
+			if selected[caName] {
+				result = append(result, cert)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i].Chain) < len(result[j].Chain) })
+
+	return result, nil
+}
+
+func (c VarsStoreCmd) notAfter(val cfgtypes.CertResponse) string {
+	cert, found, err := parseVarsStoreCertificate("", map[interface{}]interface{}{"certificate": val.Certificate})
+	if err != nil || !found {
+		return ""
+	}
+
+	return cert.Certificate.NotAfter.UTC().Format(time.RFC3339)
+}
+
+func (c VarsStoreCmd) decrypt(path, keyFile string) ([]byte, error) {
+	contents, err := c.read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsEncryptedVarsStore(contents) {
+		return nil, bosherr.Errorf("Expected vars store '%s' to be encrypted", path)
+	}
+
+	cipher, err := NewVarsStoreKeySource(VarsStoreEnvPrefix, keyFile, c.fs, c.ui).Cipher(false)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err = cipher.Decrypt(contents)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Decrypting vars store '%s'", path)
+	}
+
+	return contents, nil
+}
+
+func (c VarsStoreCmd) read(path string) ([]byte, error) {
+	contents, err := c.fs.ReadFile(path)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading vars store '%s'", path)
+	}
+
+	return contents, nil
+}
+
+func (c VarsStoreCmd) write(path string, contents []byte, cipher *VarsStoreCipher) error {
+	if cipher != nil {
+		var err error
+
+		contents, err = cipher.Encrypt(contents)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Encrypting vars store '%s'", path)
+		}
+	}
+
+	err := c.fs.WriteFile(path, contents)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing vars store '%s'", path)
+	}
+
+	return nil
+}
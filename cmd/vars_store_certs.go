@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+// VarsStoreCertificate is a certificate variable found in vars store.
+type VarsStoreCertificate struct {
+	Name        string
+	Certificate *x509.Certificate
+
+	// CertificatePEM is kept to be able to trust old certificate after rotation
+	CertificatePEM string
+
+	// CAName is a name of the variable with issuing CA; empty if self-signed
+	// or if issuing CA is not found in vars store (see IssuerFound)
+	CAName      string
+	IssuerFound bool
+
+	// Chain includes names of issuing CAs from closest to root
+	Chain []string
+}
+
+// FindVarsStoreCertificates finds variables that have certificate values
+// and links them to issuing CAs found in the same vars store.
+func FindVarsStoreCertificates(vars boshtpl.StaticVariables) ([]VarsStoreCertificate, error) {
+	var certs []VarsStoreCertificate
+
+	for name, val := range vars {
+		cert, found, err := parseVarsStoreCertificate(name, val)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			certs = append(certs, cert)
+		}
+	}
+
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Name < certs[j].Name })
+
+	for i, cert := range certs {
+		if cert.IsSelfSigned() {
+			certs[i].IssuerFound = true
+			continue
+		}
+
+		for _, ca := range certs {
+			if ca.Name != cert.Name && ca.Certificate.IsCA && cert.Certificate.CheckSignatureFrom(ca.Certificate) == nil {
+				certs[i].CAName = ca.Name
+				certs[i].IssuerFound = true
+				break
+			}
+		}
+	}
+
+	byName := map[string]VarsStoreCertificate{}
+
+	for _, cert := range certs {
+		byName[cert.Name] = cert
+	}
+
+	for i, cert := range certs {
+		for caName := cert.CAName; len(caName) > 0; caName = byName[caName].CAName {
+			// Protects against cross-signed CAs issuing each other
+			if caName == cert.Name || len(certs[i].Chain) > len(certs) {
+				break
+			}
+
+			certs[i].Chain = append(certs[i].Chain, caName)
+		}
+	}
+
+	return certs, nil
+}
+
+func parseVarsStoreCertificate(name string, val interface{}) (VarsStoreCertificate, bool, error) {
+	if _, ok := val.(map[interface{}]interface{}); !ok {
+		return VarsStoreCertificate{}, false, nil
+	}
+
+	// Convert to YAML for easier struct parsing
+	valBytes, err := yaml.Marshal(val)
+	if err != nil {
+		return VarsStoreCertificate{}, false, bosherr.WrapErrorf(err, "Expected variable '%s' to be serializable", name)
+	}
+
+	var certVal struct {
+		Certificate string
+	}
+
+	err = yaml.Unmarshal(valBytes, &certVal)
+	if err != nil || len(certVal.Certificate) == 0 {
+		return VarsStoreCertificate{}, false, nil
+	}
+
+	block, _ := pem.Decode([]byte(certVal.Certificate))
+	if block == nil {
+		return VarsStoreCertificate{}, false, bosherr.Errorf("Expected variable '%s' certificate to contain PEM formatted block", name)
+	}
+
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return VarsStoreCertificate{}, false, bosherr.WrapErrorf(err, "Parsing variable '%s' certificate", name)
+	}
+
+	cert := VarsStoreCertificate{
+		Name:           name,
+		Certificate:    crt,
+		CertificatePEM: string(pem.EncodeToMemory(block)),
+	}
+
+	return cert, true, nil
+}
+
+func (c VarsStoreCertificate) IsSelfSigned() bool {
+	return bytes.Equal(c.Certificate.RawIssuer, c.Certificate.RawSubject) &&
+		c.Certificate.CheckSignatureFrom(c.Certificate) == nil
+}
+
+// AlternativeNames returns DNS names and IPs in the same form
+// as certificate variable 'alternative_names' option.
+func (c VarsStoreCertificate) AlternativeNames() []string {
+	names := append([]string{}, c.Certificate.DNSNames...)
+
+	for _, ip := range c.Certificate.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	return names
+}
+
+// GenerationOptions returns certificate variable options
+// that generate certificate similar to the current one.
+func (c VarsStoreCertificate) GenerationOptions() (map[interface{}]interface{}, error) {
+	if !c.IssuerFound {
+		return nil, bosherr.Errorf("Expected issuing CA of certificate '%s' to be in vars store", c.Name)
+	}
+
+	if !c.Certificate.IsCA && len(c.CAName) == 0 {
+		return nil, bosherr.Errorf("Expected certificate '%s' to be a CA or to be issued by a CA", c.Name)
+	}
+
+	opts := map[interface{}]interface{}{
+		"common_name": c.Certificate.Subject.CommonName,
+	}
+
+	if len(c.Certificate.Subject.Organization) > 0 {
+		opts["organization"] = c.Certificate.Subject.Organization[0]
+	}
+
+	if c.Certificate.IsCA {
+		opts["is_ca"] = true
+	}
+
+	if len(c.CAName) > 0 {
+		opts["ca"] = c.CAName
+	}
+
+	if altNames := c.AlternativeNames(); len(altNames) > 0 {
+		opts["alternative_names"] = altNames
+	}
+
+	var extKeyUsages []string
+
+	for _, usage := range c.Certificate.ExtKeyUsage {
+		switch usage {
+		case x509.ExtKeyUsageClientAuth:
+			extKeyUsages = append(extKeyUsages, "client_auth")
+		case x509.ExtKeyUsageServerAuth:
+			extKeyUsages = append(extKeyUsages, "server_auth")
+		}
+	}
+
+	if len(extKeyUsages) > 0 {
+		opts["extended_key_usage"] = extKeyUsages
+	}
+
+	return opts, nil
+}
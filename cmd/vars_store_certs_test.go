@@ -0,0 +1,146 @@
+package cmd_test
+
+import (
+	cfgtypes "github.com/cloudfoundry/config-server/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+var varsStoreCertsFixture boshtpl.StaticVariables
+
+// generateVarsStoreCerts returns vars with root CA, intermediate CA and leaf certificates.
+// Certificates are generated once since RSA key generation is slow.
+func generateVarsStoreCerts() boshtpl.StaticVariables {
+	if varsStoreCertsFixture == nil {
+		vars := boshtpl.StaticVariables{"password": "secret"}
+
+		generator, err := cfgtypes.NewValueGeneratorConcrete(NewVarsCertLoader(vars)).GetGenerator("certificate")
+		Expect(err).ToNot(HaveOccurred())
+
+		defs := []boshtpl.VariableDefinition{
+			{Name: "root_ca", Options: map[interface{}]interface{}{"is_ca": true, "common_name": "root"}},
+			{Name: "int_ca", Options: map[interface{}]interface{}{"is_ca": true, "common_name": "int", "ca": "root_ca"}},
+			{Name: "leaf", Options: map[interface{}]interface{}{
+				"common_name":        "leaf",
+				"ca":                 "int_ca",
+				"organization":       "org",
+				"alternative_names":  []interface{}{"leaf.com", "10.0.0.1"},
+				"extended_key_usage": []interface{}{"client_auth"},
+			}},
+		}
+
+		for _, def := range defs {
+			val, err := generator.Generate(def.Options)
+			Expect(err).ToNot(HaveOccurred())
+
+			bytes, err := yaml.Marshal(val)
+			Expect(err).ToNot(HaveOccurred())
+
+			var mapVal map[interface{}]interface{}
+
+			err = yaml.Unmarshal(bytes, &mapVal)
+			Expect(err).ToNot(HaveOccurred())
+
+			vars[def.Name] = mapVal
+		}
+
+		varsStoreCertsFixture = vars
+	}
+
+	vars := boshtpl.StaticVariables{}
+
+	for k, v := range varsStoreCertsFixture {
+		vars[k] = v
+	}
+
+	return vars
+}
+
+var _ = Describe("FindVarsStoreCertificates", func() {
+	It("finds certificates with their CA chains", func() {
+		certs, err := FindVarsStoreCertificates(generateVarsStoreCerts())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certs).To(HaveLen(3))
+
+		Expect(certs[0].Name).To(Equal("int_ca"))
+		Expect(certs[0].CAName).To(Equal("root_ca"))
+		Expect(certs[0].Chain).To(Equal([]string{"root_ca"}))
+		Expect(certs[0].IsSelfSigned()).To(BeFalse())
+
+		Expect(certs[1].Name).To(Equal("leaf"))
+		Expect(certs[1].Certificate.Subject.CommonName).To(Equal("leaf"))
+		Expect(certs[1].CAName).To(Equal("int_ca"))
+		Expect(certs[1].Chain).To(Equal([]string{"int_ca", "root_ca"}))
+		Expect(certs[1].AlternativeNames()).To(Equal([]string{"leaf.com", "10.0.0.1"}))
+
+		Expect(certs[2].Name).To(Equal("root_ca"))
+		Expect(certs[2].CAName).To(BeEmpty())
+		Expect(certs[2].Chain).To(BeEmpty())
+		Expect(certs[2].IsSelfSigned()).To(BeTrue())
+		Expect(certs[2].IssuerFound).To(BeTrue())
+	})
+
+	It("marks certificates whose issuing CA is not in vars store", func() {
+		vars := generateVarsStoreCerts()
+		delete(vars, "int_ca")
+
+		certs, err := FindVarsStoreCertificates(vars)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certs).To(HaveLen(2))
+
+		Expect(certs[0].Name).To(Equal("leaf"))
+		Expect(certs[0].IssuerFound).To(BeFalse())
+		Expect(certs[0].Chain).To(BeEmpty())
+
+		_, err = certs[0].GenerationOptions()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected issuing CA of certificate 'leaf' to be in vars store"))
+	})
+
+	It("returns error if certificate cannot be parsed", func() {
+		certs, err := FindVarsStoreCertificates(boshtpl.StaticVariables{
+			"cert": map[interface{}]interface{}{"certificate": "not-pem"},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected variable 'cert' certificate to contain PEM formatted block"))
+		Expect(certs).To(BeNil())
+	})
+
+	Describe("GenerationOptions", func() {
+		It("returns options that generate similar certificates", func() {
+			certs, err := FindVarsStoreCertificates(generateVarsStoreCerts())
+			Expect(err).ToNot(HaveOccurred())
+
+			opts, err := certs[0].GenerationOptions()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opts).To(Equal(map[interface{}]interface{}{
+				"common_name":  "int",
+				"organization": "Cloud Foundry",
+				"is_ca":        true,
+				"ca":           "root_ca",
+			}))
+
+			opts, err = certs[1].GenerationOptions()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opts).To(Equal(map[interface{}]interface{}{
+				"common_name":        "leaf",
+				"organization":       "org",
+				"ca":                 "int_ca",
+				"alternative_names":  []string{"leaf.com", "10.0.0.1"},
+				"extended_key_usage": []string{"client_auth"},
+			}))
+
+			opts, err = certs[2].GenerationOptions()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opts).To(Equal(map[interface{}]interface{}{
+				"common_name":  "root",
+				"organization": "Cloud Foundry",
+				"is_ca":        true,
+			}))
+		})
+	})
+})
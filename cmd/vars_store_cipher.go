@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	varsStoreEncryptionVersion = 1
+	varsStoreCipherName        = "aes-256-gcm"
+	varsStoreKDFNone           = "none"
+	varsStoreKDFPBKDF2         = "pbkdf2-sha256"
+	varsStoreKDFIterations     = 200000
+	varsStoreMaxKDFIterations  = 10000000
+	varsStoreKeyLen            = 32
+	varsStoreSaltLen           = 16
+)
+
+// VarsStoreKey is either a 32 byte key or a passphrase that a key is derived from.
+type VarsStoreKey struct {
+	Key        []byte
+	Passphrase string
+}
+
+type varsStoreEnvelope struct {
+	Encrypted varsStoreEncryptedData `yaml:"encrypted_vars_store"`
+}
+
+type varsStoreEncryptedData struct {
+	Version       int    `yaml:"version"`
+	Cipher        string `yaml:"cipher"`
+	KDF           string `yaml:"kdf"`
+	KDFIterations int    `yaml:"kdf_iterations,omitempty"`
+	Salt          string `yaml:"salt,omitempty"`
+	Nonce         string `yaml:"nonce"`
+	Ciphertext    string `yaml:"ciphertext"`
+}
+
+// VarsStoreCipher encrypts vars store contents with AES-256-GCM
+// into a YAML document so that encrypted files remain diffable text.
+type VarsStoreCipher struct {
+	key  VarsStoreKey
+	rand io.Reader
+
+	// Derived key is kept for the last used salt since key derivation is slow
+	salt       []byte
+	iterations int
+	derivedKey []byte
+}
+
+func NewVarsStoreCipher(key VarsStoreKey) *VarsStoreCipher {
+	return &VarsStoreCipher{key: key, rand: rand.Reader}
+}
+
+// IsEncryptedVarsStore checks if contents were produced by VarsStoreCipher.
+func IsEncryptedVarsStore(data []byte) bool {
+	var envelope varsStoreEnvelope
+
+	err := yaml.Unmarshal(data, &envelope)
+	if err != nil {
+		return false
+	}
+
+	return envelope.Encrypted.Version > 0
+}
+
+func (c *VarsStoreCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	data := varsStoreEncryptedData{
+		Version: varsStoreEncryptionVersion,
+		Cipher:  varsStoreCipherName,
+		KDF:     varsStoreKDFNone,
+	}
+
+	key := c.key.Key
+
+	if len(c.key.Passphrase) > 0 {
+		if c.derivedKey == nil {
+			salt := make([]byte, varsStoreSaltLen)
+
+			_, err := io.ReadFull(c.rand, salt)
+			if err != nil {
+				return nil, bosherr.WrapError(err, "Generating salt")
+			}
+
+			c.deriveKey(salt, varsStoreKDFIterations)
+		}
+
+		key = c.derivedKey
+
+		data.KDF = varsStoreKDFPBKDF2
+		data.KDFIterations = c.iterations
+		data.Salt = base64.StdEncoding.EncodeToString(c.salt)
+	}
+
+	aead, err := c.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err = io.ReadFull(c.rand, nonce)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Generating nonce")
+	}
+
+	data.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	data.Ciphertext = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, c.additionalData(data)))
+
+	contents, err := yaml.Marshal(varsStoreEnvelope{Encrypted: data})
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Serializing encrypted variables")
+	}
+
+	return contents, nil
+}
+
+func (c *VarsStoreCipher) Decrypt(contents []byte) ([]byte, error) {
+	var envelope varsStoreEnvelope
+
+	err := yaml.Unmarshal(contents, &envelope)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Deserializing encrypted variables")
+	}
+
+	data := envelope.Encrypted
+
+	if data.Version != varsStoreEncryptionVersion || data.Cipher != varsStoreCipherName {
+		return nil, bosherr.Errorf("Expected encrypted variables to be version '%d' using '%s' but was version '%d' using '%s'",
+			varsStoreEncryptionVersion, varsStoreCipherName, data.Version, data.Cipher)
+	}
+
+	var key []byte
+
+	switch data.KDF {
+	case varsStoreKDFNone:
+		if len(c.key.Key) == 0 {
+			return nil, bosherr.Error("Expected key to be provided since variables were encrypted with a key, not a passphrase")
+		}
+
+		key = c.key.Key
+
+	case varsStoreKDFPBKDF2:
+		if len(c.key.Passphrase) == 0 {
+			return nil, bosherr.Error("Expected passphrase to be provided since variables were encrypted with a passphrase, not a key")
+		}
+
+		salt, err := base64.StdEncoding.DecodeString(data.Salt)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Decoding salt")
+		}
+
+		// Iterations are checked before deriving a key since they are not authenticated until decryption
+		if data.KDFIterations < varsStoreKDFIterations || data.KDFIterations > varsStoreMaxKDFIterations {
+			return nil, bosherr.Errorf("Expected key derivation iterations to be between %d and %d but was %d",
+				varsStoreKDFIterations, varsStoreMaxKDFIterations, data.KDFIterations)
+		}
+
+		if !bytes.Equal(salt, c.salt) || data.KDFIterations != c.iterations {
+			c.deriveKey(salt, data.KDFIterations)
+		}
+
+		key = c.derivedKey
+
+	default:
+		return nil, bosherr.Errorf("Expected key derivation function '%s' to be supported", data.KDF)
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(data.Nonce)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Decoding nonce")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(data.Ciphertext)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Decoding ciphertext")
+	}
+
+	aead, err := c.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != aead.NonceSize() {
+		return nil, bosherr.Errorf("Expected nonce to be %d bytes", aead.NonceSize())
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, c.additionalData(data))
+	if err != nil {
+		return nil, bosherr.Error("Decrypting variables failed: key or passphrase is incorrect or contents were modified")
+	}
+
+	return plaintext, nil
+}
+
+func (c *VarsStoreCipher) aead(key []byte) (cipher.AEAD, error) {
+	if len(key) != varsStoreKeyLen {
+		return nil, bosherr.Errorf("Expected key to be %d bytes but was %d bytes", varsStoreKeyLen, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Building cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Building cipher")
+	}
+
+	return aead, nil
+}
+
+// additionalData authenticates encryption parameters so that they cannot be tampered with
+func (c *VarsStoreCipher) additionalData(data varsStoreEncryptedData) []byte {
+	data.Nonce = ""
+	data.Ciphertext = ""
+
+	params, _ := yaml.Marshal(data)
+
+	return params
+}
+
+func (c *VarsStoreCipher) deriveKey(salt []byte, iterations int) {
+	c.salt = salt
+	c.iterations = iterations
+	c.derivedKey = pbkdf2.Key([]byte(c.key.Passphrase), salt, iterations, varsStoreKeyLen, sha256.New)
+}
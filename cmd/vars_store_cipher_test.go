@@ -0,0 +1,115 @@
+package cmd_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+)
+
+var _ = Describe("VarsStoreCipher", func() {
+	var (
+		key = []byte("01234567890123456789012345678901")
+	)
+
+	It("encrypts and decrypts contents with a key", func() {
+		cipher := NewVarsStoreCipher(VarsStoreKey{Key: key})
+
+		encrypted, err := cipher.Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(encrypted)).ToNot(ContainSubstring("val"))
+		Expect(string(encrypted)).To(ContainSubstring("kdf: none"))
+		Expect(IsEncryptedVarsStore(encrypted)).To(BeTrue())
+
+		decrypted, err := NewVarsStoreCipher(VarsStoreKey{Key: key}).Decrypt(encrypted)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal("key: val\n"))
+	})
+
+	It("encrypts and decrypts contents with a passphrase", func() {
+		cipher := NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"})
+
+		encrypted, err := cipher.Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(encrypted)).To(ContainSubstring("kdf: pbkdf2-sha256"))
+
+		decrypted, err := NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"}).Decrypt(encrypted)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decrypted)).To(Equal("key: val\n"))
+
+		_, err = NewVarsStoreCipher(VarsStoreKey{Passphrase: "other"}).Decrypt(encrypted)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("key or passphrase is incorrect"))
+	})
+
+	It("uses new nonce every time contents are encrypted", func() {
+		cipher := NewVarsStoreCipher(VarsStoreKey{Key: key})
+
+		encrypted1, err := cipher.Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		encrypted2, err := cipher.Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(encrypted1).ToNot(Equal(encrypted2))
+	})
+
+	It("returns error if key is incorrect", func() {
+		encrypted, err := NewVarsStoreCipher(VarsStoreKey{Key: key}).Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = NewVarsStoreCipher(VarsStoreKey{Key: []byte("10987654321098765432109876543210")}).Decrypt(encrypted)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("key or passphrase is incorrect"))
+	})
+
+	It("returns error if encryption parameters were modified", func() {
+		encrypted, err := NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"}).Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		modified := strings.Replace(string(encrypted), "kdf_iterations: 200000", "kdf_iterations: 200001", 1)
+		Expect(modified).ToNot(Equal(string(encrypted)))
+
+		_, err = NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"}).Decrypt([]byte(modified))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("contents were modified"))
+	})
+
+	It("returns error if key derivation iterations are out of bounds", func() {
+		encrypted, err := NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"}).Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, iterations := range []string{"1", "199999", "10000001"} {
+			modified := strings.Replace(string(encrypted), "kdf_iterations: 200000", "kdf_iterations: "+iterations, 1)
+
+			_, err = NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"}).Decrypt([]byte(modified))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected key derivation iterations to be between 200000 and 10000000 but was " + iterations))
+		}
+	})
+
+	It("returns error if contents were encrypted with a passphrase but key is given", func() {
+		encrypted, err := NewVarsStoreCipher(VarsStoreKey{Passphrase: "passphrase"}).Encrypt([]byte("key: val\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = NewVarsStoreCipher(VarsStoreKey{Key: key}).Decrypt(encrypted)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected passphrase to be provided since variables were encrypted with a passphrase, not a key"))
+	})
+
+	It("returns error if key has wrong length", func() {
+		_, err := NewVarsStoreCipher(VarsStoreKey{Key: []byte("short")}).Encrypt([]byte("key: val\n"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected key to be 32 bytes but was 5 bytes"))
+	})
+
+	Describe("IsEncryptedVarsStore", func() {
+		It("returns false for plain variables", func() {
+			Expect(IsEncryptedVarsStore([]byte("key: val\n"))).To(BeFalse())
+			Expect(IsEncryptedVarsStore([]byte("---"))).To(BeFalse())
+			Expect(IsEncryptedVarsStore([]byte("- item"))).To(BeFalse())
+		})
+	})
+})
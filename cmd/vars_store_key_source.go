@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+const (
+	VarsStoreEnvPrefix    = "BOSH_VARS_STORE_"
+	VarsStoreNewEnvPrefix = "BOSH_VARS_STORE_NEW_"
+)
+
+/*
+VarsStoreKeySource finds key for vars store encryption. In order:
+
+  - key file given explicitly (e.g. via --key-file)
+  - <prefix>KEY environment variable with base64 encoded 32 byte key
+  - <prefix>KEY_FILE environment variable with path to a file containing such key
+  - <prefix>PASSPHRASE environment variable
+  - passphrase prompt if UI is interactive
+
+Key could be generated with 'openssl rand -base64 32'.
+*/
+type VarsStoreKeySource struct {
+	envPrefix string
+	keyFile   string
+
+	fs boshsys.FileSystem
+	ui boshui.UI
+
+	GetenvFunc func(string) string
+
+	cipher *VarsStoreCipher
+}
+
+func NewVarsStoreKeySource(envPrefix, keyFile string, fs boshsys.FileSystem, ui boshui.UI) *VarsStoreKeySource {
+	return &VarsStoreKeySource{
+		envPrefix: envPrefix,
+		keyFile:   keyFile,
+
+		fs: fs,
+		ui: ui,
+
+		GetenvFunc: os.Getenv,
+	}
+}
+
+// ConfiguredCipher returns cipher only if key was configured without prompting.
+func (s *VarsStoreKeySource) ConfiguredCipher() (*VarsStoreCipher, bool, error) {
+	if s.cipher != nil {
+		return s.cipher, true, nil
+	}
+
+	key, found, err := s.configuredKey()
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	s.cipher = NewVarsStoreCipher(key)
+
+	return s.cipher, true, nil
+}
+
+// Cipher returns configured cipher or asks for a passphrase.
+// Passphrase is asked twice when confirm is true.
+func (s *VarsStoreKeySource) Cipher(confirm bool) (*VarsStoreCipher, error) {
+	cipher, found, err := s.ConfiguredCipher()
+	if err != nil || found {
+		return cipher, err
+	}
+
+	if s.ui == nil || !s.ui.IsInteractive() {
+		return nil, bosherr.Errorf(
+			"Expected vars store key to be provided via '%sKEY', '%sKEY_FILE' or '%sPASSPHRASE' environment variables",
+			s.envPrefix, s.envPrefix, s.envPrefix)
+	}
+
+	label := "Vars store passphrase"
+	if s.envPrefix == VarsStoreNewEnvPrefix {
+		label = "New vars store passphrase"
+	}
+
+	passphrase, err := s.ui.AskForPassword(label)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Asking for passphrase")
+	}
+
+	if len(passphrase) == 0 {
+		return nil, bosherr.Error("Expected passphrase to be non-empty")
+	}
+
+	if confirm {
+		confirmation, err := s.ui.AskForPassword(label + " (confirm)")
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Asking for passphrase")
+		}
+
+		if confirmation != passphrase {
+			return nil, bosherr.Error("Expected passphrase confirmation to match passphrase")
+		}
+	}
+
+	s.cipher = NewVarsStoreCipher(VarsStoreKey{Passphrase: passphrase})
+
+	return s.cipher, nil
+}
+
+func (s *VarsStoreKeySource) configuredKey() (VarsStoreKey, bool, error) {
+	if len(s.keyFile) > 0 {
+		return s.readKeyFile(s.keyFile)
+	}
+
+	if encodedKey := s.GetenvFunc(s.envPrefix + "KEY"); len(encodedKey) > 0 {
+		key, err := s.decodeKey(encodedKey)
+		if err != nil {
+			return VarsStoreKey{}, false, bosherr.WrapErrorf(err, "Decoding key from '%sKEY'", s.envPrefix)
+		}
+
+		return key, true, nil
+	}
+
+	if keyFile := s.GetenvFunc(s.envPrefix + "KEY_FILE"); len(keyFile) > 0 {
+		return s.readKeyFile(keyFile)
+	}
+
+	if passphrase := s.GetenvFunc(s.envPrefix + "PASSPHRASE"); len(passphrase) > 0 {
+		return VarsStoreKey{Passphrase: passphrase}, true, nil
+	}
+
+	return VarsStoreKey{}, false, nil
+}
+
+func (s *VarsStoreKeySource) readKeyFile(path string) (VarsStoreKey, bool, error) {
+	absPath, err := s.fs.ExpandPath(path)
+	if err != nil {
+		return VarsStoreKey{}, false, bosherr.WrapErrorf(err, "Getting absolute path '%s'", path)
+	}
+
+	contents, err := s.fs.ReadFileString(absPath)
+	if err != nil {
+		return VarsStoreKey{}, false, bosherr.WrapErrorf(err, "Reading key file '%s'", absPath)
+	}
+
+	key, err := s.decodeKey(contents)
+	if err != nil {
+		return VarsStoreKey{}, false, bosherr.WrapErrorf(err, "Decoding key from file '%s'", absPath)
+	}
+
+	return key, true, nil
+}
+
+func (s *VarsStoreKeySource) decodeKey(encodedKey string) (VarsStoreKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return VarsStoreKey{}, err
+	}
+
+	if len(key) != varsStoreKeyLen {
+		return VarsStoreKey{}, bosherr.Errorf("Expected key to be %d bytes but was %d bytes", varsStoreKeyLen, len(key))
+	}
+
+	return VarsStoreKey{Key: key}, nil
+}
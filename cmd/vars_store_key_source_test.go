@@ -0,0 +1,145 @@
+package cmd_test
+
+import (
+	"encoding/base64"
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("VarsStoreKeySource", func() {
+	var (
+		fs     *fakesys.FakeFileSystem
+		ui     *fakeui.FakeUI
+		env    map[string]string
+		key    []byte
+		source *VarsStoreKeySource
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		env = map[string]string{}
+		key = []byte("01234567890123456789012345678901")
+	})
+
+	build := func(keyFile string) *VarsStoreKeySource {
+		source := NewVarsStoreKeySource("PREFIX_", keyFile, fs, ui)
+		source.GetenvFunc = func(name string) string { return env[name] }
+		return source
+	}
+
+	encryptAndCheck := func(cipher *VarsStoreCipher, expectedKey VarsStoreKey) {
+		encrypted, err := cipher.Encrypt([]byte("key: val"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = NewVarsStoreCipher(expectedKey).Decrypt(encrypted)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Describe("ConfiguredCipher", func() {
+		It("uses explicitly given key file", func() {
+			fs.WriteFileString("/key", base64.StdEncoding.EncodeToString(key)+"\n")
+			env["PREFIX_PASSPHRASE"] = "passphrase"
+
+			source = build("/key")
+
+			cipher, found, err := source.ConfiguredCipher()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			encryptAndCheck(cipher, VarsStoreKey{Key: key})
+		})
+
+		It("uses key from environment", func() {
+			env["PREFIX_KEY"] = base64.StdEncoding.EncodeToString(key)
+			env["PREFIX_PASSPHRASE"] = "passphrase"
+
+			cipher, found, err := build("").ConfiguredCipher()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			encryptAndCheck(cipher, VarsStoreKey{Key: key})
+		})
+
+		It("uses key file from environment", func() {
+			fs.WriteFileString("/key", base64.StdEncoding.EncodeToString(key))
+			env["PREFIX_KEY_FILE"] = "/key"
+
+			cipher, found, err := build("").ConfiguredCipher()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			encryptAndCheck(cipher, VarsStoreKey{Key: key})
+		})
+
+		It("uses passphrase from environment", func() {
+			env["PREFIX_PASSPHRASE"] = "passphrase"
+
+			cipher, found, err := build("").ConfiguredCipher()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			encryptAndCheck(cipher, VarsStoreKey{Passphrase: "passphrase"})
+		})
+
+		It("returns not found if nothing is configured", func() {
+			_, found, err := build("").ConfiguredCipher()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns error if key is not 32 bytes", func() {
+			env["PREFIX_KEY"] = base64.StdEncoding.EncodeToString([]byte("short"))
+
+			_, _, err := build("").ConfiguredCipher()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Decoding key from 'PREFIX_KEY': Expected key to be 32 bytes but was 5 bytes"))
+		})
+
+		It("returns error if key file cannot be read", func() {
+			fs.RegisterReadFileError("/key", errors.New("fake-err"))
+
+			_, _, err := build("/key").ConfiguredCipher()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading key file '/key'"))
+		})
+	})
+
+	Describe("Cipher", func() {
+		It("asks for passphrase if nothing is configured and UI is interactive", func() {
+			ui.Interactive = true
+			ui.AskedPasswords = []fakeui.Answer{{Text: "passphrase"}, {Text: "passphrase"}}
+
+			source = build("")
+
+			cipher, err := source.Cipher(true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.AskedPasswordLabels).To(Equal([]string{
+				"Vars store passphrase", "Vars store passphrase (confirm)"}))
+			encryptAndCheck(cipher, VarsStoreKey{Passphrase: "passphrase"})
+
+			By("remembering passphrase")
+			_, err = source.Cipher(false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.AskedPasswordLabels).To(HaveLen(2))
+		})
+
+		It("returns error if passphrase confirmation does not match", func() {
+			ui.Interactive = true
+			ui.AskedPasswords = []fakeui.Answer{{Text: "passphrase"}, {Text: "other"}}
+
+			_, err := build("").Cipher(true)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected passphrase confirmation to match passphrase"))
+		})
+
+		It("returns error if nothing is configured and UI is not interactive", func() {
+			_, err := build("").Cipher(false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected vars store key to be provided via 'PREFIX_KEY', 'PREFIX_KEY_FILE' or 'PREFIX_PASSPHRASE' environment variables"))
+			Expect(ui.AskedPasswordLabels).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,410 @@
+package cmd_test
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("VarsStoreCmd", func() {
+	var (
+		fs          *fakesys.FakeFileSystem
+		ui          *fakeui.FakeUI
+		timeService *fakeclock.FakeClock
+		key         []byte
+		newKey      []byte
+		command     VarsStoreCmd
+		args        VarsStoreArgs
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		timeService = fakeclock.NewFakeClock(time.Now())
+		command = NewVarsStoreCmd(fs, ui, timeService)
+
+		key = []byte("01234567890123456789012345678901")
+		newKey = []byte("10987654321098765432109876543210")
+
+		fs.WriteFileString("/key", base64.StdEncoding.EncodeToString(key))
+		fs.WriteFileString("/new-key", base64.StdEncoding.EncodeToString(newKey))
+
+		args = VarsStoreArgs{Path: FileArg{ExpandedPath: "/creds.yml"}}
+	})
+
+	encrypt := func(contents string, key VarsStoreKey) {
+		encrypted, err := NewVarsStoreCipher(key).Encrypt([]byte(contents))
+		Expect(err).ToNot(HaveOccurred())
+
+		fs.WriteFile("/creds.yml", encrypted)
+	}
+
+	decrypt := func(key VarsStoreKey) string {
+		contents, err := fs.ReadFile("/creds.yml")
+		Expect(err).ToNot(HaveOccurred())
+
+		decrypted, err := NewVarsStoreCipher(key).Decrypt(contents)
+		Expect(err).ToNot(HaveOccurred())
+
+		return string(decrypted)
+	}
+
+	Describe("Encrypt", func() {
+		It("encrypts plaintext file with key from key file", func() {
+			fs.WriteFileString("/creds.yml", "key: val\n")
+
+			err := command.Encrypt(VarsStoreEncryptOpts{Args: args, KeyFile: "/key"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(decrypt(VarsStoreKey{Key: key})).To(Equal("key: val\n"))
+			Expect(ui.Said).To(Equal([]string{"Encrypted vars store '/creds.yml'"}))
+		})
+
+		It("encrypts plaintext file with confirmed passphrase", func() {
+			ui.Interactive = true
+			ui.AskedPasswords = []fakeui.Answer{{Text: "passphrase"}, {Text: "passphrase"}}
+
+			fs.WriteFileString("/creds.yml", "key: val\n")
+
+			err := command.Encrypt(VarsStoreEncryptOpts{Args: args})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(decrypt(VarsStoreKey{Passphrase: "passphrase"})).To(Equal("key: val\n"))
+		})
+
+		It("returns error if file is already encrypted", func() {
+			encrypt("key: val\n", VarsStoreKey{Key: key})
+
+			err := command.Encrypt(VarsStoreEncryptOpts{Args: args, KeyFile: "/key"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected vars store '/creds.yml' to not be already encrypted"))
+		})
+
+		It("returns error if file cannot be read", func() {
+			err := command.Encrypt(VarsStoreEncryptOpts{Args: args, KeyFile: "/key"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading vars store '/creds.yml'"))
+		})
+	})
+
+	Describe("Decrypt", func() {
+		It("decrypts encrypted file", func() {
+			encrypt("key: val\n", VarsStoreKey{Key: key})
+
+			err := command.Decrypt(VarsStoreDecryptOpts{Args: args, KeyFile: "/key"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/creds.yml")).To(Equal("key: val\n"))
+			Expect(ui.Said).To(Equal([]string{"Decrypted vars store '/creds.yml'"}))
+		})
+
+		It("returns error and leaves file unchanged if key is incorrect", func() {
+			encrypt("key: val\n", VarsStoreKey{Key: key})
+
+			err := command.Decrypt(VarsStoreDecryptOpts{Args: args, KeyFile: "/new-key"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Decrypting vars store '/creds.yml'"))
+
+			Expect(decrypt(VarsStoreKey{Key: key})).To(Equal("key: val\n"))
+		})
+
+		It("returns error if file is not encrypted", func() {
+			fs.WriteFileString("/creds.yml", "key: val\n")
+
+			err := command.Decrypt(VarsStoreDecryptOpts{Args: args, KeyFile: "/key"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected vars store '/creds.yml' to be encrypted"))
+		})
+	})
+
+	Describe("Rekey", func() {
+		It("re-encrypts file with new key", func() {
+			encrypt("key: val\n", VarsStoreKey{Key: key})
+
+			err := command.Rekey(VarsStoreRekeyOpts{Args: args, KeyFile: "/key", NewKeyFile: "/new-key"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(decrypt(VarsStoreKey{Key: newKey})).To(Equal("key: val\n"))
+			Expect(ui.Said).To(Equal([]string{"Re-encrypted vars store '/creds.yml'"}))
+		})
+
+		It("re-encrypts file with new passphrase", func() {
+			ui.Interactive = true
+			ui.AskedPasswords = []fakeui.Answer{{Text: "passphrase"}, {Text: "new-passphrase"}, {Text: "new-passphrase"}}
+
+			encrypt("key: val\n", VarsStoreKey{Passphrase: "passphrase"})
+
+			err := command.Rekey(VarsStoreRekeyOpts{Args: args})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.AskedPasswordLabels).To(Equal([]string{
+				"Vars store passphrase",
+				"New vars store passphrase",
+				"New vars store passphrase (confirm)",
+			}))
+
+			Expect(decrypt(VarsStoreKey{Passphrase: "new-passphrase"})).To(Equal("key: val\n"))
+		})
+
+		It("returns error if file is not encrypted", func() {
+			fs.WriteFileString("/creds.yml", "key: val\n")
+
+			err := command.Rekey(VarsStoreRekeyOpts{Args: args, KeyFile: "/key", NewKeyFile: "/new-key"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected vars store '/creds.yml' to be encrypted"))
+		})
+	})
+
+	Context("certificates", func() {
+		var (
+			vars      boshtpl.StaticVariables
+			store     VarsFSStore
+			notAfter  time.Time
+			storeArgs VarsStoreVarsArgs
+		)
+
+		BeforeEach(func() {
+			vars = generateVarsStoreCerts()
+
+			bytes, err := yaml.Marshal(vars)
+			Expect(err).ToNot(HaveOccurred())
+
+			fs.WriteFile("/creds.yml", bytes)
+
+			store = VarsFSStore{FS: fs}
+
+			err = (&store).UnmarshalFlag("/creds.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			storeArgs = VarsStoreVarsArgs{VarsStore: store}
+
+			certs, err := FindVarsStoreCertificates(vars)
+			Expect(err).ToNot(HaveOccurred())
+
+			notAfter = certs[0].Certificate.NotAfter
+			timeService = fakeclock.NewFakeClock(notAfter.Add(-10*24*time.Hour - time.Hour))
+			command = NewVarsStoreCmd(fs, ui, timeService)
+		})
+
+		parseCert := func(certPEM string) *x509.Certificate {
+			block, _ := pem.Decode([]byte(certPEM))
+			Expect(block).ToNot(BeNil())
+
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).ToNot(HaveOccurred())
+
+			return cert
+		}
+
+		loadVars := func() boshtpl.StaticVariables {
+			var newVars boshtpl.StaticVariables
+
+			bytes, err := fs.ReadFile("/creds.yml")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = yaml.Unmarshal(bytes, &newVars)
+			Expect(err).ToNot(HaveOccurred())
+
+			return newVars
+		}
+
+		certField := func(vars boshtpl.StaticVariables, name, field string) string {
+			return vars[name].(map[interface{}]interface{})[field].(string)
+		}
+
+		Describe("Check", func() {
+			It("shows certificates with their expiry, CA chains and alternative names", func() {
+				err := command.Check(VarsStoreCheckOpts{Args: storeArgs})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table).To(Equal(boshtbl.Table{
+					Content: "certificates",
+
+					Header: []boshtbl.Header{
+						boshtbl.NewHeader("Name"),
+						boshtbl.NewHeader("Common Name"),
+						boshtbl.NewHeader("CA Chain"),
+						boshtbl.NewHeader("Alternative Names"),
+						boshtbl.NewHeader("Expires"),
+						boshtbl.NewHeader("Expires In"),
+					},
+
+					SortBy: []boshtbl.ColumnSort{
+						{Column: 4, Asc: true},
+						{Column: 0, Asc: true},
+					},
+
+					Rows: [][]boshtbl.Value{
+						{
+							boshtbl.NewValueString("int_ca"),
+							boshtbl.NewValueString("int"),
+							boshtbl.NewValueStrings([]string{"root_ca"}),
+							boshtbl.NewValueStrings([]string{}),
+							boshtbl.NewValueTime(notAfter.UTC()),
+							boshtbl.NewValueFmt(boshtbl.NewValueString("10d"), false),
+						},
+						{
+							boshtbl.NewValueString("leaf"),
+							boshtbl.NewValueString("leaf"),
+							boshtbl.NewValueStrings([]string{"int_ca", "root_ca"}),
+							boshtbl.NewValueStrings([]string{"leaf.com", "10.0.0.1"}),
+							boshtbl.NewValueTime(ui.Table.Rows[1][4].(boshtbl.ValueTime).T),
+							ui.Table.Rows[1][5],
+						},
+						{
+							boshtbl.NewValueString("root_ca"),
+							boshtbl.NewValueString("root"),
+							boshtbl.NewValueStrings([]string{"(self-signed)"}),
+							boshtbl.NewValueStrings([]string{}),
+							boshtbl.NewValueTime(ui.Table.Rows[2][4].(boshtbl.ValueTime).T),
+							ui.Table.Rows[2][5],
+						},
+					},
+				}))
+			})
+
+			It("marks expired certificates", func() {
+				timeService.Increment(11 * 24 * time.Hour)
+
+				err := command.Check(VarsStoreCheckOpts{Args: storeArgs})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Rows[0][5]).To(Equal(boshtbl.NewValueFmt(boshtbl.NewValueString("expired"), true)))
+			})
+		})
+
+		Describe("Rotate", func() {
+			It("rotates named CA and certificates issued by it", func() {
+				err := command.Rotate(VarsStoreRotateOpts{Args: storeArgs, Names: []string{"int_ca"}})
+				Expect(err).ToNot(HaveOccurred())
+
+				newVars := loadVars()
+
+				Expect(newVars["root_ca"]).To(Equal(vars["root_ca"]))
+				Expect(newVars["password"]).To(Equal("secret"))
+
+				Expect(certField(newVars, "int_ca", "certificate")).ToNot(Equal(certField(vars, "int_ca", "certificate")))
+				Expect(certField(newVars, "leaf", "certificate")).ToNot(Equal(certField(vars, "leaf", "certificate")))
+
+				newIntCA := parseCert(certField(newVars, "int_ca", "certificate"))
+				newLeaf := parseCert(certField(newVars, "leaf", "certificate"))
+
+				Expect(newLeaf.CheckSignatureFrom(newIntCA)).ToNot(HaveOccurred())
+				Expect(newLeaf.Subject.CommonName).To(Equal("leaf"))
+				Expect(newLeaf.DNSNames).To(Equal([]string{"leaf.com"}))
+				Expect(newLeaf.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}))
+
+				Expect(certField(newVars, "leaf", "ca")).To(Equal(certField(newVars, "int_ca", "certificate")))
+
+				Expect(ui.Said).To(HaveLen(2))
+				Expect(ui.Said[0]).To(HavePrefix("Rotated certificate 'int_ca'"))
+				Expect(ui.Said[1]).To(HavePrefix("Rotated certificate 'leaf'"))
+			})
+
+			It("keeps old CA next to new one when requested", func() {
+				err := command.Rotate(VarsStoreRotateOpts{Args: storeArgs, Names: []string{"int_ca"}, KeepOldCA: true})
+				Expect(err).ToNot(HaveOccurred())
+
+				newVars := loadVars()
+
+				leafCA := certField(newVars, "leaf", "ca")
+				Expect(strings.Count(leafCA, "BEGIN CERTIFICATE")).To(Equal(2))
+				Expect(leafCA).To(HavePrefix(certField(newVars, "int_ca", "certificate")))
+				Expect(leafCA).To(HaveSuffix(certField(vars, "int_ca", "certificate")))
+
+				Expect(certField(newVars, "int_ca", "ca")).To(Equal(certField(vars, "root_ca", "certificate")))
+			})
+
+			It("rotates certificates expiring within duration starting with CAs", func() {
+				err := command.Rotate(VarsStoreRotateOpts{
+					Args:           storeArgs,
+					ExpiringWithin: DurationArg{Duration: 30 * 24 * time.Hour},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Said).To(HaveLen(3))
+				Expect(ui.Said[0]).To(HavePrefix("Rotated certificate 'root_ca'"))
+				Expect(ui.Said[1]).To(HavePrefix("Rotated certificate 'int_ca'"))
+				Expect(ui.Said[2]).To(HavePrefix("Rotated certificate 'leaf'"))
+
+				newVars := loadVars()
+
+				newRootCA := parseCert(certField(newVars, "root_ca", "certificate"))
+				newIntCA := parseCert(certField(newVars, "int_ca", "certificate"))
+
+				Expect(newIntCA.CheckSignatureFrom(newRootCA)).ToNot(HaveOccurred())
+			})
+
+			It("does not change vars store if no certificates expire within duration", func() {
+				err := command.Rotate(VarsStoreRotateOpts{
+					Args:           storeArgs,
+					ExpiringWithin: DurationArg{Duration: 5 * 24 * time.Hour},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Said).To(Equal([]string{"No certificates to rotate"}))
+				Expect(loadVars()).To(Equal(vars))
+			})
+
+			It("returns error if certificates are not selected", func() {
+				err := command.Rotate(VarsStoreRotateOpts{Args: storeArgs})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected certificates to rotate to be selected with --name or --expiring-within"))
+			})
+
+			It("returns error if named certificate is not found", func() {
+				err := command.Rotate(VarsStoreRotateOpts{Args: storeArgs, Names: []string{"password"}})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected to find certificate variable 'password'"))
+			})
+		})
+
+		Context("when vars store is encrypted", func() {
+			BeforeEach(func() {
+				bytes, err := yaml.Marshal(vars)
+				Expect(err).ToNot(HaveOccurred())
+
+				encrypt(string(bytes), VarsStoreKey{Key: key})
+			})
+
+			It("checks certificates with key from key file", func() {
+				err := command.Check(VarsStoreCheckOpts{Args: storeArgs, KeyFile: "/key"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Rows).To(HaveLen(3))
+			})
+
+			It("rotates certificates with key from key file and keeps file encrypted", func() {
+				err := command.Rotate(VarsStoreRotateOpts{Args: storeArgs, KeyFile: "/key", Names: []string{"leaf"}})
+				Expect(err).ToNot(HaveOccurred())
+
+				var newVars boshtpl.StaticVariables
+
+				err = yaml.Unmarshal([]byte(decrypt(VarsStoreKey{Key: key})), &newVars)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(newVars["int_ca"]).To(Equal(vars["int_ca"]))
+				Expect(certField(newVars, "leaf", "certificate")).ToNot(Equal(certField(vars, "leaf", "certificate")))
+			})
+
+			It("returns error if key from key file is incorrect", func() {
+				err := command.Check(VarsStoreCheckOpts{Args: storeArgs, KeyFile: "/new-key"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Decrypting variables file store '/creds.yml'"))
+			})
+		})
+	})
+})
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+// VaultVars loads variables from HashiCorp Vault KV secrets engine.
+// Each variable is a secret under configured path; secrets with
+// a single 'value' key resolve to that value, others to all of their keys.
+type VaultVars struct {
+	client *http.Client
+
+	baseURL string
+	mount   string
+	path    string
+	version int
+	token   string
+}
+
+var _ boshtpl.Variables = VaultVars{}
+
+func NewVaultVars(client *http.Client, baseURL, mount, path string, version int, token string) VaultVars {
+	return VaultVars{
+		client: client,
+
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		mount:   strings.Trim(mount, "/"),
+		path:    strings.Trim(path, "/"),
+		version: version,
+		token:   token,
+	}
+}
+
+type vaultSecretResp struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type vaultSecretV2Resp struct {
+	Data struct {
+		Data json.RawMessage `json:"data"`
+	} `json:"data"`
+}
+
+type vaultListResp struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+func (v VaultVars) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	req, err := v.request("GET", v.secretURL("data", strings.TrimPrefix(varDef.Name, "/")))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data json.RawMessage
+
+	if v.version == 2 {
+		var resp vaultSecretV2Resp
+
+		found, err := varsSourceGet(v.client, req, &resp)
+		if err != nil || !found {
+			return nil, false, err
+		}
+
+		data = resp.Data.Data
+	} else {
+		var resp vaultSecretResp
+
+		found, err := varsSourceGet(v.client, req, &resp)
+		if err != nil || !found {
+			return nil, false, err
+		}
+
+		data = resp.Data
+	}
+
+	val, err := varsSourceValue(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if secret, ok := val.(map[interface{}]interface{}); ok && len(secret) == 1 {
+		if singleVal, found := secret["value"]; found {
+			return singleVal, true, nil
+		}
+	}
+
+	return val, true, nil
+}
+
+func (v VaultVars) List() ([]boshtpl.VariableDefinition, error) {
+	req, err := v.request("LIST", v.secretURL("metadata", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp vaultListResp
+
+	_, err = varsSourceGet(v.client, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []boshtpl.VariableDefinition
+
+	for _, key := range resp.Data.Keys {
+		// Nested paths are not considered to be variables
+		if !strings.HasSuffix(key, "/") {
+			defs = append(defs, boshtpl.VariableDefinition{Name: key})
+		}
+	}
+
+	return defs, nil
+}
+
+// secretURL includes KV v2 specific path segment ('data' or 'metadata') after mount path
+func (v VaultVars) secretURL(v2Segment, name string) string {
+	segments := []string{v.baseURL, "v1", v.mount}
+
+	if v.version == 2 {
+		segments = append(segments, v2Segment)
+	}
+
+	for _, segment := range []string{v.path, name} {
+		if len(segment) > 0 {
+			segments = append(segments, segment)
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func (v VaultVars) request(method, url string) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Building Vault request")
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+
+	return req, nil
+}
@@ -0,0 +1,156 @@
+package cmd_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/cloudfoundry/bosh-cli/cmd"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+var _ = Describe("VaultVars", func() {
+	var (
+		server *ghttp.Server
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when using KV version 1", func() {
+		var vars VaultVars
+
+		BeforeEach(func() {
+			vars = NewVaultVars(http.DefaultClient, server.URL(), "secret", "bosh/env", 1, "token")
+		})
+
+		It("returns single value of a secret", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/secret/bosh/env/admin_password"),
+					ghttp.VerifyHeader(http.Header{"X-Vault-Token": []string{"token"}}),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"value":"secret"}}`),
+				),
+			)
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("secret"))
+		})
+
+		It("returns all keys of a secret with multiple keys", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/secret/bosh/env/cert"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"ca":"ca","certificate":"cert"}}`),
+				),
+			)
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{Name: "cert"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal(map[interface{}]interface{}{"ca": "ca", "certificate": "cert"}))
+		})
+
+		It("returns not found if secret does not exist", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"errors":[]}`))
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{Name: "missing"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+			Expect(val).To(BeNil())
+		})
+
+		It("returns error if request is not successful", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, `{"errors":["permission denied"]}`))
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected response for '/v1/secret/bosh/env/admin_password' to be successful but was '403'"))
+		})
+
+		It("returns error if response cannot be closed", func() {
+			client := &http.Client{Transport: vaultVarsRoundTripper(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       vaultVarsBody{Reader: strings.NewReader(`{"data":{"value":"secret"}}`)},
+				}, nil
+			})}
+
+			vars = NewVaultVars(client, server.URL(), "secret", "bosh/env", 1, "token")
+
+			_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Closing response for '/v1/secret/bosh/env/admin_password'"))
+			Expect(found).To(BeFalse())
+		})
+
+		It("lists secrets skipping nested paths", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("LIST", "/v1/secret/bosh/env"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"keys":["admin_password","nested/"]}}`),
+				),
+			)
+
+			defs, err := vars.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defs).To(Equal([]boshtpl.VariableDefinition{{Name: "admin_password"}}))
+		})
+	})
+
+	Context("when using KV version 2", func() {
+		var vars VaultVars
+
+		BeforeEach(func() {
+			vars = NewVaultVars(http.DefaultClient, server.URL(), "secret", "bosh", 2, "token")
+		})
+
+		It("returns value of a secret", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/secret/data/bosh/admin_password"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"data":{"value":"secret"},"metadata":{}}}`),
+				),
+			)
+
+			val, found, err := vars.Get(boshtpl.VariableDefinition{Name: "admin_password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("secret"))
+		})
+
+		It("lists secrets via metadata", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("LIST", "/v1/secret/metadata/bosh"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"keys":["admin_password"]}}`),
+				),
+			)
+
+			defs, err := vars.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defs).To(Equal([]boshtpl.VariableDefinition{{Name: "admin_password"}}))
+		})
+	})
+})
+
+type vaultVarsRoundTripper func(*http.Request) (*http.Response, error)
+
+func (f vaultVarsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type vaultVarsBody struct {
+	*strings.Reader
+}
+
+func (b vaultVarsBody) Close() error { return errors.New("fake-close-err") }
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ArrayItemSegment returns go-patch like path segment that identifies
+// an array item by its name (e.g. name=router) if it has one,
+// or by its index in the array otherwise (e.g. 0).
+func ArrayItemSegment(index int, name interface{}, hasName bool) string {
+	if hasName {
+		return fmt.Sprintf("name=%v", name)
+	}
+
+	return strconv.Itoa(index)
+}
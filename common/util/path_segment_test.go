@@ -0,0 +1,19 @@
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/common/util"
+)
+
+var _ = Describe("ArrayItemSegment", func() {
+	It("identifies item by its name", func() {
+		Expect(util.ArrayItemSegment(2, "router", true)).To(Equal("name=router"))
+		Expect(util.ArrayItemSegment(2, 5, true)).To(Equal("name=5"))
+	})
+
+	It("identifies item by its index if it does not have a name", func() {
+		Expect(util.ArrayItemSegment(2, nil, false)).To(Equal("2"))
+	})
+})
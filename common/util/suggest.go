@@ -0,0 +1,57 @@
+package util
+
+import (
+	"sort"
+)
+
+// SuggestName returns a candidate that is most likely to be what was meant
+// by a misspelled name, or an empty string if no candidate is close enough.
+func SuggestName(name string, candidates []string) string {
+	sortedCandidates := make([]string, len(candidates))
+	copy(sortedCandidates, candidates)
+	sort.Strings(sortedCandidates)
+
+	var suggestion string
+	bestDist := len(name)/3 + 1
+
+	for _, candidate := range sortedCandidates {
+		if dist := editDistance(name, candidate); dist < bestDist {
+			suggestion, bestDist = candidate, dist
+		}
+	}
+
+	return suggestion
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,24 @@
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/common/util"
+)
+
+var _ = Describe("SuggestName", func() {
+	It("returns closest candidate", func() {
+		Expect(util.SuggestName("instance_group", []string{"name", "instance_groups", "instances"})).To(Equal("instance_groups"))
+		Expect(util.SuggestName("instnces", []string{"instance_groups", "instances"})).To(Equal("instances"))
+	})
+
+	It("prefers alphabetically first candidate when candidates are equally close", func() {
+		Expect(util.SuggestName("port", []string{"sort", "part"})).To(Equal("part"))
+	})
+
+	It("returns empty string when no candidate is close enough", func() {
+		Expect(util.SuggestName("xyz", []string{"name", "releases"})).To(Equal(""))
+		Expect(util.SuggestName("name", nil)).To(Equal(""))
+	})
+})
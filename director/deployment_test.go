@@ -502,6 +502,38 @@ var _ = Describe("Deployment", func() {
 					Expect(err).ToNot(HaveOccurred())
 				})
 
+				It("changes state with given manifest", func() {
+					manifest := []byte("name: dep")
+
+					switch state {
+					case "started":
+						startOpts.Manifest = manifest
+					case "recreate":
+						recreateOpts.Manifest = manifest
+					case "stopped":
+						stopOpts.Manifest = manifest
+					case "detached":
+						detachedOpts.Manifest = manifest
+					case "restart":
+						restartOpts.Manifest = manifest
+					}
+
+					ConfigureTaskResult(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("PUT", "/deployments/dep/jobs/*", fmt.Sprintf("state=%s", state)),
+							ghttp.VerifyBasicAuth("username", "password"),
+							ghttp.VerifyHeader(http.Header{
+								"Content-Type": []string{"text/yaml"},
+							}),
+							ghttp.VerifyBody(manifest),
+						),
+						``,
+						server,
+					)
+					err := stateFunc(deployment)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
 				if state == "recreate" {
 					It("changes state with dry run", func() {
 						recreateOpts.DryRun = true
@@ -0,0 +1,250 @@
+package director
+
+import (
+	"strings"
+
+	boshutil "github.com/cloudfoundry/bosh-cli/common/util"
+)
+
+const (
+	DiffChangeAdded   = "added"
+	DiffChangeRemoved = "removed"
+	DiffChangeChanged = "changed"
+
+	diffRedactedValue = "<redacted>"
+)
+
+// DiffChange describes a single changed scalar value in a deployment diff.
+// Path is a go-patch like pointer where array items are identified
+// by their name (e.g. /instance_groups/name=router/instances) or by
+// their index (e.g. /instance_groups/name=router/azs/0) same as in
+// manifest policy paths. Indexes of removed items refer to old array
+// and indexes of other items refer to new array since Director shows
+// all items of an array when its items do not have names.
+type DiffChange struct {
+	Path          string
+	InstanceGroup string
+
+	Type     string
+	OldValue string
+	NewValue string
+
+	Redacted bool
+}
+
+type diffFrame struct {
+	indent  int
+	segment string
+	isItem  bool
+
+	// Added and removed items are counted separately
+	// since they come from new and old manifests
+	oldItems int
+	newItems int
+}
+
+// Changes parses Director's YAML-ish diff lines into a list of changes
+func (d DeploymentDiff) Changes() []DiffChange {
+	return DiffLines(d.Diff).Changes()
+}
+
+func (l DiffLines) Changes() []DiffChange {
+	var changes []DiffChange
+	var stack []*diffFrame
+
+	root := &diffFrame{indent: -1}
+	stack = append(stack, root)
+
+	changeIdxs := map[string]int{}
+
+	record := func(path, value, state string) {
+		if state != DiffChangeAdded && state != DiffChangeRemoved {
+			return
+		}
+
+		if idx, found := changeIdxs[path]; found {
+			change := changes[idx]
+
+			if change.Type == DiffChangeRemoved && state == DiffChangeAdded {
+				change.Type = DiffChangeChanged
+				change.NewValue = value
+				change.Redacted = change.Redacted || value == diffRedactedValue
+				changes[idx] = change
+				return
+			}
+		}
+
+		change := DiffChange{
+			Path:          path,
+			InstanceGroup: diffInstanceGroup(path),
+			Type:          state,
+			Redacted:      value == diffRedactedValue,
+		}
+
+		if state == DiffChangeAdded {
+			change.NewValue = value
+		} else {
+			change.OldValue = value
+		}
+
+		changeIdxs[path] = len(changes)
+		changes = append(changes, change)
+	}
+
+	pathOf := func() string {
+		var segments []string
+		for _, frame := range stack[1:] {
+			segments = append(segments, frame.segment)
+		}
+		return "/" + strings.Join(segments, "/")
+	}
+
+	pop := func(indent int, isItem bool) {
+		for len(stack) > 1 {
+			top := stack[len(stack)-1]
+
+			// Array items may be indented at the same level as their parent key
+			if top.indent < indent || (isItem && top.indent == indent && !top.isItem) {
+				break
+			}
+
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	blockIndent := -1
+
+	for _, line := range l {
+		if len(line) < 2 {
+			continue
+		}
+
+		text, _ := line[0].(string)
+		state, _ := line[1].(string)
+
+		content := strings.TrimLeft(text, " ")
+		indent := len(text) - len(content)
+
+		if len(strings.TrimSpace(content)) == 0 {
+			continue
+		}
+
+		// Skip contents of multiline strings
+		if blockIndent >= 0 {
+			if indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+
+		if content == "-" || strings.HasPrefix(content, "- ") {
+			pop(indent, true)
+
+			parent := stack[len(stack)-1]
+			rest := strings.TrimSpace(strings.TrimPrefix(content, "-"))
+			key, value, isKey := diffKeyValue(rest)
+
+			segment := diffItemSegment(parent, key, value, isKey, state)
+
+			stack = append(stack, &diffFrame{indent: indent, segment: segment, isItem: true})
+
+			if !isKey {
+				record(pathOf(), diffUnquote(rest), state)
+				continue
+			}
+
+			content = rest
+			indent += 2
+		}
+
+		key, value, isKey := diffKeyValue(content)
+		if !isKey {
+			continue
+		}
+
+		pop(indent, false)
+
+		if len(value) == 0 {
+			stack = append(stack, &diffFrame{indent: indent, segment: key})
+			continue
+		}
+
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = indent
+		}
+
+		stack = append(stack, &diffFrame{indent: indent, segment: key})
+		record(pathOf(), diffUnquote(value), state)
+		stack = stack[:len(stack)-1]
+	}
+
+	return diffWithoutUnchanged(changes)
+}
+
+func diffItemSegment(parent *diffFrame, key, value string, isKey bool, state string) string {
+	var index int
+
+	switch state {
+	case DiffChangeRemoved:
+		index = parent.oldItems
+		parent.oldItems++
+
+	case DiffChangeAdded:
+		index = parent.newItems
+		parent.newItems++
+
+	default:
+		index = parent.newItems
+		parent.oldItems++
+		parent.newItems++
+	}
+
+	return boshutil.ArrayItemSegment(index, diffUnquote(value), isKey && key == "name")
+}
+
+// diffWithoutUnchanged drops values that were removed and added back
+// at the same path (e.g. when Director shows whole array as replaced)
+func diffWithoutUnchanged(changes []DiffChange) []DiffChange {
+	var result []DiffChange
+
+	for _, change := range changes {
+		if change.Type == DiffChangeChanged && change.OldValue == change.NewValue {
+			continue
+		}
+		result = append(result, change)
+	}
+
+	return result
+}
+
+func diffKeyValue(content string) (string, string, bool) {
+	if strings.HasSuffix(content, ":") {
+		return diffUnquote(strings.TrimSuffix(content, ":")), "", true
+	}
+
+	pieces := strings.SplitN(content, ": ", 2)
+	if len(pieces) != 2 {
+		return "", "", false
+	}
+
+	return diffUnquote(pieces[0]), strings.TrimSpace(pieces[1]), true
+}
+
+func diffUnquote(str string) string {
+	if len(str) >= 2 {
+		if (str[0] == '"' && str[len(str)-1] == '"') || (str[0] == '\'' && str[len(str)-1] == '\'') {
+			return str[1 : len(str)-1]
+		}
+	}
+	return str
+}
+
+func diffInstanceGroup(path string) string {
+	const prefix = "/instance_groups/name="
+
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+
+	return strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)[0]
+}
@@ -0,0 +1,142 @@
+package director_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/director"
+)
+
+var _ = Describe("DiffLines", func() {
+	Describe("Changes", func() {
+		It("returns added, removed and changed values identified by path", func() {
+			lines := DiffLines{
+				{"name: dep", nil},
+				{"update:", nil},
+				{"  max_in_flight: 1", "removed"},
+				{"  max_in_flight: 2", "added"},
+				{"instance_groups:", nil},
+				{"- name: router", nil},
+				{"  instances: 2", nil},
+				{"  persistent_disk: 20480", "removed"},
+				{"  persistent_disk: 10240", "added"},
+				{"  jobs:", nil},
+				{"  - name: gorouter", nil},
+				{"    properties:", nil},
+				{"      password: \"<redacted>\"", "added"},
+				{"- name: db", "added"},
+				{"  azs:", "added"},
+				{"  - z1", "added"},
+				{"stemcells:", nil},
+				{"- alias: default", nil},
+				{"  version: '1'", "removed"},
+			}
+
+			Expect(lines.Changes()).To(Equal([]DiffChange{
+				{
+					Path:     "/update/max_in_flight",
+					Type:     "changed",
+					OldValue: "1",
+					NewValue: "2",
+				},
+				{
+					Path:          "/instance_groups/name=router/persistent_disk",
+					InstanceGroup: "router",
+					Type:          "changed",
+					OldValue:      "20480",
+					NewValue:      "10240",
+				},
+				{
+					Path:          "/instance_groups/name=router/jobs/name=gorouter/properties/password",
+					InstanceGroup: "router",
+					Type:          "added",
+					NewValue:      "<redacted>",
+					Redacted:      true,
+				},
+				{
+					Path:          "/instance_groups/name=db/name",
+					InstanceGroup: "db",
+					Type:          "added",
+					NewValue:      "db",
+				},
+				{
+					Path:          "/instance_groups/name=db/azs/0",
+					InstanceGroup: "db",
+					Type:          "added",
+					NewValue:      "z1",
+				},
+				{
+					Path:     "/stemcells/0/version",
+					Type:     "removed",
+					OldValue: "1",
+				},
+			}))
+		})
+
+		It("identifies array items without names by their indexes", func() {
+			lines := DiffLines{
+				{"stemcells:", nil},
+				{"- alias: default", nil},
+				{"  version: '1'", nil},
+				{"- alias: other", nil},
+				{"  version: '1'", "removed"},
+				{"  version: '2'", "added"},
+				{"releases:", nil},
+				{"- url: file:///release.tgz", nil},
+				{"  version: '3'", "added"},
+			}
+
+			Expect(lines.Changes()).To(Equal([]DiffChange{
+				{Path: "/stemcells/1/version", Type: "changed", OldValue: "1", NewValue: "2"},
+				{Path: "/releases/0/version", Type: "added", NewValue: "3"},
+			}))
+		})
+
+		It("identifies replaced array items by their indexes in old and new arrays", func() {
+			lines := DiffLines{
+				{"instance_groups:", nil},
+				{"- name: db", nil},
+				{"  azs:", nil},
+				{"  - z1", "removed"},
+				{"  - z2", "removed"},
+				{"  - z1", "added"},
+				{"  - z3", "added"},
+				{"  - z4", "added"},
+			}
+
+			Expect(lines.Changes()).To(Equal([]DiffChange{
+				{
+					Path:          "/instance_groups/name=db/azs/1",
+					InstanceGroup: "db",
+					Type:          "changed",
+					OldValue:      "z2",
+					NewValue:      "z3",
+				},
+				{
+					Path:          "/instance_groups/name=db/azs/2",
+					InstanceGroup: "db",
+					Type:          "added",
+					NewValue:      "z4",
+				},
+			}))
+		})
+
+		It("skips contents of multiline values", func() {
+			lines := DiffLines{
+				{"properties:", nil},
+				{"  cert: |", "added"},
+				{"    key: not-a-key", "added"},
+				{"  other: val", "added"},
+			}
+
+			Expect(lines.Changes()).To(Equal([]DiffChange{
+				{Path: "/properties/cert", Type: "added", NewValue: "|"},
+				{Path: "/properties/other", Type: "added", NewValue: "val"},
+			}))
+		})
+
+		It("returns no changes when diff is empty", func() {
+			Expect(DeploymentDiff{}.Changes()).To(BeEmpty())
+		})
+	})
+})
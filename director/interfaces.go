@@ -178,6 +178,7 @@ type Deployment interface {
 type StartOpts struct {
 	Canaries    string
 	MaxInFlight string
+	Manifest    []byte
 }
 
 type StopOpts struct {
@@ -186,6 +187,7 @@ type StopOpts struct {
 	Force       bool
 	SkipDrain   bool
 	Hard        bool
+	Manifest    []byte
 }
 
 type RestartOpts struct {
@@ -193,6 +195,7 @@ type RestartOpts struct {
 	MaxInFlight string
 	Force       bool
 	SkipDrain   bool
+	Manifest    []byte
 }
 
 type RecreateOpts struct {
@@ -202,6 +205,7 @@ type RecreateOpts struct {
 	Fix         bool
 	SkipDrain   bool
 	DryRun      bool
+	Manifest    []byte
 }
 
 type UpdateOpts struct {
@@ -0,0 +1,226 @@
+package director
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// NewManifestDiff compares two manifests without contacting the Director.
+// Similarly to the Director, arrays of hashes that all have a name
+// (instance groups, jobs, networks, etc.) are compared by name instead of index.
+func NewManifestDiff(oldManifest, newManifest []byte) (DeploymentDiff, error) {
+	var oldObj, newObj yaml.MapSlice
+
+	err := yaml.Unmarshal(oldManifest, &oldObj)
+	if err != nil {
+		return DeploymentDiff{}, bosherr.WrapError(err, "Unmarshalling old manifest")
+	}
+
+	err = yaml.Unmarshal(newManifest, &newObj)
+	if err != nil {
+		return DeploymentDiff{}, bosherr.WrapError(err, "Unmarshalling new manifest")
+	}
+
+	differ := &manifestDiffer{}
+	differ.diffMaps(oldObj, newObj, 0, "")
+
+	return NewDeploymentDiff(differ.lines, nil), nil
+}
+
+type manifestDiffer struct {
+	lines [][]interface{}
+}
+
+func (d *manifestDiffer) diffMaps(oldMap, newMap yaml.MapSlice, indent int, skipKey string) {
+	for _, oldItem := range oldMap {
+		if oldItem.Key == skipKey {
+			continue
+		}
+		if _, found := manifestDiffLookup(newMap, oldItem.Key); !found {
+			d.addLines(manifestDiffMarshal(yaml.MapSlice{oldItem}), indent, DiffChangeRemoved)
+		}
+	}
+
+	for _, newItem := range newMap {
+		if newItem.Key == skipKey {
+			continue
+		}
+
+		oldVal, found := manifestDiffLookup(oldMap, newItem.Key)
+		if !found {
+			d.addLines(manifestDiffMarshal(yaml.MapSlice{newItem}), indent, DiffChangeAdded)
+			continue
+		}
+
+		d.diffValues(newItem.Key, oldVal, newItem.Value, indent)
+	}
+}
+
+func (d *manifestDiffer) diffValues(key, oldVal, newVal interface{}, indent int) {
+	if manifestDiffEqual(oldVal, newVal) {
+		return
+	}
+
+	oldMap, oldIsMap := oldVal.(yaml.MapSlice)
+	newMap, newIsMap := newVal.(yaml.MapSlice)
+
+	if oldIsMap && newIsMap {
+		d.withContext(fmt.Sprintf("%v:", key), indent, func() {
+			d.diffMaps(oldMap, newMap, indent+1, "")
+		})
+		return
+	}
+
+	oldArr, oldIsArr := oldVal.([]interface{})
+	newArr, newIsArr := newVal.([]interface{})
+
+	if oldIsArr && newIsArr && manifestDiffNamed(oldArr) && manifestDiffNamed(newArr) {
+		d.withContext(fmt.Sprintf("%v:", key), indent, func() {
+			d.diffNamedArrays(oldArr, newArr, indent)
+		})
+		return
+	}
+
+	d.addLines(manifestDiffMarshal(yaml.MapSlice{{Key: key, Value: oldVal}}), indent, DiffChangeRemoved)
+	d.addLines(manifestDiffMarshal(yaml.MapSlice{{Key: key, Value: newVal}}), indent, DiffChangeAdded)
+}
+
+func (d *manifestDiffer) diffNamedArrays(oldArr, newArr []interface{}, indent int) {
+	for _, oldItem := range oldArr {
+		if _, found := manifestDiffFindNamed(newArr, manifestDiffName(oldItem)); !found {
+			d.addLines(manifestDiffMarshal([]interface{}{oldItem}), indent, DiffChangeRemoved)
+		}
+	}
+
+	for _, newItem := range newArr {
+		name := manifestDiffName(newItem)
+
+		oldItem, found := manifestDiffFindNamed(oldArr, name)
+		if !found {
+			d.addLines(manifestDiffMarshal([]interface{}{newItem}), indent, DiffChangeAdded)
+			continue
+		}
+
+		if manifestDiffEqual(oldItem, newItem) {
+			continue
+		}
+
+		d.withContext(fmt.Sprintf("- name: %s", name), indent, func() {
+			d.diffMaps(oldItem.(yaml.MapSlice), newItem.(yaml.MapSlice), indent+1, "name")
+		})
+	}
+}
+
+// withContext adds context line only if nested diff is not empty
+// (e.g. named array items that were only reordered)
+func (d *manifestDiffer) withContext(line string, indent int, nestedFunc func()) {
+	d.addLine(line, indent, nil)
+
+	count := len(d.lines)
+
+	nestedFunc()
+
+	if len(d.lines) == count {
+		d.lines = d.lines[:count-1]
+	}
+}
+
+func (d *manifestDiffer) addLine(line string, indent int, state interface{}) {
+	d.lines = append(d.lines, []interface{}{strings.Repeat("  ", indent) + line, state})
+}
+
+func (d *manifestDiffer) addLines(lines []string, indent int, state string) {
+	for _, line := range lines {
+		d.addLine(line, indent, state)
+	}
+}
+
+func manifestDiffMarshal(obj interface{}) []string {
+	bytes, err := yaml.Marshal(obj)
+	if err != nil {
+		return []string{fmt.Sprintf("%#v", obj)}
+	}
+
+	return strings.Split(strings.TrimSuffix(string(bytes), "\n"), "\n")
+}
+
+func manifestDiffLookup(m yaml.MapSlice, key interface{}) (interface{}, bool) {
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+func manifestDiffName(item interface{}) string {
+	m, _ := item.(yaml.MapSlice)
+	name, _ := manifestDiffLookup(m, "name")
+	str, _ := name.(string)
+	return str
+}
+
+// manifestDiffNamed returns true if all items are hashes with unique names
+func manifestDiffNamed(arr []interface{}) bool {
+	if len(arr) == 0 {
+		return false
+	}
+
+	seen := map[string]struct{}{}
+
+	for _, item := range arr {
+		name := manifestDiffName(item)
+		if len(name) == 0 {
+			return false
+		}
+		if _, found := seen[name]; found {
+			return false
+		}
+		seen[name] = struct{}{}
+	}
+
+	return true
+}
+
+func manifestDiffFindNamed(arr []interface{}, name string) (interface{}, bool) {
+	for _, item := range arr {
+		if manifestDiffName(item) == name {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// manifestDiffEqual compares values ignoring order of hash keys
+func manifestDiffEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(manifestDiffNormalize(a), manifestDiffNormalize(b))
+}
+
+func manifestDiffNormalize(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case yaml.MapSlice:
+		result := make(yaml.MapSlice, len(typedVal))
+		for i, item := range typedVal {
+			result[i] = yaml.MapItem{Key: item.Key, Value: manifestDiffNormalize(item.Value)}
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			return fmt.Sprintf("%v", result[i].Key) < fmt.Sprintf("%v", result[j].Key)
+		})
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(typedVal))
+		for i, item := range typedVal {
+			result[i] = manifestDiffNormalize(item)
+		}
+		return result
+
+	default:
+		return val
+	}
+}
@@ -0,0 +1,125 @@
+package director_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/director"
+)
+
+var _ = Describe("NewManifestDiff", func() {
+	It("returns no lines when manifests are semantically equal", func() {
+		diff, err := NewManifestDiff(
+			[]byte("name: dep\nupdate: {canaries: 1, max_in_flight: 2}\n"),
+			[]byte("update: {max_in_flight: 2, canaries: 1}\nname: dep\n"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Diff).To(BeEmpty())
+	})
+
+	It("shows changed, added and removed values with their parents as context", func() {
+		diff, err := NewManifestDiff(
+			[]byte(`
+name: dep
+update:
+  canaries: 1
+  max_in_flight: 2
+tags:
+  team: a
+`),
+			[]byte(`
+name: dep
+update:
+  canaries: 1
+  max_in_flight: 3
+  serial: true
+`),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Diff).To(Equal([][]interface{}{
+			{"tags:", "removed"},
+			{"  team: a", "removed"},
+			{"update:", nil},
+			{"  max_in_flight: 2", "removed"},
+			{"  max_in_flight: 3", "added"},
+			{"  serial: true", "added"},
+		}))
+	})
+
+	It("compares arrays of named hashes by name instead of index", func() {
+		diff, err := NewManifestDiff(
+			[]byte(`
+instance_groups:
+- name: db
+  instances: 1
+- name: router
+  instances: 2
+  jobs:
+  - name: gorouter
+    properties: {port: 80}
+  - name: metrics
+`),
+			[]byte(`
+instance_groups:
+- name: router
+  instances: 2
+  jobs:
+  - name: gorouter
+    properties: {port: 8080}
+- name: web
+  instances: 1
+  azs: [z1]
+`),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Diff).To(Equal([][]interface{}{
+			{"instance_groups:", nil},
+			{"- name: db", "removed"},
+			{"  instances: 1", "removed"},
+			{"- name: router", nil},
+			{"  jobs:", nil},
+			{"  - name: metrics", "removed"},
+			{"  - name: gorouter", nil},
+			{"    properties:", nil},
+			{"      port: 80", "removed"},
+			{"      port: 8080", "added"},
+			{"- name: web", "added"},
+			{"  instances: 1", "added"},
+			{"  azs:", "added"},
+			{"  - z1", "added"},
+		}))
+
+		Expect(diff.Changes()).To(ContainElement(DiffChange{
+			Path:          "/instance_groups/name=router/jobs/name=gorouter/properties/port",
+			InstanceGroup: "router",
+			Type:          "changed",
+			OldValue:      "80",
+			NewValue:      "8080",
+		}))
+	})
+
+	It("replaces arrays that are not named as a whole", func() {
+		diff, err := NewManifestDiff(
+			[]byte("azs: [z1, z2]\n"),
+			[]byte("azs: [z1]\n"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Diff).To(Equal([][]interface{}{
+			{"azs:", "removed"},
+			{"- z1", "removed"},
+			{"- z2", "removed"},
+			{"azs:", "added"},
+			{"- z1", "added"},
+		}))
+	})
+
+	It("returns error if manifests cannot be parsed", func() {
+		_, err := NewManifestDiff([]byte("{"), []byte{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling old manifest"))
+
+		_, err = NewManifestDiff([]byte{}, []byte("{"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling new manifest"))
+	})
+})
@@ -19,6 +19,7 @@ type SSHResult struct {
 type Host struct {
 	Job       string
 	IndexOrID string
+	Index     *int
 
 	Username      string
 	Host          string
@@ -72,6 +73,7 @@ func (d DeploymentImpl) SetUpSSH(slug AllOrInstanceGroupOrInstanceSlug, opts SSH
 		result.Hosts = append(result.Hosts, Host{
 			Job:       resp.Job,
 			IndexOrID: resp.IndexOrID(),
+			Index:     resp.Index,
 
 			Username:      opts.Username,
 			Host:          resp.IP,
@@ -31,6 +31,9 @@ var _ = Describe("NewSSHOpts", func() {
 	})
 
 	Describe("SetUpSSH", func() {
+		index1 := 1
+		index2 := 2
+
 		It("sets up SSH sessions without gateway configuration", func() {
 			respBody := `[
 	{
@@ -84,6 +87,7 @@ var _ = Describe("NewSSHOpts", func() {
 					{
 						Job:       "",
 						IndexOrID: "1",
+						Index:     &index1,
 
 						Username:      "user",
 						Host:          "host1-ip",
@@ -92,6 +96,7 @@ var _ = Describe("NewSSHOpts", func() {
 					{
 						Job:       "",
 						IndexOrID: "2",
+						Index:     &index2,
 
 						Username:      "user",
 						Host:          "host2-ip",
@@ -140,6 +145,7 @@ var _ = Describe("NewSSHOpts", func() {
 					{
 						Job:       "",
 						IndexOrID: "1",
+						Index:     &index1,
 
 						Username:      "user",
 						Host:          "host1-ip",
@@ -148,6 +154,7 @@ var _ = Describe("NewSSHOpts", func() {
 					{
 						Job:       "",
 						IndexOrID: "2",
+						Index:     &index2,
 
 						Username:      "user",
 						Host:          "host2-ip",
@@ -207,6 +214,7 @@ var _ = Describe("NewSSHOpts", func() {
 					{
 						Job:       "",
 						IndexOrID: "1",
+						Index:     &index1,
 
 						Username:      "user",
 						Host:          "host1-ip",
@@ -250,6 +258,7 @@ var _ = Describe("NewSSHOpts", func() {
 					{
 						Job:       "",
 						IndexOrID: "host1-id",
+						Index:     &index1,
 
 						Username:      "user",
 						Host:          "host1-ip",
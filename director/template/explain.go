@@ -0,0 +1,160 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+)
+
+// VariableExplanation describes which variables supplied a variable value
+// and where variable is referenced in the template.
+type VariableExplanation struct {
+	Name string
+
+	// Source is a name of NamedVars (or type of Variables) that supplied the value;
+	// empty if value was not found
+	Source string
+
+	// Paths are locations of variable references after operations were applied
+	Paths []patch.Pointer
+}
+
+// Explain evaluates template similarly to Evaluate but instead of returning
+// resulting document it describes where each variable came from.
+func (t Template) Explain(vars Variables, op patch.Op) ([]VariableExplanation, error) {
+	var obj interface{}
+
+	err := yaml.Unmarshal(t.bytes, &obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if op != nil {
+		obj, err = op.Apply(obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refs := map[string][]patch.Pointer{}
+
+	t.collectVarRefs(obj, []patch.Token{patch.RootToken{}}, refs)
+
+	sourcesVars := sourcesTrackingVars{vars: vars, sources: map[string]string{}}
+
+	_, err = t.interpolateRoot(obj, newVarsTracker(sourcesVars, false, false))
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+
+	for name := range refs {
+		names[name] = struct{}{}
+	}
+
+	for name := range sourcesVars.sources {
+		names[name] = struct{}{}
+	}
+
+	var explanations []VariableExplanation
+
+	for name := range names {
+		paths := refs[name]
+
+		sort.Slice(paths, func(i, j int) bool { return paths[i].String() < paths[j].String() })
+
+		explanations = append(explanations, VariableExplanation{
+			Name:   name,
+			Source: sourcesVars.sources[name],
+			Paths:  paths,
+		})
+	}
+
+	sort.Slice(explanations, func(i, j int) bool { return explanations[i].Name < explanations[j].Name })
+
+	return explanations, nil
+}
+
+func (t Template) collectVarRefs(node interface{}, tokens []patch.Token, refs map[string][]patch.Pointer) {
+	switch typedNode := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range typedNode {
+			t.collectVarRefs(k, tokens, refs)
+			t.collectVarRefs(v, t.appendToken(tokens, patch.KeyToken{Key: fmt.Sprintf("%v", k)}), refs)
+		}
+
+	case []interface{}:
+		for idx, x := range typedNode {
+			var token patch.Token = patch.IndexToken{Index: idx}
+
+			// Prefer names since they are more stable and readable than indices
+			if item, ok := x.(map[interface{}]interface{}); ok {
+				if name, ok := item["name"].(string); ok {
+					token = patch.MatchingIndexToken{Key: "name", Value: name}
+				}
+			}
+
+			t.collectVarRefs(x, t.appendToken(tokens, token), refs)
+		}
+
+	case string:
+		seen := map[string]struct{}{}
+
+		for _, name := range (interpolator{}).extractVarNames(typedNode) {
+			name = strings.Split(name, ".")[0]
+
+			if _, found := seen[name]; !found {
+				seen[name] = struct{}{}
+				refs[name] = append(refs[name], patch.NewPointer(t.copyTokens(tokens)))
+			}
+		}
+	}
+}
+
+// appendToken copies tokens so that pointers do not share underlying arrays
+func (t Template) appendToken(tokens []patch.Token, token patch.Token) []patch.Token {
+	return append(t.copyTokens(tokens), token)
+}
+
+func (Template) copyTokens(tokens []patch.Token) []patch.Token {
+	result := make([]patch.Token, len(tokens))
+	copy(result, tokens)
+	return result
+}
+
+type sourcesTrackingVars struct {
+	vars    Variables
+	sources map[string]string
+}
+
+func (v sourcesTrackingVars) Get(varDef VariableDefinition) (interface{}, bool, error) {
+	source := v.vars
+
+	var val interface{}
+	var found bool
+	var err error
+
+	if multiVars, ok := v.vars.(MultiVars); ok {
+		val, source, found, err = multiVars.GetWithSource(varDef)
+	} else {
+		val, found, err = v.vars.Get(varDef)
+	}
+
+	if found && err == nil {
+		if namedVars, ok := source.(NamedVars); ok {
+			v.sources[varDef.Name] = namedVars.Name
+		} else {
+			v.sources[varDef.Name] = fmt.Sprintf("%T", source)
+		}
+	}
+
+	return val, found, err
+}
+
+func (v sourcesTrackingVars) List() ([]VariableDefinition, error) {
+	return v.vars.List()
+}
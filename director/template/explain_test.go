@@ -0,0 +1,99 @@
+package template_test
+
+import (
+	"errors"
+
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/director/template"
+)
+
+var _ = Describe("Template", func() {
+	Describe("Explain", func() {
+		var (
+			template Template
+		)
+
+		BeforeEach(func() {
+			template = NewTemplate([]byte(`
+name: ((name))
+instance_groups:
+- name: web
+  properties:
+    password: ((password))
+    url: https://((host)):((port))/((host))
+- properties:
+    cert: ((cert.certificate))
+    missing: ((missing))
+variables:
+- name: generated
+  type: password
+`))
+		})
+
+		It("explains which named variables supplied values and where variables are referenced", func() {
+			vars := NewMultiVars([]Variables{
+				NamedVars{Name: "first", Vars: StaticVariables{"password": "first", "host": "host"}},
+				NamedVars{Name: "second", Vars: StaticVariables{"password": "second", "port": 80, "generated": "val"}},
+				StaticVariables{"cert": map[interface{}]interface{}{"certificate": "cert"}},
+			})
+
+			op := patch.ReplaceOp{Path: patch.MustNewPointerFromString("/name"), Value: "((op_name))"}
+
+			explanations, err := template.Explain(vars, op)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(explanations).To(Equal([]VariableExplanation{
+				{
+					Name:   "cert",
+					Source: "template.StaticVariables",
+					Paths:  []patch.Pointer{patch.MustNewPointerFromString("/instance_groups/1/properties/cert")},
+				},
+				{
+					Name:   "generated",
+					Source: "second",
+				},
+				{
+					Name:   "host",
+					Source: "first",
+					Paths:  []patch.Pointer{patch.MustNewPointerFromString("/instance_groups/name=web/properties/url")},
+				},
+				{
+					Name:  "missing",
+					Paths: []patch.Pointer{patch.MustNewPointerFromString("/instance_groups/1/properties/missing")},
+				},
+				{
+					Name:  "op_name",
+					Paths: []patch.Pointer{patch.MustNewPointerFromString("/name")},
+				},
+				{
+					Name:   "password",
+					Source: "first",
+					Paths:  []patch.Pointer{patch.MustNewPointerFromString("/instance_groups/name=web/properties/password")},
+				},
+				{
+					Name:   "port",
+					Source: "second",
+					Paths:  []patch.Pointer{patch.MustNewPointerFromString("/instance_groups/name=web/properties/url")},
+				},
+			}))
+		})
+
+		It("returns error if getting variable fails", func() {
+			vars := &FakeVariables{GetErr: errors.New("fake-err")}
+
+			_, err := template.Explain(vars, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if operation fails", func() {
+			op := patch.RemoveOp{Path: patch.MustNewPointerFromString("/unknown")}
+
+			_, err := template.Explain(StaticVariables{}, op)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -11,14 +11,20 @@ func NewMultiVars(varss []Variables) MultiVars {
 var _ Variables = MultiVars{}
 
 func (m MultiVars) Get(varDef VariableDefinition) (interface{}, bool, error) {
+	val, _, found, err := m.GetWithSource(varDef)
+	return val, found, err
+}
+
+// GetWithSource is similar to Get but also returns variables that supplied the value.
+func (m MultiVars) GetWithSource(varDef VariableDefinition) (interface{}, Variables, bool, error) {
 	for _, vars := range m.varss {
 		val, found, err := vars.Get(varDef)
 		if found || err != nil {
-			return val, found, err
+			return val, vars, found, err
 		}
 	}
 
-	return nil, false, nil
+	return nil, nil, false, nil
 }
 
 func (m MultiVars) List() ([]VariableDefinition, error) {
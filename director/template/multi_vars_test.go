@@ -70,6 +70,30 @@ var _ = Describe("MultiVariables", func() {
 		})
 	})
 
+	Describe("GetWithSource", func() {
+		It("returns variables that supplied found value", func() {
+			vars1 := &FakeVariables{}
+			vars2 := NamedVars{Name: "vars2", Vars: StaticVariables{"key2": "val"}}
+			vars := NewMultiVars([]Variables{vars1, vars2})
+
+			val, source, found, err := vars.GetWithSource(VariableDefinition{Name: "key2"})
+			Expect(val).To(Equal("val"))
+			Expect(source).To(Equal(vars2))
+			Expect(found).To(BeTrue())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns no source if variable is not found", func() {
+			vars := NewMultiVars([]Variables{StaticVariables{}})
+
+			val, source, found, err := vars.GetWithSource(VariableDefinition{Name: "key"})
+			Expect(val).To(BeNil())
+			Expect(source).To(BeNil())
+			Expect(found).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
 	Describe("List", func() {
 		It("returns list of names from multiple vars with duplicates", func() {
 			defs, err := NewMultiVars(nil).List()
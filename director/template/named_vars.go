@@ -0,0 +1,18 @@
+package template
+
+// NamedVars associates variables with a name that describes
+// where they came from (e.g. '--vars-file creds.yml').
+type NamedVars struct {
+	Name string
+	Vars Variables
+}
+
+var _ Variables = NamedVars{}
+
+func (v NamedVars) Get(varDef VariableDefinition) (interface{}, bool, error) {
+	return v.Vars.Get(varDef)
+}
+
+func (v NamedVars) List() ([]VariableDefinition, error) {
+	return v.Vars.List()
+}
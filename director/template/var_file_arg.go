@@ -10,6 +10,7 @@ import (
 type VarFileArg struct {
 	FS boshsys.FileSystem
 
+	Path string
 	Vars StaticVariables
 }
 
@@ -37,6 +38,7 @@ func (a *VarFileArg) UnmarshalFlag(data string) error {
 		return bosherr.WrapErrorf(err, "Reading variable from file '%s'", absPath)
 	}
 
+	(*a).Path = absPath
 	(*a).Vars = StaticVariables{pieces[0]: string(bytes)}
 
 	return nil
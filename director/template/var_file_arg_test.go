@@ -27,6 +27,7 @@ var _ = Describe("VarFileArg", func() {
 
 			err := (&arg).UnmarshalFlag("name=/some/path")
 			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Path).To(Equal("/some/path"))
 			Expect(arg.Vars).To(Equal(StaticVariables{"name": "val\nval"}))
 		})
 
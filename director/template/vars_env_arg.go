@@ -9,7 +9,8 @@ import (
 )
 
 type VarsEnvArg struct {
-	Vars StaticVariables
+	Prefix string
+	Vars   StaticVariables
 
 	EnvironFunc func() []string
 }
@@ -46,6 +47,7 @@ func (a *VarsEnvArg) UnmarshalFlag(prefix string) error {
 		vars[strings.TrimPrefix(pieces[0], prefix+"_")] = val
 	}
 
+	(*a).Prefix = prefix
 	(*a).Vars = vars
 
 	return nil
@@ -26,6 +26,7 @@ var _ = Describe("VarsEnvArg", func() {
 
 			err := (&arg).UnmarshalFlag("name")
 			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Prefix).To(Equal("name"))
 			Expect(arg.Vars).To(Equal(StaticVariables{
 				"key1": "var1",
 				"key2": "var2",
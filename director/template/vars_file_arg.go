@@ -9,6 +9,7 @@ import (
 type VarsFileArg struct {
 	FS boshsys.FileSystem
 
+	Path string
 	Vars StaticVariables
 }
 
@@ -29,6 +30,7 @@ func (a *VarsFileArg) UnmarshalFlag(filePath string) error {
 		return bosherr.WrapErrorf(err, "Deserializing variables file '%s'", filePath)
 	}
 
+	(*a).Path = filePath
 	(*a).Vars = vars
 
 	return nil
@@ -27,6 +27,7 @@ var _ = Describe("VarsFileArg", func() {
 
 			err := (&arg).UnmarshalFlag("/some/path")
 			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Path).To(Equal("/some/path"))
 			Expect(arg.Vars).To(Equal(StaticVariables{
 				"name1": "var1",
 				"name2": "var2",
@@ -26,6 +26,7 @@ type InstallerFactory interface {
 type installerFactory struct {
 	ui                     biui.UI
 	runner                 boshsys.CmdRunner
+	erbRenderer            bierbrenderer.ERBRenderer
 	extractor              boshcmd.Compressor
 	releaseJobResolver     bideplrel.JobResolver
 	uuidGenerator          boshuuid.Generator
@@ -39,6 +40,7 @@ type installerFactory struct {
 func NewInstallerFactory(
 	ui biui.UI,
 	runner boshsys.CmdRunner,
+	erbRenderer bierbrenderer.ERBRenderer,
 	extractor boshcmd.Compressor,
 	releaseJobResolver bideplrel.JobResolver,
 	uuidGenerator boshuuid.Generator,
@@ -50,6 +52,7 @@ func NewInstallerFactory(
 	return &installerFactory{
 		ui:                     ui,
 		runner:                 runner,
+		erbRenderer:            erbRenderer,
 		extractor:              extractor,
 		releaseJobResolver:     releaseJobResolver,
 		uuidGenerator:          uuidGenerator,
@@ -65,6 +68,7 @@ func (f *installerFactory) NewInstaller(target Target) Installer {
 	context := &installerFactoryContext{
 		target:                 target,
 		runner:                 f.runner,
+		erbRenderer:            f.erbRenderer,
 		logger:                 f.logger,
 		extractor:              f.extractor,
 		uuidGenerator:          f.uuidGenerator,
@@ -88,6 +92,7 @@ type installerFactoryContext struct {
 	target             Target
 	fs                 boshsys.FileSystem
 	runner             boshsys.CmdRunner
+	erbRenderer        bierbrenderer.ERBRenderer
 	logger             boshlog.Logger
 	extractor          boshcmd.Compressor
 	uuidGenerator      boshuuid.Generator
@@ -102,9 +107,7 @@ type installerFactoryContext struct {
 }
 
 func (c *installerFactoryContext) JobRenderer() JobRenderer {
-
-	erbRenderer := bierbrenderer.NewERBRenderer(c.fs, c.runner, c.logger)
-	jobRenderer := bitemplate.NewJobRenderer(erbRenderer, c.fs, c.uuidGenerator, c.logger)
+	jobRenderer := bitemplate.NewJobRenderer(c.erbRenderer, c.fs, c.uuidGenerator, c.logger)
 	jobListRenderer := bitemplate.NewJobListRenderer(jobRenderer, c.logger)
 
 	return NewJobRenderer(
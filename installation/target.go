@@ -38,6 +38,10 @@ func (t Target) JobsPath() string {
 	return filepath.Join(t.path, "jobs")
 }
 
+func (t Target) RenderedTemplatesCachePath() string {
+	return filepath.Join(t.path, "rendered_templates")
+}
+
 func (t Target) TmpPath() string {
 	return filepath.Join(t.path, "tmp")
 }
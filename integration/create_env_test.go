@@ -477,6 +477,7 @@ cloud_provider:
 					deploymentManifestParser,
 					tempRootConfigurator,
 					targetProvider,
+					nil,
 				)
 			}
 
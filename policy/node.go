@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	boshutil "github.com/cloudfoundry/bosh-cli/common/util"
+)
+
+// node is a single value in a manifest identified by path segments.
+// Array items are identified by their name if available (e.g. name=router)
+// or by their index otherwise, same as in deployment diff changes.
+type node struct {
+	Segments []string
+	Value    interface{}
+	IsScalar bool
+
+	// Number of instances of an enclosing instance group, if any
+	Instances    float64
+	HasInstances bool
+}
+
+func (n node) Path() string { return "/" + strings.Join(n.Segments, "/") }
+
+func newNodes(obj interface{}) []node {
+	var nodes []node
+	collectNodes(obj, nil, node{}, &nodes)
+	return nodes
+}
+
+func collectNodes(obj interface{}, segments []string, ctx node, nodes *[]node) {
+	if len(segments) == 2 && segments[0] == "instance_groups" {
+		ctx.Instances, ctx.HasInstances = instancesOf(obj)
+	}
+
+	if len(segments) > 0 {
+		*nodes = append(*nodes, node{
+			Segments:     segments,
+			Value:        obj,
+			IsScalar:     isScalar(obj),
+			Instances:    ctx.Instances,
+			HasInstances: ctx.HasInstances,
+		})
+	}
+
+	switch typedObj := obj.(type) {
+	case map[interface{}]interface{}:
+		var keys []string
+		vals := map[string]interface{}{}
+
+		for k, v := range typedObj {
+			key := fmt.Sprintf("%v", k)
+			keys = append(keys, key)
+			vals[key] = v
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			collectNodes(vals[key], appendSegment(segments, key), ctx, nodes)
+		}
+
+	case []interface{}:
+		for i, item := range typedObj {
+			collectNodes(item, appendSegment(segments, itemSegment(item, i)), ctx, nodes)
+		}
+	}
+}
+
+func appendSegment(segments []string, segment string) []string {
+	result := make([]string, len(segments), len(segments)+1)
+	copy(result, segments)
+	return append(result, segment)
+}
+
+func itemSegment(item interface{}, i int) string {
+	typedItem, _ := item.(map[interface{}]interface{})
+	name, found := typedItem["name"]
+
+	return boshutil.ArrayItemSegment(i, name, found)
+}
+
+func instancesOf(obj interface{}) (float64, bool) {
+	typedObj, ok := obj.(map[interface{}]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	instances, err := parseQuantity(typedObj["instances"])
+	if err != nil || instances.Percent {
+		return 0, false
+	}
+
+	return instances.Num, true
+}
+
+func isScalar(obj interface{}) bool {
+	switch obj.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return false
+	default:
+		return true
+	}
+}
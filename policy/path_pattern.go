@@ -0,0 +1,70 @@
+package policy
+
+import (
+	gopath "path"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// pathPattern matches manifest paths such as /instance_groups/name=router/instances.
+// Each segment may contain shell-like wildcards ('*', '?', '[...]')
+// and '**' segment matches zero or more segments.
+type pathPattern struct {
+	segments []string
+}
+
+func newPathPattern(str string) (pathPattern, error) {
+	if !strings.HasPrefix(str, "/") {
+		return pathPattern{}, bosherr.Errorf("Expected path '%s' to start with '/'", str)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(str, "/"), "/")
+
+	for _, segment := range segments {
+		if segment == "**" {
+			continue
+		}
+
+		_, err := gopath.Match(segment, "")
+		if err != nil {
+			return pathPattern{}, bosherr.WrapErrorf(err, "Parsing path '%s'", str)
+		}
+	}
+
+	return pathPattern{segments}, nil
+}
+
+func (p pathPattern) Matches(segments []string) bool {
+	return matchSegments(p.segments, segments)
+}
+
+func (p pathPattern) MatchesPath(path string) bool {
+	return p.Matches(strings.Split(strings.TrimPrefix(path, "/"), "/"))
+}
+
+func matchSegments(patterns, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(patterns[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	matched, _ := gopath.Match(patterns[0], segments[0])
+	if !matched {
+		return false
+	}
+
+	return matchSegments(patterns[1:], segments[1:])
+}
@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"path/filepath"
+	"sort"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"gopkg.in/yaml.v2"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+// Policy files contain a list of rules, e.g.:
+//
+//   rules:
+//   - name: max-in-flight-limit
+//     description: max_in_flight must be <= 30%
+//     path: /**/update/max_in_flight
+//     max: 30%
+//   - name: no-persistent-disk-shrinking
+//     path: /instance_groups/*/persistent_disk
+//     no_decrease: true
+
+type Policy struct {
+	Rules []Rule
+}
+
+type Definition struct {
+	Rules []RuleDefinition `yaml:"rules"`
+}
+
+func NewPolicyFromDir(dir string, fs boshsys.FileSystem) (Policy, error) {
+	var paths []string
+
+	for _, ext := range []string{"*.yml", "*.yaml"} {
+		matches, err := fs.Glob(filepath.Join(dir, ext))
+		if err != nil {
+			return Policy{}, bosherr.WrapErrorf(err, "Listing policy files in '%s'", dir)
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	if len(paths) == 0 {
+		return Policy{}, bosherr.Errorf("Expected to find at least one policy file in '%s'", dir)
+	}
+
+	sort.Strings(paths)
+
+	var policy Policy
+
+	for _, path := range paths {
+		bytes, err := fs.ReadFile(path)
+		if err != nil {
+			return Policy{}, bosherr.WrapErrorf(err, "Reading policy file '%s'", path)
+		}
+
+		filePolicy, err := NewPolicyFromBytes(bytes)
+		if err != nil {
+			return Policy{}, bosherr.WrapErrorf(err, "Building policy from file '%s'", path)
+		}
+
+		policy.Rules = append(policy.Rules, filePolicy.Rules...)
+	}
+
+	return policy, nil
+}
+
+func NewPolicyFromBytes(bytes []byte) (Policy, error) {
+	var def Definition
+
+	err := yaml.Unmarshal(bytes, &def)
+	if err != nil {
+		return Policy{}, bosherr.WrapError(err, "Deserializing policy")
+	}
+
+	var policy Policy
+
+	for i, ruleDef := range def.Rules {
+		rule, err := NewRuleFromDefinition(ruleDef)
+		if err != nil {
+			return Policy{}, bosherr.WrapErrorf(err, "Building rule (%d)", i)
+		}
+
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	return policy, nil
+}
+
+// Check evaluates all rules against interpolated manifest and
+// changes made by the manifest compared to the current deployment.
+func (p Policy) Check(manifest []byte, changes []boshdir.DiffChange) (Violations, error) {
+	var obj interface{}
+
+	err := yaml.Unmarshal(manifest, &obj)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling manifest")
+	}
+
+	nodes := newNodes(obj)
+
+	var violations Violations
+
+	for _, rule := range p.Rules {
+		violations = append(violations, rule.Check(nodes, changes)...)
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,268 @@
+package policy_test
+
+import (
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/cloudfoundry/bosh-cli/policy"
+)
+
+var _ = Describe("Policy", func() {
+	Describe("NewPolicyFromDir", func() {
+		var (
+			fs *fakesys.FakeFileSystem
+		)
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+		})
+
+		It("loads rules from all YAML files in a directory", func() {
+			fs.SetGlob("/policy/*.yml", []string{"/policy/b.yml"})
+			fs.SetGlob("/policy/*.yaml", []string{"/policy/a.yaml"})
+			fs.WriteFileString("/policy/a.yaml", "rules: [{name: a, path: /a, required: true}]")
+			fs.WriteFileString("/policy/b.yml", "rules: [{name: b, path: /b, required: true}]")
+
+			policy, err := NewPolicyFromDir("/policy", fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Rules).To(HaveLen(2))
+			Expect(policy.Rules[0].Name()).To(Equal("a"))
+			Expect(policy.Rules[1].Name()).To(Equal("b"))
+		})
+
+		It("returns error if directory does not contain policy files", func() {
+			_, err := NewPolicyFromDir("/policy", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find at least one policy file in '/policy'"))
+		})
+
+		It("returns error if listing files fails", func() {
+			fs.GlobErr = errors.New("fake-err")
+
+			_, err := NewPolicyFromDir("/policy", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if policy file is not valid", func() {
+			fs.SetGlob("/policy/*.yml", []string{"/policy/a.yml"})
+			fs.WriteFileString("/policy/a.yml", "rules: [{name: a, path: /a}]")
+
+			_, err := NewPolicyFromDir("/policy", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Building policy from file '/policy/a.yml'"))
+			Expect(err.Error()).To(ContainSubstring("Expected rule 'a' to specify at least one check"))
+		})
+	})
+
+	Describe("NewPolicyFromBytes", func() {
+		It("returns error if rule does not have a name", func() {
+			_, err := NewPolicyFromBytes([]byte("rules: [{path: /a, required: true}]"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected rule name to be non-empty"))
+		})
+
+		It("returns error if path is not absolute", func() {
+			_, err := NewPolicyFromBytes([]byte("rules: [{name: a, path: a, required: true}]"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected path 'a' to start with '/'"))
+		})
+
+		It("returns error if severity is unknown", func() {
+			_, err := NewPolicyFromBytes([]byte("rules: [{name: a, path: /a, required: true, severity: fatal}]"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected rule 'a' severity to be either 'error' or 'warning'"))
+		})
+
+		It("returns error if regexp is invalid", func() {
+			_, err := NewPolicyFromBytes([]byte("rules: [{name: a, path: /a, match: '['}]"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Building rule 'a' match"))
+		})
+
+		It("returns error if max is not a number", func() {
+			_, err := NewPolicyFromBytes([]byte("rules: [{name: a, path: /a, max: abc}]"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected 'abc' to be a number or a percentage"))
+		})
+	})
+
+	Describe("Check", func() {
+		manifest := []byte(`
+name: dep
+update:
+  max_in_flight: 50%
+instance_groups:
+- name: router
+  instances: 10
+  update:
+    max_in_flight: 2
+  jobs:
+  - name: gorouter
+    properties:
+      admin_password: secret
+      db_password: ((db_password))
+- name: db
+  persistent_disk: 1024
+  azs: [z1, z2]
+`)
+
+		check := func(rules string, changes []boshdir.DiffChange) Violations {
+			policy, err := NewPolicyFromBytes([]byte(rules))
+			Expect(err).ToNot(HaveOccurred())
+
+			violations, err := policy.Check(manifest, changes)
+			Expect(err).ToNot(HaveOccurred())
+
+			return violations
+		}
+
+		It("reports values above max converting absolute values based on number of instances", func() {
+			violations := check(`
+rules:
+- name: mif
+  description: max_in_flight must be <= 30%
+  path: /**/update/max_in_flight
+  max: 30%
+`, nil)
+
+			Expect(violations).To(Equal(Violations{
+				{
+					Rule:     "mif",
+					Severity: "error",
+					Path:     "/update/max_in_flight",
+					Message:  "max_in_flight must be <= 30%: Expected value '50%' to be at most '30%'",
+				},
+			}))
+		})
+
+		It("reports values that cannot be compared", func() {
+			violations := check(`
+rules:
+- name: mif
+  path: /**/update/max_in_flight
+  max: 1
+`, nil)
+
+			Expect(violations).To(Equal(Violations{
+				{
+					Rule:     "mif",
+					Severity: "error",
+					Path:     "/instance_groups/name=router/update/max_in_flight",
+					Message:  "Expected value '2' to be at most '1'",
+				},
+				{
+					Rule:     "mif",
+					Severity: "error",
+					Path:     "/update/max_in_flight",
+					Message:  "Expected value '50%' to be comparable with '1'",
+				},
+			}))
+		})
+
+		It("reports values below min", func() {
+			violations := check("rules: [{name: disk, path: /instance_groups/*/persistent_disk, min: 2048}]", nil)
+			Expect(violations).To(Equal(Violations{
+				{
+					Rule:     "disk",
+					Severity: "error",
+					Path:     "/instance_groups/name=db/persistent_disk",
+					Message:  "Expected value '1024' to be at least '2048'",
+				},
+			}))
+		})
+
+		It("reports plaintext values with warning severity", func() {
+			violations := check(`
+rules:
+- name: passwords
+  severity: warning
+  path: /instance_groups/*/jobs/*/properties/**/*password*
+  match: ^\(\(.+\)\)$
+`, nil)
+
+			Expect(violations).To(Equal(Violations{
+				{
+					Rule:     "passwords",
+					Severity: "warning",
+					Path:     "/instance_groups/name=router/jobs/name=gorouter/properties/admin_password",
+					Message:  `Expected value to match '^\(\(.+\)\)$'`,
+				},
+			}))
+			Expect(violations.Errors()).To(BeEmpty())
+		})
+
+		It("reports required, forbidden, not matching and not allowed values", func() {
+			violations := check(`
+rules:
+- {name: required, path: /stemcells, required: true}
+- {name: forbidden, path: /instance_groups/name=db/persistent_disk, forbidden: true}
+- {name: not-match, path: /name, not_match: ^dep$}
+- {name: one-of, path: /instance_groups/*/instances, one_of: [1, 3]}
+`, nil)
+
+			Expect(violations).To(Equal(Violations{
+				{Rule: "required", Severity: "error", Path: "/stemcells", Message: "Expected path to be present"},
+				{Rule: "forbidden", Severity: "error", Path: "/instance_groups/name=db/persistent_disk", Message: "Expected path to not be present"},
+				{Rule: "not-match", Severity: "error", Path: "/name", Message: "Expected value to not match '^dep$'"},
+				{Rule: "one-of", Severity: "error", Path: "/instance_groups/name=router/instances", Message: "Expected value '10' to be one of [1 3]"},
+			}))
+		})
+
+		It("reports decreased and removed values in deployment diff", func() {
+			changes := []boshdir.DiffChange{
+				{Path: "/instance_groups/name=db/persistent_disk", Type: "changed", OldValue: "2048", NewValue: "1024"},
+				{Path: "/instance_groups/name=web/persistent_disk", Type: "changed", OldValue: "1024", NewValue: "2048"},
+				{Path: "/instance_groups/name=old/persistent_disk", Type: "removed", OldValue: "1024"},
+				{Path: "/instance_groups/name=sec/persistent_disk", Type: "changed", OldValue: "<redacted>", NewValue: "<redacted>", Redacted: true},
+			}
+
+			violations := check(`
+rules:
+- {name: shrink, path: /instance_groups/*/persistent_disk, no_decrease: true, no_removal: true}
+`, changes)
+
+			Expect(violations).To(Equal(Violations{
+				{Rule: "shrink", Severity: "error", Path: "/instance_groups/name=db/persistent_disk", Message: "Expected value to not decrease from '2048' to '1024'"},
+				{Rule: "shrink", Severity: "error", Path: "/instance_groups/name=old/persistent_disk", Message: "Expected value to not be removed"},
+			}))
+		})
+
+		It("matches manifest values and diff changes of array items without names by the same path", func() {
+			lines := boshdir.DiffLines{
+				{"update:", nil},
+				{"  max_in_flight: 50%", nil},
+				{"instance_groups:", nil},
+				{"- name: db", nil},
+				{"  azs:", nil},
+				{"  - z1", "removed"},
+				{"  - z3", "removed"},
+				{"  - z1", "added"},
+				{"  - z2", "added"},
+			}
+
+			violations := check(`
+rules:
+- {name: second-az, path: /instance_groups/name=db/azs/1, one_of: [z1], no_decrease: true}
+`, lines.Changes())
+
+			Expect(violations).To(Equal(Violations{
+				{Rule: "second-az", Severity: "error", Path: "/instance_groups/name=db/azs/1", Message: "Expected value 'z2' to be one of [z1]"},
+				{Rule: "second-az", Severity: "error", Path: "/instance_groups/name=db/azs/1", Message: "Expected change from 'z3' to 'z2' to be comparable"},
+			}))
+		})
+
+		It("returns error if manifest cannot be parsed", func() {
+			policy, err := NewPolicyFromBytes([]byte("rules: [{name: a, path: /a, required: true}]"))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = policy.Check([]byte("{"), nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unmarshalling manifest"))
+		})
+	})
+})
@@ -0,0 +1,66 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// quantity is a number optionally expressed as a percentage (e.g. max_in_flight: 30%)
+type quantity struct {
+	Num     float64
+	Percent bool
+}
+
+func parseQuantity(val interface{}) (quantity, error) {
+	switch typedVal := val.(type) {
+	case int:
+		return quantity{Num: float64(typedVal)}, nil
+	case int64:
+		return quantity{Num: float64(typedVal)}, nil
+	case uint64:
+		return quantity{Num: float64(typedVal)}, nil
+	case float64:
+		return quantity{Num: typedVal}, nil
+	case string:
+		str := strings.TrimSpace(typedVal)
+		percent := strings.HasSuffix(str, "%")
+
+		num, err := strconv.ParseFloat(strings.TrimSuffix(str, "%"), 64)
+		if err != nil {
+			return quantity{}, bosherr.Errorf("Expected '%s' to be a number or a percentage", typedVal)
+		}
+
+		return quantity{Num: num, Percent: percent}, nil
+	default:
+		return quantity{}, bosherr.Errorf("Expected '%v' to be a number or a percentage", val)
+	}
+}
+
+// comparableWith converts quantity into the same unit as other quantity
+// using number of instances when converting between absolute and percentage values.
+func (q quantity) comparableWith(other quantity, n node) (quantity, bool) {
+	if q.Percent == other.Percent {
+		return q, true
+	}
+
+	if !n.HasInstances || n.Instances <= 0 {
+		return q, false
+	}
+
+	if q.Percent {
+		return quantity{Num: q.Num * n.Instances / 100}, true
+	}
+
+	return quantity{Num: q.Num / n.Instances * 100, Percent: true}, true
+}
+
+func (q quantity) String() string {
+	str := strconv.FormatFloat(q.Num, 'f', -1, 64)
+	if q.Percent {
+		return fmt.Sprintf("%s%%", str)
+	}
+	return str
+}
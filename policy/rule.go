@@ -0,0 +1,242 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+type RuleDefinition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Severity    string `yaml:"severity"`
+
+	Path string `yaml:"path"`
+
+	// Checks against interpolated manifest
+	Required  bool          `yaml:"required"`
+	Forbidden bool          `yaml:"forbidden"`
+	Min       interface{}   `yaml:"min"`
+	Max       interface{}   `yaml:"max"`
+	Match     string        `yaml:"match"`
+	NotMatch  string        `yaml:"not_match"`
+	OneOf     []interface{} `yaml:"one_of"`
+
+	// Checks against deployment diff
+	NoDecrease bool `yaml:"no_decrease"`
+	NoRemoval  bool `yaml:"no_removal"`
+}
+
+type Rule struct {
+	def RuleDefinition
+
+	path     pathPattern
+	min      *quantity
+	max      *quantity
+	match    *regexp.Regexp
+	notMatch *regexp.Regexp
+}
+
+func NewRuleFromDefinition(def RuleDefinition) (Rule, error) {
+	if len(def.Name) == 0 {
+		return Rule{}, bosherr.Error("Expected rule name to be non-empty")
+	}
+
+	switch def.Severity {
+	case "":
+		def.Severity = SeverityError
+	case SeverityError, SeverityWarning:
+	default:
+		return Rule{}, bosherr.Errorf("Expected rule '%s' severity to be either '%s' or '%s'",
+			def.Name, SeverityError, SeverityWarning)
+	}
+
+	path, err := newPathPattern(def.Path)
+	if err != nil {
+		return Rule{}, bosherr.WrapErrorf(err, "Building rule '%s'", def.Name)
+	}
+
+	rule := Rule{def: def, path: path}
+
+	if def.Min != nil {
+		min, err := parseQuantity(def.Min)
+		if err != nil {
+			return Rule{}, bosherr.WrapErrorf(err, "Building rule '%s' min", def.Name)
+		}
+		rule.min = &min
+	}
+
+	if def.Max != nil {
+		max, err := parseQuantity(def.Max)
+		if err != nil {
+			return Rule{}, bosherr.WrapErrorf(err, "Building rule '%s' max", def.Name)
+		}
+		rule.max = &max
+	}
+
+	if len(def.Match) > 0 {
+		rule.match, err = regexp.Compile(def.Match)
+		if err != nil {
+			return Rule{}, bosherr.WrapErrorf(err, "Building rule '%s' match", def.Name)
+		}
+	}
+
+	if len(def.NotMatch) > 0 {
+		rule.notMatch, err = regexp.Compile(def.NotMatch)
+		if err != nil {
+			return Rule{}, bosherr.WrapErrorf(err, "Building rule '%s' not_match", def.Name)
+		}
+	}
+
+	if !rule.hasChecks() {
+		return Rule{}, bosherr.Errorf("Expected rule '%s' to specify at least one check", def.Name)
+	}
+
+	return rule, nil
+}
+
+func (r Rule) Name() string { return r.def.Name }
+
+func (r Rule) hasChecks() bool {
+	d := r.def
+	return d.Required || d.Forbidden || r.min != nil || r.max != nil || r.match != nil ||
+		r.notMatch != nil || len(d.OneOf) > 0 || d.NoDecrease || d.NoRemoval
+}
+
+func (r Rule) Check(nodes []node, changes []boshdir.DiffChange) Violations {
+	var violations Violations
+
+	var matched []node
+
+	for _, n := range nodes {
+		if r.path.Matches(n.Segments) {
+			matched = append(matched, n)
+		}
+	}
+
+	if r.def.Required && len(matched) == 0 {
+		violations = append(violations, r.violation(r.def.Path, "Expected path to be present"))
+	}
+
+	for _, n := range matched {
+		for _, msg := range r.checkNode(n) {
+			violations = append(violations, r.violation(n.Path(), msg))
+		}
+	}
+
+	for _, change := range changes {
+		if r.path.MatchesPath(change.Path) {
+			for _, msg := range r.checkChange(change) {
+				violations = append(violations, r.violation(change.Path, msg))
+			}
+		}
+	}
+
+	return violations
+}
+
+func (r Rule) checkNode(n node) []string {
+	var msgs []string
+
+	if r.def.Forbidden {
+		msgs = append(msgs, "Expected path to not be present")
+	}
+
+	if !n.IsScalar || n.Value == nil {
+		return msgs
+	}
+
+	if r.min != nil || r.max != nil {
+		val, err := parseQuantity(n.Value)
+		if err != nil {
+			return append(msgs, err.Error())
+		}
+
+		if r.min != nil {
+			msgs = append(msgs, r.compare(val, *r.min, n, "at least", func(a, b float64) bool { return a >= b })...)
+		}
+
+		if r.max != nil {
+			msgs = append(msgs, r.compare(val, *r.max, n, "at most", func(a, b float64) bool { return a <= b })...)
+		}
+	}
+
+	str := fmt.Sprintf("%v", n.Value)
+
+	if r.match != nil && !r.match.MatchString(str) {
+		msgs = append(msgs, fmt.Sprintf("Expected value to match '%s'", r.match.String()))
+	}
+
+	if r.notMatch != nil && r.notMatch.MatchString(str) {
+		msgs = append(msgs, fmt.Sprintf("Expected value to not match '%s'", r.notMatch.String()))
+	}
+
+	if len(r.def.OneOf) > 0 {
+		var found bool
+
+		for _, allowed := range r.def.OneOf {
+			if fmt.Sprintf("%v", allowed) == str {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			msgs = append(msgs, fmt.Sprintf("Expected value '%s' to be one of %v", str, r.def.OneOf))
+		}
+	}
+
+	return msgs
+}
+
+func (r Rule) compare(val, limit quantity, n node, desc string, okFunc func(float64, float64) bool) []string {
+	converted, ok := val.comparableWith(limit, n)
+	if !ok {
+		return []string{fmt.Sprintf("Expected value '%s' to be comparable with '%s'", val, limit)}
+	}
+
+	if !okFunc(converted.Num, limit.Num) {
+		return []string{fmt.Sprintf("Expected value '%s' to be %s '%s'", val, desc, limit)}
+	}
+
+	return nil
+}
+
+func (r Rule) checkChange(change boshdir.DiffChange) []string {
+	var msgs []string
+
+	if r.def.NoRemoval && change.Type == boshdir.DiffChangeRemoved {
+		msgs = append(msgs, "Expected value to not be removed")
+	}
+
+	if r.def.NoDecrease && change.Type == boshdir.DiffChangeChanged && !change.Redacted {
+		oldVal, oldErr := parseQuantity(change.OldValue)
+		newVal, newErr := parseQuantity(change.NewValue)
+
+		if oldErr != nil || newErr != nil || oldVal.Percent != newVal.Percent {
+			msgs = append(msgs, fmt.Sprintf("Expected change from '%s' to '%s' to be comparable",
+				change.OldValue, change.NewValue))
+		} else if newVal.Num < oldVal.Num {
+			msgs = append(msgs, fmt.Sprintf("Expected value to not decrease from '%s' to '%s'",
+				change.OldValue, change.NewValue))
+		}
+	}
+
+	return msgs
+}
+
+func (r Rule) violation(path, msg string) Violation {
+	if len(r.def.Description) > 0 {
+		msg = r.def.Description + ": " + msg
+	}
+
+	return Violation{
+		Rule:     r.def.Name,
+		Severity: r.def.Severity,
+		Path:     path,
+		Message:  msg,
+	}
+}
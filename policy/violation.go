@@ -0,0 +1,27 @@
+package policy
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+type Violation struct {
+	Rule     string
+	Severity string
+	Path     string
+	Message  string
+}
+
+type Violations []Violation
+
+func (v Violations) Errors() Violations {
+	var errs Violations
+
+	for _, violation := range v {
+		if violation.Severity == SeverityError {
+			errs = append(errs, violation)
+		}
+	}
+
+	return errs
+}
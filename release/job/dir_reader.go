@@ -16,11 +16,15 @@ import (
 
 type DirReaderImpl struct {
 	archiveFactory ArchiveFunc
+	readProperties bool
 	fs             boshsys.FileSystem
 }
 
-func NewDirReaderImpl(archiveFactory ArchiveFunc, fs boshsys.FileSystem) DirReaderImpl {
-	return DirReaderImpl{archiveFactory: archiveFactory, fs: fs}
+// NewDirReaderImpl only parses property definitions when asked to since
+// creating releases does not need them and some defaults (e.g. maps
+// with non-string keys) cannot be parsed
+func NewDirReaderImpl(archiveFactory ArchiveFunc, readProperties bool, fs boshsys.FileSystem) DirReaderImpl {
+	return DirReaderImpl{archiveFactory: archiveFactory, readProperties: readProperties, fs: fs}
 }
 
 func (r DirReaderImpl) Read(path string) (*Job, error) {
@@ -37,8 +41,17 @@ func (r DirReaderImpl) Read(path string) (*Job, error) {
 	}
 
 	job := NewJob(NewResource(manifest.Name, fp, archive))
+	job.Templates = manifest.Templates
 	job.PackageNames = manifest.Packages
-	// Does not read all manifest values...
+
+	// Templates and properties are read so that templates can be rendered locally
+	// and manifests can be checked against job specs; does not read all manifest values...
+	if r.readProperties {
+		job.Properties, err = newPropertyDefinitions(job.Name(), manifest.Properties)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return job, nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"path/filepath"
 
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -21,12 +22,13 @@ var _ = Describe("DirReaderImpl", func() {
 		collectedFollowSymlinks bool
 		archive                 *fakeres.FakeArchive
 		fs                      *fakesys.FakeFileSystem
+		archiveFactory          ArchiveFunc
 		reader                  DirReaderImpl
 	)
 
 	BeforeEach(func() {
 		archive = &fakeres.FakeArchive{}
-		archiveFactory := func(args ArchiveFactoryArgs) Archive {
+		archiveFactory = func(args ArchiveFactoryArgs) Archive {
 			collectedFiles = args.Files
 			collectedPrepFiles = args.PrepFiles
 			collectedChunks = args.Chunks
@@ -34,7 +36,7 @@ var _ = Describe("DirReaderImpl", func() {
 			return archive
 		}
 		fs = fakesys.NewFakeFileSystem()
-		reader = NewDirReaderImpl(archiveFactory, fs)
+		reader = NewDirReaderImpl(archiveFactory, false, fs)
 	})
 
 	Describe("Read", func() {
@@ -55,6 +57,7 @@ properties:
 			archive.FingerprintReturns("fp", nil)
 
 			expectedJob := NewJob(NewResource("my-job", "fp", archive))
+			expectedJob.Templates = map[string]string{"src": "dst"}
 			expectedJob.PackageNames = []string{"pkg"} // only expect pkg names
 
 			job, err := reader.Read(filepath.Join("/", "my-job"))
@@ -97,6 +100,47 @@ properties:
 			Expect(err.Error()).To(ContainSubstring("Collecting job files"))
 		})
 
+		It("does not parse property defaults unless asked to", func() {
+			fs.WriteFileString(filepath.Join("/", "my-job", "spec"), "---\nname: my-job\nproperties: {prop: {default: {1: val}}}")
+
+			job, err := reader.Read(filepath.Join("/", "my-job"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Properties).To(BeNil())
+		})
+
+		Context("when reading properties", func() {
+			BeforeEach(func() {
+				reader = NewDirReaderImpl(archiveFactory, true, fs)
+			})
+
+			It("returns a job with property definitions", func() {
+				fs.WriteFileString(filepath.Join("/", "my-job", "spec"), `---
+name: my-job
+properties:
+  prop:
+    description: prop-desc
+    default: prop-default
+`)
+
+				job, err := reader.Read(filepath.Join("/", "my-job"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(job.Properties).To(Equal(map[string]PropertyDefinition{
+					"prop": PropertyDefinition{
+						Description: "prop-desc",
+						Default:     biproperty.Property("prop-default"),
+					},
+				}))
+			})
+
+			It("returns error if property default cannot be parsed", func() {
+				fs.WriteFileString(filepath.Join("/", "my-job", "spec"), "---\nname: my-job\nproperties: {prop: {default: {1: val}}}")
+
+				_, err := reader.Read(filepath.Join("/", "my-job"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Parsing job 'my-job' property 'prop' default"))
+			})
+		})
+
 		It("returns error if fingerprinting fails", func() {
 			fs.WriteFileString(filepath.Join("/", "my-job", "spec"), "---\nname: my-job")
 
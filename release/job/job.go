@@ -6,6 +6,7 @@ import (
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
 	"github.com/cloudfoundry/bosh-cli/crypto"
+	boshjobman "github.com/cloudfoundry/bosh-cli/release/job/manifest"
 	boshpkg "github.com/cloudfoundry/bosh-cli/release/pkg"
 	. "github.com/cloudfoundry/bosh-cli/release/resource"
 	crypto2 "github.com/cloudfoundry/bosh-utils/crypto"
@@ -34,6 +35,25 @@ type PropertyDefinition struct {
 	Default     biproperty.Property
 }
 
+func newPropertyDefinitions(jobName string, rawPropertyDefs map[string]boshjobman.PropertyDefinition) (map[string]PropertyDefinition, error) {
+	properties := make(map[string]PropertyDefinition, len(rawPropertyDefs))
+
+	for propertyName, rawPropertyDef := range rawPropertyDefs {
+		defaultValue, err := biproperty.Build(rawPropertyDef.Default)
+		if err != nil {
+			errMsg := "Parsing job '%s' property '%s' default: %#v"
+			return nil, bosherr.WrapErrorf(err, errMsg, jobName, propertyName, rawPropertyDef.Default)
+		}
+
+		properties[propertyName] = PropertyDefinition{
+			Description: rawPropertyDef.Description,
+			Default:     defaultValue,
+		}
+	}
+
+	return properties, nil
+}
+
 func NewJob(resource Resource) *Job {
 	return &Job{resource: resource}
 }
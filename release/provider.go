@@ -62,21 +62,35 @@ func (p Provider) archiveReader(extracting bool) ArchiveReader {
 }
 
 func (p Provider) NewDirReader(dirPath string) DirReader {
-	archiveFactory := func(args ArchiveFactoryArgs) Archive {
-		return NewArchiveImpl(
-			args, dirPath, p.fingerprinterFactory(args.FollowSymlinks), p.compressor, p.digestCalculator, p.cmdRunner, p.fs)
-	}
+	archiveFactory := p.archiveFactory(dirPath)
 
 	srcDirPath := filepath.Join(dirPath, "src")
 	blobsDirPath := filepath.Join(dirPath, "blobs")
 
-	jobDirReader := boshjob.NewDirReaderImpl(archiveFactory, p.fs)
+	jobDirReader := boshjob.NewDirReaderImpl(archiveFactory, false, p.fs)
 	pkgDirReader := boshpkg.NewDirReaderImpl(archiveFactory, srcDirPath, blobsDirPath, p.fs)
 	licDirReader := boshlic.NewDirReaderImpl(archiveFactory, p.fs)
 
 	return NewDirReader(jobDirReader, pkgDirReader, licDirReader, p.fs, p.logger)
 }
 
+// NewExtractingJobArchiveReader and NewJobDirReader read individual jobs,
+// e.g. to inspect job specs without reading packages of a release
+func (p Provider) NewExtractingJobArchiveReader() boshjob.ArchiveReader {
+	return boshjob.NewArchiveReaderImpl(true, p.compressor, p.fs)
+}
+
+func (p Provider) NewJobDirReader(dirPath string) boshjob.DirReader {
+	return boshjob.NewDirReaderImpl(p.archiveFactory(dirPath), true, p.fs)
+}
+
+func (p Provider) archiveFactory(dirPath string) ArchiveFunc {
+	return func(args ArchiveFactoryArgs) Archive {
+		return NewArchiveImpl(
+			args, dirPath, p.fingerprinterFactory(args.FollowSymlinks), p.compressor, p.digestCalculator, p.cmdRunner, p.fs)
+	}
+}
+
 func (p Provider) NewManifestReader() ManifestReader {
 	return NewManifestReader(p.fs, p.logger)
 }
@@ -0,0 +1,316 @@
+package schema
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+const (
+	TypeDeployment = "deployment"
+	TypeCloud      = "cloud"
+	TypeRuntime    = "runtime"
+	TypeCPI        = "cpi"
+)
+
+var BuiltinTypes = []string{TypeDeployment, TypeCloud, TypeRuntime, TypeCPI}
+
+// Built-in schemas only describe structure that the Director relies on;
+// maps that are passed through to jobs or CPIs (properties, cloud_properties, env, etc.) are open.
+var builtinSchemas = map[string]string{
+	TypeDeployment: `
+type: object
+required: [name]
+additionalProperties: false
+properties:
+  name: {type: string}
+  director_uuid: {type: string}
+  releases:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        version: {type: [string, number]}
+        url: {type: string}
+        sha1: {type: string}
+        stemcell:
+          type: object
+          additionalProperties: false
+          properties:
+            os: {type: string}
+            version: {type: [string, number]}
+        exported_from: {type: array}
+  stemcells:
+    type: array
+    items:
+      type: object
+      required: [alias]
+      additionalProperties: false
+      properties:
+        alias: {type: string}
+        os: {type: string}
+        name: {type: string}
+        version: {type: [string, number]}
+  instance_groups:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        azs: {type: array, items: {type: string}}
+        instances: {type: integer, minimum: 0}
+        lifecycle: {enum: [service, errand]}
+        jobs:
+          type: array
+          items: &job
+            type: object
+            required: [name, release]
+            additionalProperties: false
+            properties:
+              name: {type: string}
+              release: {type: string}
+              properties: {type: [object, "null"]}
+              consumes: {type: object}
+              provides: {type: object}
+              custom_provider_definitions: {type: array}
+        templates: {type: array}
+        vm_type: {type: string}
+        vm_extensions: {type: array, items: {type: string}}
+        vm_resources: {type: object}
+        resource_pool: {type: string}
+        stemcell: {type: string}
+        persistent_disk: {type: integer, minimum: 0}
+        persistent_disk_type: {type: string}
+        persistent_disk_pool: {type: string}
+        networks:
+          type: array
+          items:
+            type: object
+            required: [name]
+            additionalProperties: false
+            properties:
+              name: {type: string}
+              static_ips: {type: array}
+              default: {type: array, items: {type: string}}
+        update: &update
+          type: object
+          additionalProperties: false
+          properties:
+            canaries: {type: [integer, string]}
+            max_in_flight: {type: [integer, string]}
+            canary_watch_time: {type: [integer, string]}
+            update_watch_time: {type: [integer, string]}
+            serial: {type: boolean}
+            vm_strategy: {enum: [create-swap-delete, delete-create]}
+            initial_deploy_az_update_strategy: {enum: [parallel, serial]}
+        migrated_from: {type: array}
+        properties: {type: [object, "null"]}
+        env: {type: object}
+  update: *update
+  variables:
+    type: array
+    items:
+      type: object
+      required: [name, type]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        type: {type: string}
+        options: {type: object}
+        consumes: {type: object}
+        update_mode: {enum: [converge, no-overwrite]}
+  addons:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        jobs: {type: array, items: *job}
+        include: {type: object}
+        exclude: {type: object}
+        properties: {type: [object, "null"]}
+  features: {type: object}
+  tags: {type: object}
+  properties: {type: [object, "null"]}
+  # Legacy (v1) manifest sections
+  jobs: {type: array}
+  networks: {type: array}
+  resource_pools: {type: array}
+  disk_pools: {type: array}
+  compilation: {type: object}
+`,
+
+	TypeCloud: `
+type: object
+additionalProperties: false
+properties:
+  azs:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        cpi: {type: string}
+        cloud_properties: {type: object}
+  networks:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        type: {enum: [manual, dynamic, vip]}
+        dns: {type: array}
+        cloud_properties: {type: object}
+        subnets:
+          type: array
+          items:
+            type: object
+            additionalProperties: false
+            properties:
+              name: {type: string}
+              range: {type: string}
+              gateway: {type: string}
+              dns: {type: array}
+              reserved: {type: array}
+              static: {type: array}
+              az: {type: string}
+              azs: {type: array, items: {type: string}}
+              cloud_properties: {type: object}
+  vm_types: &named_cloud_properties
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        cloud_properties: {type: object}
+  vm_extensions: *named_cloud_properties
+  disk_types:
+    type: array
+    items:
+      type: object
+      required: [name, disk_size]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        disk_size: {type: integer, minimum: 0}
+        cloud_properties: {type: object}
+  compilation:
+    type: object
+    required: [workers]
+    additionalProperties: false
+    properties:
+      workers: {type: integer, minimum: 1}
+      reuse_compilation_vms: {type: boolean}
+      orphan_workers: {type: boolean}
+      az: {type: string}
+      vm_type: {type: string}
+      vm_resources: {type: object}
+      network: {type: string}
+      env: {type: object}
+      cloud_properties: {type: object}
+`,
+
+	TypeRuntime: `
+type: object
+additionalProperties: false
+properties:
+  releases:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        version: {type: [string, number]}
+        url: {type: string}
+        sha1: {type: string}
+        stemcell: {type: object}
+        exported_from: {type: array}
+  addons:
+    type: array
+    items:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        jobs:
+          type: array
+          items:
+            type: object
+            required: [name, release]
+            additionalProperties: false
+            properties:
+              name: {type: string}
+              release: {type: string}
+              properties: {type: [object, "null"]}
+              consumes: {type: object}
+              provides: {type: object}
+              custom_provider_definitions: {type: array}
+        include: {type: object}
+        exclude: {type: object}
+        properties: {type: [object, "null"]}
+  variables:
+    type: array
+    items:
+      type: object
+      required: [name, type]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        type: {type: string}
+        options: {type: object}
+        consumes: {type: object}
+        update_mode: {enum: [converge, no-overwrite]}
+  tags: {type: object}
+`,
+
+	TypeCPI: `
+type: object
+required: [cpis]
+additionalProperties: false
+properties:
+  cpis:
+    type: array
+    items:
+      type: object
+      required: [name, type]
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        type: {type: string}
+        exec_path: {type: string}
+        properties: {type: object}
+        migrated_from: {type: array}
+`,
+}
+
+// NewBuiltinSchema returns schema for one of BuiltinTypes
+func NewBuiltinSchema(manifestType string) (Schema, error) {
+	def, found := builtinSchemas[manifestType]
+	if !found {
+		return Schema{}, bosherr.Errorf("Expected manifest type '%s' to be one of: %s",
+			manifestType, strings.Join(BuiltinTypes, ", "))
+	}
+
+	schema, err := NewSchemaFromBytes([]byte(def))
+	if err != nil {
+		return Schema{}, bosherr.WrapErrorf(err, "Building '%s' schema", manifestType)
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,140 @@
+package schema_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/schema"
+)
+
+var _ = Describe("NewBuiltinSchema", func() {
+	validate := func(manifestType, manifest string) Violations {
+		schema, err := NewBuiltinSchema(manifestType)
+		Expect(err).ToNot(HaveOccurred())
+
+		violations, err := schema.ValidateBytes([]byte(manifest))
+		Expect(err).ToNot(HaveOccurred())
+
+		return violations
+	}
+
+	It("accepts typical deployment manifest", func() {
+		Expect(validate(TypeDeployment, `
+name: dep
+releases:
+- {name: capi, version: 1.2, url: https://capi-url, sha1: capi-sha1}
+stemcells:
+- {alias: default, os: ubuntu-xenial, version: latest}
+update:
+  canaries: 1
+  max_in_flight: 30%
+  canary_watch_time: 1000-30000
+  update_watch_time: 1000
+  serial: false
+instance_groups:
+- name: web
+  azs: [z1]
+  instances: ((web_instances))
+  vm_type: default
+  stemcell: default
+  persistent_disk_type: 10GB
+  networks: [{name: default, default: [dns, gateway]}]
+  jobs:
+  - name: api
+    release: capi
+    properties: {port: 8080}
+    provides: {api: {as: web-api}}
+variables:
+- {name: password, type: password}
+addons:
+- name: dns
+  jobs: [{name: bosh-dns, release: bosh-dns}]
+  include: {stemcell: [{os: ubuntu-xenial}]}
+features: {use_dns_addresses: true}
+tags: {env: prod}
+`)).To(BeEmpty())
+	})
+
+	It("reports misspelled and misplaced deployment manifest keys", func() {
+		Expect(validate(TypeDeployment, `
+name: dep
+instance_group:
+- name: web
+instance_groups:
+- name: web
+  instance: 2
+  jobs: [{name: api}]
+`)).To(Equal(Violations{
+			{Path: "/instance_group", Message: "Expected key to be known (did you mean 'instance_groups'?)", UnknownKey: true},
+			{Path: "/instance_groups/name=web/instance", Message: "Expected key to be known (did you mean 'instances'?)", UnknownKey: true},
+			{Path: "/instance_groups/name=web/jobs/name=api/release", Message: "Expected key to be present"},
+		}))
+	})
+
+	It("accepts typical cloud config", func() {
+		Expect(validate(TypeCloud, `
+azs:
+- {name: z1, cloud_properties: {zone: us-east-1a}}
+networks:
+- name: default
+  type: manual
+  subnets:
+  - {range: 10.0.0.0/24, gateway: 10.0.0.1, azs: [z1], reserved: [10.0.0.2-10.0.0.10], cloud_properties: {subnet: s-1}}
+- {name: vip, type: vip}
+vm_types:
+- {name: default, cloud_properties: {instance_type: m4.large}}
+vm_extensions:
+- {name: lb, cloud_properties: {elbs: [lb]}}
+disk_types:
+- {name: 10GB, disk_size: 10240}
+compilation:
+  workers: 5
+  reuse_compilation_vms: true
+  az: z1
+  vm_type: default
+  network: default
+`)).To(BeEmpty())
+	})
+
+	It("reports invalid cloud config", func() {
+		Expect(validate(TypeCloud, `
+vm_type: []
+networks:
+- {name: default, type: static}
+disk_types:
+- {name: 10GB}
+`)).To(Equal(Violations{
+			{Path: "/disk_types/name=10GB/disk_size", Message: "Expected key to be present"},
+			{Path: "/networks/name=default/type", Message: "Expected value to be one of: manual, dynamic, vip"},
+			{Path: "/vm_type", Message: "Expected key to be known (did you mean 'vm_types'?)", UnknownKey: true},
+		}))
+	})
+
+	It("validates runtime config", func() {
+		Expect(validate(TypeRuntime, `
+releases:
+- {name: bosh-dns, version: 1.0.0}
+addons:
+- name: dns
+  jobs: [{name: bosh-dns, release: bosh-dns}]
+`)).To(BeEmpty())
+
+		Expect(validate(TypeRuntime, "name: runtime")).To(Equal(Violations{
+			{Path: "/name", Message: "Expected key to be known", UnknownKey: true},
+		}))
+	})
+
+	It("validates cpi config", func() {
+		Expect(validate(TypeCPI, "cpis: [{name: aws, type: aws, properties: {region: us-east-1}}]")).To(BeEmpty())
+
+		Expect(validate(TypeCPI, "cpis: [{name: aws}]")).To(Equal(Violations{
+			{Path: "/cpis/name=aws/type", Message: "Expected key to be present"},
+		}))
+	})
+
+	It("returns error for unknown manifest type", func() {
+		_, err := NewBuiltinSchema("cloud-config")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected manifest type 'cloud-config' to be one of: deployment, cloud, runtime, cpi"))
+	})
+})
@@ -0,0 +1,406 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+
+	boshutil "github.com/cloudfoundry/bosh-cli/common/util"
+)
+
+// Schema files use a subset of JSON Schema written as YAML or JSON, e.g.:
+//
+//   type: object
+//   required: [name]
+//   additionalProperties: false
+//   properties:
+//     name: {type: string}
+//     instance_groups:
+//       type: array
+//       items:
+//         type: object
+//         properties:
+//           instances: {type: integer, minimum: 0}
+//
+// Supported keywords are type, properties, required, additionalProperties,
+// items, enum, minItems and minimum. Values that are unresolved variables
+// (e.g. ((password))) are not checked.
+
+type Schema struct {
+	root *node
+}
+
+type node struct {
+	types                []string
+	properties           map[string]*node
+	required             []string
+	closed               bool
+	additionalProperties *node
+	items                *node
+	enum                 []interface{}
+	minItems             *int
+	minimum              *float64
+}
+
+var (
+	knownTypes = []string{"object", "array", "string", "integer", "number", "boolean", "null"}
+
+	// Annotations do not affect validation
+	ignoredKeywords = []string{"$schema", "$id", "$comment", "title", "description", "default", "examples"}
+
+	variableRegexp = regexp.MustCompile(`^\(\([^()]+\)\)$`)
+)
+
+func NewSchemaFromBytes(bytes []byte) (Schema, error) {
+	var def interface{}
+
+	err := yaml.Unmarshal(bytes, &def)
+	if err != nil {
+		return Schema{}, bosherr.WrapError(err, "Deserializing schema")
+	}
+
+	root, err := newNode(def, "#")
+	if err != nil {
+		return Schema{}, err
+	}
+
+	return Schema{root}, nil
+}
+
+func newNode(def interface{}, path string) (*node, error) {
+	typedDef, ok := def.(map[interface{}]interface{})
+	if !ok {
+		return nil, bosherr.Errorf("Expected schema at '%s' to be a map", path)
+	}
+
+	n := &node{}
+
+	for k, v := range typedDef {
+		keyword := fmt.Sprintf("%v", k)
+
+		var err error
+
+		switch keyword {
+		case "type":
+			n.types, err = schemaStrings(v, path, keyword)
+			if err == nil {
+				err = checkTypes(n.types, path)
+			}
+
+		case "properties":
+			n.properties, err = schemaProperties(v, path)
+
+		case "required":
+			n.required, err = schemaStrings(v, path, keyword)
+
+		case "additionalProperties":
+			if allowed, ok := v.(bool); ok {
+				n.closed = !allowed
+			} else {
+				n.additionalProperties, err = newNode(v, path+"/additionalProperties")
+			}
+
+		case "items":
+			n.items, err = newNode(v, path+"/items")
+
+		case "enum":
+			var isSlice bool
+			n.enum, isSlice = v.([]interface{})
+			if !isSlice {
+				err = bosherr.Errorf("Expected schema keyword 'enum' at '%s' to be an array", path)
+			}
+
+		case "minItems":
+			i, isInt := v.(int)
+			if !isInt || i < 0 {
+				err = bosherr.Errorf("Expected schema keyword 'minItems' at '%s' to be a non-negative integer", path)
+			}
+			n.minItems = &i
+
+		case "minimum":
+			f, isNum := number(v)
+			if !isNum {
+				err = bosherr.Errorf("Expected schema keyword 'minimum' at '%s' to be a number", path)
+			}
+			n.minimum = &f
+
+		default:
+			if !containsString(ignoredKeywords, keyword) {
+				err = bosherr.Errorf("Expected schema keyword '%s' at '%s' to be supported", keyword, path)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+func schemaProperties(def interface{}, path string) (map[string]*node, error) {
+	typedDef, ok := def.(map[interface{}]interface{})
+	if !ok {
+		return nil, bosherr.Errorf("Expected schema keyword 'properties' at '%s' to be a map", path)
+	}
+
+	props := map[string]*node{}
+
+	for k, v := range typedDef {
+		name := fmt.Sprintf("%v", k)
+
+		prop, err := newNode(v, path+"/properties/"+name)
+		if err != nil {
+			return nil, err
+		}
+
+		props[name] = prop
+	}
+
+	return props, nil
+}
+
+func schemaStrings(def interface{}, path, keyword string) ([]string, error) {
+	switch typedDef := def.(type) {
+	case string:
+		return []string{typedDef}, nil
+
+	case []interface{}:
+		var strs []string
+
+		for _, item := range typedDef {
+			str, ok := item.(string)
+			if !ok {
+				return nil, bosherr.Errorf("Expected schema keyword '%s' at '%s' to only contain strings", keyword, path)
+			}
+
+			strs = append(strs, str)
+		}
+
+		return strs, nil
+
+	default:
+		return nil, bosherr.Errorf("Expected schema keyword '%s' at '%s' to be a string or an array", keyword, path)
+	}
+}
+
+func checkTypes(types []string, path string) error {
+	for _, t := range types {
+		if !containsString(knownTypes, t) {
+			return bosherr.Errorf("Expected schema type '%s' at '%s' to be one of: %s",
+				t, path, strings.Join(knownTypes, ", "))
+		}
+	}
+
+	return nil
+}
+
+// Validate checks deserialized manifest against the schema.
+// Map keys are visited in sorted order so that violations are stable.
+func (s Schema) Validate(manifest interface{}) Violations {
+	var violations Violations
+	s.root.validate(manifest, nil, &violations)
+	return violations
+}
+
+// ValidateBytes deserializes manifest and checks it against the schema.
+func (s Schema) ValidateBytes(bytes []byte) (Violations, error) {
+	var manifest interface{}
+
+	err := yaml.Unmarshal(bytes, &manifest)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Deserializing manifest")
+	}
+
+	return s.Validate(manifest), nil
+}
+
+func (n *node) validate(obj interface{}, segments []string, violations *Violations) {
+	if str, ok := obj.(string); ok && variableRegexp.MatchString(str) {
+		return
+	}
+
+	add := func(segments []string, msg string, args ...interface{}) {
+		*violations = append(*violations, Violation{
+			Path:    "/" + strings.Join(segments, "/"),
+			Message: fmt.Sprintf(msg, args...),
+		})
+	}
+
+	if len(n.types) > 0 {
+		actual := typeOf(obj)
+
+		if !n.allowsType(actual) {
+			add(segments, "Expected value to be of type '%s' but was '%s'", strings.Join(n.types, "' or '"), actual)
+			return
+		}
+	}
+
+	if len(n.enum) > 0 && !n.allowsValue(obj) {
+		var vals []string
+
+		for _, val := range n.enum {
+			vals = append(vals, fmt.Sprintf("%v", val))
+		}
+
+		add(segments, "Expected value to be one of: %s", strings.Join(vals, ", "))
+	}
+
+	if n.minimum != nil {
+		if num, ok := number(obj); ok && num < *n.minimum {
+			add(segments, "Expected value to be greater than or equal to %v", *n.minimum)
+		}
+	}
+
+	switch typedObj := obj.(type) {
+	case map[interface{}]interface{}:
+		var keys []string
+		vals := map[string]interface{}{}
+
+		for k, v := range typedObj {
+			key := fmt.Sprintf("%v", k)
+			keys = append(keys, key)
+			vals[key] = v
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range n.required {
+			if _, found := vals[key]; !found {
+				add(appendSegment(segments, key), "Expected key to be present")
+			}
+		}
+
+		for _, key := range keys {
+			keySegments := appendSegment(segments, key)
+
+			if prop, found := n.properties[key]; found {
+				prop.validate(vals[key], keySegments, violations)
+			} else if n.additionalProperties != nil {
+				n.additionalProperties.validate(vals[key], keySegments, violations)
+			} else if n.closed {
+				violation := Violation{
+					Path:       "/" + strings.Join(keySegments, "/"),
+					Message:    "Expected key to be known",
+					UnknownKey: true,
+				}
+
+				if suggestion := n.suggestKey(key); len(suggestion) > 0 {
+					violation.Message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+				}
+
+				*violations = append(*violations, violation)
+			}
+		}
+
+	case []interface{}:
+		if n.minItems != nil && len(typedObj) < *n.minItems {
+			add(segments, "Expected at least %d item(s) but found %d", *n.minItems, len(typedObj))
+		}
+
+		if n.items != nil {
+			for i, item := range typedObj {
+				n.items.validate(item, appendSegment(segments, itemSegment(item, i)), violations)
+			}
+		}
+	}
+}
+
+func (n *node) allowsType(actual string) bool {
+	for _, t := range n.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n *node) allowsValue(obj interface{}) bool {
+	for _, val := range n.enum {
+		if reflect.DeepEqual(val, obj) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suggestKey returns closest known key that is likely to have been misspelled
+func (n *node) suggestKey(key string) string {
+	var names []string
+
+	for name := range n.properties {
+		names = append(names, name)
+	}
+
+	return boshutil.SuggestName(key, names)
+}
+
+func typeOf(obj interface{}) string {
+	switch typedObj := obj.(type) {
+	case nil:
+		return "null"
+	case map[interface{}]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		if typedObj == math.Trunc(typedObj) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", obj)
+	}
+}
+
+func number(obj interface{}) (float64, bool) {
+	switch typedObj := obj.(type) {
+	case int:
+		return float64(typedObj), true
+	case int64:
+		return float64(typedObj), true
+	case uint64:
+		return float64(typedObj), true
+	case float64:
+		return typedObj, true
+	default:
+		return 0, false
+	}
+}
+
+func appendSegment(segments []string, segment string) []string {
+	result := make([]string, len(segments), len(segments)+1)
+	copy(result, segments)
+	return append(result, segment)
+}
+
+func itemSegment(item interface{}, i int) string {
+	typedItem, _ := item.(map[interface{}]interface{})
+	name, found := typedItem["name"]
+
+	return boshutil.ArrayItemSegment(i, name, found)
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,161 @@
+package schema_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/schema"
+)
+
+var _ = Describe("Schema", func() {
+	Describe("NewSchemaFromBytes", func() {
+		It("accepts JSON schema documents and ignores annotations", func() {
+			_, err := NewSchemaFromBytes([]byte(`{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"title": "custom",
+				"type": "object",
+				"properties": {"name": {"type": "string", "description": "Name"}}
+			}`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns error if schema cannot be deserialized", func() {
+			_, err := NewSchemaFromBytes([]byte("-"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected schema at '#' to be a map"))
+
+			_, err = NewSchemaFromBytes([]byte("{"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Deserializing schema"))
+		})
+
+		It("returns error if keyword is not supported", func() {
+			_, err := NewSchemaFromBytes([]byte("properties: {name: {pattern: '^a'}}"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected schema keyword 'pattern' at '#/properties/name' to be supported"))
+		})
+
+		It("returns error if type is not known", func() {
+			_, err := NewSchemaFromBytes([]byte("items: {type: [string, str]}"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Expected schema type 'str' at '#/items' to be one of: object, array, string, integer, number, boolean, null"))
+		})
+
+		It("returns error if keywords have wrong values", func() {
+			_, err := NewSchemaFromBytes([]byte("enum: a"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected schema keyword 'enum' at '#' to be an array"))
+
+			_, err = NewSchemaFromBytes([]byte("minItems: -1"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected schema keyword 'minItems' at '#' to be a non-negative integer"))
+
+			_, err = NewSchemaFromBytes([]byte("required: [1]"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected schema keyword 'required' at '#' to only contain strings"))
+		})
+	})
+
+	Describe("ValidateBytes", func() {
+		var (
+			schema Schema
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			schema, err = NewSchemaFromBytes([]byte(`
+type: object
+required: [name]
+additionalProperties: false
+properties:
+  name: {type: string}
+  instance_groups:
+    type: array
+    minItems: 1
+    items:
+      type: object
+      additionalProperties: false
+      properties:
+        name: {type: string}
+        instances: {type: integer, minimum: 0}
+        lifecycle: {enum: [service, errand]}
+        ratio: {type: number}
+        env: {type: object, additionalProperties: {type: string}}
+`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns no violations for valid manifest", func() {
+			violations, err := schema.ValidateBytes([]byte(`
+name: dep
+instance_groups:
+- name: web
+  instances: 2
+  lifecycle: errand
+  ratio: 1.5
+  env: {a: b}
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(BeEmpty())
+		})
+
+		It("returns violations with paths that identify array items by name", func() {
+			violations, err := schema.ValidateBytes([]byte(`
+instance_group: []
+instance_groups:
+- name: web
+  instances: many
+  lifecycle: daemon
+  env: {a: 1}
+  persistent_disk: 10
+- instances: -1
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(Equal(Violations{
+				{Path: "/name", Message: "Expected key to be present"},
+				{Path: "/instance_group", Message: "Expected key to be known (did you mean 'instance_groups'?)", UnknownKey: true},
+				{Path: "/instance_groups/name=web/env/a", Message: "Expected value to be of type 'string' but was 'integer'"},
+				{Path: "/instance_groups/name=web/instances", Message: "Expected value to be of type 'integer' but was 'string'"},
+				{Path: "/instance_groups/name=web/lifecycle", Message: "Expected value to be one of: service, errand"},
+				{Path: "/instance_groups/name=web/persistent_disk", Message: "Expected key to be known", UnknownKey: true},
+				{Path: "/instance_groups/1/instances", Message: "Expected value to be greater than or equal to 0"},
+			}))
+		})
+
+		It("returns violation if array has too few items", func() {
+			violations, err := schema.ValidateBytes([]byte("name: dep\ninstance_groups: []"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(Equal(Violations{
+				{Path: "/instance_groups", Message: "Expected at least 1 item(s) but found 0"},
+			}))
+		})
+
+		It("allows integers where numbers are expected", func() {
+			violations, err := schema.ValidateBytes([]byte("name: dep\ninstance_groups: [{ratio: 1}]"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(BeEmpty())
+		})
+
+		It("does not check values of unresolved variables", func() {
+			violations, err := schema.ValidateBytes([]byte("name: ((name))\ninstance_groups: [{instances: ((count))}]"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(BeEmpty())
+		})
+
+		It("returns violation if root has wrong type", func() {
+			violations, err := schema.ValidateBytes([]byte("manifest"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(violations).To(Equal(Violations{
+				{Path: "/", Message: "Expected value to be of type 'object' but was 'string'"},
+			}))
+		})
+
+		It("returns error if manifest cannot be deserialized", func() {
+			_, err := schema.ValidateBytes([]byte("{"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Deserializing manifest"))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package schema
+
+type Violation struct {
+	Path    string
+	Message string
+
+	// UnknownKey is set for keys that are not allowed by a schema
+	// (additionalProperties: false) since built-in schemas may not
+	// know about keys supported by newer Directors
+	UnknownKey bool
+}
+
+type Violations []Violation
@@ -0,0 +1,66 @@
+package ssh
+
+import (
+	"os"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+// ClientSelectingRunner uses built-in SSH client
+// when it was requested via connection options.
+type ClientSelectingRunner struct {
+	execRunner   Runner
+	nativeRunner Runner
+}
+
+func NewClientSelectingRunner(execRunner, nativeRunner Runner) ClientSelectingRunner {
+	return ClientSelectingRunner{execRunner: execRunner, nativeRunner: nativeRunner}
+}
+
+func (r ClientSelectingRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, rawCmd []string) error {
+	if connOpts.Native {
+		return r.nativeRunner.Run(connOpts, result, rawCmd)
+	}
+	return r.execRunner.Run(connOpts, result, rawCmd)
+}
+
+// ClientSelectingSCPRunner downloads files from multiple instances
+// into per instance directories regardless of selected SSH client.
+type ClientSelectingSCPRunner struct {
+	execRunner   SCPRunner
+	nativeRunner SCPRunner
+	fs           boshsys.FileSystem
+}
+
+func NewClientSelectingSCPRunner(execRunner, nativeRunner SCPRunner, fs boshsys.FileSystem) ClientSelectingSCPRunner {
+	return ClientSelectingSCPRunner{execRunner: execRunner, nativeRunner: nativeRunner, fs: fs}
+}
+
+func (r ClientSelectingSCPRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, scpArgs SCPArgs) error {
+	// Syncing relies on checksums which are only available with built-in client
+	if scpArgs.Sync() && !connOpts.Native {
+		return bosherr.Error("Expected --sync to be used with built-in SSH client (--native-ssh)")
+	}
+
+	if len(result.Hosts) > 1 {
+		scpArgs = scpArgs.WithPerInstanceDst(true)
+
+		for _, host := range result.Hosts {
+			if dst, found := scpArgs.LocalDstForHost(host); found {
+				err := r.fs.MkdirAll(dst, os.ModePerm)
+				if err != nil {
+					return bosherr.WrapErrorf(err, "Creating directory '%s'", dst)
+				}
+			}
+		}
+	}
+
+	if connOpts.Native {
+		return r.nativeRunner.Run(connOpts, result, scpArgs)
+	}
+
+	return r.execRunner.Run(connOpts, result, scpArgs)
+}
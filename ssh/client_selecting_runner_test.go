@@ -0,0 +1,137 @@
+package ssh_test
+
+import (
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/cloudfoundry/bosh-cli/ssh"
+	fakessh "github.com/cloudfoundry/bosh-cli/ssh/sshfakes"
+)
+
+var _ = Describe("ClientSelectingSCPRunner", func() {
+	var (
+		execRunner   *fakessh.FakeSCPRunner
+		nativeRunner *fakessh.FakeSCPRunner
+		fs           *fakesys.FakeFileSystem
+		runner       ClientSelectingSCPRunner
+		result       boshdir.SSHResult
+	)
+
+	BeforeEach(func() {
+		execRunner = &fakessh.FakeSCPRunner{}
+		nativeRunner = &fakessh.FakeSCPRunner{}
+		fs = fakesys.NewFakeFileSystem()
+		runner = NewClientSelectingSCPRunner(execRunner, nativeRunner, fs)
+		result = boshdir.SSHResult{}
+	})
+
+	It("uses scp binary by default", func() {
+		scpArgs := NewSCPArgs([]string{"job:file", "/dst"}, false)
+
+		err := runner.Run(ConnectionOpts{}, result, scpArgs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(execRunner.RunCallCount()).To(Equal(1))
+		Expect(nativeRunner.RunCallCount()).To(Equal(0))
+	})
+
+	It("uses built-in client when it was requested", func() {
+		scpArgs := NewSCPArgs([]string{"job:file", "/dst"}, false).WithSync(true)
+
+		err := runner.Run(ConnectionOpts{Native: true}, result, scpArgs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(execRunner.RunCallCount()).To(Equal(0))
+		Expect(nativeRunner.RunCallCount()).To(Equal(1))
+	})
+
+	It("returns error if syncing without built-in client", func() {
+		scpArgs := NewSCPArgs([]string{"job:file", "/dst"}, false).WithSync(true)
+
+		err := runner.Run(ConnectionOpts{}, result, scpArgs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected --sync to be used with built-in SSH client (--native-ssh)"))
+
+		Expect(execRunner.RunCallCount()).To(Equal(0))
+		Expect(nativeRunner.RunCallCount()).To(Equal(0))
+	})
+
+	Context("when copying from multiple instances", func() {
+		BeforeEach(func() {
+			result.Hosts = []boshdir.Host{
+				{Job: "job", IndexOrID: "id1", Username: "user", Host: "ip1"},
+				{Job: "job", IndexOrID: "id2", Username: "user", Host: "ip2"},
+			}
+		})
+
+		It("downloads into per instance directories with scp binary", func() {
+			scpArgs := NewSCPArgs([]string{"job:file", "/dst"}, false)
+
+			err := runner.Run(ConnectionOpts{}, result, scpArgs)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.FileExists("/dst/job/id1")).To(BeTrue())
+			Expect(fs.FileExists("/dst/job/id2")).To(BeTrue())
+
+			Expect(execRunner.RunCallCount()).To(Equal(1))
+			_, _, runArgs := execRunner.RunArgsForCall(0)
+			Expect(runArgs.ForHost(result.Hosts[0])).To(Equal([]string{"user@ip1:file", "/dst/job/id1"}))
+			Expect(runArgs.ForHost(result.Hosts[1])).To(Equal([]string{"user@ip2:file", "/dst/job/id2"}))
+		})
+
+		It("downloads into per instance directories with built-in client", func() {
+			scpArgs := NewSCPArgs([]string{"job:file", "/dst"}, false)
+
+			err := runner.Run(ConnectionOpts{Native: true}, result, scpArgs)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.FileExists("/dst/job/id1")).To(BeTrue())
+			Expect(fs.FileExists("/dst/job/id2")).To(BeTrue())
+
+			Expect(nativeRunner.RunCallCount()).To(Equal(1))
+			_, _, runArgs := nativeRunner.RunArgsForCall(0)
+			Expect(runArgs.PathsForHost(result.Hosts[1])).To(Equal([]SCPPath{
+				{Path: "file", Remote: true},
+				{Path: "/dst/job/id2"},
+			}))
+		})
+
+		It("uploads into same destination on each instance", func() {
+			scpArgs := NewSCPArgs([]string{"/src", "job:dst"}, false)
+
+			err := runner.Run(ConnectionOpts{}, result, scpArgs)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.MkdirAllCallCount).To(Equal(0))
+
+			_, _, runArgs := execRunner.RunArgsForCall(0)
+			Expect(runArgs.ForHost(result.Hosts[1])).To(Equal([]string{"/src", "user@ip2:dst"}))
+		})
+
+		It("returns error if per instance directory cannot be created", func() {
+			fs.MkdirAllError = errors.New("fake-err")
+
+			err := runner.Run(ConnectionOpts{}, result, NewSCPArgs([]string{"job:file", "/dst"}, false))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Creating directory '/dst/job/id1'"))
+
+			Expect(execRunner.RunCallCount()).To(Equal(0))
+		})
+	})
+
+	It("downloads from single instance into destination as is", func() {
+		result.Hosts = []boshdir.Host{{Job: "job", IndexOrID: "id1", Username: "user", Host: "ip1"}}
+
+		err := runner.Run(ConnectionOpts{}, result, NewSCPArgs([]string{"job:file", "/dst"}, false))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fs.MkdirAllCallCount).To(Equal(0))
+
+		_, _, runArgs := execRunner.RunArgsForCall(0)
+		Expect(runArgs.ForHost(result.Hosts[0])).To(Equal([]string{"user@ip1:file", "/dst"}))
+	})
+})
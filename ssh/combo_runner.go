@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"os"
+	"sync"
 	"syscall"
 	"time"
 
@@ -19,9 +20,10 @@ type ComboRunner struct {
 	sessionFactory   func(ConnectionOpts, boshdir.SSHResult) Session
 	signalNotifyFunc func(chan<- os.Signal, ...os.Signal)
 
-	writer Writer
-	fs     boshsys.FileSystem
-	ui     boshui.UI
+	writer   Writer
+	recorder SessionRecorder
+	fs       boshsys.FileSystem
+	ui       boshui.UI
 
 	logTag string
 	logger boshlog.Logger
@@ -32,6 +34,7 @@ func NewComboRunner(
 	sessionFactory func(ConnectionOpts, boshdir.SSHResult) Session,
 	signalNotifyFunc func(chan<- os.Signal, ...os.Signal),
 	writer Writer,
+	recorder SessionRecorder,
 	fs boshsys.FileSystem,
 	ui boshui.UI,
 	logger boshlog.Logger,
@@ -41,9 +44,10 @@ func NewComboRunner(
 		sessionFactory:   sessionFactory,
 		signalNotifyFunc: signalNotifyFunc,
 
-		writer: writer,
-		fs:     fs,
-		ui:     ui,
+		writer:   writer,
+		recorder: recorder,
+		fs:       fs,
+		ui:       ui,
 
 		logTag: "ComboRunner",
 		logger: logger,
@@ -62,13 +66,20 @@ func (r ComboRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, cmdF
 		_ = sess.Finish()
 	}()
 
+	if connOpts.Rolling() {
+		if _, ok := r.writer.(*ResultsWriter); !ok {
+			// Streamed output of many hosts is hard to follow so summarise exit statuses at the end
+			r.writer = NewMultiWriter(r.writer, NewExitStatusResultsWriter(r.ui))
+		}
+	}
+
 	cancelCh := make(chan struct{}, 1)
 
 	go r.setUpInterrupt(cancelCh, sess)
 
 	cmds := r.makeCmds(result.Hosts, sshArgs, cmdFactory)
 
-	ps, doneCh := r.runCmds(cmds)
+	ps, doneCh := r.runCmds(cmds, connOpts)
 
 	return r.waitProcs(ps, doneCh, cancelCh)
 }
@@ -76,6 +87,8 @@ func (r ComboRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, cmdF
 type comboRunnerCmd struct {
 	boshsys.Command
 	InstanceWriter
+
+	host boshdir.Host
 }
 
 func (r ComboRunner) makeCmds(hosts []boshdir.Host, sshArgs SSHArgs, cmdFactory func(boshdir.Host, SSHArgs) boshsys.Command) []comboRunnerCmd {
@@ -96,60 +109,200 @@ func (r ComboRunner) makeCmds(hosts []boshdir.Host, sshArgs SSHArgs, cmdFactory
 			cmd.Stderr = instWriter.Stderr()
 		}
 
-		cmds = append(cmds, comboRunnerCmd{cmd, instWriter})
+		cmds = append(cmds, comboRunnerCmd{cmd, instWriter, host})
 	}
 
 	return cmds
 }
 
-func (r ComboRunner) runCmds(cmds []comboRunnerCmd) ([]boshsys.Process, chan []boshsys.Result) {
-	var processes []boshsys.Process
+// comboRunnerProcs keeps track of started processes
+// so that they could be terminated upon a signal.
+type comboRunnerProcs struct {
+	ps        []boshsys.Process
+	cancelled bool
+	lock      sync.Mutex
+}
+
+func (p *comboRunnerProcs) Add(process boshsys.Process) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.ps = append(p.ps, process)
+
+	return !p.cancelled
+}
+
+func (p *comboRunnerProcs) Cancel() []boshsys.Process {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.cancelled = true
+
+	return p.ps
+}
+
+func (p *comboRunnerProcs) Cancelled() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.cancelled
+}
+
+func (r ComboRunner) runCmds(cmds []comboRunnerCmd, connOpts ConnectionOpts) (*comboRunnerProcs, chan []boshsys.Result) {
+	procs := &comboRunnerProcs{}
+
+	batchSize := len(cmds)
+
+	if connOpts.MaxInFlight > 0 && connOpts.MaxInFlight < batchSize {
+		batchSize = connOpts.MaxInFlight
+	}
+
+	doneCh := make(chan []boshsys.Result)
+
+	go func() {
+		var rs []boshsys.Result
+		var failed bool
+
+		for i := 0; i < len(cmds); i += batchSize {
+			batch := cmds[i:]
+			if len(batch) > batchSize {
+				batch = batch[:batchSize]
+			}
+
+			if (failed && connOpts.FailFast) || procs.Cancelled() {
+				for _, cmd := range batch {
+					cmd.InstanceWriter.End(0, bosherr.Error("Skipped since execution was stopped"))
+				}
+				continue
+			}
+
+			for _, result := range r.runBatch(batch, connOpts, procs) {
+				if result.Error != nil || result.ExitStatus != 0 {
+					failed = true
+				}
+				rs = append(rs, result)
+			}
+		}
+
+		doneCh <- rs
+	}()
 
+	return procs, doneCh
+}
+
+func (r ComboRunner) runBatch(cmds []comboRunnerCmd, connOpts ConnectionOpts, procs *comboRunnerProcs) []boshsys.Result {
 	allResultsCh := make(chan boshsys.Result, len(cmds))
 
 	for _, cmd := range cmds {
+		cmd.InstanceWriter.Start()
+
+		recording, err := r.startRecording(&cmd, connOpts.Recording)
+		if err != nil {
+			cmd.InstanceWriter.End(0, err)
+			allResultsCh <- boshsys.Result{Error: err}
+			continue
+		}
+
 		process, err := r.cmdRunner.RunComplexCommandAsync(cmd.Command)
 		if err != nil {
 			r.logger.Error(r.logTag, "Process immediately failed")
+			r.endRecording(recording, 0, err)
 			cmd.InstanceWriter.End(0, err)
 			allResultsCh <- boshsys.Result{Error: err}
 			continue
 		}
 
-		processes = append(processes, process)
-
-		// Call Wait outside of goroutine
+		// Call Wait before registering process
 		// to make sure TerminateNicely is not called before
 		resultCh := process.Wait()
 
+		if !procs.Add(process) {
+			// Signal was received while this batch was starting
+			_ = process.TerminateNicely(10 * time.Second)
+		}
+
 		// local variable to keep it in scope
 		instWriter := cmd.InstanceWriter
 
 		go func() {
-			result := <-resultCh
+			result := r.waitProc(process, resultCh, connOpts.Timeout)
+			r.endRecording(recording, result.ExitStatus, result.Error)
 			instWriter.End(result.ExitStatus, result.Error)
 			allResultsCh <- result
 		}()
 	}
 
-	r.logger.Debug(r.logTag, "Started all processes")
+	r.logger.Debug(r.logTag, "Started %d processes", len(cmds))
 
-	doneCh := make(chan []boshsys.Result)
+	var rs []boshsys.Result
 
-	go func() {
-		var rs []boshsys.Result
+	for i := 0; i < len(cmds); i++ {
+		rs = append(rs, <-allResultsCh)
+	}
+
+	return rs
+}
+
+// startRecording captures command output if sessions are recorded
+func (r ComboRunner) startRecording(cmd *comboRunnerCmd, opts RecordingOpts) (*SessionRecording, error) {
+	if len(opts.Dir) == 0 {
+		return nil, nil
+	}
+
+	recording, err := r.recorder.Record(opts, cmd.host)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Recording session")
+	}
 
-		for i := 0; i < len(cmds); i++ {
-			rs = append(rs, <-allResultsCh)
+	r.logger.Debug(r.logTag, "Recording session into '%s'", recording.Path())
+
+	if cmd.Command.Stdout != nil {
+		cmd.Command.Stdout = recording.Writer(cmd.Command.Stdout)
+	}
+
+	if cmd.Command.Stderr != nil {
+		cmd.Command.Stderr = recording.Writer(cmd.Command.Stderr)
+	}
+
+	return recording, nil
+}
+
+func (r ComboRunner) endRecording(recording *SessionRecording, exitStatus int, err error) {
+	if recording == nil {
+		return
+	}
+
+	endErr := recording.End(exitStatus, err)
+	if endErr != nil {
+		r.logger.Error(r.logTag, "Failed to finish recording with error '%s'", endErr.Error())
+	}
+}
+
+func (r ComboRunner) waitProc(process boshsys.Process, resultCh <-chan boshsys.Result, timeout time.Duration) boshsys.Result {
+	if timeout <= 0 {
+		return <-resultCh
+	}
+
+	select {
+	case result := <-resultCh:
+		return result
+
+	case <-time.After(timeout):
+		r.logger.Debug(r.logTag, "Process timed out after '%s'", timeout)
+
+		err := process.TerminateNicely(10 * time.Second)
+		if err != nil {
+			r.logger.Error(r.logTag, "Failed to terminate with error '%s'", err.Error())
 		}
 
-		doneCh <- rs
-	}()
+		result := <-resultCh
+		result.Error = bosherr.Errorf("Timed out after '%s'", timeout)
 
-	return processes, doneCh
+		return result
+	}
 }
 
-func (r ComboRunner) waitProcs(ps []boshsys.Process, doneCh chan []boshsys.Result, cancelCh chan struct{}) error {
+func (r ComboRunner) waitProcs(procs *comboRunnerProcs, doneCh chan []boshsys.Result, cancelCh chan struct{}) error {
 	r.logger.Debug(r.logTag, "Waiting for all processes or cancel signal")
 
 	for {
@@ -172,7 +325,7 @@ func (r ComboRunner) waitProcs(ps []boshsys.Process, doneCh chan []boshsys.Resul
 		case <-cancelCh:
 			r.logger.Debug(r.logTag, "Received cancel signal")
 
-			for _, p := range ps {
+			for _, p := range procs.Cancel() {
 				err := p.TerminateNicely(10 * time.Second)
 				if err != nil {
 					r.logger.Error(r.logTag, "Failed to terminate with error '%s'", err.Error())
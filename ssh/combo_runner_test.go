@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
+	"code.cloudfoundry.org/clock"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
@@ -19,6 +23,7 @@ import (
 	fakessh "github.com/cloudfoundry/bosh-cli/ssh/sshfakes"
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
 	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 var _ = Describe("ComboRunner", func() {
@@ -55,7 +60,7 @@ var _ = Describe("ComboRunner", func() {
 		logger = boshlog.NewLogger(boshlog.LevelNone)
 
 		comboRunner = NewComboRunner(
-			cmdRunner, sessFactory, signalNotifyFunc, writer, fs, ui, logger)
+			cmdRunner, sessFactory, signalNotifyFunc, writer, NewSessionRecorder(fs, clock.NewClock()), fs, ui, logger)
 	})
 
 	Describe("Run", func() {
@@ -231,6 +236,81 @@ var _ = Describe("ComboRunner", func() {
 			Expect(stderr.String()).To(Equal("stderr"))
 		})
 
+		Describe("recording", func() {
+			var (
+				recordDir string
+			)
+
+			BeforeEach(func() {
+				var err error
+
+				recordDir, err = ioutil.TempDir("", "bosh-ssh-recordings")
+				Expect(err).ToNot(HaveOccurred())
+
+				osFS := boshsys.NewOsFileSystem(logger)
+				recorder := NewSessionRecorder(osFS, clock.NewClock())
+
+				comboRunner = NewComboRunner(
+					cmdRunner, func(_ ConnectionOpts, _ boshdir.SSHResult) Session { return session },
+					func(ch chan<- os.Signal, s ...os.Signal) {}, writer, recorder, fs, ui, logger)
+
+				connOpts.Recording = RecordingOpts{
+					Dir:         recordDir,
+					Environment: "https://director:25555",
+					Deployment:  "dep",
+					LocalUser:   "local-user",
+				}
+
+				result.Hosts = []boshdir.Host{
+					{Job: "job1", IndexOrID: "id1", Host: "127.0.0.1", Username: "user"},
+					{Job: "job2", IndexOrID: "id2", Host: "127.0.0.2", Username: "user"},
+				}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(recordDir)
+			})
+
+			It("records session of each host into a separate file", func() {
+				proc1 := &fakesys.FakeProcess{WaitResult: boshsys.Result{ExitStatus: 3}}
+				cmdRunner.AddProcess("cmd 127.0.0.1", proc1)
+				cmdRunner.AddProcess("cmd 127.0.0.2", &fakesys.FakeProcess{})
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).ToNot(HaveOccurred())
+
+				paths, err := filepath.Glob(filepath.Join(recordDir, "dep-job1-id1-*.cast"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paths).To(HaveLen(1))
+
+				contents, err := ioutil.ReadFile(paths[0])
+				Expect(err).ToNot(HaveOccurred())
+
+				lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+				Expect(lines).To(HaveLen(2))
+				Expect(lines[0]).To(ContainSubstring(`"version":2`))
+				Expect(lines[0]).To(ContainSubstring(`"environment":"https://director:25555","deployment":"dep","instance":"job1/id1","host":"127.0.0.1","username":"user","local_user":"local-user"`))
+				Expect(lines[1]).To(ContainSubstring(`session ended with exit status 3`))
+
+				paths, err = filepath.Glob(filepath.Join(recordDir, "dep-job2-id2-*.cast"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paths).To(HaveLen(1))
+
+				// Output is still passed through
+				proc1.Stdout.Write([]byte("stdout1\n"))
+				Expect(ui.Blocks).To(ContainElement("stdout1"))
+			})
+
+			It("returns an error if recording cannot be started", func() {
+				connOpts.Recording.Dir = filepath.Join(recordDir, "file", "dir")
+				Expect(ioutil.WriteFile(filepath.Join(recordDir, "file"), nil, 0600)).ToNot(HaveOccurred())
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Recording session"))
+			})
+		})
+
 		It("ultimately returns an error if any processes fail to start", func() {
 			result.Hosts = []boshdir.Host{
 				{Host: "127.0.0.1"},
@@ -299,6 +379,125 @@ var _ = Describe("ComboRunner", func() {
 			Expect(err.Error()).To(ContainSubstring("fake-err3"))
 		})
 
+		Describe("rolling execution", func() {
+			BeforeEach(func() {
+				result.Hosts = []boshdir.Host{
+					{Job: "job", IndexOrID: "id1", Host: "127.0.0.1"},
+					{Job: "job", IndexOrID: "id2", Host: "127.0.0.2"},
+					{Job: "job", IndexOrID: "id3", Host: "127.0.0.3"},
+				}
+			})
+
+			It("runs hosts in batches of max in flight", func() {
+				connOpts.MaxInFlight = 2
+
+				proc1 := &fakesys.FakeProcess{}
+				cmdRunner.AddProcess("cmd 127.0.0.1", proc1)
+
+				proc2 := &fakesys.FakeProcess{}
+				cmdRunner.AddProcess("cmd 127.0.0.2", proc2)
+
+				var startedWithPrevBatchDone bool
+
+				proc3 := &fakesys.FakeProcess{}
+				cmdRunner.AddProcess("cmd 127.0.0.3", proc3)
+				cmdRunner.SetCmdCallback("cmd 127.0.0.3", func() {
+					startedWithPrevBatchDone = proc1.Waited && proc2.Waited
+				})
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(cmdRunner.RunComplexCommands).To(HaveLen(3))
+				Expect(startedWithPrevBatchDone).To(BeTrue())
+			})
+
+			It("skips remaining batches after a failure when failing fast", func() {
+				connOpts.MaxInFlight = 1
+				connOpts.FailFast = true
+
+				cmdRunner.AddProcess("cmd 127.0.0.1", &fakesys.FakeProcess{})
+				cmdRunner.AddProcess("cmd 127.0.0.2", &fakesys.FakeProcess{
+					WaitResult: boshsys.Result{ExitStatus: 1, Error: errors.New("fake-err")},
+				})
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(cmdRunner.RunComplexCommands).To(HaveLen(2))
+			})
+
+			It("terminates hosts that exceed timeout", func() {
+				connOpts.Timeout = 10 * time.Millisecond
+
+				cmdRunner.AddProcess("cmd 127.0.0.1", &fakesys.FakeProcess{})
+
+				proc2 := &fakesys.FakeProcess{
+					TerminatedNicelyCallBack: func(p *fakesys.FakeProcess) {
+						p.WaitCh <- boshsys.Result{}
+					},
+				}
+				cmdRunner.AddProcess("cmd 127.0.0.2", proc2)
+
+				cmdRunner.AddProcess("cmd 127.0.0.3", &fakesys.FakeProcess{})
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Timed out after '10ms'"))
+
+				Expect(proc2.TerminatedNicely).To(BeTrue())
+			})
+
+			It("summarises exit statuses of all hosts at the end", func() {
+				connOpts.MaxInFlight = 1
+				connOpts.FailFast = true
+
+				cmdRunner.AddProcess("cmd 127.0.0.1", &fakesys.FakeProcess{})
+				cmdRunner.AddProcess("cmd 127.0.0.2", &fakesys.FakeProcess{
+					WaitResult: boshsys.Result{ExitStatus: 2, Error: errors.New("fake-err")},
+				})
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).To(HaveOccurred())
+
+				Expect(ui.Table.Header).To(Equal([]boshtbl.Header{
+					boshtbl.NewHeader("Instance"),
+					boshtbl.NewHeader("Exit Code"),
+					boshtbl.NewHeader("Error"),
+				}))
+
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("job/id1"),
+						boshtbl.NewValueInt(0),
+						boshtbl.NewValueError(nil),
+					},
+					{
+						boshtbl.NewValueString("job/id2"),
+						boshtbl.NewValueInt(2),
+						boshtbl.NewValueError(errors.New("fake-err")),
+					},
+					{
+						boshtbl.NewValueString("job/id3"),
+						boshtbl.NewValueInt(0),
+						boshtbl.NewValueError(errors.New("Skipped since execution was stopped")),
+					},
+				}))
+			})
+
+			It("does not summarise exit statuses when not rolling", func() {
+				cmdRunner.AddProcess("cmd 127.0.0.1", &fakesys.FakeProcess{})
+				cmdRunner.AddProcess("cmd 127.0.0.2", &fakesys.FakeProcess{})
+				cmdRunner.AddProcess("cmd 127.0.0.3", &fakesys.FakeProcess{})
+
+				err := comboRunner.Run(connOpts, result, cmdFactory)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Rows).To(BeEmpty())
+			})
+		})
+
 		Describe("signal handling", func() {
 			var errCh chan error
 
@@ -0,0 +1,202 @@
+package ssh
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"golang.org/x/crypto/ssh"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+// hostKeyPinsMutex serializes updates to known_hosts files
+// since hosts may be connected to concurrently.
+var hostKeyPinsMutex sync.Mutex
+
+// HostKeyPins keeps host public keys seen in previous sessions
+// in a CLI managed known_hosts file so that changed keys are detected.
+type HostKeyPins struct {
+	path string
+	fs   boshsys.FileSystem
+}
+
+func NewHostKeyPins(path string, fs boshsys.FileSystem) HostKeyPins {
+	return HostKeyPins{path: path, fs: fs}
+}
+
+// Pin records Director provided host keys and fills in previously pinned
+// keys for hosts that Director did not provide a key for.
+// Error is returned if Director provided key does not match pinned key.
+func (p HostKeyPins) Pin(result boshdir.SSHResult) (boshdir.SSHResult, error) {
+	hosts := make([]boshdir.Host, 0, len(result.Hosts))
+
+	for _, host := range result.Hosts {
+		pinnedHost, err := p.PinHost(host)
+		if err != nil {
+			return boshdir.SSHResult{}, err
+		}
+
+		hosts = append(hosts, pinnedHost)
+	}
+
+	result.Hosts = hosts
+
+	return result, nil
+}
+
+// PinHost is similar to Pin but only for a single host.
+func (p HostKeyPins) PinHost(host boshdir.Host) (boshdir.Host, error) {
+	hostKeyPinsMutex.Lock()
+	defer hostKeyPinsMutex.Unlock()
+
+	pins, path, err := p.read()
+	if err != nil {
+		return boshdir.Host{}, err
+	}
+
+	pinnedKey, found := pins[host.Host]
+
+	if len(host.HostPublicKey) == 0 {
+		host.HostPublicKey = pinnedKey
+		return host, nil
+	}
+
+	key := p.normalizeKey(host.HostPublicKey)
+
+	if found {
+		if pinnedKey != key {
+			return boshdir.Host{}, p.mismatchErr(host, path)
+		}
+
+		return host, nil
+	}
+
+	return host, p.append(path, host.Host, key)
+}
+
+// Trust pins host key presented by the host itself when neither the Director
+// nor previous sessions provided one (trust on first use).
+func (p HostKeyPins) Trust(host boshdir.Host, key ssh.PublicKey) error {
+	host.HostPublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+
+	_, err := p.PinHost(host)
+
+	return err
+}
+
+// TrustKnownHosts pins host keys found in known_hosts contents
+// (e.g. collected by the ssh binary with StrictHostKeyChecking=accept-new).
+func (p HostKeyPins) TrustKnownHosts(content string) error {
+	for hostAddr, key := range p.parse(content) {
+		_, err := p.PinHost(boshdir.Host{Host: hostAddr, HostPublicKey: key})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p HostKeyPins) mismatchErr(host boshdir.Host, path string) error {
+	return bosherr.Errorf(
+		"Expected host key for instance '%s/%s' (%s) to match previously pinned host key in '%s'. "+
+			"Someone could be eavesdropping on the connection (man-in-the-middle attack) "+
+			"or the host key has legitimately changed, for example, because the VM was recreated. "+
+			"If the change is expected, remove the old key with 'ssh-keygen -R %s -f %s'",
+		host.Job, host.IndexOrID, host.Host, path, host.Host, path)
+}
+
+func (p HostKeyPins) read() (map[string]string, string, error) {
+	path, err := p.fs.ExpandPath(p.path)
+	if err != nil {
+		return nil, "", bosherr.WrapErrorf(err, "Expanding known hosts path '%s'", p.path)
+	}
+
+	if !p.fs.FileExists(path) {
+		return map[string]string{}, path, nil
+	}
+
+	content, err := p.fs.ReadFileString(path)
+	if err != nil {
+		return nil, "", bosherr.WrapErrorf(err, "Reading known hosts '%s'", path)
+	}
+
+	return p.parse(content), path, nil
+}
+
+func (p HostKeyPins) append(path, hostAddr, key string) error {
+	var content string
+
+	if p.fs.FileExists(path) {
+		var err error
+
+		content, err = p.fs.ReadFileString(path)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading known hosts '%s'", path)
+		}
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	content += fmt.Sprintf("%s %s\n", hostAddr, key)
+
+	err := p.fs.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Creating known hosts directory '%s'", filepath.Dir(path))
+	}
+
+	err = p.fs.WriteFileString(path, content)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing known hosts '%s'", path)
+	}
+
+	err = p.fs.Chmod(path, 0600)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Setting known hosts '%s' permissions", path)
+	}
+
+	return nil
+}
+
+// parse only understands plain known_hosts entries (as written by KnownHosts);
+// hashed and marker entries are ignored.
+func (p HostKeyPins) parse(content string) map[string]string {
+	pins := map[string]string{}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "|") || strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		key := p.normalizeKey(strings.Join(fields[1:], " "))
+
+		for _, hostAddr := range strings.Split(fields[0], ",") {
+			pins[hostAddr] = key
+		}
+	}
+
+	return pins
+}
+
+// normalizeKey drops optional key comment so that only key type and key data are compared
+func (p HostKeyPins) normalizeKey(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) > 2 {
+		fields = fields[:2]
+	}
+
+	return strings.Join(fields, " ")
+}
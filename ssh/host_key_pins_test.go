@@ -0,0 +1,117 @@
+package ssh_test
+
+import (
+	"errors"
+	"os"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/cloudfoundry/bosh-cli/ssh"
+)
+
+var _ = Describe("HostKeyPins", func() {
+	var (
+		fs   *fakesys.FakeFileSystem
+		pins HostKeyPins
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		fs.ExpandPathExpanded = "/home/.bosh/known_hosts/env"
+		pins = NewHostKeyPins("~/.bosh/known_hosts/env", fs)
+	})
+
+	Describe("Pin", func() {
+		It("pins Director provided host keys", func() {
+			result, err := pins.Pin(boshdir.SSHResult{
+				Hosts: []boshdir.Host{
+					{Host: "10.0.0.1", HostPublicKey: "ssh-rsa key1 comment"},
+					{Host: "10.0.0.2", HostPublicKey: "ssh-rsa key2"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Hosts).To(HaveLen(2))
+
+			Expect(fs.ReadFileString("/home/.bosh/known_hosts/env")).To(Equal(
+				"10.0.0.1 ssh-rsa key1\n10.0.0.2 ssh-rsa key2\n"))
+			Expect(fs.GetFileTestStat("/home/.bosh/known_hosts/env").FileMode).To(Equal(os.FileMode(0600)))
+		})
+
+		It("fills in pinned host keys for hosts without Director provided host key", func() {
+			fs.WriteFileString("/home/.bosh/known_hosts/env", "# comment\n10.0.0.1,other ssh-rsa key1\n")
+
+			result, err := pins.Pin(boshdir.SSHResult{
+				Hosts: []boshdir.Host{{Host: "10.0.0.1"}, {Host: "10.0.0.2"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Hosts).To(Equal([]boshdir.Host{
+				{Host: "10.0.0.1", HostPublicKey: "ssh-rsa key1"},
+				{Host: "10.0.0.2"},
+			}))
+		})
+
+		It("keeps matching pinned host keys", func() {
+			fs.WriteFileString("/home/.bosh/known_hosts/env", "10.0.0.1 ssh-rsa key1\n")
+
+			_, err := pins.Pin(boshdir.SSHResult{
+				Hosts: []boshdir.Host{{Host: "10.0.0.1", HostPublicKey: "ssh-rsa key1 comment"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/home/.bosh/known_hosts/env")).To(Equal("10.0.0.1 ssh-rsa key1\n"))
+		})
+
+		It("returns error if Director provided host key does not match pinned host key", func() {
+			fs.WriteFileString("/home/.bosh/known_hosts/env", "10.0.0.1 ssh-rsa key1\n")
+
+			_, err := pins.Pin(boshdir.SSHResult{
+				Hosts: []boshdir.Host{{Job: "db", IndexOrID: "db-id", Host: "10.0.0.1", HostPublicKey: "ssh-rsa other-key"}},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(
+				"Expected host key for instance 'db/db-id' (10.0.0.1) to match previously pinned host key in '/home/.bosh/known_hosts/env'"))
+			Expect(err.Error()).To(ContainSubstring("ssh-keygen -R 10.0.0.1 -f /home/.bosh/known_hosts/env"))
+
+			Expect(fs.ReadFileString("/home/.bosh/known_hosts/env")).To(Equal("10.0.0.1 ssh-rsa key1\n"))
+		})
+
+		It("returns error if reading known hosts fails", func() {
+			fs.WriteFileString("/home/.bosh/known_hosts/env", "")
+			fs.RegisterReadFileError("/home/.bosh/known_hosts/env", errors.New("fake-err"))
+
+			_, err := pins.Pin(boshdir.SSHResult{Hosts: []boshdir.Host{{Host: "10.0.0.1"}}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if writing known hosts fails", func() {
+			fs.WriteFileError = errors.New("fake-err")
+
+			_, err := pins.Pin(boshdir.SSHResult{
+				Hosts: []boshdir.Host{{Host: "10.0.0.1", HostPublicKey: "ssh-rsa key1"}},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+
+	Describe("TrustKnownHosts", func() {
+		It("pins plain entries and ignores hashed entries", func() {
+			err := pins.TrustKnownHosts("10.0.0.1 ssh-rsa key1\n|1|salt|hash ssh-rsa key2\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/home/.bosh/known_hosts/env")).To(Equal("10.0.0.1 ssh-rsa key1\n"))
+		})
+
+		It("returns error if key does not match pinned host key", func() {
+			fs.WriteFileString("/home/.bosh/known_hosts/env", "10.0.0.1 ssh-rsa key1\n")
+
+			err := pins.TrustKnownHosts("10.0.0.1 ssh-rsa other-key\n")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("to match previously pinned host key"))
+		})
+	})
+})
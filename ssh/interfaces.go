@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"io"
+	"time"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/director"
 )
@@ -18,6 +19,17 @@ type SCPRunner interface {
 	Run(ConnectionOpts, boshdir.SSHResult, SCPArgs) error
 }
 
+//go:generate counterfeiter . PortForwarder
+
+type PortForwarder interface {
+	Forward(ConnectionOpts, boshdir.SSHResult, []PortForward) error
+}
+
+type PortForward struct {
+	LocalPort  int
+	RemotePort int
+}
+
 type ConnectionOpts struct {
 	PrivateKey string
 
@@ -30,6 +42,29 @@ type ConnectionOpts struct {
 	SOCKS5Proxy string
 
 	RawOpts []string
+
+	// CLI managed known_hosts file used to pin host keys across sessions
+	KnownHostsPath string
+	// Pin host keys presented by hosts when Director does not provide them
+	TrustOnFirstUse bool
+
+	// Record sessions into a directory when it's specified
+	Recording RecordingOpts
+
+	// Use built-in SSH client instead of ssh/scp binaries
+	Native bool
+
+	// Number of hosts to run on at a time; 0 means all hosts at once
+	MaxInFlight int
+	// Do not start remaining hosts after a failure
+	FailFast bool
+	// Per host time limit; 0 means no limit
+	Timeout time.Duration
+}
+
+// Rolling returns true if hosts should not simply run all at once.
+func (o ConnectionOpts) Rolling() bool {
+	return o.MaxInFlight > 0 || o.FailFast || o.Timeout > 0
 }
 
 //go:generate counterfeiter . Session
@@ -45,6 +80,7 @@ type Writer interface {
 }
 
 type InstanceWriter interface {
+	Start()
 	Stdout() io.Writer
 	Stderr() io.Writer
 	End(exitStatus int, err error)
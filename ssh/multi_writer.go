@@ -0,0 +1,60 @@
+package ssh
+
+import (
+	"io"
+)
+
+// MultiWriter sends output and results of each instance to all writers.
+type MultiWriter struct {
+	writers []Writer
+}
+
+func NewMultiWriter(writers ...Writer) MultiWriter {
+	return MultiWriter{writers: writers}
+}
+
+func (w MultiWriter) ForInstance(jobName, indexOrID string) InstanceWriter {
+	var instWriters multiInstanceWriter
+
+	for _, writer := range w.writers {
+		instWriters = append(instWriters, writer.ForInstance(jobName, indexOrID))
+	}
+
+	return instWriters
+}
+
+func (w MultiWriter) Flush() {
+	for _, writer := range w.writers {
+		writer.Flush()
+	}
+}
+
+type multiInstanceWriter []InstanceWriter
+
+func (w multiInstanceWriter) Start() {
+	for _, instWriter := range w {
+		instWriter.Start()
+	}
+}
+
+func (w multiInstanceWriter) Stdout() io.Writer {
+	var writers []io.Writer
+	for _, instWriter := range w {
+		writers = append(writers, instWriter.Stdout())
+	}
+	return io.MultiWriter(writers...)
+}
+
+func (w multiInstanceWriter) Stderr() io.Writer {
+	var writers []io.Writer
+	for _, instWriter := range w {
+		writers = append(writers, instWriter.Stderr())
+	}
+	return io.MultiWriter(writers...)
+}
+
+func (w multiInstanceWriter) End(exitStatus int, err error) {
+	for _, instWriter := range w {
+		instWriter.End(exitStatus, err)
+	}
+}
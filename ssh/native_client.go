@@ -0,0 +1,316 @@
+package ssh
+
+import (
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	proxy "github.com/cloudfoundry/socks5-proxy"
+	"golang.org/x/crypto/ssh"
+	goproxy "golang.org/x/net/proxy"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+const (
+	nativeDefaultPort       = 22
+	nativeConnectTimeout    = 30 * time.Second
+	nativeKeepAliveInterval = 30 * time.Second
+)
+
+// NativeClient is an SSH connection to a single host made
+// without relying on ssh binary (possibly via a gateway).
+type NativeClient struct {
+	*ssh.Client
+
+	gwClient *ssh.Client
+}
+
+func (c NativeClient) Close() error {
+	err := c.Client.Close()
+
+	if c.gwClient != nil {
+		_ = c.gwClient.Close()
+	}
+
+	return err
+}
+
+type NativeClientFactory struct {
+	port int
+
+	fs     boshsys.FileSystem
+	logTag string
+	logger boshlog.Logger
+}
+
+func NewNativeClientFactory(port int, fs boshsys.FileSystem, logger boshlog.Logger) NativeClientFactory {
+	return NativeClientFactory{
+		port: port,
+
+		fs:     fs,
+		logTag: "NativeClientFactory",
+		logger: logger,
+	}
+}
+
+func (f NativeClientFactory) New(connOpts ConnectionOpts, result boshdir.SSHResult, host boshdir.Host) (NativeClient, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(connOpts.PrivateKey))
+	if err != nil {
+		return NativeClient{}, bosherr.WrapError(err, "Parsing SSH private key")
+	}
+
+	hostKeyCallback, err := f.hostKeyCallback(connOpts, host)
+	if err != nil {
+		return NativeClient{}, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            host.Username,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         nativeConnectTimeout,
+	}
+
+	addr := net.JoinHostPort(host.Host, strconv.Itoa(f.port))
+
+	dialFunc, gwClient, err := f.dialFunc(connOpts, result)
+	if err != nil {
+		return NativeClient{}, err
+	}
+
+	f.logger.Debug(f.logTag, "Dialing host '%s'", addr)
+
+	conn, err := dialFunc("tcp", addr)
+	if err != nil {
+		f.closeGateway(gwClient)
+		return NativeClient{}, bosherr.WrapErrorf(err, "Dialing host '%s'", addr)
+	}
+
+	client, err := f.newClient(conn, addr, config)
+	if err != nil {
+		f.closeGateway(gwClient)
+		return NativeClient{}, bosherr.WrapErrorf(err, "Connecting to host '%s'", addr)
+	}
+
+	return NativeClient{Client: client, gwClient: gwClient}, nil
+}
+
+func (f NativeClientFactory) hostKeyCallback(connOpts ConnectionOpts, host boshdir.Host) (ssh.HostKeyCallback, error) {
+	if len(connOpts.KnownHostsPath) > 0 {
+		pins := NewHostKeyPins(connOpts.KnownHostsPath, f.fs)
+
+		var err error
+
+		host, err = pins.PinHost(host)
+		if err != nil {
+			return nil, err
+		}
+
+		// Similarly to StrictHostKeyChecking=accept-new host key is pinned on first use
+		if len(host.HostPublicKey) == 0 && connOpts.TrustOnFirstUse {
+			return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+				return pins.Trust(host, key)
+			}, nil
+		}
+	}
+
+	// Similarly to StrictHostKeyChecking=yes only Director provided (or pinned) host key is accepted
+	if len(host.HostPublicKey) == 0 {
+		return func(string, net.Addr, ssh.PublicKey) error {
+			return bosherr.Errorf("Expected host public key for '%s' to be provided by the Director", host.Host)
+		}, nil
+	}
+
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(host.HostPublicKey))
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Parsing host public key for '%s'", host.Host)
+	}
+
+	return ssh.FixedHostKey(hostKey), nil
+}
+
+func (f NativeClientFactory) dialFunc(connOpts ConnectionOpts, result boshdir.SSHResult) (proxy.DialFunc, *ssh.Client, error) {
+	if len(connOpts.SOCKS5Proxy) > 0 {
+		dialFunc, err := f.socks5DialFunc(connOpts.SOCKS5Proxy)
+		return dialFunc, nil, err
+	}
+
+	gwUsername, gwHost, gwPrivKeyPath := gatewayOpts(connOpts, result)
+
+	if len(gwHost) == 0 {
+		dialer := &net.Dialer{Timeout: nativeConnectTimeout}
+		return dialer.Dial, nil, nil
+	}
+
+	gwClient, err := f.gatewayClient(gwUsername, gwHost, gwPrivKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gwClient.Dial, gwClient, nil
+}
+
+func (f NativeClientFactory) socks5DialFunc(proxyStr string) (proxy.DialFunc, error) {
+	proxyURL, err := url.Parse(strings.TrimPrefix(proxyStr, "ssh+"))
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Parsing SOCKS5 proxy URL")
+	}
+
+	if !strings.HasPrefix(proxyStr, "ssh+") {
+		dialer, err := goproxy.FromURL(proxyURL, goproxy.Direct)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Building SOCKS5 dialer")
+		}
+
+		return dialer.Dial, nil
+	}
+
+	keyPath := proxyURL.Query().Get("private-key")
+	if len(keyPath) == 0 {
+		return nil, bosherr.Error("Expected SOCKS5 proxy URL to include 'private-key' query param")
+	}
+
+	key, err := f.fs.ReadFileString(keyPath)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading private key file for SOCKS5 proxy")
+	}
+
+	var username string
+
+	if proxyURL.User != nil {
+		username = proxyURL.User.Username()
+	}
+
+	socks5Proxy := proxy.NewSocks5Proxy(proxy.NewHostKey(), nil)
+
+	dialFunc, err := socks5Proxy.Dialer(username, key, proxyURL.Host)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Building SOCKS5 dialer")
+	}
+
+	return dialFunc, nil
+}
+
+func (f NativeClientFactory) gatewayClient(username, host, privKeyPath string) (*ssh.Client, error) {
+	authMethods, err := f.gatewayAuthMethods(privKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User: username,
+		Auth: authMethods,
+		// Strict host key checking for a gateway is not necessary
+		// since it's only used for forwarding TCP
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         nativeConnectTimeout,
+	}
+
+	addr := host
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, strconv.Itoa(nativeDefaultPort))
+	}
+
+	f.logger.Debug(f.logTag, "Dialing gateway '%s'", addr)
+
+	conn, err := net.DialTimeout("tcp", addr, nativeConnectTimeout)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Dialing gateway '%s'", addr)
+	}
+
+	client, err := f.newClient(conn, addr, config)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Connecting to gateway '%s'", addr)
+	}
+
+	return client, nil
+}
+
+func (f NativeClientFactory) gatewayAuthMethods(privKeyPath string) ([]ssh.AuthMethod, error) {
+	paths := []string{privKeyPath}
+
+	if len(privKeyPath) == 0 {
+		// Similarly to ssh binary fall back to default identity files
+		homeDir, err := f.fs.HomeDir("")
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Finding home directory for gateway private key")
+		}
+
+		paths = nil
+
+		for _, name := range []string{"id_rsa", "id_ecdsa", "id_ed25519"} {
+			path := filepath.Join(homeDir, ".ssh", name)
+			if f.fs.FileExists(path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	var signers []ssh.Signer
+
+	for _, path := range paths {
+		expandedPath, err := f.fs.ExpandPath(path)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Expanding gateway private key path '%s'", path)
+		}
+
+		key, err := f.fs.ReadFileString(expandedPath)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Reading gateway private key '%s'", path)
+		}
+
+		signer, err := ssh.ParsePrivateKey([]byte(key))
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Parsing gateway private key '%s'", path)
+		}
+
+		signers = append(signers, signer)
+	}
+
+	if len(signers) == 0 {
+		return nil, bosherr.Error("Expected gateway private key to be specified")
+	}
+
+	return []ssh.AuthMethod{ssh.PublicKeys(signers...)}, nil
+}
+
+func (f NativeClientFactory) newClient(conn net.Conn, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	client := ssh.NewClient(c, chans, reqs)
+
+	go f.keepAlive(client)
+
+	return client, nil
+}
+
+// keepAlive is similar to ServerAliveInterval option
+func (f NativeClientFactory) keepAlive(client *ssh.Client) {
+	ticker := time.NewTicker(nativeKeepAliveInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (f NativeClientFactory) closeGateway(gwClient *ssh.Client) {
+	if gwClient != nil {
+		_ = gwClient.Close()
+	}
+}
@@ -0,0 +1,293 @@
+package ssh
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/hashicorp/go-multierror"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+type NativeHostFunc func(boshdir.Host, NativeClient, InstanceWriter) (int, error)
+
+// NativeComboRunner is similar to ComboRunner, except that it connects
+// to all hosts with built-in SSH client instead of running ssh/scp binaries.
+type NativeComboRunner struct {
+	clientFactory    NativeClientFactory
+	signalNotifyFunc func(chan<- os.Signal, ...os.Signal)
+
+	writer   Writer
+	recorder SessionRecorder
+	ui       boshui.UI
+
+	logTag string
+	logger boshlog.Logger
+}
+
+func NewNativeComboRunner(
+	clientFactory NativeClientFactory,
+	signalNotifyFunc func(chan<- os.Signal, ...os.Signal),
+	writer Writer,
+	recorder SessionRecorder,
+	ui boshui.UI,
+	logger boshlog.Logger,
+) NativeComboRunner {
+	return NativeComboRunner{
+		clientFactory:    clientFactory,
+		signalNotifyFunc: signalNotifyFunc,
+
+		writer:   writer,
+		recorder: recorder,
+		ui:       ui,
+
+		logTag: "NativeComboRunner",
+		logger: logger,
+	}
+}
+
+func (r NativeComboRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, hostFunc NativeHostFunc) error {
+	if len(connOpts.RawOpts) > 0 {
+		return bosherr.Error("Expected raw SSH options to not be used with native SSH client")
+	}
+
+	writer := r.writer
+
+	if connOpts.Rolling() {
+		if _, ok := writer.(*ResultsWriter); !ok {
+			// Streamed output of many hosts is hard to follow so summarise exit statuses at the end
+			writer = NewMultiWriter(writer, NewExitStatusResultsWriter(r.ui))
+		}
+	}
+
+	var instWriters []InstanceWriter
+
+	for _, host := range result.Hosts {
+		jobName := "?"
+		if len(host.Job) > 0 {
+			jobName = host.Job
+		}
+
+		instWriters = append(instWriters, writer.ForInstance(jobName, host.IndexOrID))
+	}
+
+	clients := &nativeClients{}
+
+	go r.setUpInterrupt(clients)
+
+	batchSize := len(result.Hosts)
+
+	if connOpts.MaxInFlight > 0 && connOpts.MaxInFlight < batchSize {
+		batchSize = connOpts.MaxInFlight
+	}
+
+	r.logger.Debug(r.logTag, "Waiting for all hosts to finish")
+
+	var errs error
+
+	for i := 0; i < len(result.Hosts); i += batchSize {
+		end := i + batchSize
+		if end > len(result.Hosts) {
+			end = len(result.Hosts)
+		}
+
+		if (errs != nil && connOpts.FailFast) || clients.Closed() {
+			for _, instWriter := range instWriters[i:end] {
+				instWriter.End(0, bosherr.Error("Skipped since execution was stopped"))
+			}
+			continue
+		}
+
+		errCh := make(chan error, end-i)
+
+		for j := i; j < end; j++ {
+			go func(host boshdir.Host, instWriter InstanceWriter) {
+				instWriter.Start()
+
+				recording, err := r.startRecording(host, connOpts.Recording)
+				if err != nil {
+					instWriter.End(0, err)
+					errCh <- err
+					return
+				}
+
+				hostWriter := instWriter
+				if recording != nil {
+					hostWriter = nativeRecordingInstanceWriter{InstanceWriter: instWriter, recording: recording}
+				}
+
+				exitStatus, err := r.runHost(connOpts, result, host, hostWriter, clients, hostFunc)
+				r.endRecording(recording, exitStatus, err)
+				instWriter.End(exitStatus, err)
+
+				if err == nil && exitStatus != 0 {
+					err = bosherr.Errorf("Command on host '%s' exited with status %d", host.Host, exitStatus)
+				}
+
+				errCh <- err
+			}(result.Hosts[j], instWriters[j])
+		}
+
+		for j := i; j < end; j++ {
+			if err := <-errCh; err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
+
+	r.logger.Debug(r.logTag, "All hosts finished with errors '%s'", errs)
+
+	writer.Flush()
+
+	return errs
+}
+
+func (r NativeComboRunner) runHost(
+	connOpts ConnectionOpts,
+	result boshdir.SSHResult,
+	host boshdir.Host,
+	instWriter InstanceWriter,
+	clients *nativeClients,
+	hostFunc NativeHostFunc,
+) (int, error) {
+	client, err := r.clientFactory.New(connOpts, result, host)
+	if err != nil {
+		return 0, err
+	}
+
+	if !clients.Add(client) {
+		_ = client.Close()
+		return 0, bosherr.Error("Interrupted")
+	}
+
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if connOpts.Timeout <= 0 {
+		return hostFunc(host, client, instWriter)
+	}
+
+	// Closing connection makes host function return
+	timer := time.AfterFunc(connOpts.Timeout, func() {
+		r.logger.Debug(r.logTag, "Host '%s' timed out after '%s'", host.Host, connOpts.Timeout)
+		_ = client.Close()
+	})
+
+	exitStatus, err := hostFunc(host, client, instWriter)
+
+	if !timer.Stop() {
+		return exitStatus, bosherr.Errorf("Timed out after '%s'", connOpts.Timeout)
+	}
+
+	return exitStatus, err
+}
+
+// startRecording starts recording host output if sessions are recorded
+func (r NativeComboRunner) startRecording(host boshdir.Host, opts RecordingOpts) (*SessionRecording, error) {
+	if len(opts.Dir) == 0 {
+		return nil, nil
+	}
+
+	recording, err := r.recorder.Record(opts, host)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Recording session")
+	}
+
+	r.logger.Debug(r.logTag, "Recording session into '%s'", recording.Path())
+
+	return recording, nil
+}
+
+func (r NativeComboRunner) endRecording(recording *SessionRecording, exitStatus int, err error) {
+	if recording == nil {
+		return
+	}
+
+	endErr := recording.End(exitStatus, err)
+	if endErr != nil {
+		r.logger.Error(r.logTag, "Failed to finish recording with error '%s'", endErr.Error())
+	}
+}
+
+func (r NativeComboRunner) setUpInterrupt(clients *nativeClients) {
+	signalCh := make(chan os.Signal, 1)
+
+	r.signalNotifyFunc(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	for sig := range signalCh {
+		r.logger.Debug(r.logTag, "Received a signal: %v", sig)
+
+		r.ui.PrintLinef("\nReceived a signal, exiting...\n")
+
+		// Closing connections makes all in-progress host functions return
+		clients.CloseAll()
+	}
+}
+
+type nativeClients struct {
+	clients []NativeClient
+	closed  bool
+	lock    sync.Mutex
+}
+
+func (c *nativeClients) Add(client NativeClient) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	c.clients = append(c.clients, client)
+
+	return true
+}
+
+func (c *nativeClients) Closed() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.closed
+}
+
+func (c *nativeClients) CloseAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.closed = true
+
+	for _, client := range c.clients {
+		_ = client.Close()
+	}
+}
+
+// nativeRecordingInstanceWriter records output written to instance writer
+type nativeRecordingInstanceWriter struct {
+	InstanceWriter
+	recording *SessionRecording
+}
+
+func (w nativeRecordingInstanceWriter) Stdout() io.Writer {
+	return w.recording.Writer(w.InstanceWriter.Stdout())
+}
+
+func (w nativeRecordingInstanceWriter) Stderr() io.Writer {
+	return w.recording.Writer(w.InstanceWriter.Stderr())
+}
+
+// nativeRecordedWriter records output that host functions write
+// directly to w (e.g. terminal) bypassing instance writer.
+func nativeRecordedWriter(instWriter InstanceWriter, w io.Writer) io.Writer {
+	if recWriter, ok := instWriter.(nativeRecordingInstanceWriter); ok {
+		return recWriter.recording.Writer(w)
+	}
+
+	return w
+}
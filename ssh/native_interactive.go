@@ -0,0 +1,128 @@
+package ssh
+
+import (
+	"io"
+	"os"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/terminal"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+type NativeInteractiveRunner struct {
+	comboRunner NativeComboRunner
+
+	stdin  *os.File
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewNativeInteractiveRunner(comboRunner NativeComboRunner) NativeInteractiveRunner {
+	return NativeInteractiveRunner{
+		comboRunner: comboRunner,
+
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+}
+
+func (r NativeInteractiveRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, rawCmd []string) error {
+	if len(result.Hosts) != 1 {
+		return bosherr.Errorf("Interactive SSH only works for a single host at a time")
+	}
+
+	if len(rawCmd) != 0 {
+		return bosherr.Errorf("Interactive SSH does not accept commands")
+	}
+
+	hostFunc := func(host boshdir.Host, client NativeClient, instWriter InstanceWriter) (int, error) {
+		sess, err := client.NewSession()
+		if err != nil {
+			return 0, bosherr.WrapError(err, "Opening SSH session")
+		}
+
+		defer func() {
+			_ = sess.Close()
+		}()
+
+		sess.Stdin = r.stdin
+		sess.Stdout = nativeRecordedWriter(instWriter, r.stdout)
+		sess.Stderr = nativeRecordedWriter(instWriter, r.stderr)
+
+		fd := int(r.stdin.Fd())
+
+		if terminal.IsTerminal(fd) {
+			restoreFunc, err := r.requestPty(sess, fd)
+			if err != nil {
+				return 0, err
+			}
+
+			defer restoreFunc()
+		}
+
+		err = sess.Shell()
+		if err != nil {
+			return 0, bosherr.WrapError(err, "Starting shell")
+		}
+
+		return nativeExitStatus(sess.Wait())
+	}
+
+	return r.comboRunner.Run(connOpts, result, hostFunc)
+}
+
+func (r NativeInteractiveRunner) requestPty(sess *ssh.Session, fd int) (func(), error) {
+	width, height, err := terminal.GetSize(fd)
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	term := os.Getenv("TERM")
+	if len(term) == 0 {
+		term = "xterm"
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+
+	err = sess.RequestPty(term, height, width, modes)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Requesting pseudo terminal")
+	}
+
+	state, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Switching terminal to raw mode")
+	}
+
+	stopResizeFunc := forwardWindowChanges(sess, fd)
+
+	return func() {
+		stopResizeFunc()
+		_ = terminal.Restore(fd, state)
+	}, nil
+}
+
+// nativeExitStatus converts result of a remote command
+// into an exit status similarly to how ssh binary exits.
+func nativeExitStatus(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+
+	if exitErr, ok := err.(*ssh.ExitError); ok {
+		return exitErr.ExitStatus(), nil
+	}
+
+	if _, ok := err.(*ssh.ExitMissingError); ok {
+		return 255, nil
+	}
+
+	return 0, bosherr.WrapError(err, "Running command")
+}
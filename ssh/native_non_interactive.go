@@ -0,0 +1,53 @@
+package ssh
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"golang.org/x/crypto/ssh"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+type NativeNonInteractiveRunner struct {
+	comboRunner NativeComboRunner
+}
+
+func NewNativeNonInteractiveRunner(comboRunner NativeComboRunner) NativeNonInteractiveRunner {
+	return NativeNonInteractiveRunner{comboRunner}
+}
+
+func (r NativeNonInteractiveRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, rawCmd []string) error {
+	if len(result.Hosts) == 0 {
+		return bosherr.Errorf("Non-interactive SSH expects at least one host")
+	}
+
+	if len(rawCmd) == 0 {
+		return bosherr.Errorf("Non-interactive SSH expects non-empty command")
+	}
+
+	hostFunc := func(host boshdir.Host, client NativeClient, instWriter InstanceWriter) (int, error) {
+		sess, err := client.NewSession()
+		if err != nil {
+			return 0, bosherr.WrapError(err, "Opening SSH session")
+		}
+
+		defer func() {
+			_ = sess.Close()
+		}()
+
+		// Similarly to ssh -tt force pseudo terminal allocation
+		// so that remote processes are terminated with the session
+		err = sess.RequestPty("xterm", 24, 80, ssh.TerminalModes{ssh.ECHO: 0})
+		if err != nil {
+			return 0, bosherr.WrapError(err, "Requesting pseudo terminal")
+		}
+
+		sess.Stdout = instWriter.Stdout()
+		sess.Stderr = instWriter.Stderr()
+
+		return nativeExitStatus(sess.Run(strings.Join(rawCmd, " ")))
+	}
+
+	return r.comboRunner.Run(connOpts, result, hostFunc)
+}
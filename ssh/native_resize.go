@@ -0,0 +1,41 @@
+//go:build !windows
+// +build !windows
+
+package ssh
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// forwardWindowChanges propagates local terminal size changes
+// to remote pseudo terminal until returned function is called.
+func forwardWindowChanges(sess *ssh.Session, fd int) func() {
+	sigCh := make(chan os.Signal, 1)
+	doneCh := make(chan struct{})
+
+	signal.Notify(sigCh, syscall.SIGWINCH)
+
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				width, height, err := terminal.GetSize(fd)
+				if err == nil {
+					_ = sess.WindowChange(height, width)
+				}
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(doneCh)
+	}
+}
@@ -0,0 +1,10 @@
+package ssh
+
+import (
+	"golang.org/x/crypto/ssh"
+)
+
+// forwardWindowChanges is a no-op since there is no SIGWINCH on Windows.
+func forwardWindowChanges(sess *ssh.Session, fd int) func() {
+	return func() {}
+}
@@ -0,0 +1,421 @@
+package ssh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"github.com/pkg/sftp"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+// NativeSCPRunner copies files over SFTP subsystem
+// instead of relying on scp binary.
+type NativeSCPRunner struct {
+	comboRunner NativeComboRunner
+	fs          boshsys.FileSystem
+	ui          boshui.UI
+}
+
+func NewNativeSCPRunner(comboRunner NativeComboRunner, fs boshsys.FileSystem, ui boshui.UI) NativeSCPRunner {
+	return NativeSCPRunner{comboRunner: comboRunner, fs: fs, ui: ui}
+}
+
+func (r NativeSCPRunner) Run(connOpts ConnectionOpts, result boshdir.SSHResult, scpArgs SCPArgs) error {
+	var reports []nativeSCPReport
+	var reportsLock sync.Mutex
+
+	hostFunc := func(host boshdir.Host, client NativeClient, _ InstanceWriter) (int, error) {
+		sftpClient, err := sftp.NewClient(client.Client)
+		if err != nil {
+			return 0, bosherr.WrapError(err, "Starting SFTP session")
+		}
+
+		defer func() {
+			_ = sftpClient.Close()
+		}()
+
+		copier := &nativeSCPCopier{
+			local:     nativeLocalFS{r.fs},
+			remote:    nativeRemoteFS{sftpClient, client},
+			recursive: scpArgs.Recursive(),
+			sync:      scpArgs.Sync(),
+		}
+
+		err = copier.Copy(scpArgs.PathsForHost(host))
+
+		reportsLock.Lock()
+		reports = append(reports, nativeSCPReport{host, copier.copied, copier.skipped, copier.verified})
+		reportsLock.Unlock()
+
+		return 0, err
+	}
+
+	err := r.comboRunner.Run(connOpts, result, hostFunc)
+
+	r.printReports(reports, scpArgs.Sync())
+
+	return err
+}
+
+type nativeSCPReport struct {
+	Host     boshdir.Host
+	Copied   int
+	Skipped  int
+	Verified int
+}
+
+// printReports highlights instances with unverified files
+// only when syncing since copied files are verified only then
+func (r NativeSCPRunner) printReports(reports []nativeSCPReport, sync bool) {
+	table := boshtbl.Table{
+		Content: "instances",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Instance"),
+			boshtbl.NewHeader("Copied"),
+			boshtbl.NewHeader("Skipped"),
+			boshtbl.NewHeader("Verified"),
+		},
+
+		SortBy: []boshtbl.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, report := range reports {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(fmt.Sprintf("%s/%s", report.Host.Job, report.Host.IndexOrID)),
+			boshtbl.NewValueInt(report.Copied),
+			boshtbl.NewValueInt(report.Skipped),
+			boshtbl.NewValueFmt(boshtbl.NewValueInt(report.Verified), sync && report.Verified != report.Copied),
+		})
+	}
+
+	r.ui.PrintTable(table)
+}
+
+type nativeSCPFS interface {
+	Stat(string) (os.FileInfo, error)
+	ReadDir(string) ([]os.FileInfo, error)
+	Open(string) (io.ReadCloser, error)
+	Create(string, os.FileMode) (io.WriteCloser, error)
+	Mkdir(string, os.FileMode) error
+	Checksum(string) (string, error)
+	Join(...string) string
+	Base(string) string
+}
+
+type nativeSCPCopier struct {
+	local     nativeSCPFS
+	remote    nativeSCPFS
+	recursive bool
+
+	// Skip files with matching checksums and verify copied files
+	sync bool
+
+	copied   int
+	skipped  int
+	verified int
+}
+
+func (c *nativeSCPCopier) Copy(paths []SCPPath) error {
+	if len(paths) < 2 {
+		return bosherr.Error("Expected at least one source and a destination")
+	}
+
+	srcs, dst := paths[:len(paths)-1], paths[len(paths)-1]
+
+	srcFS, dstFS := c.local, c.remote
+
+	if !dst.Remote {
+		srcFS, dstFS = c.remote, c.local
+	}
+
+	for _, src := range srcs {
+		if src.Remote == dst.Remote {
+			return bosherr.Error("Expected to either upload to or download from instances")
+		}
+	}
+
+	dstPath := c.remotePath(dst)
+
+	dstInfo, err := dstFS.Stat(dstPath)
+	dstIsDir := err == nil && dstInfo.IsDir()
+
+	if len(srcs) > 1 && !dstIsDir {
+		return bosherr.Errorf("Expected destination '%s' to be a directory", dst.Path)
+	}
+
+	for _, src := range srcs {
+		srcPath := c.remotePath(src)
+
+		srcInfo, err := srcFS.Stat(srcPath)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Checking source '%s'", src.Path)
+		}
+
+		target := dstPath
+
+		if dstIsDir {
+			target = dstFS.Join(dstPath, srcFS.Base(srcPath))
+		}
+
+		err = c.copyEntry(srcFS, srcPath, srcInfo, dstFS, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remotePath makes remote paths relative to home directory
+// since SFTP does not expand '~' like a shell would.
+func (c *nativeSCPCopier) remotePath(p SCPPath) string {
+	if !p.Remote {
+		return p.Path
+	}
+
+	if p.Path == "" || p.Path == "~" {
+		return "."
+	}
+
+	return strings.TrimPrefix(p.Path, "~/")
+}
+
+func (c *nativeSCPCopier) copyEntry(srcFS nativeSCPFS, src string, srcInfo os.FileInfo, dstFS nativeSCPFS, dst string) error {
+	if !srcInfo.IsDir() {
+		return c.copyFile(srcFS, src, srcInfo, dstFS, dst)
+	}
+
+	if !c.recursive {
+		return bosherr.Errorf("Expected '%s' to not be a directory since copying is not recursive", src)
+	}
+
+	if dstInfo, err := dstFS.Stat(dst); err != nil || !dstInfo.IsDir() {
+		err := dstFS.Mkdir(dst, srcInfo.Mode().Perm())
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Creating directory '%s'", dst)
+		}
+	}
+
+	infos, err := srcFS.ReadDir(src)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Listing directory '%s'", src)
+	}
+
+	for _, info := range infos {
+		err := c.copyEntry(srcFS, srcFS.Join(src, info.Name()), info, dstFS, dstFS.Join(dst, info.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *nativeSCPCopier) copyFile(srcFS nativeSCPFS, src string, srcInfo os.FileInfo, dstFS nativeSCPFS, dst string) error {
+	if !c.sync {
+		return c.copyFileContents(srcFS, src, srcInfo, dstFS, dst)
+	}
+
+	srcChecksum, err := srcFS.Checksum(src)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Calculating checksum of '%s'", src)
+	}
+
+	if dstInfo, err := dstFS.Stat(dst); err == nil && dstInfo.Size() == srcInfo.Size() {
+		dstChecksum, err := dstFS.Checksum(dst)
+		if err == nil && dstChecksum == srcChecksum {
+			c.skipped++
+			return nil
+		}
+	}
+
+	err = c.copyFileContents(srcFS, src, srcInfo, dstFS, dst)
+	if err != nil {
+		return err
+	}
+
+	dstChecksum, err := dstFS.Checksum(dst)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Calculating checksum of '%s'", dst)
+	}
+
+	if dstChecksum != srcChecksum {
+		return bosherr.Errorf("Expected checksum of '%s' to be '%s' but was '%s'", dst, srcChecksum, dstChecksum)
+	}
+
+	c.verified++
+
+	return nil
+}
+
+func (c *nativeSCPCopier) copyFileContents(srcFS nativeSCPFS, src string, srcInfo os.FileInfo, dstFS nativeSCPFS, dst string) error {
+	srcFile, err := srcFS.Open(src)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Opening file '%s'", src)
+	}
+
+	defer func() {
+		_ = srcFile.Close()
+	}()
+
+	dstFile, err := dstFS.Create(dst, srcInfo.Mode().Perm())
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Creating file '%s'", dst)
+	}
+
+	_, err = io.Copy(dstFile, srcFile)
+	if err != nil {
+		_ = dstFile.Close()
+		return bosherr.WrapErrorf(err, "Copying '%s' to '%s'", src, dst)
+	}
+
+	err = dstFile.Close()
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Closing file '%s'", dst)
+	}
+
+	c.copied++
+
+	return nil
+}
+
+type nativeLocalFS struct {
+	fs boshsys.FileSystem
+}
+
+func (l nativeLocalFS) Stat(p string) (os.FileInfo, error) { return l.fs.Stat(p) }
+
+// ReadDir only includes direct children of a directory
+func (l nativeLocalFS) ReadDir(p string) ([]os.FileInfo, error) {
+	var infos []os.FileInfo
+
+	root := filepath.Clean(p)
+
+	// Trailing separator makes symlinked directories to be followed
+	err := l.fs.Walk(root+string(filepath.Separator), func(path string, info os.FileInfo, err error) error {
+		if filepath.Clean(path) == root {
+			return err
+		}
+
+		if filepath.Dir(path) == root {
+			if err != nil {
+				return err
+			}
+
+			infos = append(infos, info)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+func (l nativeLocalFS) Open(p string) (io.ReadCloser, error) { return l.fs.OpenFile(p, os.O_RDONLY, 0) }
+
+func (l nativeLocalFS) Create(p string, mode os.FileMode) (io.WriteCloser, error) {
+	return l.fs.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+}
+
+func (l nativeLocalFS) Mkdir(p string, mode os.FileMode) error { return l.fs.MkdirAll(p, mode) }
+
+func (l nativeLocalFS) Checksum(p string) (string, error) {
+	f, err := l.Open(p)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (nativeLocalFS) Join(elem ...string) string { return filepath.Join(elem...) }
+func (nativeLocalFS) Base(p string) string       { return filepath.Base(p) }
+
+type nativeRemoteFS struct {
+	client    *sftp.Client
+	sshClient NativeClient
+}
+
+func (fs nativeRemoteFS) Stat(p string) (os.FileInfo, error)      { return fs.client.Stat(p) }
+func (fs nativeRemoteFS) ReadDir(p string) ([]os.FileInfo, error) { return fs.client.ReadDir(p) }
+func (fs nativeRemoteFS) Open(p string) (io.ReadCloser, error)    { return fs.client.Open(p) }
+
+func (fs nativeRemoteFS) Create(p string, mode os.FileMode) (io.WriteCloser, error) {
+	f, err := fs.client.Create(p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.client.Chmod(p, mode)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (fs nativeRemoteFS) Mkdir(p string, mode os.FileMode) error {
+	err := fs.client.Mkdir(p)
+	if err != nil {
+		return err
+	}
+
+	return fs.client.Chmod(p, mode)
+}
+
+// Checksum is calculated on the instance to avoid transferring file contents
+func (fs nativeRemoteFS) Checksum(p string) (string, error) {
+	sess, err := fs.sshClient.NewSession()
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = sess.Close()
+	}()
+
+	output, err := sess.Output("sha256sum -b -- '" + strings.Replace(p, "'", `'\''`, -1) + "'")
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", bosherr.Errorf("Expected checksum output for '%s' to be non-empty", p)
+	}
+
+	return fields[0], nil
+}
+
+func (fs nativeRemoteFS) Join(elem ...string) string { return path.Join(elem...) }
+func (fs nativeRemoteFS) Base(p string) string       { return path.Base(p) }
@@ -0,0 +1,645 @@
+package ssh_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/clock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/cloudfoundry/bosh-cli/ssh"
+	fakessh "github.com/cloudfoundry/bosh-cli/ssh/sshfakes"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+// nativeTestServer is a minimal in-process SSH server that supports
+// running commands, SFTP subsystem and forwarding TCP connections.
+type nativeTestServer struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+
+	commands []string
+}
+
+func newNativeTestServer(clientKey ssh.PublicKey) *nativeTestServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	srv := &nativeTestServer{listener: listener, hostKey: nativeTestSigner()}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, fmt.Errorf("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(srv.hostKey)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serveConn(conn, config)
+		}
+	}()
+
+	return srv
+}
+
+func (s *nativeTestServer) Port() int { return s.listener.Addr().(*net.TCPAddr).Port }
+
+func (s *nativeTestServer) HostPublicKey() string {
+	return string(ssh.MarshalAuthorizedKey(s.hostKey.PublicKey()))
+}
+
+func (s *nativeTestServer) Close() { _ = s.listener.Close() }
+
+func (s *nativeTestServer) serveConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		switch newCh.ChannelType() {
+		case "session":
+			ch, chReqs, err := newCh.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveSession(ch, chReqs)
+
+		case "direct-tcpip":
+			var payload struct {
+				Host     string
+				Port     uint32
+				OrigHost string
+				OrigPort uint32
+			}
+			Expect(ssh.Unmarshal(newCh.ExtraData(), &payload)).ToNot(HaveOccurred())
+
+			target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+			if err != nil {
+				_ = newCh.Reject(ssh.ConnectionFailed, err.Error())
+				continue
+			}
+
+			ch, chReqs, err := newCh.Accept()
+			if err != nil {
+				return
+			}
+			go ssh.DiscardRequests(chReqs)
+			go func() { _, _ = io.Copy(ch, target); _ = ch.Close() }()
+			go func() { _, _ = io.Copy(target, ch); _ = target.Close() }()
+
+		default:
+			_ = newCh.Reject(ssh.UnknownChannelType, "unknown channel type")
+		}
+	}
+}
+
+func (s *nativeTestServer) serveSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		switch req.Type {
+		case "pty-req":
+			_ = req.Reply(true, nil)
+
+		case "exec":
+			cmd := string(req.Payload[4:])
+			s.commands = append(s.commands, cmd)
+			_ = req.Reply(true, nil)
+
+			exitStatus := 0
+			if strings.HasPrefix(cmd, "exit ") {
+				exitStatus, _ = strconv.Atoi(strings.TrimPrefix(cmd, "exit "))
+			}
+
+			if strings.HasPrefix(cmd, "sha256sum -b -- '") {
+				contents, err := ioutil.ReadFile(strings.TrimSuffix(strings.TrimPrefix(cmd, "sha256sum -b -- '"), "'"))
+				if err != nil {
+					exitStatus = 1
+				}
+				_, _ = fmt.Fprintf(ch, "%x *file\n", sha256.Sum256(contents))
+			} else {
+				_, _ = fmt.Fprintf(ch, "ran %s\r\n", cmd)
+			}
+
+			status := make([]byte, 4)
+			binary.BigEndian.PutUint32(status, uint32(exitStatus))
+			_, _ = ch.SendRequest("exit-status", false, status)
+			_ = ch.Close()
+
+		case "subsystem":
+			_ = req.Reply(true, nil)
+
+			go func() {
+				server, err := sftp.NewServer(ch)
+				if err == nil {
+					_ = server.Serve()
+				}
+				_ = ch.Close()
+			}()
+
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+var nativeTestKey *rsa.PrivateKey
+
+func nativeTestSigner() ssh.Signer {
+	if nativeTestKey == nil {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		nativeTestKey = key
+	}
+
+	signer, err := ssh.NewSignerFromKey(nativeTestKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	return signer
+}
+
+func nativeTestPrivateKeyPEM() string {
+	nativeTestSigner()
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(nativeTestKey),
+	}))
+}
+
+var _ = Describe("Native SSH client", func() {
+	var (
+		server *nativeTestServer
+		ui     *fakeui.FakeUI
+		writer Writer
+		logger boshlog.Logger
+
+		comboRunner NativeComboRunner
+
+		connOpts ConnectionOpts
+		result   boshdir.SSHResult
+	)
+
+	BeforeEach(func() {
+		server = newNativeTestServer(nativeTestSigner().PublicKey())
+
+		ui = &fakeui.FakeUI{}
+		writer = NewStreamingWriter(boshui.NewComboWriter(ui))
+		logger = boshlog.NewLogger(boshlog.LevelNone)
+
+		fs := boshsys.NewOsFileSystem(logger)
+		clientFactory := NewNativeClientFactory(server.Port(), fs, logger)
+		signalNotifyFunc := func(ch chan<- os.Signal, s ...os.Signal) {}
+
+		recorder := NewSessionRecorder(fs, clock.NewClock())
+
+		comboRunner = NewNativeComboRunner(clientFactory, signalNotifyFunc, writer, recorder, ui, logger)
+
+		connOpts = ConnectionOpts{PrivateKey: nativeTestPrivateKeyPEM(), GatewayDisable: true, Native: true}
+
+		result = boshdir.SSHResult{
+			Hosts: []boshdir.Host{{
+				Job:           "job",
+				IndexOrID:     "id",
+				Username:      "user",
+				Host:          "127.0.0.1",
+				HostPublicKey: server.HostPublicKey(),
+			}},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NativeNonInteractiveRunner", func() {
+		var runner NativeNonInteractiveRunner
+
+		BeforeEach(func() {
+			runner = NewNativeNonInteractiveRunner(comboRunner)
+		})
+
+		It("runs command on the host and prints its output", func() {
+			err := runner.Run(connOpts, result, []string{"cmd", "arg1"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(server.commands).To(Equal([]string{"cmd arg1"}))
+			Expect(strings.Join(ui.Blocks, "")).To(Equal("job/id: stdout | ran cmd arg1\r\n"))
+		})
+
+		It("returns error if command exits with non-zero status", func() {
+			err := runner.Run(connOpts, result, []string{"exit", "3"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exited with status 3"))
+		})
+
+		It("returns error if host key does not match the one provided by the Director", func() {
+			result.Hosts[0].HostPublicKey = string(ssh.MarshalAuthorizedKey(nativeTestOtherKey()))
+
+			err := runner.Run(connOpts, result, []string{"cmd"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Connecting to host"))
+		})
+
+		It("returns error if host key was not provided by the Director", func() {
+			result.Hosts[0].HostPublicKey = ""
+
+			err := runner.Run(connOpts, result, []string{"cmd"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected host public key"))
+		})
+
+		Context("when host keys are pinned", func() {
+			var (
+				knownHostsDir string
+			)
+
+			BeforeEach(func() {
+				var err error
+
+				knownHostsDir, err = ioutil.TempDir("", "bosh-native-ssh")
+				Expect(err).ToNot(HaveOccurred())
+
+				connOpts.KnownHostsPath = filepath.Join(knownHostsDir, "known_hosts")
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(knownHostsDir)
+			})
+
+			It("trusts host key on first use and pins it for the following sessions", func() {
+				result.Hosts[0].HostPublicKey = ""
+				connOpts.TrustOnFirstUse = true
+
+				err := runner.Run(connOpts, result, []string{"cmd"})
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := ioutil.ReadFile(connOpts.KnownHostsPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("127.0.0.1 " + server.HostPublicKey()))
+
+				connOpts.TrustOnFirstUse = false
+
+				err = runner.Run(connOpts, result, []string{"cmd"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns error if host key was not provided by the Director without trusting on first use", func() {
+				result.Hosts[0].HostPublicKey = ""
+
+				err := runner.Run(connOpts, result, []string{"cmd"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Expected host public key"))
+			})
+
+			It("returns error if Director provided host key does not match pinned host key", func() {
+				err := runner.Run(connOpts, result, []string{"cmd"})
+				Expect(err).ToNot(HaveOccurred())
+
+				result.Hosts[0].HostPublicKey = string(ssh.MarshalAuthorizedKey(nativeTestOtherKey()))
+
+				err = runner.Run(connOpts, result, []string{"cmd"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("to match previously pinned host key"))
+				Expect(server.commands).To(Equal([]string{"cmd"}))
+			})
+		})
+
+		It("connects through a gateway", func() {
+			keyDir, err := ioutil.TempDir("", "bosh-native-ssh")
+			Expect(err).ToNot(HaveOccurred())
+
+			defer os.RemoveAll(keyDir)
+
+			keyPath := filepath.Join(keyDir, "gw-key")
+			Expect(ioutil.WriteFile(keyPath, []byte(nativeTestPrivateKeyPEM()), 0600)).ToNot(HaveOccurred())
+
+			connOpts.GatewayDisable = false
+			connOpts.GatewayUsername = "gw-user"
+			connOpts.GatewayHost = net.JoinHostPort("127.0.0.1", strconv.Itoa(server.Port()))
+			connOpts.GatewayPrivateKeyPath = keyPath
+
+			err = runner.Run(connOpts, result, []string{"cmd"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.commands).To(Equal([]string{"cmd"}))
+		})
+
+		It("returns error if raw options are specified", func() {
+			connOpts.RawOpts = []string{"-v"}
+
+			err := runner.Run(connOpts, result, []string{"cmd"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected raw SSH options to not be used"))
+		})
+
+		Context("when sessions are recorded", func() {
+			var (
+				recordDir string
+			)
+
+			BeforeEach(func() {
+				var err error
+
+				recordDir, err = ioutil.TempDir("", "bosh-native-ssh-recordings")
+				Expect(err).ToNot(HaveOccurred())
+
+				connOpts.Recording = RecordingOpts{Dir: recordDir, Deployment: "dep"}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(recordDir)
+			})
+
+			It("records output and exit status of the session", func() {
+				err := runner.Run(connOpts, result, []string{"cmd", "arg1"})
+				Expect(err).ToNot(HaveOccurred())
+
+				paths, err := filepath.Glob(filepath.Join(recordDir, "dep-job-id-*.cast"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paths).To(HaveLen(1))
+
+				contents, err := ioutil.ReadFile(paths[0])
+				Expect(err).ToNot(HaveOccurred())
+
+				lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+				Expect(lines).To(HaveLen(3))
+				Expect(lines[0]).To(ContainSubstring(`"deployment":"dep","instance":"job/id","host":"127.0.0.1","username":"user"`))
+				Expect(lines[1]).To(ContainSubstring(`"o","ran cmd arg1\r\n"`))
+				Expect(lines[2]).To(ContainSubstring(`session ended with exit status 0`))
+
+				// Output is still passed through
+				Expect(strings.Join(ui.Blocks, "")).To(Equal("job/id: stdout | ran cmd arg1\r\n"))
+			})
+
+			It("returns error if recording cannot be started", func() {
+				connOpts.Recording.Dir = filepath.Join(recordDir, "file")
+				Expect(ioutil.WriteFile(connOpts.Recording.Dir, []byte{}, 0600)).ToNot(HaveOccurred())
+
+				err := runner.Run(connOpts, result, []string{"cmd"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Recording session"))
+				Expect(server.commands).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("NativeSCPRunner", func() {
+		var (
+			runner  NativeSCPRunner
+			tempDir string
+		)
+
+		BeforeEach(func() {
+			runner = NewNativeSCPRunner(comboRunner, boshsys.NewOsFileSystem(logger), ui)
+
+			var err error
+
+			tempDir, err = ioutil.TempDir("", "bosh-native-scp")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(tempDir)
+		})
+
+		It("uploads files to the host", func() {
+			src := filepath.Join(tempDir, "src")
+			Expect(ioutil.WriteFile(src, []byte("content"), 0640)).ToNot(HaveOccurred())
+
+			dst := filepath.Join(tempDir, "dst-((instance_id))")
+
+			err := runner.Run(connOpts, result, NewSCPArgs([]string{src, "job:" + dst}, false))
+			Expect(err).ToNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(tempDir, "dst-id"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("content"))
+		})
+
+		It("downloads directories recursively into existing destination directory", func() {
+			Expect(os.MkdirAll(filepath.Join(tempDir, "remote", "sub"), 0755)).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(tempDir, "remote", "sub", "file"), []byte("content"), 0644)).ToNot(HaveOccurred())
+			Expect(os.Mkdir(filepath.Join(tempDir, "local"), 0755)).ToNot(HaveOccurred())
+
+			scpArgs := NewSCPArgs([]string{"job:" + filepath.Join(tempDir, "remote"), filepath.Join(tempDir, "local")}, true)
+
+			err := runner.Run(connOpts, result, scpArgs)
+			Expect(err).ToNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(tempDir, "local", "remote", "sub", "file"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("content"))
+		})
+
+		It("downloads into per instance directories from multiple hosts and reports copied files", func() {
+			result.Hosts = append(result.Hosts, result.Hosts[0])
+			result.Hosts[1].IndexOrID = "id2"
+
+			Expect(ioutil.WriteFile(filepath.Join(tempDir, "remote"), []byte("content"), 0644)).ToNot(HaveOccurred())
+			Expect(os.Mkdir(filepath.Join(tempDir, "local"), 0755)).ToNot(HaveOccurred())
+
+			scpArgs := NewSCPArgs([]string{"job:" + filepath.Join(tempDir, "remote"), filepath.Join(tempDir, "local")}, false)
+
+			selectingRunner := NewClientSelectingSCPRunner(&fakessh.FakeSCPRunner{}, runner, boshsys.NewOsFileSystem(logger))
+
+			err := selectingRunner.Run(connOpts, result, scpArgs)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, id := range []string{"id", "id2"} {
+				contents, err := ioutil.ReadFile(filepath.Join(tempDir, "local", "job", id, "remote"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(Equal("content"))
+			}
+
+			Expect(ui.Table.Rows).To(ConsistOf(
+				[]boshtbl.Value{
+					boshtbl.NewValueString("job/id"),
+					boshtbl.NewValueInt(1),
+					boshtbl.NewValueInt(0),
+					boshtbl.NewValueFmt(boshtbl.NewValueInt(0), false),
+				},
+				[]boshtbl.Value{
+					boshtbl.NewValueString("job/id2"),
+					boshtbl.NewValueInt(1),
+					boshtbl.NewValueInt(0),
+					boshtbl.NewValueFmt(boshtbl.NewValueInt(0), false),
+				},
+			))
+		})
+
+		Context("when syncing", func() {
+			var (
+				remoteDir string
+				localDir  string
+				scpArgs   SCPArgs
+			)
+
+			BeforeEach(func() {
+				remoteDir = filepath.Join(tempDir, "remote")
+				localDir = filepath.Join(tempDir, "local")
+
+				Expect(os.MkdirAll(filepath.Join(remoteDir, "sub"), 0755)).ToNot(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(remoteDir, "file1"), []byte("content1"), 0644)).ToNot(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(remoteDir, "sub", "file2"), []byte("content2"), 0644)).ToNot(HaveOccurred())
+				Expect(os.Mkdir(localDir, 0755)).ToNot(HaveOccurred())
+
+				scpArgs = NewSCPArgs([]string{"job:" + remoteDir, localDir}, true).WithSync(true)
+			})
+
+			It("downloads files and reports verified files", func() {
+				err := runner.Run(connOpts, result, scpArgs)
+				Expect(err).ToNot(HaveOccurred())
+
+				contents, err := ioutil.ReadFile(filepath.Join(localDir, "remote", "sub", "file2"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(Equal("content2"))
+
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{{
+					boshtbl.NewValueString("job/id"),
+					boshtbl.NewValueInt(2),
+					boshtbl.NewValueInt(0),
+					boshtbl.NewValueFmt(boshtbl.NewValueInt(2), false),
+				}}))
+			})
+
+			It("skips files with matching checksums when run again", func() {
+				err := runner.Run(connOpts, result, scpArgs)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ioutil.WriteFile(filepath.Join(remoteDir, "file1"), []byte("changed"), 0644)).ToNot(HaveOccurred())
+
+				err = runner.Run(connOpts, result, scpArgs)
+				Expect(err).ToNot(HaveOccurred())
+
+				contents, err := ioutil.ReadFile(filepath.Join(localDir, "remote", "file1"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(Equal("changed"))
+
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{{
+					boshtbl.NewValueString("job/id"),
+					boshtbl.NewValueInt(1),
+					boshtbl.NewValueInt(1),
+					boshtbl.NewValueFmt(boshtbl.NewValueInt(1), false),
+				}}))
+			})
+		})
+
+		It("returns error when copying a directory without recursive flag", func() {
+			scpArgs := NewSCPArgs([]string{"job:" + tempDir, filepath.Join(tempDir, "local")}, false)
+
+			err := runner.Run(connOpts, result, scpArgs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("copying is not recursive"))
+		})
+	})
+
+	Describe("NativePortForwarder", func() {
+		var (
+			forwarder NativePortForwarder
+			signalCh  chan chan<- os.Signal
+		)
+
+		BeforeEach(func() {
+			signalCh = make(chan chan<- os.Signal, 1)
+			signalNotifyFunc := func(ch chan<- os.Signal, s ...os.Signal) { signalCh <- ch }
+
+			clientFactory := NewNativeClientFactory(server.Port(), boshsys.NewOsFileSystem(logger), logger)
+			forwarder = NewNativePortForwarder(clientFactory, signalNotifyFunc, ui, logger)
+		})
+
+		It("forwards local connections to the port on the host until interrupted", func() {
+			target, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+
+			defer target.Close()
+
+			go func() {
+				conn, err := target.Accept()
+				if err == nil {
+					_, _ = io.Copy(conn, conn)
+					_ = conn.Close()
+				}
+			}()
+
+			local, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+
+			localPort := local.Addr().(*net.TCPAddr).Port
+			Expect(local.Close()).ToNot(HaveOccurred())
+
+			forwards := []PortForward{{LocalPort: localPort, RemotePort: target.Addr().(*net.TCPAddr).Port}}
+
+			errCh := make(chan error, 1)
+
+			go func() {
+				errCh <- forwarder.Forward(connOpts, result, forwards)
+			}()
+
+			var interruptCh chan<- os.Signal
+			Eventually(signalCh).Should(Receive(&interruptCh))
+
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = conn.Write([]byte("ping"))
+			Expect(err).ToNot(HaveOccurred())
+
+			buf := make([]byte, 4)
+			_, err = io.ReadFull(conn, buf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(buf)).To(Equal("ping"))
+
+			Expect(conn.Close()).ToNot(HaveOccurred())
+
+			interruptCh <- os.Interrupt
+			Eventually(errCh).Should(Receive(BeNil()))
+		})
+
+		It("returns error if there is more than one host", func() {
+			result.Hosts = append(result.Hosts, result.Hosts[0])
+
+			err := forwarder.Forward(connOpts, result, []PortForward{{LocalPort: 1, RemotePort: 1}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("single host"))
+		})
+	})
+})
+
+func nativeTestOtherKey() ssh.PublicKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).ToNot(HaveOccurred())
+
+	pubKey, err := ssh.NewPublicKey(&key.PublicKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	return pubKey
+}
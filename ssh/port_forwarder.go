@@ -0,0 +1,146 @@
+package ssh
+
+import (
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	boshui "github.com/cloudfoundry/bosh-cli/ui"
+)
+
+// NativePortForwarder listens on local ports and forwards
+// accepted connections to ports on an instance over SSH.
+type NativePortForwarder struct {
+	clientFactory    NativeClientFactory
+	signalNotifyFunc func(chan<- os.Signal, ...os.Signal)
+
+	ui boshui.UI
+
+	logTag string
+	logger boshlog.Logger
+}
+
+func NewNativePortForwarder(
+	clientFactory NativeClientFactory,
+	signalNotifyFunc func(chan<- os.Signal, ...os.Signal),
+	ui boshui.UI,
+	logger boshlog.Logger,
+) NativePortForwarder {
+	return NativePortForwarder{
+		clientFactory:    clientFactory,
+		signalNotifyFunc: signalNotifyFunc,
+
+		ui: ui,
+
+		logTag: "NativePortForwarder",
+		logger: logger,
+	}
+}
+
+func (f NativePortForwarder) Forward(connOpts ConnectionOpts, result boshdir.SSHResult, forwards []PortForward) error {
+	if len(result.Hosts) != 1 {
+		return bosherr.Errorf("Port forwarding only works for a single host at a time")
+	}
+
+	if len(forwards) == 0 {
+		return bosherr.Errorf("Port forwarding expects at least one port")
+	}
+
+	host := result.Hosts[0]
+
+	client, err := f.clientFactory.New(connOpts, result, host)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = client.Close()
+	}()
+
+	var listeners []net.Listener
+
+	defer func() {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+	}()
+
+	for _, forward := range forwards {
+		localAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(forward.LocalPort))
+
+		listener, err := net.Listen("tcp", localAddr)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Listening on '%s'", localAddr)
+		}
+
+		listeners = append(listeners, listener)
+
+		f.ui.PrintLinef("Forwarding '%s' to port '%d' on instance '%s/%s'",
+			listener.Addr(), forward.RemotePort, host.Job, host.IndexOrID)
+
+		go f.accept(listener, client, forward.RemotePort)
+	}
+
+	doneCh := make(chan error, 1)
+
+	go func() {
+		doneCh <- client.Wait()
+	}()
+
+	signalCh := make(chan os.Signal, 1)
+
+	f.signalNotifyFunc(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	select {
+	case sig := <-signalCh:
+		f.logger.Debug(f.logTag, "Received a signal: %v", sig)
+		f.ui.PrintLinef("\nReceived a signal, exiting...\n")
+		return nil
+
+	case err := <-doneCh:
+		return bosherr.WrapErrorf(err, "SSH connection to '%s' closed", host.Host)
+	}
+}
+
+func (f NativePortForwarder) accept(listener net.Listener, client NativeClient, remotePort int) {
+	remoteAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(remotePort))
+
+	for {
+		localConn, err := listener.Accept()
+		if err != nil {
+			f.logger.Debug(f.logTag, "Stopped accepting connections: %s", err)
+			return
+		}
+
+		remoteConn, err := client.Dial("tcp", remoteAddr)
+		if err != nil {
+			f.ui.ErrorLinef("Failed to connect to port '%d' on instance: %s", remotePort, err)
+			_ = localConn.Close()
+			continue
+		}
+
+		go f.copy(localConn, remoteConn)
+		go f.copy(remoteConn, localConn)
+	}
+}
+
+func (f NativePortForwarder) copy(dst, src net.Conn) {
+	defer func() {
+		_ = dst.Close()
+		_ = src.Close()
+	}()
+
+	bytesNum, err := io.Copy(dst, src)
+
+	f.logger.Debug(f.logTag, "Copied %d bytes from '%s' to '%s'", bytesNum, src.RemoteAddr(), dst.RemoteAddr())
+
+	if err != nil {
+		f.logger.Debug(f.logTag, "Failed copying bytes: %s", err)
+	}
+}
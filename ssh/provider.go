@@ -3,6 +3,7 @@ package ssh
 import (
 	"os/signal"
 
+	"code.cloudfoundry.org/clock"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
@@ -11,9 +12,19 @@ import (
 )
 
 type Provider struct {
-	streamingSSH ComboRunner
-	resultsSSH   ComboRunner
-	scp          ComboRunner
+	streamingSSH         ComboRunner
+	resultsSSH           ComboRunner
+	structuredResultsSSH ComboRunner
+	scp                  ComboRunner
+
+	fs boshsys.FileSystem
+	ui boshui.UI
+
+	nativeStreamingSSH         NativeComboRunner
+	nativeResultsSSH           NativeComboRunner
+	nativeStructuredResultsSSH NativeComboRunner
+
+	portForwarder NativePortForwarder
 }
 
 func NewProvider(cmdRunner boshsys.CmdRunner, fs boshsys.FileSystem, ui boshui.UI, logger boshlog.Logger) Provider {
@@ -21,32 +32,89 @@ func NewProvider(cmdRunner boshsys.CmdRunner, fs boshsys.FileSystem, ui boshui.U
 		return NewSessionImpl(connOpts, SessionImplOpts{ForceTTY: true}, result, fs)
 	}
 
+	recorder := NewSessionRecorder(fs, clock.NewClock())
+
 	streamingWriter := NewStreamingWriter(boshui.NewComboWriter(ui))
+	resultsWriter := NewResultsWriter(ui, clock.NewClock())
+	structuredResultsWriter := NewStructuredResultsWriter(ui, clock.NewClock())
 
 	streamingSSH := NewComboRunner(
-		cmdRunner, sshSessionFactory, signal.Notify, streamingWriter, fs, ui, logger)
+		cmdRunner, sshSessionFactory, signal.Notify, streamingWriter, recorder, fs, ui, logger)
 
 	resultsSSH := NewComboRunner(
-		cmdRunner, sshSessionFactory, signal.Notify, NewResultsWriter(ui), fs, ui, logger)
+		cmdRunner, sshSessionFactory, signal.Notify, resultsWriter, recorder, fs, ui, logger)
+
+	structuredResultsSSH := NewComboRunner(
+		cmdRunner, sshSessionFactory, signal.Notify, structuredResultsWriter, recorder, fs, ui, logger)
 
 	scpSessionFactory := func(connOpts ConnectionOpts, result boshdir.SSHResult) Session {
 		return NewSessionImpl(connOpts, SessionImplOpts{}, result, fs)
 	}
 
-	scp := NewComboRunner(cmdRunner, scpSessionFactory, signal.Notify, streamingWriter, fs, ui, logger)
+	scp := NewComboRunner(cmdRunner, scpSessionFactory, signal.Notify, streamingWriter, recorder, fs, ui, logger)
+
+	nativeClientFactory := NewNativeClientFactory(nativeDefaultPort, fs, logger)
+
+	nativeStreamingSSH := NewNativeComboRunner(
+		nativeClientFactory, signal.Notify, streamingWriter, recorder, ui, logger)
+
+	nativeResultsSSH := NewNativeComboRunner(
+		nativeClientFactory, signal.Notify, resultsWriter, recorder, ui, logger)
+
+	nativeStructuredResultsSSH := NewNativeComboRunner(
+		nativeClientFactory, signal.Notify, structuredResultsWriter, recorder, ui, logger)
 
-	return Provider{streamingSSH: streamingSSH, resultsSSH: resultsSSH, scp: scp}
+	return Provider{
+		streamingSSH:         streamingSSH,
+		resultsSSH:           resultsSSH,
+		structuredResultsSSH: structuredResultsSSH,
+		scp:                  scp,
+
+		fs: fs,
+		ui: ui,
+
+		nativeStreamingSSH:         nativeStreamingSSH,
+		nativeResultsSSH:           nativeResultsSSH,
+		nativeStructuredResultsSSH: nativeStructuredResultsSSH,
+
+		portForwarder: NewNativePortForwarder(nativeClientFactory, signal.Notify, ui, logger),
+	}
 }
 
 func (p Provider) NewResultsSSHRunner(interactive bool) Runner {
-	return NewNonInteractiveRunner(p.resultsSSH)
+	return NewClientSelectingRunner(
+		NewNonInteractiveRunner(p.resultsSSH),
+		NewNativeNonInteractiveRunner(p.nativeResultsSSH),
+	)
+}
+
+// NewStructuredResultsSSHRunner prints results as JSON records (e.g. for --json)
+func (p Provider) NewStructuredResultsSSHRunner() Runner {
+	return NewClientSelectingRunner(
+		NewNonInteractiveRunner(p.structuredResultsSSH),
+		NewNativeNonInteractiveRunner(p.nativeStructuredResultsSSH),
+	)
 }
 
 func (p Provider) NewSSHRunner(interactive bool) Runner {
 	if interactive {
-		return NewInteractiveRunner(p.streamingSSH)
+		return NewClientSelectingRunner(
+			NewInteractiveRunner(p.streamingSSH),
+			NewNativeInteractiveRunner(p.nativeStreamingSSH),
+		)
 	}
-	return NewNonInteractiveRunner(p.streamingSSH)
+	return NewClientSelectingRunner(
+		NewNonInteractiveRunner(p.streamingSSH),
+		NewNativeNonInteractiveRunner(p.nativeStreamingSSH),
+	)
+}
+
+func (p Provider) NewSCPRunner() SCPRunner {
+	return NewClientSelectingSCPRunner(
+		NewSCPRunner(p.scp),
+		NewNativeSCPRunner(p.nativeStreamingSSH, p.fs, p.ui),
+		p.fs,
+	)
 }
 
-func (p Provider) NewSCPRunner() SCPRunner { return NewSCPRunner(p.scp) }
+func (p Provider) NewPortForwarder() PortForwarder { return p.portForwarder }
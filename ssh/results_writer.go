@@ -2,29 +2,78 @@ package ssh
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
 
 	boshui "github.com/cloudfoundry/bosh-cli/ui"
 	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
 )
 
 type ResultsWriter struct {
-	ui boshui.UI
+	ui          boshui.UI
+	timeService clock.Clock
+
+	exitStatusOnly bool
+	structured     bool
+	instances      []*resultsInstanceWriter
+}
+
+type resultsRecord struct {
+	InstanceGroup   string  `json:"instance_group"`
+	ID              string  `json:"id"`
+	ExitStatus      int     `json:"exit_status"`
+	Stdout          string  `json:"stdout"`
+	Stderr          string  `json:"stderr"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error"`
+}
+
+func NewResultsWriter(ui boshui.UI, timeService clock.Clock) *ResultsWriter {
+	return &ResultsWriter{ui: ui, timeService: timeService}
+}
 
-	instances []*resultsInstanceWriter
+// NewStructuredResultsWriter returns writer that prints a JSON record
+// per instance so that results can be parsed without reading a table.
+func NewStructuredResultsWriter(ui boshui.UI, timeService clock.Clock) *ResultsWriter {
+	return &ResultsWriter{ui: ui, timeService: timeService, structured: true}
 }
 
-func NewResultsWriter(ui boshui.UI) *ResultsWriter {
-	return &ResultsWriter{ui: ui}
+// NewExitStatusResultsWriter returns writer that discards output
+// and only summarises exit statuses of each instance.
+func NewExitStatusResultsWriter(ui boshui.UI) *ResultsWriter {
+	return &ResultsWriter{ui: ui, timeService: clock.NewClock(), exitStatusOnly: true}
 }
 
 func (w *ResultsWriter) ForInstance(jobName, indexOrID string) InstanceWriter {
-	w.instances = append(w.instances, newBufferedInstanceWriter(jobName, indexOrID))
+	instWriter := newBufferedInstanceWriter(jobName, indexOrID, w.timeService)
+
+	if w.exitStatusOnly {
+		instWriter.stdout = nil
+		instWriter.stderr = nil
+	}
+
+	w.instances = append(w.instances, instWriter)
+
 	return w.instances[len(w.instances)-1]
 }
 
 func (w *ResultsWriter) Flush() {
+	if w.exitStatusOnly {
+		w.flushExitStatuses()
+		return
+	}
+
+	if w.structured {
+		w.flushRecords()
+		return
+	}
+
 	table := boshtbl.Table{
 		Content: "results",
 
@@ -33,6 +82,7 @@ func (w *ResultsWriter) Flush() {
 			boshtbl.NewHeader("Stdout"),
 			boshtbl.NewHeader("Stderr"),
 			boshtbl.NewHeader("Exit Code"),
+			boshtbl.NewHeader("Duration"),
 			boshtbl.NewHeader("Error"),
 		},
 
@@ -49,6 +99,33 @@ func (w *ResultsWriter) Flush() {
 			boshtbl.NewValueString(inst.StdoutAsString()),
 			boshtbl.NewValueString(inst.StderrAsString()),
 			boshtbl.NewValueInt(inst.ExitStatus()),
+			boshtbl.NewValueString(inst.Duration().String()),
+			boshtbl.NewValueError(inst.Error()),
+		})
+	}
+
+	w.ui.PrintTable(table)
+}
+
+func (w *ResultsWriter) flushExitStatuses() {
+	table := boshtbl.Table{
+		Content: "results",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Instance"),
+			boshtbl.NewHeader("Exit Code"),
+			boshtbl.NewHeader("Error"),
+		},
+
+		SortBy: []boshtbl.ColumnSort{
+			{Column: 0, Asc: true},
+		},
+	}
+
+	for _, inst := range w.instances {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(inst.Instance()),
+			boshtbl.NewValueInt(inst.ExitStatus()),
 			boshtbl.NewValueError(inst.Error()),
 		})
 	}
@@ -56,21 +133,68 @@ func (w *ResultsWriter) Flush() {
 	w.ui.PrintTable(table)
 }
 
+func (w *ResultsWriter) flushRecords() {
+	records := []resultsRecord{}
+
+	for _, inst := range w.instances {
+		record := resultsRecord{
+			InstanceGroup:   inst.jobName,
+			ID:              inst.indexOrID,
+			ExitStatus:      inst.ExitStatus(),
+			Stdout:          inst.StdoutAsString(),
+			Stderr:          inst.StderrAsString(),
+			DurationSeconds: inst.Duration().Seconds(),
+		}
+
+		if inst.Error() != nil {
+			record.Error = inst.Error().Error()
+		}
+
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].InstanceGroup != records[j].InstanceGroup {
+			return records[i].InstanceGroup < records[j].InstanceGroup
+		}
+		return records[i].ID < records[j].ID
+	})
+
+	var buf bytes.Buffer
+
+	// Avoid escaping of command output
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(records)
+	if err != nil {
+		w.ui.ErrorLinef("Failed to marshal results: %s", err.Error())
+		return
+	}
+
+	w.ui.PrintBlock(buf.Bytes())
+}
+
 type resultsInstanceWriter struct {
-	jobName   string
-	indexOrID string
+	jobName     string
+	indexOrID   string
+	timeService clock.Clock
 
 	stdout *bytes.Buffer
 	stderr *bytes.Buffer
 
+	startedAt  time.Time
+	duration   time.Duration
 	exitStatus int
 	error      error
 }
 
-func newBufferedInstanceWriter(jobName, indexOrID string) *resultsInstanceWriter {
+func newBufferedInstanceWriter(jobName, indexOrID string, timeService clock.Clock) *resultsInstanceWriter {
 	return &resultsInstanceWriter{
-		jobName:   jobName,
-		indexOrID: indexOrID,
+		jobName:     jobName,
+		indexOrID:   indexOrID,
+		timeService: timeService,
 
 		stdout: bytes.NewBufferString(""),
 		stderr: bytes.NewBufferString(""),
@@ -81,16 +205,35 @@ func (w *resultsInstanceWriter) Instance() string {
 	return fmt.Sprintf("%s/%s", w.jobName, w.indexOrID)
 }
 
-func (w *resultsInstanceWriter) Stdout() io.Writer      { return w.stdout }
+func (w *resultsInstanceWriter) Start() { w.startedAt = w.timeService.Now() }
+
+func (w *resultsInstanceWriter) Stdout() io.Writer {
+	if w.stdout == nil {
+		return ioutil.Discard
+	}
+	return w.stdout
+}
+
 func (w *resultsInstanceWriter) StdoutAsString() string { return w.stdout.String() }
 
-func (w *resultsInstanceWriter) Stderr() io.Writer      { return w.stderr }
+func (w *resultsInstanceWriter) Stderr() io.Writer {
+	if w.stderr == nil {
+		return ioutil.Discard
+	}
+	return w.stderr
+}
+
 func (w *resultsInstanceWriter) StderrAsString() string { return w.stderr.String() }
 
 func (w *resultsInstanceWriter) End(exitStatus int, err error) {
+	if !w.startedAt.IsZero() {
+		w.duration = w.timeService.Since(w.startedAt)
+	}
+
 	w.exitStatus = exitStatus
 	w.error = err
 }
 
-func (w *resultsInstanceWriter) ExitStatus() int { return w.exitStatus }
-func (w *resultsInstanceWriter) Error() error    { return w.error }
+func (w *resultsInstanceWriter) Duration() time.Duration { return w.duration }
+func (w *resultsInstanceWriter) ExitStatus() int         { return w.exitStatus }
+func (w *resultsInstanceWriter) Error() error            { return w.error }
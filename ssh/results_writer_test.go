@@ -0,0 +1,153 @@
+package ssh_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/ssh"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/ui/table"
+)
+
+var _ = Describe("ResultsWriter", func() {
+	var (
+		ui          *fakeui.FakeUI
+		timeService *fakeclock.FakeClock
+		writer      *ResultsWriter
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		timeService = fakeclock.NewFakeClock(time.Now())
+		writer = NewResultsWriter(ui, timeService)
+	})
+
+	It("prints one record per instance with output, exit status, duration and error", func() {
+		inst1 := writer.ForInstance("job", "id2")
+		inst1.Start()
+		inst1.Stdout().Write([]byte("out2"))
+		inst1.Stderr().Write([]byte("err2"))
+		timeService.Increment(2 * time.Second)
+		inst1.End(1, errors.New("fake-err"))
+
+		inst2 := writer.ForInstance("job", "id1")
+		inst2.Start()
+		inst2.Stdout().Write([]byte("out1"))
+		timeService.Increment(500 * time.Millisecond)
+		inst2.End(0, nil)
+
+		writer.Flush()
+
+		Expect(ui.Table.Content).To(Equal("results"))
+
+		Expect(ui.Table.Header).To(Equal([]boshtbl.Header{
+			boshtbl.NewHeader("Instance"),
+			boshtbl.NewHeader("Stdout"),
+			boshtbl.NewHeader("Stderr"),
+			boshtbl.NewHeader("Exit Code"),
+			boshtbl.NewHeader("Duration"),
+			boshtbl.NewHeader("Error"),
+		}))
+
+		Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+			{
+				boshtbl.NewValueString("job/id2"),
+				boshtbl.NewValueString("out2"),
+				boshtbl.NewValueString("err2"),
+				boshtbl.NewValueInt(1),
+				boshtbl.NewValueString("2s"),
+				boshtbl.NewValueError(errors.New("fake-err")),
+			},
+			{
+				boshtbl.NewValueString("job/id1"),
+				boshtbl.NewValueString("out1"),
+				boshtbl.NewValueString(""),
+				boshtbl.NewValueInt(0),
+				boshtbl.NewValueString("500ms"),
+				boshtbl.NewValueError(nil),
+			},
+		}))
+	})
+
+	It("reports zero duration for instances that never started", func() {
+		writer.ForInstance("job", "id").End(0, errors.New("Skipped"))
+		writer.Flush()
+
+		Expect(ui.Table.Rows[0][4]).To(Equal(boshtbl.NewValueString("0s")))
+	})
+
+	Describe("NewStructuredResultsWriter", func() {
+		It("prints one JSON record per instance sorted by instance", func() {
+			writer = NewStructuredResultsWriter(ui, timeService)
+
+			inst1 := writer.ForInstance("job", "id2")
+			inst1.Start()
+			inst1.Stdout().Write([]byte("line1\n<line2>\n"))
+			inst1.Stderr().Write([]byte("err2"))
+			timeService.Increment(1500 * time.Millisecond)
+			inst1.End(1, errors.New("fake-err"))
+
+			inst2 := writer.ForInstance("job", "id1")
+			inst2.Start()
+			inst2.Stdout().Write([]byte("out1"))
+			timeService.Increment(2 * time.Second)
+			inst2.End(0, nil)
+
+			writer.Flush()
+
+			Expect(ui.Tables).To(BeEmpty())
+			Expect(ui.Blocks).To(Equal([]string{`[
+  {
+    "instance_group": "job",
+    "id": "id1",
+    "exit_status": 0,
+    "stdout": "out1",
+    "stderr": "",
+    "duration_seconds": 2,
+    "error": ""
+  },
+  {
+    "instance_group": "job",
+    "id": "id2",
+    "exit_status": 1,
+    "stdout": "line1\n<line2>\n",
+    "stderr": "err2",
+    "duration_seconds": 1.5,
+    "error": "fake-err"
+  }
+]
+`}))
+		})
+	})
+
+	Describe("NewExitStatusResultsWriter", func() {
+		It("only prints exit statuses and errors", func() {
+			writer = NewExitStatusResultsWriter(ui)
+
+			inst := writer.ForInstance("job", "id")
+			inst.Start()
+			inst.Stdout().Write([]byte("out"))
+			inst.End(3, nil)
+
+			writer.Flush()
+
+			Expect(ui.Table.Header).To(Equal([]boshtbl.Header{
+				boshtbl.NewHeader("Instance"),
+				boshtbl.NewHeader("Exit Code"),
+				boshtbl.NewHeader("Error"),
+			}))
+
+			Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("job/id"),
+					boshtbl.NewValueInt(3),
+					boshtbl.NewValueError(nil),
+				},
+			}))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -15,6 +16,9 @@ var windowsDisk = regexp.MustCompile(`^[A-Za-z]:\\`)
 type SCPArgs struct {
 	raw       []string
 	recursive bool
+	sync      bool
+
+	perInstanceDst bool
 }
 
 func NewSCPArgs(rawArgs []string, recursive bool) SCPArgs {
@@ -42,12 +46,14 @@ func (a SCPArgs) ForHost(host boshdir.Host) []string {
 		args = append(args, "-r")
 	}
 
-	for _, rawArg := range a.raw {
+	for i, rawArg := range a.raw {
 		pieces := strings.SplitN(rawArg, ":", 2)
 
 		if len(pieces) == 2 && !windowsDisk.MatchString(rawArg) {
 			// Resolve named host to actual user@ip
 			pieces[0] = fmt.Sprintf("%s@%s", host.Username, printableHost{host})
+		} else if i == len(a.raw)-1 {
+			pieces = []string{a.localDst(rawArg, host)}
 		}
 
 		for i := range pieces {
@@ -59,3 +65,74 @@ func (a SCPArgs) ForHost(host boshdir.Host) []string {
 
 	return args
 }
+
+type SCPPath struct {
+	Path   string
+	Remote bool
+}
+
+// PathsForHost returns source and destination paths (destination comes last)
+// for a particular host without remote host information.
+func (a SCPArgs) PathsForHost(host boshdir.Host) []SCPPath {
+	var paths []SCPPath
+
+	for i, rawArg := range a.raw {
+		pieces := strings.SplitN(rawArg, ":", 2)
+
+		path := SCPPath{Path: rawArg}
+
+		if len(pieces) == 2 && !windowsDisk.MatchString(rawArg) {
+			path = SCPPath{Path: pieces[1], Remote: true}
+		} else if i == len(a.raw)-1 {
+			path.Path = a.localDst(rawArg, host)
+		}
+
+		path.Path = strings.Replace(path.Path, "((instance_id))", host.IndexOrID, -1)
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// LocalDstForHost returns local destination for a particular host
+// when files are downloaded from instances.
+func (a SCPArgs) LocalDstForHost(host boshdir.Host) (string, bool) {
+	paths := a.PathsForHost(host)
+
+	if len(paths) < 2 || paths[len(paths)-1].Remote {
+		return "", false
+	}
+
+	return paths[len(paths)-1].Path, true
+}
+
+func (a SCPArgs) localDst(path string, host boshdir.Host) string {
+	if !a.perInstanceDst {
+		return path
+	}
+
+	jobName := "?"
+	if len(host.Job) > 0 {
+		jobName = host.Job
+	}
+
+	return filepath.Join(path, jobName, host.IndexOrID)
+}
+
+func (a SCPArgs) Recursive() bool { return a.recursive }
+
+// WithSync makes copying skip files with matching checksums and verify copied files.
+func (a SCPArgs) WithSync(sync bool) SCPArgs {
+	a.sync = sync
+	return a
+}
+
+func (a SCPArgs) Sync() bool { return a.sync }
+
+// WithPerInstanceDst makes files downloaded from instances go into
+// '<dst>/<instance-group>/<id>/' so that they do not overwrite each other.
+func (a SCPArgs) WithPerInstanceDst(perInstanceDst bool) SCPArgs {
+	a.perInstanceDst = perInstanceDst
+	return a
+}
@@ -104,4 +104,45 @@ var _ = Describe("SCPArgs", func() {
 			Expect(scpArgs.ForHost(host)).To(Equal([]string{}))
 		})
 	})
+
+	Describe("WithPerInstanceDst", func() {
+		BeforeEach(func() {
+			host.Job = "job"
+		})
+
+		It("places downloaded files into instance group and instance id directories", func() {
+			scpArgs := NewSCPArgs([]string{"host:file", "/dst"}, false).WithPerInstanceDst(true)
+
+			Expect(scpArgs.ForHost(host)).To(Equal([]string{"user@127.0.0.1:file", "/dst/job/id"}))
+			Expect(scpArgs.PathsForHost(host)).To(Equal([]SCPPath{
+				{Path: "file", Remote: true},
+				{Path: "/dst/job/id"},
+			}))
+		})
+
+		It("uses '?' directory for hosts without instance group", func() {
+			host.Job = ""
+
+			scpArgs := NewSCPArgs([]string{"host:file", "/dst"}, false).WithPerInstanceDst(true)
+			Expect(scpArgs.ForHost(host)).To(Equal([]string{"user@127.0.0.1:file", "/dst/?/id"}))
+		})
+
+		It("does not change destination when uploading", func() {
+			scpArgs := NewSCPArgs([]string{"/src", "host:dst"}, false).WithPerInstanceDst(true)
+			Expect(scpArgs.ForHost(host)).To(Equal([]string{"/src", "user@127.0.0.1:dst"}))
+		})
+	})
+
+	Describe("LocalDstForHost", func() {
+		It("returns local destination when downloading", func() {
+			dst, found := NewSCPArgs([]string{"host:file", "/dst-((instance_id))"}, false).LocalDstForHost(host)
+			Expect(found).To(BeTrue())
+			Expect(dst).To(Equal("/dst-id"))
+		})
+
+		It("returns not found when uploading", func() {
+			_, found := NewSCPArgs([]string{"/src", "host:dst"}, false).LocalDstForHost(host)
+			Expect(found).To(BeFalse())
+		})
+	})
 })
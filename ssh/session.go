@@ -1,8 +1,6 @@
 package ssh
 
 import (
-	"fmt"
-
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
@@ -36,6 +34,13 @@ func NewSessionImpl(
 func (r *SessionImpl) Start() (SSHArgs, error) {
 	var err error
 
+	if len(r.connOpts.KnownHostsPath) > 0 {
+		r.result, err = NewHostKeyPins(r.connOpts.KnownHostsPath, r.fs).Pin(r.result)
+		if err != nil {
+			return SSHArgs{}, err
+		}
+	}
+
 	r.privKeyFile, err = r.makePrivKeyFile()
 	if err != nil {
 		return SSHArgs{}, err
@@ -59,6 +64,9 @@ func (r *SessionImpl) Start() (SSHArgs, error) {
 }
 
 func (r *SessionImpl) Finish() error {
+	// Pin host keys accepted by ssh binary before known hosts file is deleted
+	trustErr := r.trustKnownHosts()
+
 	// Make sure to try to delete all files regardless of errors
 	privKeyErr := r.fs.RemoveAll(r.privKeyFile.Name())
 	knownHostsErr := r.fs.RemoveAll(r.knownHostsFile.Name())
@@ -71,7 +79,20 @@ func (r *SessionImpl) Finish() error {
 		return knownHostsErr
 	}
 
-	return nil
+	return trustErr
+}
+
+func (r SessionImpl) trustKnownHosts() error {
+	if !r.connOpts.TrustOnFirstUse || len(r.connOpts.KnownHostsPath) == 0 {
+		return nil
+	}
+
+	content, err := r.fs.ReadFileString(r.knownHostsFile.Name())
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading SSH known hosts")
+	}
+
+	return NewHostKeyPins(r.connOpts.KnownHostsPath, r.fs).TrustKnownHosts(content)
 }
 
 func (r SessionImpl) makePrivKeyFile() (boshsys.File, error) {
@@ -95,13 +116,7 @@ func (r SessionImpl) makeKnownHostsFile() (boshsys.File, error) {
 		return nil, bosherr.WrapErrorf(err, "Creating temp file for SSH known hosts")
 	}
 
-	var content string
-
-	for _, host := range r.result.Hosts {
-		if len(host.HostPublicKey) > 0 {
-			content += fmt.Sprintf("%s %s\n", host.Host, host.HostPublicKey)
-		}
-	}
+	content := KnownHosts(r.result)
 
 	if len(content) > 0 {
 		_, err := file.Write([]byte(content))
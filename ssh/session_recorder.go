@@ -0,0 +1,240 @@
+package ssh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"golang.org/x/crypto/ssh/terminal"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+const (
+	recordingDefaultWidth  = 80
+	recordingDefaultHeight = 24
+
+	recordingMaxNameAttempts = 100
+)
+
+// RecordingOpts describes where sessions are recorded
+// and which details are included into recordings.
+type RecordingOpts struct {
+	// Directory for recordings; empty means sessions are not recorded
+	Dir string
+
+	Environment string
+	Deployment  string
+	LocalUser   string
+}
+
+// SessionRecorder records sessions in asciicast v2 format
+// (https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md).
+type SessionRecorder struct {
+	fs          boshsys.FileSystem
+	timeService clock.Clock
+}
+
+func NewSessionRecorder(fs boshsys.FileSystem, timeService clock.Clock) SessionRecorder {
+	return SessionRecorder{fs: fs, timeService: timeService}
+}
+
+type sessionRecordingHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title"`
+
+	Env map[string]string `json:"env,omitempty"`
+
+	// Extra details are ignored by asciicast players
+	BOSH sessionRecordingDetails `json:"bosh"`
+}
+
+type sessionRecordingDetails struct {
+	Environment string `json:"environment"`
+	Deployment  string `json:"deployment"`
+	Instance    string `json:"instance"`
+	Host        string `json:"host"`
+	Username    string `json:"username"`
+	LocalUser   string `json:"local_user,omitempty"`
+	StartedAt   string `json:"started_at"`
+}
+
+// Record starts recording a session with a host into a new file.
+func (r SessionRecorder) Record(opts RecordingOpts, host boshdir.Host) (*SessionRecording, error) {
+	dir, err := r.fs.ExpandPath(opts.Dir)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Expanding recordings directory '%s'", opts.Dir)
+	}
+
+	err = r.fs.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Creating recordings directory '%s'", dir)
+	}
+
+	startedAt := r.timeService.Now().UTC()
+
+	path, file, err := r.createFile(dir, opts, host, startedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := r.terminalSize()
+
+	header := sessionRecordingHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: startedAt.Unix(),
+		Title:     fmt.Sprintf("%s@%s/%s (%s)", host.Username, host.Job, host.IndexOrID, opts.Deployment),
+
+		Env: map[string]string{"TERM": os.Getenv("TERM")},
+
+		BOSH: sessionRecordingDetails{
+			Environment: opts.Environment,
+			Deployment:  opts.Deployment,
+			Instance:    fmt.Sprintf("%s/%s", host.Job, host.IndexOrID),
+			Host:        host.Host,
+			Username:    host.Username,
+			LocalUser:   opts.LocalUser,
+			StartedAt:   startedAt.Format(time.RFC3339),
+		},
+	}
+
+	if len(header.Env["TERM"]) == 0 {
+		header.Env = nil
+	}
+
+	recording := &SessionRecording{
+		path:        path,
+		file:        file,
+		startedAt:   startedAt,
+		timeService: r.timeService,
+	}
+
+	err = recording.writeLine(header)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return recording, nil
+}
+
+// createFile never overwrites existing recordings; in an unlikely case
+// of a name collision (e.g. concurrent sessions) it adds a counter to the name
+func (r SessionRecorder) createFile(dir string, opts RecordingOpts, host boshdir.Host, startedAt time.Time) (string, boshsys.File, error) {
+	prefix := fmt.Sprintf("%s-%s-%s-%s",
+		opts.Deployment, host.Job, host.IndexOrID, startedAt.Format("20060102T150405.000000000Z"))
+
+	for i := 0; ; i++ {
+		path := filepath.Join(dir, prefix+".cast")
+		if i > 0 {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d.cast", prefix, i))
+		}
+
+		file, err := r.fs.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			return path, file, nil
+		}
+
+		if !os.IsExist(err) || i >= recordingMaxNameAttempts {
+			return "", nil, bosherr.WrapErrorf(err, "Creating recording '%s'", path)
+		}
+	}
+}
+
+func (r SessionRecorder) terminalSize() (int, int) {
+	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return recordingDefaultWidth, recordingDefaultHeight
+	}
+
+	return width, height
+}
+
+// SessionRecording is a single recorded session
+// that captures output written through its writers.
+type SessionRecording struct {
+	path        string
+	file        boshsys.File
+	startedAt   time.Time
+	timeService clock.Clock
+
+	// Output may be written concurrently from stdout and stderr
+	lock sync.Mutex
+}
+
+func (r *SessionRecording) Path() string { return r.path }
+
+// Writer returns writer that passes output through to w while recording it.
+func (r *SessionRecording) Writer(w io.Writer) io.Writer {
+	return sessionRecordingWriter{recording: r, w: w}
+}
+
+// End records end of the session with its exit status
+// as an output event and closes recording file.
+func (r *SessionRecording) End(exitStatus int, err error) error {
+	msg := fmt.Sprintf("\r\n[bosh: session ended with exit status %d", exitStatus)
+
+	if err != nil {
+		msg += fmt.Sprintf(" and error: %s", err)
+	}
+
+	recordErr := r.output([]byte(msg + "]\r\n"))
+
+	closeErr := r.file.Close()
+	if closeErr != nil {
+		return bosherr.WrapErrorf(closeErr, "Closing recording '%s'", r.path)
+	}
+
+	return recordErr
+}
+
+func (r *SessionRecording) output(data []byte) error {
+	elapsed := r.timeService.Since(r.startedAt).Seconds()
+
+	return r.writeLine([]interface{}{elapsed, "o", string(data)})
+}
+
+func (r *SessionRecording) writeLine(v interface{}) error {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Marshaling recording event")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, err = r.file.Write(append(bytes, '\n'))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing recording '%s'", r.path)
+	}
+
+	return nil
+}
+
+type sessionRecordingWriter struct {
+	recording *SessionRecording
+	w         io.Writer
+}
+
+func (w sessionRecordingWriter) Write(data []byte) (int, error) {
+	n, err := w.w.Write(data)
+
+	if n > 0 {
+		// Failing to record should not interrupt the session itself
+		_ = w.recording.output(data[:n])
+	}
+
+	return n, err
+}
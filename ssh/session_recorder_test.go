@@ -0,0 +1,126 @@
+package ssh_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/cloudfoundry/bosh-cli/ssh"
+	fakeui "github.com/cloudfoundry/bosh-cli/ui/fakes"
+)
+
+var _ = Describe("SessionRecorder", func() {
+	var (
+		recordDir   string
+		timeService *fakeclock.FakeClock
+		recorder    SessionRecorder
+		opts        RecordingOpts
+		host        boshdir.Host
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		recordDir, err = ioutil.TempDir("", "bosh-ssh-recordings")
+		Expect(err).ToNot(HaveOccurred())
+
+		timeService = fakeclock.NewFakeClock(time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC))
+		recorder = NewSessionRecorder(boshsys.NewOsFileSystem(boshlog.NewLogger(boshlog.LevelNone)), timeService)
+
+		opts = RecordingOpts{
+			Dir:         filepath.Join(recordDir, "recordings"),
+			Environment: "https://director:25555",
+			Deployment:  "dep",
+			LocalUser:   "local-user",
+		}
+
+		host = boshdir.Host{Job: "job", IndexOrID: "id", Host: "10.0.0.1", Username: "user"}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(recordDir)
+	})
+
+	It("records output with timings in asciicast format", func() {
+		recording, err := recorder.Record(opts, host)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(recording.Path()).To(Equal(filepath.Join(recordDir, "recordings", "dep-job-id-20170102T150405.000000000Z.cast")))
+
+		ui := &fakeui.FakeUI{}
+		writer := recording.Writer(uiBlocksWriter{ui})
+
+		_, err = writer.Write([]byte("hello\r\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		timeService.Increment(1500 * time.Millisecond)
+
+		_, err = writer.Write([]byte("\"bye\"\r\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		timeService.Increment(time.Second)
+
+		Expect(recording.End(1, errors.New("fake-err"))).ToNot(HaveOccurred())
+
+		Expect(ui.Blocks).To(Equal([]string{"hello\r\n", "\"bye\"\r\n"}))
+
+		contents, err := ioutil.ReadFile(recording.Path())
+		Expect(err).ToNot(HaveOccurred())
+
+		header := `{"version":2,"width":80,"height":24,"timestamp":1483369445,"title":"user@job/id (dep)",`
+		Expect(string(contents)).To(HavePrefix(header))
+		Expect(string(contents)).To(ContainSubstring(
+			`"bosh":{"environment":"https://director:25555","deployment":"dep","instance":"job/id","host":"10.0.0.1","username":"user","local_user":"local-user","started_at":"2017-01-02T15:04:05Z"}}` + "\n" +
+				`[0,"o","hello\r\n"]` + "\n" +
+				`[1.5,"o","\"bye\"\r\n"]` + "\n" +
+				`[2.5,"o","\r\n[bosh: session ended with exit status 1 and error: fake-err]\r\n"]` + "\n"))
+
+		info, err := os.Stat(recording.Path())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("does not overwrite existing recordings started at the same time", func() {
+		recording1, err := recorder.Record(opts, host)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recording1.End(0, nil)).ToNot(HaveOccurred())
+
+		recording2, err := recorder.Record(opts, host)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recording2.End(1, nil)).ToNot(HaveOccurred())
+
+		Expect(recording2.Path()).To(Equal(filepath.Join(recordDir, "recordings", "dep-job-id-20170102T150405.000000000Z-1.cast")))
+
+		contents, err := ioutil.ReadFile(recording1.Path())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(ContainSubstring("exit status 0"))
+	})
+
+	It("returns error if recording file cannot be created", func() {
+		fs := fakesys.NewFakeFileSystem()
+		fs.OpenFileErr = errors.New("fake-err")
+
+		_, err := NewSessionRecorder(fs, timeService).Record(opts, host)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("fake-err"))
+	})
+})
+
+type uiBlocksWriter struct {
+	ui *fakeui.FakeUI
+}
+
+func (w uiBlocksWriter) Write(data []byte) (int, error) {
+	w.ui.PrintBlock(data)
+	return len(data), nil
+}
@@ -79,6 +79,36 @@ var _ = Describe("SessionImpl", func() {
 				"127.0.0.1 pub-key1\n127.0.0.2 pub-key2\n::1 pub-key3\n"))
 		})
 
+		Context("when host keys are pinned", func() {
+			BeforeEach(func() {
+				connOpts.KnownHostsPath = "/home/.bosh/known_hosts/env"
+				fs.WriteFileString("/home/.bosh/known_hosts/env", "127.0.0.2 pub-key2\n")
+			})
+
+			It("writes out Director provided and pinned known hosts", func() {
+				result.Hosts = []boshdir.Host{
+					{Host: "127.0.0.1", HostPublicKey: "pub-key1"},
+					{Host: "127.0.0.2"},
+				}
+
+				_, err := act().Start()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fs.ReadFileString("/tmp/known-hosts")).To(Equal(
+					"127.0.0.1 pub-key1\n127.0.0.2 pub-key2\n"))
+				Expect(fs.ReadFileString("/home/.bosh/known_hosts/env")).To(Equal(
+					"127.0.0.2 pub-key2\n127.0.0.1 pub-key1\n"))
+			})
+
+			It("returns error if Director provided host key does not match pinned host key", func() {
+				result.Hosts = []boshdir.Host{{Host: "127.0.0.2", HostPublicKey: "other-key"}}
+
+				_, err := act().Start()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("to match previously pinned host key"))
+				Expect(fs.FileExists("/tmp/priv-key")).To(BeFalse())
+			})
+		})
+
 		It("returns error if cannot create known hosts temp file and deletes private key", func() {
 			fs.TempFileErrorsByPrefix = map[string]error{
 				"ssh-known-hosts": errors.New("fake-err"),
@@ -141,6 +171,23 @@ var _ = Describe("SessionImpl", func() {
 			Expect(fs.FileExists("/tmp/known-hosts")).To(BeFalse())
 		})
 
+		It("pins host keys accepted on first use", func() {
+			connOpts.KnownHostsPath = "/home/.bosh/known_hosts/env"
+			connOpts.TrustOnFirstUse = true
+
+			session = NewSessionImpl(connOpts, sessOpts, result, fs)
+
+			_, err := session.Start()
+			Expect(err).ToNot(HaveOccurred())
+
+			fs.WriteFileString("/tmp/known-hosts", "127.0.0.1 ssh-rsa key1\n")
+
+			err = session.Finish()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fs.ReadFileString("/home/.bosh/known_hosts/env")).To(Equal("127.0.0.1 ssh-rsa key1\n"))
+			Expect(fs.FileExists("/tmp/known-hosts")).To(BeFalse())
+		})
+
 		It("returns error if deleting private key file fails but still deletes known hosts file", func() {
 			fs.RemoveAllStub = func(path string) error {
 				if path == "/tmp/priv-key" {
@@ -80,10 +80,21 @@ func (a SSHArgs) OptsForHost(host boshdir.Host) []string {
 		"-o", "PasswordAuthentication=no",
 		"-o", "IdentitiesOnly=yes",
 		"-o", "IdentityFile=" + a.PrivKeyFile.Name(),
-		"-o", "StrictHostKeyChecking=yes",
-		"-o", "UserKnownHostsFile=" + a.KnownHostsFile.Name(),
 	}...)
 
+	if a.trustOnFirstUse() {
+		// Newly accepted host keys are pinned after session finishes
+		// hence they must be recorded in a parsable form
+		cmdOpts = append(cmdOpts, []string{
+			"-o", "StrictHostKeyChecking=accept-new",
+			"-o", "HashKnownHosts=no",
+		}...)
+	} else {
+		cmdOpts = append(cmdOpts, "-o", "StrictHostKeyChecking=yes")
+	}
+
+	cmdOpts = append(cmdOpts, "-o", "UserKnownHostsFile="+a.KnownHostsFile.Name())
+
 	gwUsername, gwHost, gwPrivKeyPath := a.gwOpts()
 
 	if len(a.ConnOpts.SOCKS5Proxy) > 0 {
@@ -141,24 +152,32 @@ func (a SSHArgs) OptsForHost(host boshdir.Host) []string {
 	return cmdOpts
 }
 
+func (a SSHArgs) trustOnFirstUse() bool {
+	return a.ConnOpts.TrustOnFirstUse && len(a.ConnOpts.KnownHostsPath) > 0
+}
+
 func (a SSHArgs) gwOpts() (string, string, string) {
-	if a.ConnOpts.GatewayDisable {
+	return gatewayOpts(a.ConnOpts, a.Result)
+}
+
+func gatewayOpts(connOpts ConnectionOpts, result boshdir.SSHResult) (string, string, string) {
+	if connOpts.GatewayDisable {
 		return "", "", ""
 	}
 
 	// Take server provided gateway options
-	username := a.Result.GatewayUsername
-	host := a.Result.GatewayHost
+	username := result.GatewayUsername
+	host := result.GatewayHost
 
-	if len(a.ConnOpts.GatewayUsername) > 0 {
-		username = a.ConnOpts.GatewayUsername
+	if len(connOpts.GatewayUsername) > 0 {
+		username = connOpts.GatewayUsername
 	}
 
-	if len(a.ConnOpts.GatewayHost) > 0 {
-		host = a.ConnOpts.GatewayHost
+	if len(connOpts.GatewayHost) > 0 {
+		host = connOpts.GatewayHost
 	}
 
-	privKeyPath := a.ConnOpts.GatewayPrivateKeyPath
+	privKeyPath := connOpts.GatewayPrivateKeyPath
 
 	return username, host, privKeyPath
 }
@@ -75,6 +75,22 @@ var _ = Describe("SSHArgs", func() {
 			}))
 		})
 
+		It("returns ssh options that accept and record new host keys when trusting on first use", func() {
+			connOpts.KnownHostsPath = "/home/.bosh/known_hosts/env"
+			connOpts.TrustOnFirstUse = true
+
+			Expect(act()).To(Equal([]string{
+				"-o", "ServerAliveInterval=30",
+				"-o", "ForwardAgent=no",
+				"-o", "PasswordAuthentication=no",
+				"-o", "IdentitiesOnly=yes",
+				"-o", "IdentityFile=/tmp/priv-key",
+				"-o", "StrictHostKeyChecking=accept-new",
+				"-o", "HashKnownHosts=no",
+				"-o", "UserKnownHostsFile=/tmp/known-hosts",
+			}))
+		})
+
 		It("returns ssh options with forced tty option if requested", func() {
 			forceTTY = true
 
@@ -0,0 +1,117 @@
+package ssh
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+)
+
+// SSHConfig builds OpenSSH ssh_config contents so that instances
+// could be accessed by ssh (and tools relying on it) without bosh CLI.
+type SSHConfig struct {
+	Deployment string
+
+	ConnOpts ConnectionOpts
+	Result   boshdir.SSHResult
+
+	IdentityFile   string
+	KnownHostsFile string
+}
+
+// Alias returns '<deployment>-<instance-group>-<index>' falling back to
+// instance ID when index is not known.
+func (c SSHConfig) Alias(host boshdir.Host) string {
+	indexOrID := host.IndexOrID
+
+	if host.Index != nil {
+		indexOrID = strconv.Itoa(*host.Index)
+	}
+
+	return fmt.Sprintf("%s-%s-%s", c.Deployment, host.Job, indexOrID)
+}
+
+func (c SSHConfig) String() (string, error) {
+	proxyOpt, err := c.proxyOpt()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+
+	for _, host := range c.Result.Hosts {
+		lines = append(lines,
+			"Host "+c.Alias(host),
+			"  HostName "+host.Host,
+			"  User "+host.Username,
+			"  ServerAliveInterval 30",
+			"  ForwardAgent no",
+			"  PasswordAuthentication no",
+			"  IdentitiesOnly yes",
+			"  IdentityFile "+c.quote(c.IdentityFile),
+			"  StrictHostKeyChecking yes",
+			"  UserKnownHostsFile "+c.quote(c.KnownHostsFile),
+		)
+
+		if len(proxyOpt) > 0 {
+			lines = append(lines, "  "+proxyOpt)
+		}
+
+		lines = append(lines, "")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c SSHConfig) proxyOpt() (string, error) {
+	if len(c.ConnOpts.SOCKS5Proxy) > 0 {
+		if strings.HasPrefix(c.ConnOpts.SOCKS5Proxy, "ssh+") {
+			// SOCKS5 proxy over SSH tunnel only lives as long as bosh CLI process
+			return "", bosherr.Error("Expected SOCKS5 proxy to not be tunneled over SSH when generating ssh_config")
+		}
+
+		proxyHost := strings.TrimPrefix(c.ConnOpts.SOCKS5Proxy, "socks5://")
+
+		return fmt.Sprintf("ProxyCommand nc -x %s %%h %%p", proxyHost), nil
+	}
+
+	gwUsername, gwHost, gwPrivKeyPath := gatewayOpts(c.ConnOpts, c.Result)
+
+	if len(gwHost) == 0 {
+		return "", nil
+	}
+
+	if len(gwPrivKeyPath) == 0 {
+		return fmt.Sprintf("ProxyJump %s@%s", gwUsername, gwHost), nil
+	}
+
+	// ProxyJump does not allow to specify gateway identity file
+	return fmt.Sprintf(
+		"ProxyCommand ssh -W %%h:%%p -l %s %s -o ForwardAgent=no -o ClearAllForwardings=yes -o PasswordAuthentication=no -o IdentitiesOnly=yes -o IdentityFile=%s",
+		gwUsername, gwHost, c.quote(gwPrivKeyPath),
+	), nil
+}
+
+func (c SSHConfig) quote(path string) string {
+	if strings.ContainsAny(path, " \t") {
+		return `"` + path + `"`
+	}
+
+	return path
+}
+
+// KnownHosts returns known_hosts contents with Director provided host public keys.
+func KnownHosts(result boshdir.SSHResult) string {
+	var content string
+
+	for _, host := range result.Hosts {
+		if len(host.HostPublicKey) > 0 {
+			content += fmt.Sprintf("%s %s\n", host.Host, host.HostPublicKey)
+		}
+	}
+
+	return content
+}
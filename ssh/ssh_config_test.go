@@ -0,0 +1,139 @@
+package ssh_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/director"
+	. "github.com/cloudfoundry/bosh-cli/ssh"
+)
+
+var _ = Describe("SSHConfig", func() {
+	var (
+		config SSHConfig
+	)
+
+	BeforeEach(func() {
+		index := 0
+
+		config = SSHConfig{
+			Deployment: "dep",
+
+			Result: boshdir.SSHResult{
+				Hosts: []boshdir.Host{
+					{Job: "db", IndexOrID: "db-id", Index: &index, Username: "user", Host: "10.0.0.1", HostPublicKey: "ssh-rsa key1"},
+					{Job: "web", IndexOrID: "web-id", Username: "user", Host: "10.0.0.2"},
+				},
+			},
+
+			IdentityFile:   "/dir/dep-identity",
+			KnownHostsFile: "/dir/dep-known_hosts",
+		}
+	})
+
+	Describe("Alias", func() {
+		It("uses index when it's known and falls back to ID otherwise", func() {
+			Expect(config.Alias(config.Result.Hosts[0])).To(Equal("dep-db-0"))
+			Expect(config.Alias(config.Result.Hosts[1])).To(Equal("dep-web-web-id"))
+		})
+	})
+
+	Describe("String", func() {
+		It("includes host entry for each instance", func() {
+			contents, err := config.String()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal(`Host dep-db-0
+  HostName 10.0.0.1
+  User user
+  ServerAliveInterval 30
+  ForwardAgent no
+  PasswordAuthentication no
+  IdentitiesOnly yes
+  IdentityFile /dir/dep-identity
+  StrictHostKeyChecking yes
+  UserKnownHostsFile /dir/dep-known_hosts
+
+Host dep-web-web-id
+  HostName 10.0.0.2
+  User user
+  ServerAliveInterval 30
+  ForwardAgent no
+  PasswordAuthentication no
+  IdentitiesOnly yes
+  IdentityFile /dir/dep-identity
+  StrictHostKeyChecking yes
+  UserKnownHostsFile /dir/dep-known_hosts
+`))
+		})
+
+		It("quotes paths with spaces", func() {
+			config.IdentityFile = "/my dir/dep-identity"
+
+			contents, err := config.String()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(ContainSubstring(`  IdentityFile "/my dir/dep-identity"` + "\n"))
+		})
+
+		It("uses ProxyJump for Director provided gateway", func() {
+			config.Result.GatewayUsername = "gw-user"
+			config.Result.GatewayHost = "gw-host"
+
+			contents, err := config.String()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(ContainSubstring("  ProxyJump gw-user@gw-host\n"))
+		})
+
+		It("uses ProxyCommand for gateway with private key", func() {
+			config.ConnOpts.GatewayUsername = "gw-user"
+			config.ConnOpts.GatewayHost = "gw-host"
+			config.ConnOpts.GatewayPrivateKeyPath = "/gw-key"
+
+			contents, err := config.String()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(ContainSubstring(
+				"  ProxyCommand ssh -W %h:%p -l gw-user gw-host -o ForwardAgent=no -o ClearAllForwardings=yes " +
+					"-o PasswordAuthentication=no -o IdentitiesOnly=yes -o IdentityFile=/gw-key\n"))
+		})
+
+		It("does not use gateway when it's disabled", func() {
+			config.Result.GatewayHost = "gw-host"
+			config.ConnOpts.GatewayDisable = true
+
+			contents, err := config.String()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).ToNot(ContainSubstring("Proxy"))
+		})
+
+		It("uses ProxyCommand for SOCKS5 proxy", func() {
+			config.Result.GatewayHost = "gw-host"
+			config.ConnOpts.SOCKS5Proxy = "socks5://localhost:1080"
+
+			contents, err := config.String()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(ContainSubstring("  ProxyCommand nc -x localhost:1080 %h %p\n"))
+			Expect(contents).ToNot(ContainSubstring("ProxyJump"))
+		})
+
+		It("returns error for SOCKS5 proxy tunneled over SSH", func() {
+			config.ConnOpts.SOCKS5Proxy = "ssh+socks5://user@jumpbox:22?private-key=/key"
+
+			_, err := config.String()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected SOCKS5 proxy to not be tunneled over SSH"))
+		})
+	})
+})
+
+var _ = Describe("KnownHosts", func() {
+	It("includes hosts with public keys", func() {
+		result := boshdir.SSHResult{
+			Hosts: []boshdir.Host{
+				{Host: "10.0.0.1", HostPublicKey: "ssh-rsa key1"},
+				{Host: "10.0.0.2"},
+				{Host: "10.0.0.3", HostPublicKey: "ssh-rsa key3"},
+			},
+		}
+
+		Expect(KnownHosts(result)).To(Equal("10.0.0.1 ssh-rsa key1\n10.0.0.3 ssh-rsa key3\n"))
+	})
+})
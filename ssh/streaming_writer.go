@@ -28,6 +28,8 @@ type streamingInstanceWriter struct {
 	comboWriter *boshui.ComboWriter
 }
 
+func (w streamingInstanceWriter) Start() {}
+
 func (w streamingInstanceWriter) Stdout() io.Writer {
 	return w.comboWriter.Writer(fmt.Sprintf("%s/%s: stdout | ", w.jobName, w.indexOrID))
 }
@@ -0,0 +1,246 @@
+package erbrenderer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+// CachingERBRenderer keeps rendered templates in cache directory
+// keyed by renderer, template contents and evaluation context, so that
+// unchanged templates are not rendered again by given renderer.
+// Rendered templates usually include credentials hence cache is only
+// used once cache directory is set and is only readable by current user.
+type CachingERBRenderer interface {
+	ERBRenderer
+
+	// UseCacheDir enables caching of rendered templates in given directory
+	UseCacheDir(path string) error
+
+	// PruneUnused deletes cached templates that were not used since cache dir was set
+	PruneUnused() error
+}
+
+type cachingERBRenderer struct {
+	renderer     ERBRenderer
+	rendererName string
+	fs           boshsys.FileSystem
+	logger       boshlog.Logger
+	logTag       string
+
+	cacheDir  string
+	used      map[string]struct{}
+	cacheLock sync.Mutex
+}
+
+// NewCachingERBRenderer uses renderer name as part of the cache key
+// since different renderers may not produce exactly same output
+func NewCachingERBRenderer(renderer ERBRenderer, rendererName string, fs boshsys.FileSystem, logger boshlog.Logger) CachingERBRenderer {
+	return &cachingERBRenderer{
+		renderer:     renderer,
+		rendererName: rendererName,
+		fs:           fs,
+		logger:       logger,
+		logTag:       "cachingERBRenderer",
+		used:         map[string]struct{}{},
+	}
+}
+
+func (r *cachingERBRenderer) UseCacheDir(path string) error {
+	err := r.fs.MkdirAll(path, os.FileMode(0700))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Creating rendered templates cache dir '%s'", path)
+	}
+
+	// Directory may have been created with different permissions
+	err = r.fs.Chmod(path, os.FileMode(0700))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Setting permissions on rendered templates cache dir '%s'", path)
+	}
+
+	r.cacheLock.Lock()
+	defer r.cacheLock.Unlock()
+
+	r.cacheDir = path
+	r.used = map[string]struct{}{}
+
+	return nil
+}
+
+func (r *cachingERBRenderer) PruneUnused() error {
+	r.cacheLock.Lock()
+	defer r.cacheLock.Unlock()
+
+	if len(r.cacheDir) == 0 {
+		return nil
+	}
+
+	paths, err := r.fs.Glob(filepath.Join(r.cacheDir, "*"))
+	if err != nil {
+		return bosherr.WrapError(err, "Listing cached rendered templates")
+	}
+
+	for _, path := range paths {
+		if _, found := r.used[path]; found {
+			continue
+		}
+
+		r.logger.Debug(r.logTag, "Deleting unused cached rendered template %s", path)
+
+		err = r.fs.RemoveAll(path)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Deleting cached rendered template '%s'", path)
+		}
+	}
+
+	return nil
+}
+
+func (r *cachingERBRenderer) Render(srcPath, dstPath string, context TemplateEvaluationContext) error {
+	r.cacheLock.Lock()
+	cacheDir := r.cacheDir
+	r.cacheLock.Unlock()
+
+	if len(cacheDir) == 0 {
+		return r.renderer.Render(srcPath, dstPath, context)
+	}
+
+	cachePath, err := r.cachePath(cacheDir, srcPath, context)
+	if err != nil {
+		return err
+	}
+
+	if r.fs.FileExists(cachePath) {
+		r.logger.Debug(r.logTag, "Using cached rendered template %s for %s", cachePath, dstPath)
+
+		err = r.fs.CopyFile(cachePath, dstPath)
+		if err == nil {
+			r.markUsed(cachePath)
+			return nil
+		}
+
+		r.logger.Warn(r.logTag, "Failed to copy cached rendered template %s: %s", cachePath, err.Error())
+	}
+
+	err = r.renderer.Render(srcPath, dstPath, context)
+	if err != nil {
+		return err
+	}
+
+	r.store(dstPath, cachePath)
+
+	return nil
+}
+
+func (r *cachingERBRenderer) cachePath(cacheDir, srcPath string, context TemplateEvaluationContext) (string, error) {
+	template, err := r.fs.ReadFile(srcPath)
+	if err != nil {
+		return "", bosherr.WrapError(err, "Reading template")
+	}
+
+	contextBytes, err := json.Marshal(context)
+	if err != nil {
+		return "", bosherr.WrapError(err, "Marshalling context")
+	}
+
+	contextBytes, err = r.normalizeContext(contextBytes)
+	if err != nil {
+		return "", bosherr.WrapError(err, "Normalizing context")
+	}
+
+	key := fmt.Sprintf("%s\n%x\n%x", r.rendererName, sha256.Sum256(template), sha256.Sum256(contextBytes))
+
+	return filepath.Join(cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(key)))), nil
+}
+
+// normalizeContext drops instance ID since it is generated
+// for every render when it is not known (e.g. in create-env)
+func (r *cachingERBRenderer) normalizeContext(contextBytes []byte) ([]byte, error) {
+	var context map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(contextBytes))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&context)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(context, "id")
+
+	return json.Marshal(context)
+}
+
+func (r *cachingERBRenderer) markUsed(cachePath string) {
+	r.cacheLock.Lock()
+	defer r.cacheLock.Unlock()
+
+	r.used[cachePath] = struct{}{}
+}
+
+// store does not fail rendering since cache is only an optimization
+func (r *cachingERBRenderer) store(dstPath, cachePath string) {
+	contents, err := r.fs.ReadFile(dstPath)
+	if err != nil {
+		r.logger.Warn(r.logTag, "Failed to read rendered template %s: %s", dstPath, err.Error())
+		return
+	}
+
+	// Same template may be rendered concurrently with same context
+	r.cacheLock.Lock()
+	defer r.cacheLock.Unlock()
+
+	err = r.writeCacheFile(cachePath, contents)
+	if err != nil {
+		r.logger.Warn(r.logTag, "Failed to cache rendered template %s: %s", dstPath, err.Error())
+		return
+	}
+
+	r.used[cachePath] = struct{}{}
+}
+
+// writeCacheFile writes and renames so that partially written files are never used
+func (r *cachingERBRenderer) writeCacheFile(cachePath string, contents []byte) error {
+	tmpPath := cachePath + ".tmp"
+
+	file, err := r.fs.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0600))
+	if err != nil {
+		return bosherr.WrapError(err, "Opening cache file")
+	}
+
+	_, err = file.Write(contents)
+	if err != nil {
+		file.Close()
+		r.removeSilently(tmpPath)
+		return bosherr.WrapError(err, "Writing cache file")
+	}
+
+	err = file.Close()
+	if err != nil {
+		r.removeSilently(tmpPath)
+		return bosherr.WrapError(err, "Closing cache file")
+	}
+
+	err = r.fs.Rename(tmpPath, cachePath)
+	if err != nil {
+		r.removeSilently(tmpPath)
+		return bosherr.WrapError(err, "Renaming cache file")
+	}
+
+	return nil
+}
+
+func (r *cachingERBRenderer) removeSilently(path string) {
+	err := r.fs.RemoveAll(path)
+	if err != nil {
+		r.logger.Warn(r.logTag, "Failed to remove %s: %s", path, err.Error())
+	}
+}
@@ -0,0 +1,212 @@
+package erbrenderer_test
+
+import (
+	"errors"
+	"os"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
+)
+
+type writingERBRenderer struct {
+	fs      *fakesys.FakeFileSystem
+	output  string
+	err     error
+	renders int
+}
+
+func (r *writingERBRenderer) Render(srcPath, dstPath string, context TemplateEvaluationContext) error {
+	r.renders++
+	if r.err != nil {
+		return r.err
+	}
+	return r.fs.WriteFileString(dstPath, r.output)
+}
+
+var _ = Describe("CachingERBRenderer", func() {
+	var (
+		fs          *fakesys.FakeFileSystem
+		renderer    *writingERBRenderer
+		logger      boshlog.Logger
+		erbRenderer CachingERBRenderer
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		renderer = &writingERBRenderer{fs: fs, output: "fake-rendered"}
+		logger = boshlog.NewLogger(boshlog.LevelNone)
+		erbRenderer = NewCachingERBRenderer(renderer, "fake-renderer", fs, logger)
+
+		fs.WriteFileString("/src", "fake-template")
+	})
+
+	render := func(dstPath string, context TemplateEvaluationContext) string {
+		err := erbRenderer.Render("/src", dstPath, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := fs.ReadFileString(dstPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		return result
+	}
+
+	cachedPaths := func() []string {
+		var paths []string
+		fs.Walk("/cache", func(path string, info os.FileInfo, err error) error {
+			if !info.IsDir() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		return paths
+	}
+
+	It("does not cache rendered templates unless cache dir is set", func() {
+		render("/dst1", jsonContext(`{}`))
+		render("/dst2", jsonContext(`{}`))
+
+		Expect(renderer.renders).To(Equal(2))
+		Expect(fs.FileExists("/cache")).To(BeFalse())
+	})
+
+	Context("when cache dir is set", func() {
+		BeforeEach(func() {
+			err := erbRenderer.UseCacheDir("/cache")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("creates cache dir only readable by current user", func() {
+			Expect(fs.GetFileTestStat("/cache").FileMode).To(Equal(os.FileMode(0700)))
+		})
+
+		It("renders template and reuses rendered output for same template and context", func() {
+			Expect(render("/dst1", jsonContext(`{"index":0,"job_properties":{"a":1}}`))).To(Equal("fake-rendered"))
+
+			renderer.output = "fake-rendered-again"
+			Expect(render("/dst2", jsonContext(`{"job_properties":{"a":1},"index":0}`))).To(Equal("fake-rendered"))
+
+			Expect(renderer.renders).To(Equal(1))
+		})
+
+		It("writes cached templates only readable by current user", func() {
+			render("/dst", jsonContext(`{}`))
+
+			paths := cachedPaths()
+			Expect(paths).To(HaveLen(1))
+			Expect(fs.GetFileTestStat(paths[0]).FileMode).To(Equal(os.FileMode(0600)))
+			Expect(fs.FileExists(paths[0] + ".tmp")).To(BeFalse())
+		})
+
+		It("ignores generated instance ID in context", func() {
+			render("/dst1", jsonContext(`{"id":"fake-uuid-1","index":0}`))
+			render("/dst2", jsonContext(`{"id":"fake-uuid-2","index":0}`))
+
+			Expect(renderer.renders).To(Equal(1))
+		})
+
+		It("renders template again if context changes", func() {
+			render("/dst1", jsonContext(`{"index":0,"job_properties":{"a":1}}`))
+
+			renderer.output = "fake-rendered-again"
+			Expect(render("/dst2", jsonContext(`{"index":0,"job_properties":{"a":2}}`))).To(Equal("fake-rendered-again"))
+
+			Expect(renderer.renders).To(Equal(2))
+		})
+
+		It("renders template again if template changes", func() {
+			render("/dst1", jsonContext(`{}`))
+
+			fs.WriteFileString("/src", "fake-template-changed")
+			renderer.output = "fake-rendered-again"
+			Expect(render("/dst2", jsonContext(`{}`))).To(Equal("fake-rendered-again"))
+
+			Expect(renderer.renders).To(Equal(2))
+		})
+
+		It("renders template again if renderer changes", func() {
+			render("/dst1", jsonContext(`{}`))
+
+			erbRenderer = NewCachingERBRenderer(renderer, "other-renderer", fs, logger)
+			err := erbRenderer.UseCacheDir("/cache")
+			Expect(err).ToNot(HaveOccurred())
+
+			renderer.output = "fake-rendered-again"
+			Expect(render("/dst2", jsonContext(`{}`))).To(Equal("fake-rendered-again"))
+
+			Expect(renderer.renders).To(Equal(2))
+		})
+
+		It("returns error from renderer without caching output", func() {
+			renderer.err = errors.New("fake-err")
+
+			err := erbRenderer.Render("/src", "/dst", jsonContext(`{}`))
+			Expect(err).To(Equal(renderer.err))
+			Expect(cachedPaths()).To(BeEmpty())
+		})
+
+		It("returns error if template cannot be read", func() {
+			err := erbRenderer.Render("/missing", "/dst", jsonContext(`{}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading template"))
+
+			Expect(renderer.renders).To(Equal(0))
+		})
+
+		It("does not fail rendering if output cannot be cached", func() {
+			fs.OpenFileErr = errors.New("fake-open-err")
+
+			Expect(render("/dst", jsonContext(`{}`))).To(Equal("fake-rendered"))
+		})
+
+		It("removes partially written cache file if it cannot be renamed", func() {
+			fs.RenameError = errors.New("fake-rename-err")
+
+			Expect(render("/dst", jsonContext(`{}`))).To(Equal("fake-rendered"))
+			Expect(cachedPaths()).To(BeEmpty())
+		})
+
+		Describe("PruneUnused", func() {
+			It("deletes cached templates not used since cache dir was set", func() {
+				render("/dst1", jsonContext(`{"index":0}`))
+				render("/dst2", jsonContext(`{"index":1}`))
+				paths := cachedPaths()
+				Expect(paths).To(HaveLen(2))
+
+				err := erbRenderer.UseCacheDir("/cache")
+				Expect(err).ToNot(HaveOccurred())
+
+				render("/dst3", jsonContext(`{"index":1}`))
+				Expect(renderer.renders).To(Equal(2))
+
+				fs.SetGlob("/cache/*", paths)
+
+				err = erbRenderer.PruneUnused()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(cachedPaths()).To(HaveLen(1))
+				Expect(fs.ReadFileString(cachedPaths()[0])).To(Equal("fake-rendered"))
+			})
+
+			It("returns error if cached templates cannot be listed", func() {
+				fs.GlobErr = errors.New("fake-glob-err")
+
+				err := erbRenderer.PruneUnused()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-glob-err"))
+			})
+		})
+	})
+
+	Describe("PruneUnused", func() {
+		It("does nothing unless cache dir is set", func() {
+			fs.GlobErr = errors.New("fake-glob-err")
+
+			err := erbRenderer.PruneUnused()
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package fakes
 
 import (
 	"fmt"
+	"sync"
 
 	bierbrenderer "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
 	bitestutils "github.com/cloudfoundry/bosh-cli/testutils"
@@ -11,6 +12,7 @@ import (
 type FakeERBRenderer struct {
 	RenderInputs   []RenderInput
 	renderBehavior map[string]renderOutput
+	renderLock     sync.Mutex
 }
 
 type RenderInput struct {
@@ -31,6 +33,9 @@ func NewFakeERBRender() *FakeERBRenderer {
 }
 
 func (f *FakeERBRenderer) Render(srcPath, dstPath string, context bierbrenderer.TemplateEvaluationContext) error {
+	f.renderLock.Lock()
+	defer f.renderLock.Unlock()
+
 	input := RenderInput{
 		SrcPath: srcPath,
 		DstPath: dstPath,
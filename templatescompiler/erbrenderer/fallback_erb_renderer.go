@@ -0,0 +1,33 @@
+package erbrenderer
+
+import (
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+)
+
+type fallbackERBRenderer struct {
+	renderer         ERBRenderer
+	fallbackRenderer ERBRenderer
+	logger           boshlog.Logger
+	logTag           string
+}
+
+// NewFallbackERBRenderer uses fallback renderer (usually Ruby based)
+// for templates that given renderer reports as unsupported
+func NewFallbackERBRenderer(renderer, fallbackRenderer ERBRenderer, logger boshlog.Logger) ERBRenderer {
+	return fallbackERBRenderer{
+		renderer:         renderer,
+		fallbackRenderer: fallbackRenderer,
+		logger:           logger,
+		logTag:           "fallbackERBRenderer",
+	}
+}
+
+func (r fallbackERBRenderer) Render(srcPath, dstPath string, context TemplateEvaluationContext) error {
+	err := r.renderer.Render(srcPath, dstPath, context)
+	if unsupportedErr, ok := err.(UnsupportedTemplateError); ok {
+		r.logger.Debug(r.logTag, "Falling back to render template %s: %s", dstPath, unsupportedErr.Error())
+		return r.fallbackRenderer.Render(srcPath, dstPath, context)
+	}
+
+	return err
+}
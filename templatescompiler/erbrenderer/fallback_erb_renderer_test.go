@@ -0,0 +1,73 @@
+package erbrenderer_test
+
+import (
+	"errors"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
+	fakebierbrenderer "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer/fakes"
+)
+
+type stubERBRenderer struct {
+	err     error
+	renders []string
+}
+
+func (r *stubERBRenderer) Render(srcPath, dstPath string, context TemplateEvaluationContext) error {
+	r.renders = append(r.renders, srcPath+":"+dstPath)
+	return r.err
+}
+
+var _ = Describe("FallbackERBRenderer", func() {
+	var (
+		renderer         *stubERBRenderer
+		fallbackRenderer *stubERBRenderer
+		erbRenderer      ERBRenderer
+		context          *fakebierbrenderer.FakeTemplateEvaluationContext
+	)
+
+	BeforeEach(func() {
+		renderer = &stubERBRenderer{}
+		fallbackRenderer = &stubERBRenderer{}
+		context = &fakebierbrenderer.FakeTemplateEvaluationContext{}
+
+		erbRenderer = NewFallbackERBRenderer(renderer, fallbackRenderer, boshlog.NewLogger(boshlog.LevelNone))
+	})
+
+	It("renders with given renderer", func() {
+		err := erbRenderer.Render("src", "dst", context)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(renderer.renders).To(Equal([]string{"src:dst"}))
+		Expect(fallbackRenderer.renders).To(BeEmpty())
+	})
+
+	It("renders with fallback renderer if template is not supported", func() {
+		renderer.err = UnsupportedTemplateError{Path: "src", Line: 1, Reason: "fake-reason"}
+
+		err := erbRenderer.Render("src", "dst", context)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fallbackRenderer.renders).To(Equal([]string{"src:dst"}))
+	})
+
+	It("returns error from fallback renderer", func() {
+		renderer.err = UnsupportedTemplateError{Path: "src", Line: 1, Reason: "fake-reason"}
+		fallbackRenderer.err = errors.New("fake-fallback-err")
+
+		err := erbRenderer.Render("src", "dst", context)
+		Expect(err).To(Equal(fallbackRenderer.err))
+	})
+
+	It("returns other errors without falling back", func() {
+		renderer.err = errors.New("fake-err")
+
+		err := erbRenderer.Render("src", "dst", context)
+		Expect(err).To(Equal(renderer.err))
+
+		Expect(fallbackRenderer.renders).To(BeEmpty())
+	})
+})
@@ -0,0 +1,2012 @@
+package erbrenderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// erbHash keeps insertion order of keys like Ruby's Hash
+type erbHash struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newERBHash() *erbHash {
+	return &erbHash{values: map[string]interface{}{}}
+}
+
+func (h *erbHash) get(key string) interface{} {
+	return h.values[key]
+}
+
+func (h *erbHash) has(key string) bool {
+	_, found := h.values[key]
+	return found
+}
+
+func (h *erbHash) set(key string, value interface{}) {
+	if !h.has(key) {
+		h.keys = append(h.keys, key)
+	}
+	h.values[key] = value
+}
+
+// erbStruct is an OpenStruct; missing attributes are nil
+type erbStruct struct {
+	fields *erbHash
+}
+
+type erbLink struct {
+	address    interface{}
+	instances  []interface{}
+	properties interface{}
+}
+
+// erbElseBlock is returned by if_p and if_link to support chained else/else_if_p
+type erbElseBlock struct {
+	active bool
+	link   *erbLink // else_if_p looks up properties of a link when if_p was called on it
+}
+
+// erbTemplateError corresponds to an exception raised while evaluating a template
+type erbTemplateError struct {
+	class   string
+	message string
+	line    int
+}
+
+func (e erbTemplateError) Error() string {
+	return fmt.Sprintf("#<%s: %s>", e.class, e.message)
+}
+
+type erbBlockFunc func(args []interface{}) (interface{}, error)
+
+type erbScope struct {
+	vars   map[string]interface{}
+	parent *erbScope
+}
+
+func (s *erbScope) lookup(name string) (interface{}, bool) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if value, found := scope.vars[name]; found {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (s *erbScope) assign(name string, value interface{}) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if _, found := scope.vars[name]; found {
+			scope.vars[name] = value
+			return
+		}
+	}
+	s.vars[name] = value
+}
+
+// erbEvaluator mirrors TemplateEvaluationContext from Ruby renderer script
+type erbEvaluator struct {
+	name          interface{}
+	index         interface{}
+	spec          *erbStruct
+	rawProperties *erbHash
+	links         *erbHash
+
+	out  bytes.Buffer
+	line int
+}
+
+func newERBEvaluator(contextJSON []byte) (*erbEvaluator, error) {
+	dec := json.NewDecoder(bytes.NewReader(contextJSON))
+	dec.UseNumber()
+
+	decoded, err := decodeERBValue(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, ok := decoded.(*erbHash)
+	if !ok {
+		return nil, unsupportedERB(0, "expected context to be a hash")
+	}
+
+	ev := &erbEvaluator{links: newERBHash()}
+
+	if job, ok := spec.get("job").(*erbHash); ok {
+		ev.name = job.get("name")
+	}
+
+	ev.index = spec.get("index")
+
+	var properties1 interface{}
+
+	if spec.get("job_properties") != nil {
+		properties1 = spec.get("job_properties")
+	} else {
+		global, globalOk := spec.get("global_properties").(*erbHash)
+		cluster, clusterOk := spec.get("cluster_properties").(*erbHash)
+		if !globalOk || !clusterOk {
+			return nil, unsupportedERB(0, "expected global and cluster properties to be hashes")
+		}
+
+		properties1 = mergeERBHashes(global, cluster)
+	}
+
+	defaults, ok := spec.get("default_properties").(*erbHash)
+	if !ok {
+		return nil, unsupportedERB(0, "expected default properties to be a hash")
+	}
+
+	ev.rawProperties = newERBHash()
+
+	for _, name := range defaults.keys {
+		err := copyERBProperty(ev.rawProperties, properties1, name, defaults.get(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ev.spec = toERBStruct(spec).(*erbStruct)
+
+	if links := spec.get("links"); links != nil {
+		ev.links, ok = links.(*erbHash)
+		if !ok {
+			return nil, unsupportedERB(0, "expected links to be a hash")
+		}
+	}
+
+	return ev, nil
+}
+
+func decodeERBValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch typedTok := tok.(type) {
+	case json.Delim:
+		switch typedTok {
+		case '{':
+			hash := newERBHash()
+
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := decodeERBValue(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				hash.set(keyTok.(string), value)
+			}
+
+			_, err = dec.Token()
+
+			return hash, err
+
+		case '[':
+			array := []interface{}{}
+
+			for dec.More() {
+				value, err := decodeERBValue(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				array = append(array, value)
+			}
+
+			_, err = dec.Token()
+
+			return array, err
+		}
+
+	case json.Number:
+		if !strings.ContainsAny(string(typedTok), ".eE") {
+			value, err := typedTok.Int64()
+			if err != nil {
+				return nil, unsupportedERB(0, "integer '%s' is out of range", typedTok)
+			}
+
+			return value, nil
+		}
+
+		return typedTok.Float64()
+
+	case string, bool, nil:
+		return typedTok, nil
+	}
+
+	return nil, io.ErrUnexpectedEOF
+}
+
+// mergeERBHashes mirrors Hash#recursive_merge! without modifying given hashes
+func mergeERBHashes(dst, src *erbHash) *erbHash {
+	result := newERBHash()
+
+	for _, key := range dst.keys {
+		result.set(key, dst.get(key))
+	}
+
+	for _, key := range src.keys {
+		oldHash, oldOk := result.get(key).(*erbHash)
+		newHash, newOk := src.get(key).(*erbHash)
+
+		if oldOk && newOk {
+			result.set(key, mergeERBHashes(oldHash, newHash))
+		} else {
+			result.set(key, src.get(key))
+		}
+	}
+
+	return result
+}
+
+func copyERBProperty(dst *erbHash, src interface{}, name string, defaultValue interface{}) error {
+	keys := strings.Split(name, ".")
+
+	for _, key := range keys {
+		if len(key) == 0 {
+			return unsupportedERB(0, "unsupported property name '%s'", name)
+		}
+	}
+
+	srcRef := src
+
+	for _, key := range keys {
+		hash, ok := srcRef.(*erbHash)
+		if !ok {
+			return unsupportedERB(0, "expected property '%s' to be nested in hashes", name)
+		}
+
+		srcRef = hash.get(key)
+		if srcRef == nil {
+			break
+		}
+	}
+
+	dstRef := dst
+
+	for _, key := range keys[:len(keys)-1] {
+		if !isERBTruthy(dstRef.get(key)) {
+			dstRef.set(key, newERBHash())
+		}
+
+		hash, ok := dstRef.get(key).(*erbHash)
+		if !ok {
+			return unsupportedERB(0, "expected property '%s' to be nested in hashes", name)
+		}
+
+		dstRef = hash
+	}
+
+	if srcRef == nil {
+		srcRef = defaultValue
+	}
+
+	dstRef.set(keys[len(keys)-1], srcRef)
+
+	return nil
+}
+
+func toERBStruct(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case *erbHash:
+		fields := newERBHash()
+		for _, key := range typedValue.keys {
+			fields.set(key, toERBStruct(typedValue.get(key)))
+		}
+		return &erbStruct{fields: fields}
+
+	case []interface{}:
+		array := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			array[i] = toERBStruct(item)
+		}
+		return array
+
+	default:
+		return value
+	}
+}
+
+func (ev *erbEvaluator) render(nodes []erbNode) (string, error) {
+	err := ev.execNodes(nodes, &erbScope{vars: map[string]interface{}{}})
+	if err != nil {
+		return "", err
+	}
+
+	return ev.out.String(), nil
+}
+
+func (ev *erbEvaluator) instanceName() string {
+	name, _ := ev.toS(ev.name)
+	index, _ := ev.toS(ev.index)
+	return name + "/" + index
+}
+
+func (ev *erbEvaluator) unsupported(format string, args ...interface{}) error {
+	return unsupportedERB(ev.line, format, args...)
+}
+
+func (ev *erbEvaluator) execNodes(nodes []erbNode, scope *erbScope) error {
+	for _, node := range nodes {
+		err := ev.execNode(node, scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ev *erbEvaluator) execNode(node erbNode, scope *erbScope) error {
+	switch typedNode := node.(type) {
+	case erbTextNode:
+		ev.out.WriteString(typedNode.text)
+
+	case erbOutputNode:
+		ev.line = typedNode.line
+
+		value, err := ev.eval(typedNode.expr, scope)
+		if err != nil {
+			return err
+		}
+
+		str, err := ev.toS(value)
+		if err != nil {
+			return err
+		}
+
+		ev.out.WriteString(str)
+
+	case erbExprNode:
+		ev.line = typedNode.line
+
+		_, err := ev.eval(typedNode.expr, scope)
+		return err
+
+	case erbAssignNode:
+		ev.line = typedNode.line
+
+		value, err := ev.eval(typedNode.expr, scope)
+		if err != nil {
+			return err
+		}
+
+		scope.assign(typedNode.name, value)
+
+	case erbRaiseNode:
+		ev.line = typedNode.line
+
+		message, err := ev.eval(typedNode.message, scope)
+		if err != nil {
+			return err
+		}
+
+		str, ok := message.(string)
+		if !ok {
+			return ev.unsupported("raise with non-string argument")
+		}
+
+		return erbTemplateError{class: "RuntimeError", message: str, line: ev.line}
+
+	case *erbIfNode:
+		for _, branch := range typedNode.branches {
+			ev.line = branch.line
+
+			cond, err := ev.eval(branch.cond, scope)
+			if err != nil {
+				return err
+			}
+
+			if isERBTruthy(cond) != branch.negate {
+				return ev.execNodes(branch.body, scope)
+			}
+		}
+
+		return ev.execNodes(typedNode.elseBody, scope)
+
+	case *erbBlockNode:
+		ev.line = typedNode.line
+
+		result, err := ev.evalCall(typedNode.call, ev.nodesBlock(typedNode.params, typedNode.body, scope), scope)
+		if err != nil {
+			return err
+		}
+
+		for _, elseNode := range typedNode.elses {
+			ev.line = elseNode.line
+
+			elseBlock, ok := result.(*erbElseBlock)
+			if !ok {
+				return ev.unsupported("undefined method '%s'", elseNode.name)
+			}
+
+			block := ev.nodesBlock(elseNode.params, elseNode.body, scope)
+
+			if elseNode.name == "else" {
+				result = nil
+
+				if elseBlock.active {
+					_, err := block(nil)
+					if err != nil {
+						return err
+					}
+				}
+
+				continue
+			}
+
+			result = &erbElseBlock{}
+
+			if elseBlock.active {
+				args, err := ev.evalArgs(elseNode.args, scope)
+				if err != nil {
+					return err
+				}
+
+				result, err = ev.ifP(elseBlock.link, args, block)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+	default:
+		return ev.unsupported("unknown node")
+	}
+
+	return nil
+}
+
+func (ev *erbEvaluator) nodesBlock(params []string, body []erbNode, scope *erbScope) erbBlockFunc {
+	return func(args []interface{}) (interface{}, error) {
+		blockScope := &erbScope{vars: bindERBBlockParams(params, args), parent: scope}
+		return nil, ev.execNodes(body, blockScope)
+	}
+}
+
+func (ev *erbEvaluator) exprBlock(block *erbExprBlock, scope *erbScope) erbBlockFunc {
+	return func(args []interface{}) (interface{}, error) {
+		blockScope := &erbScope{vars: bindERBBlockParams(block.params, args), parent: scope}
+		return ev.eval(block.body, blockScope)
+	}
+}
+
+func (ev *erbEvaluator) symBlock(name string) erbBlockFunc {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) == 0 {
+			return nil, ev.unsupported("block without arguments")
+		}
+		return ev.callMethod(args[0], name, nil, nil)
+	}
+}
+
+// bindERBBlockParams follows Ruby's block semantics: single array argument
+// is destructured when block has multiple parameters; missing ones are nil
+func bindERBBlockParams(params []string, args []interface{}) map[string]interface{} {
+	if len(args) == 1 && len(params) > 1 {
+		if array, ok := args[0].([]interface{}); ok {
+			args = array
+		}
+	}
+
+	vars := map[string]interface{}{}
+
+	for i, param := range params {
+		if i < len(args) {
+			vars[param] = args[i]
+		} else {
+			vars[param] = nil
+		}
+	}
+
+	return vars
+}
+
+func (ev *erbEvaluator) eval(expr erbExpr, scope *erbScope) (interface{}, error) {
+	switch typedExpr := expr.(type) {
+	case erbLiteralExpr:
+		return typedExpr.value, nil
+
+	case erbStringExpr:
+		var result strings.Builder
+
+		for _, part := range typedExpr.parts {
+			value, err := ev.eval(part, scope)
+			if err != nil {
+				return nil, err
+			}
+
+			str, err := ev.toS(value)
+			if err != nil {
+				return nil, err
+			}
+
+			result.WriteString(str)
+		}
+
+		return result.String(), nil
+
+	case erbArrayExpr:
+		array := []interface{}{}
+
+		for _, item := range typedExpr.items {
+			value, err := ev.eval(item, scope)
+			if err != nil {
+				return nil, err
+			}
+
+			array = append(array, value)
+		}
+
+		return array, nil
+
+	case erbIdentExpr:
+		if value, found := scope.lookup(typedExpr.name); found {
+			return value, nil
+		}
+
+		return ev.callFunction(typedExpr.name, nil, nil)
+
+	case erbConstExpr:
+		if typedExpr.name == "JSON" {
+			return typedExpr, nil
+		}
+
+		return nil, ev.unsupported("uninitialized constant %s", typedExpr.name)
+
+	case *erbCallExpr:
+		var block erbBlockFunc
+
+		if typedExpr.block != nil {
+			block = ev.exprBlock(typedExpr.block, scope)
+		} else if typedExpr.symBlock != "" {
+			block = ev.symBlock(typedExpr.symBlock)
+		}
+
+		return ev.evalCall(typedExpr, block, scope)
+
+	case erbIndexExpr:
+		receiver, err := ev.eval(typedExpr.receiver, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := ev.eval(typedExpr.index, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return ev.callMethod(receiver, "[]", []interface{}{index}, nil)
+
+	case erbUnaryExpr:
+		operand, err := ev.eval(typedExpr.operand, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if typedExpr.op == "!" {
+			return !isERBTruthy(operand), nil
+		}
+
+		if i, ok := operand.(int64); ok {
+			return -i, nil
+		}
+
+		if f, ok := operand.(float64); ok {
+			return -f, nil
+		}
+
+		return nil, ev.unsupported("unary minus on %s", erbTypeName(operand))
+
+	case erbBinaryExpr:
+		left, err := ev.eval(typedExpr.left, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		switch typedExpr.op {
+		case "&&":
+			if !isERBTruthy(left) {
+				return left, nil
+			}
+			return ev.eval(typedExpr.right, scope)
+
+		case "||":
+			if isERBTruthy(left) {
+				return left, nil
+			}
+			return ev.eval(typedExpr.right, scope)
+		}
+
+		right, err := ev.eval(typedExpr.right, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return ev.binaryOp(typedExpr.op, left, right)
+
+	case erbTernaryExpr:
+		cond, err := ev.eval(typedExpr.cond, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if isERBTruthy(cond) {
+			return ev.eval(typedExpr.ifTrue, scope)
+		}
+
+		return ev.eval(typedExpr.ifFalse, scope)
+	}
+
+	return nil, ev.unsupported("unknown expression")
+}
+
+func (ev *erbEvaluator) evalArgs(exprs []erbExpr, scope *erbScope) ([]interface{}, error) {
+	var args []interface{}
+
+	for _, expr := range exprs {
+		value, err := ev.eval(expr, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	return args, nil
+}
+
+func (ev *erbEvaluator) evalCall(call *erbCallExpr, block erbBlockFunc, scope *erbScope) (interface{}, error) {
+	args, err := ev.evalArgs(call.args, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if call.receiver == nil {
+		return ev.callFunction(call.name, args, block)
+	}
+
+	receiver, err := ev.eval(call.receiver, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return ev.callMethod(receiver, call.name, args, block)
+}
+
+// callFunction handles methods of template evaluation context
+func (ev *erbEvaluator) callFunction(name string, args []interface{}, block erbBlockFunc) (interface{}, error) {
+	switch name {
+	case "p":
+		return ev.p(ev.rawProperties, args)
+
+	case "if_p":
+		if block == nil {
+			return nil, ev.unsupported("if_p without block")
+		}
+		return ev.ifP(nil, args, block)
+
+	case "link", "if_link":
+		if len(args) != 1 {
+			return nil, ev.unsupported("wrong number of arguments to %s", name)
+		}
+
+		linkName, ok := args[0].(string)
+		if !ok {
+			return nil, ev.unsupported("non-string link name")
+		}
+
+		if !ev.links.has(linkName) || ev.links.get(linkName) == nil {
+			if name == "if_link" {
+				return &erbElseBlock{active: true}, nil
+			}
+
+			return nil, erbTemplateError{
+				class:   "TemplateEvaluationContext::UnknownLink",
+				message: fmt.Sprintf("Can't find link '%s'", linkName),
+				line:    ev.line,
+			}
+		}
+
+		link, err := ev.buildLink(ev.links.get(linkName))
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "link" {
+			return link, nil
+		}
+
+		if block == nil {
+			return nil, ev.unsupported("if_link without block")
+		}
+
+		_, err = block([]interface{}{link})
+		if err != nil {
+			return nil, err
+		}
+
+		return &erbElseBlock{}, nil
+	}
+
+	if len(args) > 0 || block != nil {
+		return nil, ev.unsupported("undefined method '%s'", name)
+	}
+
+	switch name {
+	case "spec":
+		return ev.spec, nil
+	case "name":
+		return ev.name, nil
+	case "index":
+		return ev.index, nil
+	case "properties":
+		return toERBStruct(ev.rawProperties), nil
+	case "raw_properties":
+		return ev.rawProperties, nil
+	}
+
+	return nil, ev.unsupported("undefined local variable or method '%s'", name)
+}
+
+func (ev *erbEvaluator) buildLink(spec interface{}) (*erbLink, error) {
+	hash, ok := spec.(*erbHash)
+	if !ok {
+		return nil, ev.unsupported("expected link to be a hash")
+	}
+
+	instances, ok := hash.get("instances").([]interface{})
+	if !ok {
+		return nil, ev.unsupported("expected link instances to be an array")
+	}
+
+	link := &erbLink{address: hash.get("address"), properties: hash.get("properties")}
+
+	for _, instance := range instances {
+		instanceHash, ok := instance.(*erbHash)
+		if !ok {
+			return nil, ev.unsupported("expected link instance to be a hash")
+		}
+
+		link.instances = append(link.instances, &erbStruct{fields: instanceHash})
+	}
+
+	return link, nil
+}
+
+func (ev *erbEvaluator) propertyNames(arg interface{}) ([]string, error) {
+	switch typedArg := arg.(type) {
+	case string:
+		return []string{typedArg}, nil
+
+	case []interface{}:
+		var names []string
+
+		for _, item := range typedArg {
+			name, ok := item.(string)
+			if !ok {
+				return nil, ev.unsupported("non-string property name")
+			}
+
+			names = append(names, name)
+		}
+
+		return names, nil
+	}
+
+	return nil, ev.unsupported("non-string property name")
+}
+
+func (ev *erbEvaluator) lookupProperty(collection interface{}, name string) (interface{}, error) {
+	ref := collection
+
+	for _, key := range strings.Split(name, ".") {
+		hash, ok := ref.(*erbHash)
+		if !ok {
+			return nil, ev.unsupported("looking up property '%s' in %s", name, erbTypeName(ref))
+		}
+
+		ref = hash.get(key)
+		if ref == nil {
+			return nil, nil
+		}
+	}
+
+	return ref, nil
+}
+
+func (ev *erbEvaluator) p(collection interface{}, args []interface{}) (interface{}, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, ev.unsupported("wrong number of arguments to p")
+	}
+
+	names, err := ev.propertyNames(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		result, err := ev.lookupProperty(collection, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	if len(args) == 2 {
+		return args[1], nil
+	}
+
+	return nil, erbTemplateError{
+		class:   "TemplateEvaluationContext::UnknownProperty",
+		message: fmt.Sprintf("Can't find property '%s'", strings.Join(names, "', or '")),
+		line:    ev.line,
+	}
+}
+
+func (ev *erbEvaluator) ifP(link *erbLink, args []interface{}, block erbBlockFunc) (interface{}, error) {
+	var collection interface{} = ev.rawProperties
+	if link != nil {
+		collection = link.properties
+	}
+
+	var values []interface{}
+
+	for _, arg := range args {
+		name, ok := arg.(string)
+		if !ok {
+			return nil, ev.unsupported("non-string property name")
+		}
+
+		value, err := ev.lookupProperty(collection, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			return &erbElseBlock{active: true, link: link}, nil
+		}
+
+		values = append(values, value)
+	}
+
+	_, err := block(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &erbElseBlock{}, nil
+}
+
+func (ev *erbEvaluator) callMethod(receiver interface{}, name string, args []interface{}, block erbBlockFunc) (interface{}, error) {
+	if name == "nil?" && len(args) == 0 && block == nil {
+		return receiver == nil, nil
+	}
+
+	if (name == "to_s" || name == "to_json") && len(args) == 0 && block == nil {
+		if _, isStruct := receiver.(*erbStruct); !isStruct {
+			if name == "to_s" {
+				return ev.toS(receiver)
+			}
+			return ev.toJSON(receiver)
+		}
+	}
+
+	var result interface{}
+	var found bool
+	var err error
+
+	switch typedReceiver := receiver.(type) {
+	case nil:
+		result, found, err = ev.callNilMethod(name, args, block)
+	case string:
+		result, found, err = ev.callStringMethod(typedReceiver, name, args, block)
+	case int64:
+		result, found, err = ev.callIntMethod(typedReceiver, name, args, block)
+	case float64:
+		if name == "to_i" && len(args) == 0 && block == nil {
+			result, found = int64(typedReceiver), true
+		}
+	case bool:
+	case []interface{}:
+		result, found, err = ev.callArrayMethod(typedReceiver, name, args, block)
+	case *erbHash:
+		result, found, err = ev.callHashMethod(typedReceiver, name, args, block)
+	case *erbStruct:
+		result, found, err = ev.callStructMethod(typedReceiver, name, args, block)
+	case *erbLink:
+		result, found, err = ev.callLinkMethod(typedReceiver, name, args, block)
+	case erbConstExpr:
+		if (name == "dump" || name == "generate") && len(args) == 1 && block == nil {
+			if str, ok := args[0].(string); ok && name == "dump" {
+				return ev.inspectString(str)
+			}
+			return ev.toJSON(args[0])
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, ev.unsupported("undefined method '%s' for %s", name, erbTypeName(receiver))
+	}
+
+	return result, nil
+}
+
+func (ev *erbEvaluator) callNilMethod(name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	if len(args) > 0 || block != nil {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "to_a":
+		return []interface{}{}, true, nil
+	case "to_i":
+		return int64(0), true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (ev *erbEvaluator) callStringMethod(str string, name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	if block != nil {
+		return nil, false, nil
+	}
+
+	if len(args) == 0 {
+		switch name {
+		case "to_str":
+			return str, true, nil
+		case "to_i":
+			return parseERBLeadingInt(str), true, nil
+		case "upcase":
+			if !isERBASCII(str) {
+				return nil, false, nil
+			}
+			return strings.ToUpper(str), true, nil
+		case "downcase":
+			if !isERBASCII(str) {
+				return nil, false, nil
+			}
+			return strings.ToLower(str), true, nil
+		case "strip":
+			return strings.Trim(str, erbWhitespace), true, nil
+		case "lstrip":
+			return strings.TrimLeft(str, erbWhitespace), true, nil
+		case "rstrip":
+			return strings.TrimRight(str, erbWhitespace), true, nil
+		case "chomp":
+			for _, suffix := range []string{"\r\n", "\n", "\r"} {
+				if strings.HasSuffix(str, suffix) {
+					return strings.TrimSuffix(str, suffix), true, nil
+				}
+			}
+			return str, true, nil
+		case "empty?":
+			return len(str) == 0, true, nil
+		case "length", "size":
+			return int64(utf8.RuneCountInString(str)), true, nil
+		case "split":
+			return toERBArray(strings.Fields(str)), true, nil
+		}
+
+		return nil, false, nil
+	}
+
+	strArgs, ok := erbStrings(args)
+	if !ok {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "include?":
+		if len(strArgs) == 1 {
+			return strings.Contains(str, strArgs[0]), true, nil
+		}
+	case "start_with?":
+		for _, prefix := range strArgs {
+			if strings.HasPrefix(str, prefix) {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+	case "end_with?":
+		for _, suffix := range strArgs {
+			if strings.HasSuffix(str, suffix) {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+	case "split":
+		if len(strArgs) == 1 && strArgs[0] == " " {
+			return toERBArray(strings.Fields(str)), true, nil
+		}
+
+		if len(strArgs) == 1 && len(strArgs[0]) > 0 {
+			parts := strings.Split(str, strArgs[0])
+
+			// Ruby drops trailing empty strings
+			for len(parts) > 0 && parts[len(parts)-1] == "" {
+				parts = parts[:len(parts)-1]
+			}
+
+			return toERBArray(parts), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+const erbWhitespace = " \t\n\v\f\r\x00"
+
+func (ev *erbEvaluator) callIntMethod(i int64, name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	if len(args) > 0 {
+		return nil, false, nil
+	}
+
+	if name == "times" && block != nil {
+		for j := int64(0); j < i; j++ {
+			_, err := block([]interface{}{j})
+			if err != nil {
+				return nil, true, err
+			}
+		}
+
+		return i, true, nil
+	}
+
+	if block != nil {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "to_i":
+		return i, true, nil
+	case "zero?":
+		return i == 0, true, nil
+	case "even?":
+		return i%2 == 0, true, nil
+	case "odd?":
+		return i%2 != 0, true, nil
+	case "abs":
+		if i < 0 {
+			return -i, true, nil
+		}
+		return i, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (ev *erbEvaluator) callArrayMethod(array []interface{}, name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	if block != nil {
+		if len(args) > 0 {
+			return nil, false, nil
+		}
+
+		return ev.callArrayBlockMethod(array, name, block)
+	}
+
+	switch name {
+	case "[]":
+		if len(args) != 1 {
+			return nil, false, nil
+		}
+
+		index, ok := args[0].(int64)
+		if !ok {
+			return nil, false, nil
+		}
+
+		if index < 0 {
+			index += int64(len(array))
+		}
+
+		if index < 0 || index >= int64(len(array)) {
+			return nil, true, nil
+		}
+
+		return array[index], true, nil
+
+	case "include?":
+		if len(args) != 1 {
+			return nil, false, nil
+		}
+
+		for _, item := range array {
+			if equal, err := ev.equal(item, args[0]); err != nil || equal {
+				return equal, true, err
+			}
+		}
+
+		return false, true, nil
+
+	case "join":
+		sep := ""
+
+		if len(args) == 1 {
+			str, ok := args[0].(string)
+			if !ok {
+				return nil, false, nil
+			}
+
+			sep = str
+		} else if len(args) > 1 {
+			return nil, false, nil
+		}
+
+		str, err := ev.join(array, sep)
+
+		return str, true, err
+	}
+
+	if len(args) > 0 {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "first":
+		if len(array) == 0 {
+			return nil, true, nil
+		}
+		return array[0], true, nil
+
+	case "last":
+		if len(array) == 0 {
+			return nil, true, nil
+		}
+		return array[len(array)-1], true, nil
+
+	case "length", "size", "count":
+		return int64(len(array)), true, nil
+
+	case "empty?":
+		return len(array) == 0, true, nil
+
+	case "any?":
+		for _, item := range array {
+			if isERBTruthy(item) {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+
+	case "to_a":
+		return array, true, nil
+
+	case "compact":
+		result := []interface{}{}
+		for _, item := range array {
+			if item != nil {
+				result = append(result, item)
+			}
+		}
+		return result, true, nil
+
+	case "reverse":
+		result := make([]interface{}, len(array))
+		for i, item := range array {
+			result[len(array)-1-i] = item
+		}
+		return result, true, nil
+
+	case "flatten":
+		return flattenERBArray(array), true, nil
+
+	case "uniq":
+		result := []interface{}{}
+
+		for _, item := range array {
+			seen := false
+
+			for _, existing := range result {
+				equal, err := ev.equal(existing, item)
+				if err != nil {
+					return nil, true, err
+				}
+
+				if equal {
+					seen = true
+					break
+				}
+			}
+
+			if !seen {
+				result = append(result, item)
+			}
+		}
+
+		return result, true, nil
+
+	case "sort":
+		return ev.sort(array)
+	}
+
+	return nil, false, nil
+}
+
+func (ev *erbEvaluator) callArrayBlockMethod(array []interface{}, name string, block erbBlockFunc) (interface{}, bool, error) {
+	switch name {
+	case "each":
+		for _, item := range array {
+			_, err := block([]interface{}{item})
+			if err != nil {
+				return nil, true, err
+			}
+		}
+		return array, true, nil
+
+	case "each_with_index":
+		for i, item := range array {
+			_, err := block([]interface{}{item, int64(i)})
+			if err != nil {
+				return nil, true, err
+			}
+		}
+		return array, true, nil
+
+	case "map", "collect":
+		result := []interface{}{}
+		for _, item := range array {
+			value, err := block([]interface{}{item})
+			if err != nil {
+				return nil, true, err
+			}
+			result = append(result, value)
+		}
+		return result, true, nil
+
+	case "select", "filter", "reject":
+		result := []interface{}{}
+		for _, item := range array {
+			value, err := block([]interface{}{item})
+			if err != nil {
+				return nil, true, err
+			}
+			if isERBTruthy(value) == (name != "reject") {
+				result = append(result, item)
+			}
+		}
+		return result, true, nil
+
+	case "find", "detect":
+		for _, item := range array {
+			value, err := block([]interface{}{item})
+			if err != nil {
+				return nil, true, err
+			}
+			if isERBTruthy(value) {
+				return item, true, nil
+			}
+		}
+		return nil, true, nil
+
+	case "any?", "all?", "none?":
+		for _, item := range array {
+			value, err := block([]interface{}{item})
+			if err != nil {
+				return nil, true, err
+			}
+
+			switch {
+			case name == "any?" && isERBTruthy(value):
+				return true, true, nil
+			case name == "all?" && !isERBTruthy(value):
+				return false, true, nil
+			case name == "none?" && isERBTruthy(value):
+				return false, true, nil
+			}
+		}
+		return name != "any?", true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (ev *erbEvaluator) callHashMethod(hash *erbHash, name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	if block != nil {
+		if len(args) > 0 {
+			return nil, false, nil
+		}
+
+		switch name {
+		case "each", "each_pair":
+			for _, key := range hash.keys {
+				_, err := block([]interface{}{[]interface{}{key, hash.get(key)}})
+				if err != nil {
+					return nil, true, err
+				}
+			}
+			return hash, true, nil
+
+		case "map", "collect", "each_with_index", "find", "detect", "any?", "all?", "none?":
+			return ev.callArrayBlockMethod(ev.hashPairs(hash), name, block)
+		}
+
+		return nil, false, nil
+	}
+
+	switch name {
+	case "[]", "key?", "has_key?", "include?", "member?":
+		if len(args) != 1 {
+			return nil, false, nil
+		}
+
+		key, ok := args[0].(string)
+		if !ok {
+			return nil, false, nil
+		}
+
+		if name == "[]" {
+			return hash.get(key), true, nil
+		}
+
+		return hash.has(key), true, nil
+
+	case "fetch":
+		if len(args) != 1 && len(args) != 2 {
+			return nil, false, nil
+		}
+
+		key, ok := args[0].(string)
+		if !ok {
+			return nil, false, nil
+		}
+
+		if hash.has(key) {
+			return hash.get(key), true, nil
+		}
+
+		if len(args) == 2 {
+			return args[1], true, nil
+		}
+
+		return nil, false, nil
+
+	case "dig":
+		keys, ok := erbStrings(args)
+		if !ok || len(keys) == 0 {
+			return nil, false, nil
+		}
+
+		var ref interface{} = hash
+
+		for _, key := range keys {
+			refHash, ok := ref.(*erbHash)
+			if !ok {
+				if ref == nil {
+					return nil, true, nil
+				}
+				return nil, false, nil
+			}
+
+			ref = refHash.get(key)
+		}
+
+		return ref, true, nil
+	}
+
+	if len(args) > 0 {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "keys":
+		return toERBArray(hash.keys), true, nil
+
+	case "values":
+		values := []interface{}{}
+		for _, key := range hash.keys {
+			values = append(values, hash.get(key))
+		}
+		return values, true, nil
+
+	case "length", "size", "count":
+		return int64(len(hash.keys)), true, nil
+
+	case "empty?":
+		return len(hash.keys) == 0, true, nil
+
+	case "any?":
+		return len(hash.keys) > 0, true, nil
+
+	case "to_a":
+		return ev.hashPairs(hash), true, nil
+
+	case "to_h", "to_hash":
+		return hash, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (ev *erbEvaluator) hashPairs(hash *erbHash) []interface{} {
+	pairs := []interface{}{}
+	for _, key := range hash.keys {
+		pairs = append(pairs, []interface{}{key, hash.get(key)})
+	}
+	return pairs
+}
+
+// erbObjectMethods are defined on every Ruby object, so OpenStruct does not
+// return nil for them and they are not supported on structs
+var erbObjectMethods = map[string]bool{
+	"to_h": true, "to_a": true, "to_yaml": true, "inspect": true, "each": true, "each_pair": true,
+	"map": true, "keys": true, "values": true, "class": true, "method": true, "methods": true,
+	"send": true, "respond_to?": true, "is_a?": true, "kind_of?": true, "instance_of?": true,
+	"dig": true, "hash": true, "freeze": true, "frozen?": true, "dup": true, "clone": true,
+	"display": true, "tap": true, "then": true, "itself": true, "object_id": true, "equal?": true,
+	"eql?": true, "instance_variables": true, "table": true, "delete_field": true, "p": true,
+}
+
+func (ev *erbEvaluator) callStructMethod(st *erbStruct, name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	if block != nil {
+		return nil, false, nil
+	}
+
+	if name == "[]" && len(args) == 1 {
+		key, ok := args[0].(string)
+		if !ok {
+			return nil, false, nil
+		}
+
+		return st.fields.get(key), true, nil
+	}
+
+	if len(args) > 0 {
+		return nil, false, nil
+	}
+
+	if st.fields.has(name) {
+		return st.fields.get(name), true, nil
+	}
+
+	if erbObjectMethods[name] || strings.HasSuffix(name, "?") || strings.HasSuffix(name, "!") || strings.HasPrefix(name, "to_") {
+		return nil, false, nil
+	}
+
+	return nil, true, nil
+}
+
+func (ev *erbEvaluator) callLinkMethod(link *erbLink, name string, args []interface{}, block erbBlockFunc) (interface{}, bool, error) {
+	switch name {
+	case "p":
+		if block != nil {
+			return nil, false, nil
+		}
+
+		result, err := ev.p(link.properties, args)
+
+		return result, true, err
+
+	case "if_p":
+		if block == nil {
+			return nil, false, nil
+		}
+
+		result, err := ev.ifP(link, args, block)
+
+		return result, true, err
+	}
+
+	if len(args) > 0 || block != nil {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "address":
+		return link.address, true, nil
+	case "instances":
+		return link.instances, true, nil
+	case "properties":
+		return link.properties, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (ev *erbEvaluator) binaryOp(op string, left, right interface{}) (interface{}, error) {
+	switch op {
+	case "==", "!=":
+		equal, err := ev.equal(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return equal == (op == "=="), nil
+
+	case "<", "<=", ">", ">=":
+		cmp, err := ev.compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+
+	switch typedLeft := left.(type) {
+	case int64:
+		typedRight, ok := right.(int64)
+		if !ok {
+			break
+		}
+
+		switch op {
+		case "+":
+			return typedLeft + typedRight, nil
+		case "-":
+			return typedLeft - typedRight, nil
+		case "*":
+			return typedLeft * typedRight, nil
+		case "/", "%":
+			if typedRight == 0 {
+				break
+			}
+
+			// Ruby rounds integer division towards negative infinity
+			quotient := typedLeft / typedRight
+			if (typedLeft%typedRight != 0) && ((typedLeft < 0) != (typedRight < 0)) {
+				quotient--
+			}
+
+			if op == "/" {
+				return quotient, nil
+			}
+
+			return typedLeft - quotient*typedRight, nil
+		}
+
+	case string:
+		if typedRight, ok := right.(string); ok && op == "+" {
+			return typedLeft + typedRight, nil
+		}
+
+		if typedRight, ok := right.(int64); ok && op == "*" && typedRight >= 0 {
+			return strings.Repeat(typedLeft, int(typedRight)), nil
+		}
+
+	case []interface{}:
+		if typedRight, ok := right.([]interface{}); ok && op == "+" {
+			result := append([]interface{}{}, typedLeft...)
+			return append(result, typedRight...), nil
+		}
+	}
+
+	return nil, ev.unsupported("'%s' for %s and %s", op, erbTypeName(left), erbTypeName(right))
+}
+
+func (ev *erbEvaluator) equal(left, right interface{}) (bool, error) {
+	switch typedLeft := left.(type) {
+	case nil, bool, string:
+		return left == right, nil
+
+	case int64:
+		switch typedRight := right.(type) {
+		case int64:
+			return typedLeft == typedRight, nil
+		case float64:
+			return float64(typedLeft) == typedRight, nil
+		}
+		return false, nil
+
+	case float64:
+		switch typedRight := right.(type) {
+		case int64:
+			return typedLeft == float64(typedRight), nil
+		case float64:
+			return typedLeft == typedRight, nil
+		}
+		return false, nil
+
+	case []interface{}:
+		typedRight, ok := right.([]interface{})
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false, nil
+		}
+
+		for i := range typedLeft {
+			equal, err := ev.equal(typedLeft[i], typedRight[i])
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+
+		return true, nil
+
+	case *erbHash:
+		typedRight, ok := right.(*erbHash)
+		if !ok || len(typedLeft.keys) != len(typedRight.keys) {
+			return false, nil
+		}
+
+		for _, key := range typedLeft.keys {
+			if !typedRight.has(key) {
+				return false, nil
+			}
+
+			equal, err := ev.equal(typedLeft.get(key), typedRight.get(key))
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}
+
+	return false, ev.unsupported("comparing %s", erbTypeName(left))
+}
+
+func (ev *erbEvaluator) compare(left, right interface{}) (int, error) {
+	switch typedLeft := left.(type) {
+	case int64, float64:
+		leftFloat := toERBFloat(typedLeft)
+
+		switch right.(type) {
+		case int64, float64:
+			rightFloat := toERBFloat(right)
+
+			switch {
+			case leftFloat < rightFloat:
+				return -1, nil
+			case leftFloat > rightFloat:
+				return 1, nil
+			}
+
+			return 0, nil
+		}
+
+	case string:
+		if typedRight, ok := right.(string); ok {
+			return strings.Compare(typedLeft, typedRight), nil
+		}
+	}
+
+	return 0, ev.unsupported("comparison of %s with %s", erbTypeName(left), erbTypeName(right))
+}
+
+func (ev *erbEvaluator) sort(array []interface{}) (interface{}, bool, error) {
+	result := append([]interface{}{}, array...)
+
+	var err error
+
+	sort.SliceStable(result, func(i, j int) bool {
+		cmp, cmpErr := ev.compare(result[i], result[j])
+		if cmpErr != nil {
+			err = cmpErr
+		}
+		return cmp < 0
+	})
+
+	return result, true, err
+}
+
+func (ev *erbEvaluator) join(array []interface{}, sep string) (string, error) {
+	var parts []string
+
+	for _, item := range array {
+		var str string
+		var err error
+
+		if nested, ok := item.([]interface{}); ok {
+			str, err = ev.join(nested, sep)
+		} else {
+			str, err = ev.toS(item)
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, str)
+	}
+
+	return strings.Join(parts, sep), nil
+}
+
+// toS converts values similarly to Ruby's to_s; inspect-like formats of
+// collections differ between Ruby versions so they are not supported
+func (ev *erbEvaluator) toS(value interface{}) (string, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typedValue, nil
+	case bool:
+		return strconv.FormatBool(typedValue), nil
+	case int64:
+		return strconv.FormatInt(typedValue, 10), nil
+	case float64:
+		return ev.formatFloat(typedValue)
+	}
+
+	return "", ev.unsupported("converting %s to string", erbTypeName(value))
+}
+
+func (ev *erbEvaluator) formatFloat(f float64) (string, error) {
+	abs := f
+	if abs < 0 {
+		abs = -abs
+	}
+
+	// Ruby switches to scientific notation for large and small numbers
+	if abs >= 1e16 || (abs != 0 && abs < 1e-4) {
+		return "", ev.unsupported("formatting float %v", f)
+	}
+
+	str := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(str, ".") {
+		str += ".0"
+	}
+
+	return str, nil
+}
+
+// toJSON produces same output as Ruby's JSON.generate
+func (ev *erbEvaluator) toJSON(value interface{}) (string, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return "null", nil
+
+	case bool, int64, float64:
+		return ev.toS(typedValue)
+
+	case string:
+		if !utf8.ValidString(typedValue) {
+			return "", ev.unsupported("invalid UTF-8 string")
+		}
+
+		var result strings.Builder
+
+		result.WriteByte('"')
+
+		for _, r := range typedValue {
+			switch r {
+			case '"':
+				result.WriteString(`\"`)
+			case '\\':
+				result.WriteString(`\\`)
+			case '\n':
+				result.WriteString(`\n`)
+			case '\r':
+				result.WriteString(`\r`)
+			case '\t':
+				result.WriteString(`\t`)
+			case '\b':
+				result.WriteString(`\b`)
+			case '\f':
+				result.WriteString(`\f`)
+			default:
+				if r < 0x20 {
+					fmt.Fprintf(&result, `\u%04x`, r)
+				} else {
+					result.WriteRune(r)
+				}
+			}
+		}
+
+		result.WriteByte('"')
+
+		return result.String(), nil
+
+	case []interface{}:
+		var items []string
+
+		for _, item := range typedValue {
+			str, err := ev.toJSON(item)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, str)
+		}
+
+		return "[" + strings.Join(items, ",") + "]", nil
+
+	case *erbHash:
+		var items []string
+
+		for _, key := range typedValue.keys {
+			keyStr, err := ev.toJSON(key)
+			if err != nil {
+				return "", err
+			}
+
+			valueStr, err := ev.toJSON(typedValue.get(key))
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, keyStr+":"+valueStr)
+		}
+
+		return "{" + strings.Join(items, ",") + "}", nil
+	}
+
+	return "", ev.unsupported("converting %s to JSON", erbTypeName(value))
+}
+
+// inspectString produces same output as Ruby's String#inspect
+// which is used by renderer script's JSON.dump for strings
+func (ev *erbEvaluator) inspectString(str string) (string, error) {
+	if !utf8.ValidString(str) {
+		return "", ev.unsupported("invalid UTF-8 string")
+	}
+
+	var result strings.Builder
+
+	result.WriteByte('"')
+
+	for i, r := range str {
+		switch r {
+		case '"':
+			result.WriteString(`\"`)
+		case '\\':
+			result.WriteString(`\\`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\r':
+			result.WriteString(`\r`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\f':
+			result.WriteString(`\f`)
+		case '\v':
+			result.WriteString(`\v`)
+		case '\b':
+			result.WriteString(`\b`)
+		case '\a':
+			result.WriteString(`\a`)
+		case '\x1b':
+			result.WriteString(`\e`)
+		case '#':
+			if i+1 < len(str) && (str[i+1] == '{' || str[i+1] == '$' || str[i+1] == '@') {
+				result.WriteString(`\#`)
+			} else {
+				result.WriteRune(r)
+			}
+		default:
+			if !unicode.IsPrint(r) {
+				return "", ev.unsupported("inspecting string with non-printable characters")
+			}
+
+			result.WriteRune(r)
+		}
+	}
+
+	result.WriteByte('"')
+
+	return result.String(), nil
+}
+
+func isERBTruthy(value interface{}) bool {
+	return value != nil && value != false
+}
+
+func erbTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return "String"
+	case bool:
+		return "Boolean"
+	case int64:
+		return "Integer"
+	case float64:
+		return "Float"
+	case []interface{}:
+		return "Array"
+	case *erbHash:
+		return "Hash"
+	case *erbStruct:
+		return "OpenStruct"
+	case *erbLink:
+		return "EvaluationLink"
+	case *erbElseBlock:
+		return "ElseBlock"
+	case erbConstExpr:
+		return "JSON"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func erbStrings(args []interface{}) ([]string, bool) {
+	var strs []string
+
+	for _, arg := range args {
+		str, ok := arg.(string)
+		if !ok {
+			return nil, false
+		}
+
+		strs = append(strs, str)
+	}
+
+	return strs, true
+}
+
+func toERBArray(strs []string) []interface{} {
+	array := []interface{}{}
+	for _, str := range strs {
+		array = append(array, str)
+	}
+	return array
+}
+
+func flattenERBArray(array []interface{}) []interface{} {
+	result := []interface{}{}
+
+	for _, item := range array {
+		if nested, ok := item.([]interface{}); ok {
+			result = append(result, flattenERBArray(nested)...)
+		} else {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func toERBFloat(value interface{}) float64 {
+	if i, ok := value.(int64); ok {
+		return float64(i)
+	}
+	return value.(float64)
+}
+
+func isERBASCII(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// parseERBLeadingInt mirrors String#to_i which ignores leading whitespace and trailing garbage
+func parseERBLeadingInt(str string) int64 {
+	str = strings.TrimLeft(str, " \t\n\v\f\r")
+
+	end := 0
+	if end < len(str) && (str[end] == '-' || str[end] == '+') {
+		end++
+	}
+
+	for end < len(str) && isERBDigit(str[end]) {
+		end++
+	}
+
+	value, _ := strconv.ParseInt(str[:end], 10, 64)
+
+	return value
+}
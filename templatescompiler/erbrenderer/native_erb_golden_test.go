@@ -0,0 +1,179 @@
+package erbrenderer_test
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
+)
+
+// nativeERBGoldenTemplates pin native renderer to output of renderer script;
+// when ruby is available outputs are also checked against renderer script
+var nativeERBGoldenTemplates = []struct {
+	name     string
+	template string
+	output   string
+}{
+	{
+		name:     "properties",
+		template: `<%= p("port") %> <%= p("ratio") %> <%= p("tls.enabled") %> <%= p("tls.cert", nil).nil? %> <%= p(["missing", "name"]) %>`,
+		output:   "8080 1.5 true true fake-name",
+	},
+	{
+		name:     "integer arithmetic",
+		template: `<%= 7 / 2 %> <%= -7 / 2 %> <%= 7 % 3 %> <%= -7 % 3 %> <%= p("port") - 80 * 2 %>`,
+		output:   "3 -4 1 2 7920",
+	},
+	{
+		name:     "floats",
+		template: `<%= 0.1 %> <%= 1234567.0 %> <%= 0.0001 %> <%= 2.50 %>`,
+		output:   "0.1 1234567.0 0.0001 2.5",
+	},
+	{
+		name:     "comparisons and logic",
+		template: `<%= 1 == 1.0 %> <%= "a" < "b" %> <%= nil || "x" %> <%= (1 > 2) ? "y" : "n" %> <%= !p("users").empty? and true %>`,
+		output:   "true true x n true",
+	},
+	{
+		name:     "strings",
+		template: `<%= "ab" * 3 %>|<%= "a" + "b" %>|<%= "42abc".to_i + 1 %>|<%= " x \n".strip %>|<%= "Ab".upcase %><%= "Ab".downcase %>|<%= "a,b,,c,,".split(",").length %>|<%= " a  b ".split.join("-") %>`,
+		output:   "ababab|ab|43|x|ABab|4|a-b",
+	},
+	{
+		name:     "string interpolation",
+		template: `<%= "port=#{p("port")} tls=#{p("tls.enabled")} cert=#{p("tls.cert", nil)}" %>`,
+		output:   "port=8080 tls=true cert=",
+	},
+	{
+		name:     "arrays",
+		template: `<%= [3, 1, 2].sort.reverse.join(",") %> <%= [1, [2, [3]], nil].flatten.compact.join(",") %> <%= [1, 1, 2].uniq.size %> <%= [1, [2, 3], nil].join("-") %>`,
+		output:   "3,2,1 1,2,3 2 1-2-3-",
+	},
+	{
+		name: "array blocks",
+		template: `<%= p("users").map { |u| u["name"] }.join(", ") %>|<%= p("users").find { |u| u["groups"].empty? }["name"] %>|` +
+			`<%= p("users").reject { |u| u["name"] == "dev" }.size %>|<%= p("users").any? { |u| u["name"] == "dev" } %>|` +
+			`<% p("users").each_with_index do |u, i| %><%= i %>:<%= u["name"] %> <% end %>`,
+		output: "admin, dev|dev|1|true|0:admin 1:dev ",
+	},
+	{
+		name:     "hashes",
+		template: `<% p("env").each do |k, v| %><%= k %>=<%= v %>;<% end %><%= p("env").keys.sort.join %>|<%= p("env").fetch("C", "3") %>|<%= p("env").key?("A") %>|<%= p("env")["A"] %>`,
+		output:   "B=2;A=1;AB|3|true|1",
+	},
+	{
+		name:     "JSON",
+		template: `<%= p("users").to_json %> <%= JSON.dump(p("env")) %> <%= JSON.dump("a\"b\n<&>") %> <%= "<&>/".to_json %> <%= p("tls.cert", nil).to_json %>`,
+		output:   `[{"name":"admin","groups":["a","b"]},{"name":"dev","groups":[]}] {"B":"2","A":"1"} "a\"b\n<&>" "<&>/" null`,
+	},
+	{
+		name: "control flow with trim mode",
+		template: `<%- if p("port") > 1000 -%>
+  high
+<%- elsif p("port") > 100 -%>
+  mid
+<%- else -%>
+  low
+<%- end -%>
+<%- unless p("users").empty? -%>
+users: <%= p("users").size %>
+<%- end -%>
+<% x = p("port") %><% x = 1 if x > 9000 %><%= x %>
+`,
+		output: "  high\nusers: 2\n8080\n",
+	},
+	{
+		name:     "comments and escaped tags",
+		template: "a<%# note %>b <%% raw %>\n  <%# indented note -%>\nc\n",
+		output:   "ab <% raw %>\n  c\n",
+	},
+	{
+		name:     "if_p",
+		template: `<% if_p("tls.cert") do |c| %>cert<% end.else_if_p("port", "name") do |port, name| %><%= name %>:<%= port %><% end %><% if_p("tls.cert") do %>x<% end.else do %> none<% end %>`,
+		output:   "fake-name:8080 none",
+	},
+	{
+		name: "links",
+		template: `<%= link("db").address %>:<%= link("db").p("port") %> <%= link("db").instances.map(&:address).join(",") %> ` +
+			`<% if_link("cache") do |c| %>cache<% end.else do %>no-cache<% end %>`,
+		output: "db.internal:5432 10.0.0.10,10.0.0.11 no-cache",
+	},
+	{
+		name:     "spec",
+		template: `<%= spec.id %> <%= spec.index %> <%= spec.az %> <%= spec.bootstrap %> <%= spec.deployment %> <%= spec.networks.default.ip %> <%= spec.job.name %>`,
+		output:   "fake-id 0 z1 true fake-deployment 10.0.0.5 web",
+	},
+}
+
+var _ = Describe("NativeERBRenderer golden templates", func() {
+	var (
+		fs          *fakesys.FakeFileSystem
+		erbRenderer ERBRenderer
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		erbRenderer = NewNativeERBRenderer(fs, boshlog.NewLogger(boshlog.LevelNone))
+	})
+
+	for _, golden := range nativeERBGoldenTemplates {
+		golden := golden
+
+		It("renders "+golden.name+" like renderer script", func() {
+			fs.WriteFileString("/src", golden.template)
+
+			err := erbRenderer.Render("/src", "/dst", nativeERBContext)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fs.ReadFileString("/dst")).To(Equal(golden.output))
+		})
+	}
+
+	Context("when ruby is available", func() {
+		var (
+			osFs   boshsys.FileSystem
+			tmpDir string
+		)
+
+		BeforeEach(func() {
+			if _, err := exec.LookPath("ruby"); err != nil {
+				Skip("ruby is not available")
+			}
+
+			logger := boshlog.NewLogger(boshlog.LevelNone)
+			osFs = boshsys.NewOsFileSystem(logger)
+			erbRenderer = NewERBRenderer(osFs, boshsys.NewExecCmdRunner(logger), logger)
+
+			var err error
+			tmpDir, err = osFs.TempDir("native-erb-golden")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			if osFs != nil {
+				osFs.RemoveAll(tmpDir)
+			}
+		})
+
+		for _, golden := range nativeERBGoldenTemplates {
+			golden := golden
+
+			It("renders "+golden.name+" with renderer script", func() {
+				srcPath := filepath.Join(tmpDir, "src")
+				dstPath := filepath.Join(tmpDir, "dst")
+
+				err := osFs.WriteFileString(srcPath, golden.template)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = erbRenderer.Render(srcPath, dstPath, nativeERBContext)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(osFs.ReadFileString(dstPath)).To(Equal(golden.output))
+			})
+		}
+	})
+})
@@ -0,0 +1,1393 @@
+package erbrenderer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Native ERB renderer understands ERB tags (<% %>, <%= %>, <%# %>, trim mode "-")
+// and a subset of Ruby commonly used in job templates. Parsing fails with
+// UnsupportedTemplateError for anything outside of that subset.
+
+type erbNode interface{}
+
+type erbTextNode struct {
+	text string
+}
+
+type erbOutputNode struct {
+	expr erbExpr
+	line int
+}
+
+type erbExprNode struct {
+	expr erbExpr
+	line int
+}
+
+type erbAssignNode struct {
+	name string
+	expr erbExpr
+	line int
+}
+
+type erbRaiseNode struct {
+	message erbExpr
+	line    int
+}
+
+type erbIfNode struct {
+	branches []*erbBranch
+	elseBody []erbNode
+}
+
+type erbBranch struct {
+	cond   erbExpr
+	negate bool
+	body   []erbNode
+	line   int
+}
+
+// erbBlockNode is a method call with a do...end block, optionally followed
+// by else/else_if_p blocks of if_p and if_link (e.g. <% end.else do %>)
+type erbBlockNode struct {
+	call   *erbCallExpr
+	params []string
+	body   []erbNode
+	elses  []*erbElseNode
+	line   int
+}
+
+type erbElseNode struct {
+	name   string
+	args   []erbExpr
+	params []string
+	body   []erbNode
+	line   int
+}
+
+type erbExpr interface{}
+
+type erbLiteralExpr struct {
+	value interface{}
+}
+
+type erbStringExpr struct {
+	parts []erbExpr
+}
+
+type erbArrayExpr struct {
+	items []erbExpr
+}
+
+type erbIdentExpr struct {
+	name string
+}
+
+type erbConstExpr struct {
+	name string
+}
+
+type erbCallExpr struct {
+	receiver erbExpr // nil for calls on template context
+	name     string
+	args     []erbExpr
+	symBlock string // e.g. map(&:address)
+	block    *erbExprBlock
+}
+
+type erbExprBlock struct {
+	params []string
+	body   erbExpr
+}
+
+type erbIndexExpr struct {
+	receiver erbExpr
+	index    erbExpr
+}
+
+type erbUnaryExpr struct {
+	op      string
+	operand erbExpr
+}
+
+type erbBinaryExpr struct {
+	op          string
+	left, right erbExpr
+}
+
+type erbTernaryExpr struct {
+	cond, ifTrue, ifFalse erbExpr
+}
+
+type erbSegmentKind int
+
+const (
+	erbTextSegment erbSegmentKind = iota
+	erbCodeSegment
+	erbOutputSegment
+)
+
+type erbSegment struct {
+	kind erbSegmentKind
+	text string
+	line int
+}
+
+func unsupportedERB(line int, format string, args ...interface{}) error {
+	return UnsupportedTemplateError{Line: line, Reason: fmt.Sprintf(format, args...)}
+}
+
+// splitERBTemplate follows scanning rules of Ruby's ERB with trim mode "-":
+// "<%-" removes indentation before it (including whitespace after previous tag)
+// and "-%>" removes newline after it
+func splitERBTemplate(template string) ([]erbSegment, error) {
+	var segments []erbSegment
+	var text []byte
+
+	line := 1
+	onlyIndent := true
+
+	flushText := func() {
+		if len(text) > 0 {
+			segments = append(segments, erbSegment{kind: erbTextSegment, text: string(text)})
+			text = nil
+		}
+	}
+
+	for i := 0; i < len(template); {
+		switch {
+		case strings.HasPrefix(template[i:], "<%%"):
+			text = append(text, "<%"...)
+			onlyIndent = true
+			i += 3
+
+		case strings.HasPrefix(template[i:], "<%"):
+			i += 2
+
+			kind := erbCodeSegment
+			comment := false
+
+			if strings.HasPrefix(template[i:], "-") {
+				i++
+
+				if onlyIndent {
+					text = []byte(strings.TrimRight(string(text), " \t"))
+				}
+			} else if strings.HasPrefix(template[i:], "=") {
+				kind = erbOutputSegment
+				i++
+			} else if strings.HasPrefix(template[i:], "#") {
+				comment = true
+				i++
+			}
+
+			code, n, trim := scanERBTag(template[i:])
+			if n == -1 {
+				return nil, unsupportedERB(line, "unterminated ERB tag")
+			}
+
+			codeLine := line
+			line += strings.Count(template[i:i+n], "\n")
+			i += n
+
+			if trim {
+				for _, newline := range []string{"\r\n", "\n"} {
+					if strings.HasPrefix(template[i:], newline) {
+						i += len(newline)
+						line++
+						break
+					}
+				}
+			}
+
+			if !comment {
+				flushText()
+				segments = append(segments, erbSegment{kind: kind, text: code, line: codeLine})
+			}
+
+			onlyIndent = true
+
+		default:
+			c := template[i]
+			text = append(text, c)
+			i++
+
+			if c == '\n' {
+				line++
+				onlyIndent = true
+			} else if c != ' ' && c != '\t' {
+				onlyIndent = false
+			}
+		}
+	}
+
+	flushText()
+
+	return segments, nil
+}
+
+// scanERBTag returns code of a tag, number of consumed bytes including
+// closing "%>" or "-%>" and whether closing tag trims following newline
+func scanERBTag(template string) (string, int, bool) {
+	var code []byte
+
+	for i := 0; i < len(template); i++ {
+		switch {
+		case strings.HasPrefix(template[i:], "-%>"):
+			return string(code), i + 3, true
+		case strings.HasPrefix(template[i:], "%%>"):
+			code = append(code, "%>"...)
+			i += 2
+		case strings.HasPrefix(template[i:], "%>"):
+			return string(code), i + 2, false
+		default:
+			code = append(code, template[i])
+		}
+	}
+
+	return "", -1, false
+}
+
+type erbParseFrame struct {
+	body    *[]erbNode
+	ifNode  *erbIfNode
+	inElse  bool
+	block   *erbBlockNode
+	lastEnd bool // block has a terminal else, so it cannot be continued
+}
+
+func parseERBTemplate(template string) ([]erbNode, error) {
+	segments, err := splitERBTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var root []erbNode
+
+	stack := []*erbParseFrame{{body: &root}}
+
+	for _, segment := range segments {
+		top := stack[len(stack)-1]
+
+		switch segment.kind {
+		case erbTextSegment:
+			*top.body = append(*top.body, erbTextNode{text: segment.text})
+
+		case erbOutputSegment:
+			statements, err := lexERBStatements(segment.text, segment.line)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(statements) != 1 {
+				return nil, unsupportedERB(segment.line, "expected single expression in output tag")
+			}
+
+			p := &erbParser{toks: statements[0]}
+			line := p.line()
+
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			node, err := p.parseModifier(erbOutputNode{expr: expr, line: line})
+			if err != nil {
+				return nil, err
+			}
+
+			if !p.atEnd() {
+				return nil, p.unexpected()
+			}
+
+			*top.body = append(*top.body, node)
+
+		case erbCodeSegment:
+			statements, err := lexERBStatements(segment.text, segment.line)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, toks := range statements {
+				stack, err = parseERBStatement(toks, stack)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, unsupportedERB(0, "missing 'end'")
+	}
+
+	return root, nil
+}
+
+func parseERBStatement(toks []erbToken, stack []*erbParseFrame) ([]*erbParseFrame, error) {
+	p := &erbParser{toks: toks}
+	top := stack[len(stack)-1]
+	line := p.line()
+
+	switch {
+	case p.isKeyword("if") || p.isKeyword("unless"):
+		negate := p.next().text == "unless"
+
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.atEnd() {
+			return nil, p.unexpected()
+		}
+
+		node := &erbIfNode{branches: []*erbBranch{{cond: cond, negate: negate, line: line}}}
+		*top.body = append(*top.body, node)
+
+		return append(stack, &erbParseFrame{body: &node.branches[0].body, ifNode: node}), nil
+
+	case p.isKeyword("elsif"):
+		p.next()
+
+		if top.ifNode == nil || top.inElse || top.ifNode.branches[0].negate {
+			return nil, unsupportedERB(line, "unexpected 'elsif'")
+		}
+
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.atEnd() {
+			return nil, p.unexpected()
+		}
+
+		branch := &erbBranch{cond: cond, line: line}
+		top.ifNode.branches = append(top.ifNode.branches, branch)
+		top.body = &branch.body
+
+		return stack, nil
+
+	case p.isKeyword("else"):
+		p.next()
+
+		if top.ifNode == nil || top.inElse || !p.atEnd() {
+			return nil, unsupportedERB(line, "unexpected 'else'")
+		}
+
+		top.inElse = true
+		top.body = &top.ifNode.elseBody
+
+		return stack, nil
+
+	case p.isKeyword("end"):
+		p.next()
+
+		if len(stack) == 1 {
+			return nil, unsupportedERB(line, "unexpected 'end'")
+		}
+
+		stack = stack[:len(stack)-1]
+
+		if p.atEnd() {
+			return stack, nil
+		}
+
+		// Continues if_p/if_link block, e.g. <% end.else do %>
+		if top.block == nil || top.lastEnd || !p.isPunct(".") {
+			return nil, p.unexpected()
+		}
+
+		p.next()
+
+		nameTok := p.next()
+		if nameTok.kind != erbTokIdent || (nameTok.text != "else" && nameTok.text != "else_if_p") {
+			return nil, unsupportedERB(line, "unexpected '%s' after 'end'", nameTok.text)
+		}
+
+		elseNode := &erbElseNode{name: nameTok.text, line: line}
+
+		if p.isPunct("(") {
+			args, _, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+
+			elseNode.args = args
+		}
+
+		if !p.isKeyword("do") {
+			return nil, p.unexpected()
+		}
+
+		p.next()
+
+		params, err := p.parseBlockParams()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.atEnd() {
+			return nil, p.unexpected()
+		}
+
+		elseNode.params = params
+		top.block.elses = append(top.block.elses, elseNode)
+
+		return append(stack, &erbParseFrame{
+			body:    &elseNode.body,
+			block:   top.block,
+			lastEnd: elseNode.name == "else",
+		}), nil
+
+	case p.isKeyword("raise"):
+		p.next()
+
+		msg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseModifier(erbRaiseNode{message: msg, line: line})
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.atEnd() {
+			return nil, p.unexpected()
+		}
+
+		*top.body = append(*top.body, node)
+
+		return stack, nil
+	}
+
+	if len(toks) > 2 && toks[0].kind == erbTokIdent && !erbKeywords[toks[0].text] && toks[1].isPunct("=") {
+		p.next()
+		p.next()
+
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		node, err := p.parseModifier(erbAssignNode{name: toks[0].text, expr: expr, line: line})
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.atEnd() {
+			return nil, p.unexpected()
+		}
+
+		*top.body = append(*top.body, node)
+
+		return stack, nil
+	}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isKeyword("do") {
+		p.next()
+
+		call, ok := expr.(*erbCallExpr)
+		if !ok || call.block != nil || call.symBlock != "" {
+			return nil, unsupportedERB(line, "unexpected 'do'")
+		}
+
+		params, err := p.parseBlockParams()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.atEnd() {
+			return nil, p.unexpected()
+		}
+
+		node := &erbBlockNode{call: call, params: params, line: line}
+		*top.body = append(*top.body, node)
+
+		return append(stack, &erbParseFrame{body: &node.body, block: node}), nil
+	}
+
+	node, err := p.parseModifier(erbExprNode{expr: expr, line: line})
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.atEnd() {
+		return nil, p.unexpected()
+	}
+
+	*top.body = append(*top.body, node)
+
+	return stack, nil
+}
+
+var erbKeywords = map[string]bool{
+	"if": true, "unless": true, "elsif": true, "else": true, "end": true, "do": true,
+	"and": true, "or": true, "not": true, "nil": true, "true": true, "false": true,
+	"then": true, "while": true, "until": true, "case": true, "when": true, "begin": true,
+	"rescue": true, "ensure": true, "def": true, "class": true, "module": true, "yield": true,
+	"return": true, "break": true, "next": true, "redo": true, "retry": true, "self": true,
+	"super": true, "defined?": true, "for": true, "in": true, "raise": true, "lambda": true,
+	"proc": true,
+}
+
+type erbTokenKind int
+
+const (
+	erbTokIdent erbTokenKind = iota
+	erbTokConst
+	erbTokInt
+	erbTokFloat
+	erbTokString
+	erbTokSymbol
+	erbTokPunct
+	erbTokNewline
+	erbTokEOF
+)
+
+type erbToken struct {
+	kind  erbTokenKind
+	text  string
+	parts []erbExpr // for strings
+	line  int
+}
+
+func (t erbToken) isPunct(text string) bool {
+	return t.kind == erbTokPunct && t.text == text
+}
+
+var erbPuncts = []string{
+	"<=>", "||=", "&&=", "**", "==", "!=", "<=", ">=", "&&", "||", "=>", "::", "+=", "-=", "*=",
+	"=~", "!~", "..", "<<", "->",
+	"(", ")", "[", "]", "{", "}", ",", ".", "+", "-", "*", "/", "%", "!", "<", ">", "=", "?",
+	":", "&", "|", ";",
+}
+
+// lexERBStatements tokenizes Ruby code and splits it into statements
+// on newlines and semicolons that do not continue an expression
+func lexERBStatements(code string, line int) ([][]erbToken, error) {
+	toks, err := lexERB(code, line)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements [][]erbToken
+	var current []erbToken
+
+	depth := 0
+
+	for i, tok := range toks {
+		if tok.kind == erbTokPunct {
+			switch tok.text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			}
+		}
+
+		if tok.kind != erbTokNewline {
+			current = append(current, tok)
+			continue
+		}
+
+		if depth > 0 && tok.text != ";" {
+			continue
+		}
+
+		if tok.text != ";" && len(current) > 0 {
+			last := current[len(current)-1]
+
+			if last.kind == erbTokPunct && !last.isPunct(")") && !last.isPunct("]") && !last.isPunct("}") && !last.isPunct("|") {
+				continue
+			}
+
+			// Leading dot continues method chain on the next line
+			if i+1 < len(toks) && toks[i+1].isPunct(".") {
+				continue
+			}
+		}
+
+		if len(current) > 0 {
+			statements = append(statements, current)
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		statements = append(statements, current)
+	}
+
+	return statements, nil
+}
+
+func lexERB(code string, line int) ([]erbToken, error) {
+	var toks []erbToken
+
+	prevIs := func(texts ...string) bool {
+		if len(toks) == 0 {
+			return false
+		}
+
+		for _, text := range texts {
+			if toks[len(toks)-1].isPunct(text) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := 0; i < len(code); {
+		c := code[i]
+
+		switch {
+		case c == '\n' || c == ';':
+			toks = append(toks, erbToken{kind: erbTokNewline, text: string(c), line: line})
+			if c == '\n' {
+				line++
+			}
+			i++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+
+		case c == '\\' && i+1 < len(code) && code[i+1] == '\n':
+			line++
+			i += 2
+
+		case c == '#':
+			for i < len(code) && code[i] != '\n' {
+				i++
+			}
+
+		case isERBIdentStart(c):
+			start := i
+			for i < len(code) && isERBIdentChar(code[i]) {
+				i++
+			}
+
+			// Predicate method names, e.g. empty? and nil?
+			if i < len(code) && (code[i] == '?' || code[i] == '!') && prevIs(".") {
+				if i+1 >= len(code) || code[i+1] != '=' {
+					i++
+				}
+			}
+
+			kind := erbTokIdent
+			if c >= 'A' && c <= 'Z' {
+				kind = erbTokConst
+			}
+
+			toks = append(toks, erbToken{kind: kind, text: code[start:i], line: line})
+
+		case c >= '0' && c <= '9':
+			start := i
+			kind := erbTokInt
+
+			for i < len(code) && (isERBDigit(code[i]) || code[i] == '_') {
+				i++
+			}
+
+			if i+1 < len(code) && code[i] == '.' && isERBDigit(code[i+1]) {
+				kind = erbTokFloat
+				i++
+				for i < len(code) && (isERBDigit(code[i]) || code[i] == '_') {
+					i++
+				}
+			}
+
+			if i < len(code) && (code[i] == 'e' || code[i] == 'E' || code[i] == 'x' || code[i] == 'b' || code[i] == 'o') {
+				return nil, unsupportedERB(line, "unsupported number literal")
+			}
+
+			toks = append(toks, erbToken{kind: kind, text: strings.Replace(code[start:i], "_", "", -1), line: line})
+
+		case c == '\'':
+			str, n, err := lexERBSingleQuoted(code[i:], line)
+			if err != nil {
+				return nil, err
+			}
+
+			toks = append(toks, erbToken{kind: erbTokString, parts: []erbExpr{erbLiteralExpr{value: str}}, line: line})
+			line += strings.Count(code[i:i+n], "\n")
+			i += n
+
+		case c == '"':
+			parts, n, err := lexERBDoubleQuoted(code[i:], line)
+			if err != nil {
+				return nil, err
+			}
+
+			toks = append(toks, erbToken{kind: erbTokString, parts: parts, line: line})
+			line += strings.Count(code[i:i+n], "\n")
+			i += n
+
+		case c == ':' && i+1 < len(code) && isERBIdentStart(code[i+1]) && (len(toks) == 0 || prevIs("&", "(", ",", "[")):
+			start := i + 1
+			i++
+			for i < len(code) && isERBIdentChar(code[i]) {
+				i++
+			}
+
+			if i < len(code) && (code[i] == '?' || code[i] == '!') {
+				i++
+			}
+
+			toks = append(toks, erbToken{kind: erbTokSymbol, text: code[start:i], line: line})
+
+		default:
+			matched := false
+
+			for _, punct := range erbPuncts {
+				if strings.HasPrefix(code[i:], punct) {
+					toks = append(toks, erbToken{kind: erbTokPunct, text: punct, line: line})
+					i += len(punct)
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return nil, unsupportedERB(line, "unexpected character '%c'", c)
+			}
+		}
+	}
+
+	return toks, nil
+}
+
+func lexERBSingleQuoted(code string, line int) (string, int, error) {
+	var result []byte
+
+	for i := 1; i < len(code); i++ {
+		switch code[i] {
+		case '\'':
+			return string(result), i + 1, nil
+		case '\\':
+			if i+1 < len(code) && (code[i+1] == '\'' || code[i+1] == '\\') {
+				i++
+			}
+		}
+
+		result = append(result, code[i])
+	}
+
+	return "", 0, unsupportedERB(line, "unterminated string")
+}
+
+var erbStringEscapes = map[byte]string{
+	'n': "\n", 't': "\t", 'r': "\r", 's': " ", '0': "\x00", 'e': "\x1b",
+	'a': "\a", 'b': "\b", 'f': "\f", 'v': "\v",
+}
+
+func lexERBDoubleQuoted(code string, line int) ([]erbExpr, int, error) {
+	var parts []erbExpr
+	var literal []byte
+
+	for i := 1; i < len(code); i++ {
+		c := code[i]
+
+		switch {
+		case c == '"':
+			if len(literal) > 0 || len(parts) == 0 {
+				parts = append(parts, erbLiteralExpr{value: string(literal)})
+			}
+
+			return parts, i + 1, nil
+
+		case c == '\\':
+			if i+1 >= len(code) {
+				return nil, 0, unsupportedERB(line, "unterminated string")
+			}
+
+			i++
+
+			if escaped, found := erbStringEscapes[code[i]]; found {
+				literal = append(literal, escaped...)
+			} else if code[i] == 'u' || code[i] == 'x' || code[i] == 'c' || code[i] == 'C' || code[i] == 'M' || (code[i] >= '1' && code[i] <= '7') {
+				return nil, 0, unsupportedERB(line, "unsupported string escape '\\%c'", code[i])
+			} else {
+				literal = append(literal, code[i])
+			}
+
+		case c == '#' && i+1 < len(code) && code[i+1] == '{':
+			end, err := findERBInterpolationEnd(code, i+2, line)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if len(literal) > 0 {
+				parts = append(parts, erbLiteralExpr{value: string(literal)})
+				literal = nil
+			}
+
+			expr, err := parseERBExpr(code[i+2:end], line+strings.Count(code[:i], "\n"))
+			if err != nil {
+				return nil, 0, err
+			}
+
+			parts = append(parts, expr)
+			i = end
+
+		case c == '#' && i+1 < len(code) && (code[i+1] == '@' || code[i+1] == '$'):
+			return nil, 0, unsupportedERB(line, "unsupported string interpolation")
+
+		default:
+			literal = append(literal, c)
+		}
+	}
+
+	return nil, 0, unsupportedERB(line, "unterminated string")
+}
+
+func findERBInterpolationEnd(code string, start int, line int) (int, error) {
+	depth := 0
+
+	for i := start; i < len(code); i++ {
+		switch code[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i, nil
+			}
+			depth--
+		case '"', '\'':
+			// Skips nested strings, e.g. "#{p("port")}"
+			quote := code[i]
+
+			for i++; i < len(code) && code[i] != quote; i++ {
+				if code[i] == '\\' {
+					i++
+				} else if quote == '"' && code[i] == '#' && i+1 < len(code) && code[i+1] == '{' {
+					return 0, unsupportedERB(line, "nested string interpolation")
+				}
+			}
+		}
+	}
+
+	return 0, unsupportedERB(line, "unterminated string interpolation")
+}
+
+func parseERBExpr(code string, line int) (erbExpr, error) {
+	statements, err := lexERBStatements(code, line)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statements) != 1 {
+		return nil, unsupportedERB(line, "expected single expression")
+	}
+
+	p := &erbParser{toks: statements[0]}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.atEnd() {
+		return nil, p.unexpected()
+	}
+
+	return expr, nil
+}
+
+func isERBIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isERBIdentChar(c byte) bool {
+	return isERBIdentStart(c) || isERBDigit(c)
+}
+
+func isERBDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type erbParser struct {
+	toks []erbToken
+	pos  int
+}
+
+func (p *erbParser) peek() erbToken {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+
+	line := 0
+	if len(p.toks) > 0 {
+		line = p.toks[len(p.toks)-1].line
+	}
+
+	return erbToken{kind: erbTokEOF, line: line}
+}
+
+func (p *erbParser) next() erbToken {
+	tok := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *erbParser) line() int { return p.peek().line }
+
+func (p *erbParser) atEnd() bool { return p.pos >= len(p.toks) }
+
+func (p *erbParser) isPunct(text string) bool { return p.peek().isPunct(text) }
+
+func (p *erbParser) isKeyword(text string) bool {
+	tok := p.peek()
+	return tok.kind == erbTokIdent && tok.text == text
+}
+
+func (p *erbParser) unexpected() error {
+	tok := p.peek()
+	if tok.kind == erbTokEOF {
+		return unsupportedERB(tok.line, "unexpected end of expression")
+	}
+
+	text := tok.text
+	if tok.kind == erbTokString {
+		text = "string"
+	}
+
+	return unsupportedERB(tok.line, "unexpected '%s'", text)
+}
+
+func (p *erbParser) expectPunct(text string) error {
+	if !p.isPunct(text) {
+		return p.unexpected()
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *erbParser) parseModifier(node erbNode) (erbNode, error) {
+	if !p.isKeyword("if") && !p.isKeyword("unless") {
+		return node, nil
+	}
+
+	line := p.line()
+	negate := p.next().text == "unless"
+
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &erbIfNode{branches: []*erbBranch{{cond: cond, negate: negate, body: []erbNode{node}, line: line}}}, nil
+}
+
+func (p *erbParser) parseBlockParams() ([]string, error) {
+	if !p.isPunct("|") {
+		return nil, nil
+	}
+
+	p.next()
+
+	var params []string
+
+	for {
+		tok := p.next()
+		if tok.kind != erbTokIdent || erbKeywords[tok.text] {
+			return nil, unsupportedERB(tok.line, "unsupported block parameters")
+		}
+
+		params = append(params, tok.text)
+
+		if p.isPunct("|") {
+			p.next()
+			return params, nil
+		}
+
+		err := p.expectPunct(",")
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseExpr parses expression with precedence similar to Ruby's:
+// and/or < not < ?: < || < && < ==/!= < comparison < +/- < */% < !/unary minus < calls
+func (p *erbParser) parseExpr() (erbExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") || p.isKeyword("or") {
+		op := "&&"
+		if p.next().text == "or" {
+			op = "||"
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = erbBinaryExpr{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *erbParser) parseNot() (erbExpr, error) {
+	if p.isKeyword("not") {
+		p.next()
+
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return erbUnaryExpr{op: "!", operand: operand}, nil
+	}
+
+	return p.parseTernary()
+}
+
+func (p *erbParser) parseTernary() (erbExpr, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isPunct("?") {
+		return cond, nil
+	}
+
+	p.next()
+
+	ifTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.expectPunct(":")
+	if err != nil {
+		return nil, err
+	}
+
+	ifFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return erbTernaryExpr{cond: cond, ifTrue: ifTrue, ifFalse: ifFalse}, nil
+}
+
+var erbBinaryPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *erbParser) parseBinary(level int) (erbExpr, error) {
+	if level == len(erbBinaryPrecedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != erbTokPunct || !containsERBOp(erbBinaryPrecedence[level], tok.text) {
+			return left, nil
+		}
+
+		p.next()
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = erbBinaryExpr{op: tok.text, left: left, right: right}
+	}
+}
+
+func containsERBOp(ops []string, op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *erbParser) parseUnary() (erbExpr, error) {
+	if p.isPunct("!") || p.isPunct("-") {
+		op := p.next().text
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return erbUnaryExpr{op: op, operand: operand}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+func (p *erbParser) parsePostfix() (erbExpr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isPunct("."):
+			p.next()
+
+			nameTok := p.next()
+			if nameTok.kind != erbTokIdent {
+				return nil, unsupportedERB(nameTok.line, "expected method name")
+			}
+
+			call := &erbCallExpr{receiver: expr, name: nameTok.text}
+
+			err := p.parseCallRest(call)
+			if err != nil {
+				return nil, err
+			}
+
+			expr = call
+
+		case p.isPunct("["):
+			p.next()
+
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			err = p.expectPunct("]")
+			if err != nil {
+				return nil, err
+			}
+
+			expr = erbIndexExpr{receiver: expr, index: index}
+
+		case p.isPunct("::"):
+			return nil, p.unexpected()
+
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *erbParser) parseCallRest(call *erbCallExpr) error {
+	if p.isPunct("(") {
+		args, symBlock, err := p.parseArgs()
+		if err != nil {
+			return err
+		}
+
+		call.args = args
+		call.symBlock = symBlock
+	}
+
+	if p.isPunct("{") {
+		p.next()
+
+		params, err := p.parseBlockParams()
+		if err != nil {
+			return err
+		}
+
+		body, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+
+		err = p.expectPunct("}")
+		if err != nil {
+			return err
+		}
+
+		call.block = &erbExprBlock{params: params, body: body}
+	}
+
+	return nil
+}
+
+func (p *erbParser) parseArgs() ([]erbExpr, string, error) {
+	err := p.expectPunct("(")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var args []erbExpr
+	var symBlock string
+
+	for !p.isPunct(")") {
+		if symBlock != "" {
+			return nil, "", p.unexpected()
+		}
+
+		if p.isPunct("&") {
+			p.next()
+
+			tok := p.next()
+			if tok.kind != erbTokSymbol {
+				return nil, "", unsupportedERB(tok.line, "unsupported block argument")
+			}
+
+			symBlock = tok.text
+		} else {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, "", err
+			}
+
+			args = append(args, arg)
+		}
+
+		if !p.isPunct(")") {
+			err := p.expectPunct(",")
+			if err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	p.next()
+
+	return args, symBlock, nil
+}
+
+func (p *erbParser) parsePrimary() (erbExpr, error) {
+	tok := p.peek()
+
+	switch tok.kind {
+	case erbTokInt:
+		p.next()
+
+		// Integers with leading zeros are octal in Ruby
+		value, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil || (len(tok.text) > 1 && tok.text[0] == '0') {
+			return nil, unsupportedERB(tok.line, "unsupported integer '%s'", tok.text)
+		}
+
+		return erbLiteralExpr{value: value}, nil
+
+	case erbTokFloat:
+		p.next()
+
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, unsupportedERB(tok.line, "unsupported float '%s'", tok.text)
+		}
+
+		return erbLiteralExpr{value: value}, nil
+
+	case erbTokString:
+		p.next()
+		return erbStringExpr{parts: tok.parts}, nil
+
+	case erbTokConst:
+		p.next()
+		return erbConstExpr{name: tok.text}, nil
+
+	case erbTokIdent:
+		switch tok.text {
+		case "nil":
+			p.next()
+			return erbLiteralExpr{value: nil}, nil
+		case "true":
+			p.next()
+			return erbLiteralExpr{value: true}, nil
+		case "false":
+			p.next()
+			return erbLiteralExpr{value: false}, nil
+		}
+
+		if erbKeywords[tok.text] {
+			return nil, p.unexpected()
+		}
+
+		p.next()
+
+		if p.isPunct("(") || p.isPunct("{") {
+			call := &erbCallExpr{name: tok.text}
+
+			err := p.parseCallRest(call)
+			if err != nil {
+				return nil, err
+			}
+
+			return call, nil
+		}
+
+		return erbIdentExpr{name: tok.text}, nil
+
+	case erbTokPunct:
+		switch tok.text {
+		case "(":
+			p.next()
+
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			err = p.expectPunct(")")
+			if err != nil {
+				return nil, err
+			}
+
+			return expr, nil
+
+		case "[":
+			p.next()
+
+			var items []erbExpr
+
+			for !p.isPunct("]") {
+				item, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+
+				items = append(items, item)
+
+				if !p.isPunct("]") {
+					err := p.expectPunct(",")
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			p.next()
+
+			return erbArrayExpr{items: items}, nil
+		}
+	}
+
+	return nil, p.unexpected()
+}
@@ -0,0 +1,374 @@
+package erbrenderer
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("native ERB parser", func() {
+	str := func(value string) erbStringExpr {
+		return erbStringExpr{parts: []erbExpr{erbLiteralExpr{value: value}}}
+	}
+
+	ident := func(name string) erbIdentExpr { return erbIdentExpr{name: name} }
+
+	num := func(value int64) erbLiteralExpr { return erbLiteralExpr{value: value} }
+
+	Describe("splitERBTemplate", func() {
+		split := func(template string) []erbSegment {
+			segments, err := splitERBTemplate(template)
+			Expect(err).ToNot(HaveOccurred())
+			return segments
+		}
+
+		It("splits template into text, code and output segments", func() {
+			Expect(split("a <%= x %> b<% y %>")).To(Equal([]erbSegment{
+				{kind: erbTextSegment, text: "a "},
+				{kind: erbOutputSegment, text: " x ", line: 1},
+				{kind: erbTextSegment, text: " b"},
+				{kind: erbCodeSegment, text: " y ", line: 1},
+			}))
+		})
+
+		It("records line on which tag starts", func() {
+			Expect(split("a\n<%= x\n %>\n<% y %>")).To(Equal([]erbSegment{
+				{kind: erbTextSegment, text: "a\n"},
+				{kind: erbOutputSegment, text: " x\n ", line: 2},
+				{kind: erbTextSegment, text: "\n"},
+				{kind: erbCodeSegment, text: " y ", line: 4},
+			}))
+		})
+
+		It("removes indentation before '<%-' and newline after '-%>'", func() {
+			Expect(split("a\n  <%- x -%>\nb <%- y -%>\r\nc")).To(Equal([]erbSegment{
+				{kind: erbTextSegment, text: "a\n"},
+				{kind: erbCodeSegment, text: " x ", line: 2},
+				{kind: erbTextSegment, text: "b "},
+				{kind: erbCodeSegment, text: " y ", line: 3},
+				{kind: erbTextSegment, text: "c"},
+			}))
+		})
+
+		It("drops comments and unescapes '<%%' and '%%>'", func() {
+			Expect(split("a<%# x %>b <%% c %> <%= '%%>' %>")).To(Equal([]erbSegment{
+				{kind: erbTextSegment, text: "ab <% c %> "},
+				{kind: erbOutputSegment, text: " '%>' ", line: 1},
+			}))
+		})
+
+		It("returns error for unterminated tag", func() {
+			_, err := splitERBTemplate("a\n<% x")
+			Expect(err).To(Equal(UnsupportedTemplateError{Line: 2, Reason: "unterminated ERB tag"}))
+		})
+	})
+
+	Describe("lexERBStatements", func() {
+		statements := func(code string) [][]string {
+			stmts, err := lexERBStatements(code, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			var result [][]string
+
+			for _, toks := range stmts {
+				var texts []string
+
+				for _, tok := range toks {
+					if tok.kind == erbTokString {
+						texts = append(texts, "string")
+					} else {
+						texts = append(texts, tok.text)
+					}
+				}
+
+				result = append(result, texts)
+			}
+
+			return result
+		}
+
+		It("splits statements on newlines and semicolons", func() {
+			Expect(statements("a = 1\nb; c")).To(Equal([][]string{{"a", "=", "1"}, {"b"}, {"c"}}))
+		})
+
+		It("continues statements inside brackets, after operators and before leading dots", func() {
+			Expect(statements("x = [1,\n2]\na +\nb\nc\n  .d \\\n+ e")).To(Equal([][]string{
+				{"x", "=", "[", "1", ",", "2", "]"},
+				{"a", "+", "b"},
+				{"c", ".", "d", "+", "e"},
+			}))
+		})
+
+		It("ends statement after block parameters", func() {
+			Expect(statements("x.each do |a, b|\ny")).To(Equal([][]string{
+				{"x", ".", "each", "do", "|", "a", ",", "b", "|"},
+				{"y"},
+			}))
+		})
+
+		It("skips comments and lexes strings, symbols and predicate methods", func() {
+			Expect(statements("p('a') # comment\nx.map(&:name).empty?")).To(Equal([][]string{
+				{"p", "(", "string", ")"},
+				{"x", ".", "map", "(", "&", "name", ")", ".", "empty?"},
+			}))
+		})
+
+		It("records token lines", func() {
+			stmts, err := lexERBStatements("a\n'b\nc'\nd", 5)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(3))
+			Expect(stmts[0][0].line).To(Equal(5))
+			Expect(stmts[1][0].line).To(Equal(6))
+			Expect(stmts[2][0].line).To(Equal(8))
+		})
+
+		It("returns error for unsupported literals and characters", func() {
+			for code, reason := range map[string]string{
+				"1e5":          "unsupported number literal",
+				"0x1F":         "unsupported number literal",
+				"'abc":         "unterminated string",
+				`"\u0041"`:     `unsupported string escape '\u'`,
+				`"#{"a#{b}"}"`: "nested string interpolation",
+				`"#@a"`:        "unsupported string interpolation",
+				"a $ b":        "unexpected character '$'",
+				`"#{a"`:        "unterminated string interpolation",
+			} {
+				_, err := lexERBStatements(code, 1)
+				Expect(err).To(HaveOccurred(), "%s", code)
+				Expect(err.(UnsupportedTemplateError).Reason).To(Equal(reason), "%s", code)
+			}
+		})
+	})
+
+	Describe("parseERBExpr", func() {
+		parse := func(code string) erbExpr {
+			expr, err := parseERBExpr(code, 1)
+			Expect(err).ToNot(HaveOccurred())
+			return expr
+		}
+
+		It("parses binary operators with Ruby precedence", func() {
+			Expect(parse("a || b && c")).To(Equal(erbBinaryExpr{
+				op:    "||",
+				left:  ident("a"),
+				right: erbBinaryExpr{op: "&&", left: ident("b"), right: ident("c")},
+			}))
+
+			Expect(parse("a + b * c == d - 1")).To(Equal(erbBinaryExpr{
+				op: "==",
+				left: erbBinaryExpr{
+					op:    "+",
+					left:  ident("a"),
+					right: erbBinaryExpr{op: "*", left: ident("b"), right: ident("c")},
+				},
+				right: erbBinaryExpr{op: "-", left: ident("d"), right: num(1)},
+			}))
+
+			Expect(parse("(a || b) && c")).To(Equal(erbBinaryExpr{
+				op:    "&&",
+				left:  erbBinaryExpr{op: "||", left: ident("a"), right: ident("b")},
+				right: ident("c"),
+			}))
+		})
+
+		It("parses 'and', 'or' and 'not' with lower precedence than other operators", func() {
+			Expect(parse("not a == b")).To(Equal(erbUnaryExpr{
+				op:      "!",
+				operand: erbBinaryExpr{op: "==", left: ident("a"), right: ident("b")},
+			}))
+
+			Expect(parse("a or b and not c")).To(Equal(erbBinaryExpr{
+				op:    "&&",
+				left:  erbBinaryExpr{op: "||", left: ident("a"), right: ident("b")},
+				right: erbUnaryExpr{op: "!", operand: ident("c")},
+			}))
+		})
+
+		It("parses right associative ternary and unary operators", func() {
+			Expect(parse("a ? b : c ? d : e")).To(Equal(erbTernaryExpr{
+				cond:    ident("a"),
+				ifTrue:  ident("b"),
+				ifFalse: erbTernaryExpr{cond: ident("c"), ifTrue: ident("d"), ifFalse: ident("e")},
+			}))
+
+			Expect(parse("!a.b? && -1")).To(Equal(erbBinaryExpr{
+				op:    "&&",
+				left:  erbUnaryExpr{op: "!", operand: &erbCallExpr{receiver: ident("a"), name: "b?"}},
+				right: erbUnaryExpr{op: "-", operand: num(1)},
+			}))
+		})
+
+		It("parses calls, indexes and blocks", func() {
+			Expect(parse(`p("a", 1).map(&:name)[0]`)).To(Equal(erbIndexExpr{
+				receiver: &erbCallExpr{
+					receiver: &erbCallExpr{name: "p", args: []erbExpr{str("a"), num(1)}},
+					name:     "map",
+					symBlock: "name",
+				},
+				index: num(0),
+			}))
+
+			Expect(parse(`x.select { |i| i > 1 }.size`)).To(Equal(&erbCallExpr{
+				receiver: &erbCallExpr{
+					receiver: ident("x"),
+					name:     "select",
+					block: &erbExprBlock{
+						params: []string{"i"},
+						body:   erbBinaryExpr{op: ">", left: ident("i"), right: num(1)},
+					},
+				},
+				name: "size",
+			}))
+
+			Expect(parse(`JSON.dump(x)`)).To(Equal(&erbCallExpr{
+				receiver: erbConstExpr{name: "JSON"},
+				name:     "dump",
+				args:     []erbExpr{ident("x")},
+			}))
+		})
+
+		It("parses literals and interpolated strings", func() {
+			Expect(parse(`[1, 2.5, nil, true, false, 'it\'s', ""]`)).To(Equal(erbArrayExpr{items: []erbExpr{
+				num(1),
+				erbLiteralExpr{value: 2.5},
+				erbLiteralExpr{value: nil},
+				erbLiteralExpr{value: true},
+				erbLiteralExpr{value: false},
+				str("it's"),
+				str(""),
+			}}))
+
+			Expect(parse(`"a\t#{b["c"]}d#{1_000}"`)).To(Equal(erbStringExpr{parts: []erbExpr{
+				erbLiteralExpr{value: "a\t"},
+				erbIndexExpr{receiver: ident("b"), index: str("c")},
+				erbLiteralExpr{value: "d"},
+				num(1000),
+			}}))
+		})
+
+		It("returns error for unsupported expressions", func() {
+			for code, reason := range map[string]string{
+				"010":             "unsupported integer '010'",
+				"a::B":            "unexpected '::'",
+				"a +":             "unexpected end of expression",
+				"a; b":            "expected single expression",
+				"f(&:a, b)":       "unexpected 'b'",
+				"f(&a)":           "unsupported block argument",
+				"a.1":             "expected method name",
+				"a ? b":           "unexpected end of expression",
+				"x.map { |1| 1 }": "unsupported block parameters",
+				"case":            "unexpected 'case'",
+				"a b":             "unexpected 'b'",
+				"[1, 2":           "unexpected end of expression",
+				`f("a" "b")`:      "unexpected 'string'",
+			} {
+				_, err := parseERBExpr(code, 1)
+				Expect(err).To(HaveOccurred(), "%s", code)
+				Expect(err.(UnsupportedTemplateError).Reason).To(Equal(reason), "%s", code)
+			}
+		})
+	})
+
+	Describe("parseERBTemplate", func() {
+		parse := func(template string) []erbNode {
+			nodes, err := parseERBTemplate(template)
+			Expect(err).ToNot(HaveOccurred())
+			return nodes
+		}
+
+		It("parses text, output, expressions and assignments", func() {
+			Expect(parse(`a<%= b %><% c = p("d"); e.f %>`)).To(Equal([]erbNode{
+				erbTextNode{text: "a"},
+				erbOutputNode{expr: ident("b"), line: 1},
+				erbAssignNode{name: "c", expr: &erbCallExpr{name: "p", args: []erbExpr{str("d")}}, line: 1},
+				erbExprNode{expr: &erbCallExpr{receiver: ident("e"), name: "f"}, line: 1},
+			}))
+		})
+
+		It("parses if/elsif/else and unless", func() {
+			Expect(parse("<% if a %>x<% elsif b %>y<% else %>z<% end %>\n<% unless c %>w<% end %>")).To(Equal([]erbNode{
+				&erbIfNode{
+					branches: []*erbBranch{
+						{cond: ident("a"), body: []erbNode{erbTextNode{text: "x"}}, line: 1},
+						{cond: ident("b"), body: []erbNode{erbTextNode{text: "y"}}, line: 1},
+					},
+					elseBody: []erbNode{erbTextNode{text: "z"}},
+				},
+				erbTextNode{text: "\n"},
+				&erbIfNode{branches: []*erbBranch{
+					{cond: ident("c"), negate: true, body: []erbNode{erbTextNode{text: "w"}}, line: 2},
+				}},
+			}))
+		})
+
+		It("parses if and unless modifiers", func() {
+			Expect(parse(`<%= a if b %><% raise "c" unless d %>`)).To(Equal([]erbNode{
+				&erbIfNode{branches: []*erbBranch{
+					{cond: ident("b"), body: []erbNode{erbOutputNode{expr: ident("a"), line: 1}}, line: 1},
+				}},
+				&erbIfNode{branches: []*erbBranch{
+					{cond: ident("d"), negate: true, body: []erbNode{erbRaiseNode{message: str("c"), line: 1}}, line: 1},
+				}},
+			}))
+		})
+
+		It("parses do blocks continued by else and else_if_p", func() {
+			template := `<% if_p("a") do |a| %>x<% end.else_if_p("b", "c") do |b, c| %>y<% end.else do %>z<% end %>`
+
+			Expect(parse(template)).To(Equal([]erbNode{
+				&erbBlockNode{
+					call:   &erbCallExpr{name: "if_p", args: []erbExpr{str("a")}},
+					params: []string{"a"},
+					body:   []erbNode{erbTextNode{text: "x"}},
+					elses: []*erbElseNode{
+						{
+							name:   "else_if_p",
+							args:   []erbExpr{str("b"), str("c")},
+							params: []string{"b", "c"},
+							body:   []erbNode{erbTextNode{text: "y"}},
+							line:   1,
+						},
+						{name: "else", body: []erbNode{erbTextNode{text: "z"}}, line: 1},
+					},
+					line: 1,
+				},
+			}))
+		})
+
+		It("parses nested blocks spanning several lines", func() {
+			Expect(parse("<% a.each do |k, v| -%>\n<% if v -%>\n<%= k %>\n<% end -%>\n<% end -%>\n")).To(Equal([]erbNode{
+				&erbBlockNode{
+					call:   &erbCallExpr{receiver: ident("a"), name: "each"},
+					params: []string{"k", "v"},
+					body: []erbNode{
+						&erbIfNode{branches: []*erbBranch{{
+							cond: ident("v"),
+							body: []erbNode{erbOutputNode{expr: ident("k"), line: 3}, erbTextNode{text: "\n"}},
+							line: 2,
+						}}},
+					},
+					line: 1,
+				},
+			}))
+		})
+
+		It("returns error for unbalanced or unsupported statements", func() {
+			for template, expectedErr := range map[string]UnsupportedTemplateError{
+				"<% if a %>":                     {Line: 0, Reason: "missing 'end'"},
+				"a\n<% end %>":                   {Line: 2, Reason: "unexpected 'end'"},
+				"<% else %>":                     {Line: 1, Reason: "unexpected 'else'"},
+				"<% if a %><% else %><% else %>": {Line: 1, Reason: "unexpected 'else'"},
+				"<% unless a %><% elsif b %>":    {Line: 1, Reason: "unexpected 'elsif'"},
+				"<% if a %><% end.else do %>":    {Line: 1, Reason: "unexpected '.'"},
+				"<% x() do %><% end.foo do %>":   {Line: 1, Reason: "unexpected 'foo' after 'end'"},
+				"<% x.map(&:a) do %><% end %>":   {Line: 1, Reason: "unexpected 'do'"},
+				"<%= a; b %>":                    {Line: 1, Reason: "expected single expression in output tag"},
+				"\n\n<% a + * b %>":              {Line: 3, Reason: "unexpected '*'"},
+
+				`<% if_p("a") do %><% end.else do %><% end.else do %><% end %>`: {Line: 1, Reason: "unexpected '.'"},
+			} {
+				_, err := parseERBTemplate(template)
+				Expect(err).To(Equal(expectedErr), "%s", template)
+			}
+		})
+	})
+})
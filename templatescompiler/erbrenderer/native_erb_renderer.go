@@ -0,0 +1,90 @@
+package erbrenderer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+// UnsupportedTemplateError is returned by native ERB renderer
+// when template uses Ruby that it does not implement
+type UnsupportedTemplateError struct {
+	Path   string
+	Line   int
+	Reason string
+}
+
+func (e UnsupportedTemplateError) Error() string {
+	return fmt.Sprintf("Unsupported ERB in template '%s' (line %d): %s", e.Path, e.Line, e.Reason)
+}
+
+type nativeERBRenderer struct {
+	fs     boshsys.FileSystem
+	logger boshlog.Logger
+	logTag string
+}
+
+// NewNativeERBRenderer renders templates without Ruby. It implements
+// same TemplateEvaluationContext API (p, if_p, link, if_link, spec)
+// as renderer script, but only supports a subset of Ruby.
+func NewNativeERBRenderer(fs boshsys.FileSystem, logger boshlog.Logger) ERBRenderer {
+	return nativeERBRenderer{
+		fs:     fs,
+		logger: logger,
+		logTag: "nativeERBRenderer",
+	}
+}
+
+func (r nativeERBRenderer) Render(srcPath, dstPath string, context TemplateEvaluationContext) error {
+	r.logger.Debug(r.logTag, "Rendering template %s", dstPath)
+
+	contextBytes, err := json.Marshal(context)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshalling context")
+	}
+
+	template, err := r.fs.ReadFileString(srcPath)
+	if err != nil {
+		return bosherr.WrapError(err, "Reading template")
+	}
+
+	nodes, err := parseERBTemplate(template)
+	if err != nil {
+		return r.templateErr(srcPath, err)
+	}
+
+	evaluator, err := newERBEvaluator(contextBytes)
+	if err != nil {
+		return r.templateErr(srcPath, err)
+	}
+
+	result, err := evaluator.render(nodes)
+	if err != nil {
+		if tplErr, ok := err.(erbTemplateError); ok {
+			// Matches error raised by renderer script
+			return bosherr.Errorf("Error filling in template '%s' for %s (line %d: %s)",
+				srcPath, evaluator.instanceName(), tplErr.line, tplErr.Error())
+		}
+
+		return r.templateErr(srcPath, err)
+	}
+
+	err = r.fs.WriteFileString(dstPath, result)
+	if err != nil {
+		return bosherr.WrapError(err, "Writing rendered template")
+	}
+
+	return nil
+}
+
+func (r nativeERBRenderer) templateErr(srcPath string, err error) error {
+	if unsupportedErr, ok := err.(UnsupportedTemplateError); ok {
+		unsupportedErr.Path = srcPath
+		return unsupportedErr
+	}
+
+	return bosherr.WrapErrorf(err, "Rendering template '%s'", srcPath)
+}
@@ -0,0 +1,241 @@
+package erbrenderer_test
+
+import (
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
+)
+
+type jsonContext string
+
+func (c jsonContext) MarshalJSON() ([]byte, error) { return []byte(c), nil }
+
+const nativeERBContext jsonContext = `{
+  "index": 0,
+  "id": "fake-id",
+  "az": "z1",
+  "bootstrap": true,
+  "job": {"name": "web"},
+  "deployment": "fake-deployment",
+  "networks": {"default": {"ip": "10.0.0.5", "netmask": "255.255.255.0", "gateway": "10.0.0.1"}},
+  "global_properties": {},
+  "cluster_properties": {},
+  "job_properties": {
+    "port": 8080,
+    "tls": {"enabled": true},
+    "users": [{"name": "admin", "groups": ["a", "b"]}, {"name": "dev", "groups": []}],
+    "env": {"B": "2", "A": "1"}
+  },
+  "default_properties": {
+    "env": {},
+    "name": "fake-name",
+    "port": 80,
+    "ratio": 1.5,
+    "tls.cert": null,
+    "tls.enabled": false,
+    "users": []
+  },
+  "links": {
+    "db": {
+      "address": "db.internal",
+      "properties": {"port": 5432, "tls": {"enabled": false}},
+      "instances": [
+        {"name": "db", "index": 0, "id": "db-0", "az": "z1", "address": "10.0.0.10", "bootstrap": true},
+        {"name": "db", "index": 1, "id": "db-1", "az": "z2", "address": "10.0.0.11", "bootstrap": false}
+      ]
+    }
+  }
+}`
+
+var _ = Describe("NativeERBRenderer", func() {
+	var (
+		fs          *fakesys.FakeFileSystem
+		erbRenderer ERBRenderer
+		context     jsonContext
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		erbRenderer = NewNativeERBRenderer(fs, boshlog.NewLogger(boshlog.LevelNone))
+
+		context = nativeERBContext
+	})
+
+	render := func(template string) (string, error) {
+		fs.WriteFileString("/src", template)
+
+		err := erbRenderer.Render("/src", "/dst", context)
+		if err != nil {
+			return "", err
+		}
+
+		return fs.ReadFileString("/dst")
+	}
+
+	expectRendered := func(template, expected string) {
+		result, err := render(template)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(expected))
+	}
+
+	expectUnsupported := func(template string) {
+		_, err := render(template)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(UnsupportedTemplateError{}))
+		Expect(err.(UnsupportedTemplateError).Path).To(Equal("/src"))
+	}
+
+	Describe("properties", func() {
+		It("renders job properties and defaults", func() {
+			expectRendered(`port=<%= p("port") %> name=<%= p("name") %> tls=<%= p("tls.enabled") %>`,
+				"port=8080 name=fake-name tls=true")
+		})
+
+		It("uses given default and first found property of several names", func() {
+			expectRendered(`<%= p("tls.cert", "none") %> <%= p(["missing", "port"]) %>`, "none 8080")
+		})
+
+		It("returns error like renderer script if property is not found", func() {
+			_, err := render("a\n<%= p('tls.cert') %>")
+			Expect(err).To(HaveOccurred())
+			Expect(err).ToNot(BeAssignableToTypeOf(UnsupportedTemplateError{}))
+			Expect(err.Error()).To(Equal("Error filling in template '/src' for web/0 " +
+				"(line 2: #<TemplateEvaluationContext::UnknownProperty: Can't find property 'tls.cert'>)"))
+		})
+
+		It("uses global and cluster properties if job properties are not set", func() {
+			context = jsonContext(`{
+  "index": 1, "job": {"name": "web"},
+  "global_properties": {"port": 1, "tls": {"enabled": true}},
+  "cluster_properties": {"port": 2},
+  "job_properties": null,
+  "default_properties": {"port": 80, "tls.enabled": false}
+}`)
+
+			expectRendered(`<%= p("port") %> <%= p("tls.enabled") %> <%= index %>`, "2 true 1")
+		})
+
+		It("renders if_p blocks with else and else_if_p", func() {
+			expectRendered(`
+<%- if_p("port", "tls.enabled") do |port, tls| -%>
+port=<%= port %> tls=<%= tls %>
+<%- end -%>
+<%- if_p("tls.cert") do |cert| -%>
+cert=<%= cert %>
+<%- end.else_if_p("missing") do |v| -%>
+missing
+<%- end.else do -%>
+no cert
+<%- end -%>
+`, "\nport=8080 tls=true\nno cert\n")
+		})
+
+		It("iterates over arrays and hashes", func() {
+			expectRendered(`
+<% p("users").each_with_index do |user, i| -%>
+<%= i %>: <%= user["name"] %> (<%= user["groups"].join(",") %>)
+<% end -%>
+<% p("env").each do |k, v| -%>
+<%= k %>=<%= v %>
+<% end -%>
+`, "\n0: admin (a,b)\n1: dev ()\nB=2\nA=1\n")
+		})
+
+		It("supports common methods, operators and local variables", func() {
+			expectRendered(
+				`<% names = p("users").map { |u| u["name"] } %>`+
+					`<%= names.join(" ") %>|<%= names.size %>|<%= names.include?("dev") ? "yes" : "no" %>|`+
+					`<%= p("port") + 1 %>|<%= p("port") > 80 && !p("users").empty? %>|`+
+					`<%= "port #{p("port")}" %>|<%= p("env").keys.sort.join %>|<%= p("ratio") %>|`+
+					`<%= "a,b,".split(",").length %>|<%= p("users").select { |u| u["groups"].empty? }.map { |u| u["name"] }.first %>`,
+				"admin dev|2|yes|8081|true|port 8080|AB|1.5|2|dev",
+			)
+		})
+
+		It("renders JSON like Ruby", func() {
+			expectRendered(`<%= p("users").to_json %> <%= JSON.dump(p("name")) %> <%= JSON.dump(p("env")) %> <%= p("tls.cert", nil).to_json %>`,
+				`[{"name":"admin","groups":["a","b"]},{"name":"dev","groups":[]}] "fake-name" {"B":"2","A":"1"} null`)
+		})
+	})
+
+	Describe("control flow", func() {
+		It("renders if/elsif/else, unless and modifiers", func() {
+			expectRendered(`
+<% if p("port") == 80 -%>
+default
+<% elsif p("port") == 8080 -%>
+alt
+<% else -%>
+other
+<% end -%>
+<% unless p("tls.enabled") -%>
+insecure
+<% end -%>
+<%= "tls" if p("tls.enabled") %>
+`, "\nalt\ntls\n")
+		})
+
+		It("raises errors like renderer script", func() {
+			_, err := render(`<% raise "port must be set" if p("port") == 8080 %>`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Error filling in template '/src' for web/0 (line 1: #<RuntimeError: port must be set>)"))
+		})
+	})
+
+	Describe("spec and links", func() {
+		It("exposes spec as open struct", func() {
+			expectRendered(`<%= spec.id %> <%= spec.networks.default.ip %> <%= spec.bootstrap %> <%= spec.job.name %> <%= spec.missing.nil? %> <%= name %>`,
+				"fake-id 10.0.0.5 true web true web")
+		})
+
+		It("exposes links", func() {
+			expectRendered(
+				`<%= link("db").address %> <%= link("db").p("port") %> `+
+					`<%= link("db").instances.map(&:address).join(",") %> <%= link("db").instances.first.az %>`+
+					`<% if_link("db") do |db| %> <%= db.p("tls.enabled") %><% end %>`+
+					`<% if_link("cache") do |cache| %>cache<% end.else do %> no cache<% end %>`+
+					`<% link("db").if_p("missing") do |v| %>v<% end.else do %> no missing<% end %>`,
+				"db.internal 5432 10.0.0.10,10.0.0.11 z1 false no cache no missing",
+			)
+		})
+
+		It("returns error like renderer script if link is not found", func() {
+			_, err := render(`<%= link("cache").address %>`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("(line 1: #<TemplateEvaluationContext::UnknownLink: Can't find link 'cache'>)"))
+		})
+	})
+
+	Describe("ERB tags", func() {
+		It("supports comments, escaped tags and trim mode", func() {
+			expectRendered("a<%# comment %>b <%% x %%>\n  <%- if true -%>\nc\n  <%- end -%>\nd <%- 1 %>e<%= 2 %> <%- 3 -%>\r\nf<%= '%%>' %>\n",
+				"ab <% x %%>\nc\nd e2f%>\n")
+		})
+	})
+
+	Describe("unsupported Ruby", func() {
+		It("returns unsupported error for constructs outside of supported subset", func() {
+			expectUnsupported(`<% case p("port") when 80 %>a<% end %>`)
+			expectUnsupported(`<%= p("users").to_yaml %>`)
+			expectUnsupported(`<%= p("users") %>`)
+			expectUnsupported(`<%= p("name").gsub("a", "b") %>`)
+			expectUnsupported(`<%= @instance %>`)
+			expectUnsupported(`<%= unknown_method %>`)
+			expectUnsupported(`<% if true %>`)
+			expectUnsupported(`<%= { "a" => 1 }.to_json %>`)
+		})
+
+		It("returns unsupported error only when unsupported code is evaluated", func() {
+			expectRendered(`<% if false %><%= p("name").gsub("a", "b") %><% end %>ok`, "ok")
+		})
+	})
+
+	It("returns error if template cannot be read", func() {
+		err := erbRenderer.Render("/src", "/dst", context)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Reading template"))
+	})
+})
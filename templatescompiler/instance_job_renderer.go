@@ -0,0 +1,84 @@
+package templatescompiler
+
+import (
+	"path/filepath"
+	"runtime"
+
+	bireljob "github.com/cloudfoundry/bosh-cli/release/job"
+	bierbrenderer "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+)
+
+// InstanceJobRenderer renders job templates for a given instance and link stubs
+// into a given directory so that templates can be checked without deploying.
+// Source path is a directory with job's monit file and templates.
+type InstanceJobRenderer interface {
+	Render(
+		releaseJob bireljob.Job,
+		sourcePath string,
+		releaseJobProperties biproperty.Map,
+		instance InstanceSpec,
+		links map[string]LinkSpec,
+		deploymentName string,
+		destinationPath string,
+	) error
+}
+
+type instanceJobRenderer struct {
+	erbRenderer bierbrenderer.ERBRenderer
+	fs          boshsys.FileSystem
+	uuidGen     boshuuid.Generator
+	logger      boshlog.Logger
+	logTag      string
+}
+
+func NewInstanceJobRenderer(
+	erbRenderer bierbrenderer.ERBRenderer,
+	fs boshsys.FileSystem,
+	uuidGen boshuuid.Generator,
+	logger boshlog.Logger,
+) InstanceJobRenderer {
+	return &instanceJobRenderer{
+		erbRenderer: newLimitedERBRenderer(erbRenderer, runtime.NumCPU()),
+		fs:          fs,
+		uuidGen:     uuidGen,
+		logger:      logger,
+		logTag:      "instanceJobRenderer",
+	}
+}
+
+func (r *instanceJobRenderer) Render(
+	releaseJob bireljob.Job,
+	sourcePath string,
+	releaseJobProperties biproperty.Map,
+	instance InstanceSpec,
+	links map[string]LinkSpec,
+	deploymentName string,
+	destinationPath string,
+) error {
+	r.logger.Debug(r.logTag, "Rendering job '%s' from '%s' to '%s'", releaseJob.Name(), sourcePath, destinationPath)
+
+	context := NewInstanceJobEvaluationContext(
+		releaseJob, releaseJobProperties, instance, links, deploymentName, r.uuidGen, r.logger)
+
+	err := renderJobTemplates(r.erbRenderer, r.fs, releaseJob, sourcePath, destinationPath, context)
+	if err != nil {
+		return err
+	}
+
+	// Unlike jobs in release tarballs, jobs in release directories may not have monit files
+	monitPath := filepath.Join(sourcePath, "monit")
+
+	if r.fs.FileExists(monitPath) {
+		err = renderFile(r.erbRenderer, r.fs, monitPath, filepath.Join(destinationPath, "monit"), context)
+		if err != nil {
+			return bosherr.WrapError(err, "Rendering monit file")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package templatescompiler_test
+
+import (
+	"path/filepath"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshreljob "github.com/cloudfoundry/bosh-cli/release/job"
+	. "github.com/cloudfoundry/bosh-cli/release/resource"
+	. "github.com/cloudfoundry/bosh-cli/templatescompiler"
+	bierbrenderer "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
+	fakebirender "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer/fakes"
+)
+
+var _ = Describe("InstanceJobRenderer", func() {
+	var (
+		jobRenderer     InstanceJobRenderer
+		fakeERBRenderer *fakebirender.FakeERBRenderer
+		job             *boshreljob.Job
+		properties      biproperty.Map
+		instance        InstanceSpec
+		links           map[string]LinkSpec
+		context         bierbrenderer.TemplateEvaluationContext
+		fs              *fakesys.FakeFileSystem
+	)
+
+	BeforeEach(func() {
+		job = boshreljob.NewJob(NewResource("web", "job-fp", nil))
+		job.Templates = map[string]string{"config.yml.erb": "config/config.yml"}
+
+		properties = biproperty.Map{"port": 8080}
+
+		instance = DefaultInstanceSpec()
+		instance.ID = "fake-id"
+
+		links = map[string]LinkSpec{"db": LinkSpec{Address: "db.internal"}}
+
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		uuidGen := fakeuuid.NewFakeGenerator()
+
+		context = NewInstanceJobEvaluationContext(*job, properties, instance, links, "fake-deployment-name", uuidGen, logger)
+
+		fakeERBRenderer = fakebirender.NewFakeERBRender()
+		fs = fakesys.NewFakeFileSystem()
+		jobRenderer = NewInstanceJobRenderer(fakeERBRenderer, fs, uuidGen, logger)
+
+		fakeERBRenderer.SetRenderBehavior("/src/templates/config.yml.erb", "/dst/config/config.yml", context, nil)
+		fakeERBRenderer.SetRenderBehavior("/src/monit", "/dst/monit", context, nil)
+	})
+
+	act := func() error {
+		return jobRenderer.Render(*job, "/src", properties, instance, links, "fake-deployment-name", "/dst")
+	}
+
+	Describe("Render", func() {
+		It("renders job templates and monit file into destination directory", func() {
+			fs.WriteFileString("/src/monit", "monit")
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeERBRenderer.RenderInputs).To(Equal([]fakebirender.RenderInput{
+				{SrcPath: "/src/templates/config.yml.erb", DstPath: "/dst/config/config.yml", Context: context},
+				{SrcPath: "/src/monit", DstPath: "/dst/monit", Context: context},
+			}))
+
+			Expect(fs.FileExists(filepath.Join("/dst", "config"))).To(BeTrue())
+		})
+
+		It("does not render monit file if job does not have it", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeERBRenderer.RenderInputs).To(HaveLen(1))
+		})
+
+		It("returns an error if rendering template fails", func() {
+			fakeERBRenderer.SetRenderBehavior(
+				"/src/templates/config.yml.erb", "/dst/config/config.yml", context, bosherr.Error("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Rendering template src: config.yml.erb, dst: config/config.yml"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package templatescompiler
+
+import (
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+)
+
+// InstanceSpec describes an instance that job templates are rendered for
+// when there is no deployment, e.g. when release authors check their templates.
+// Empty ID is replaced with a generated one.
+type InstanceSpec struct {
+	Index     int
+	ID        string
+	AZ        string
+	Bootstrap bool
+	Address   string
+	Networks  map[string]NetworkSpec
+}
+
+type NetworkSpec struct {
+	IP      string
+	Netmask string
+	Gateway string
+}
+
+// LinkSpec is a stub of a consumed link that is exposed
+// via link() and if_link() in ERB templates
+type LinkSpec struct {
+	Address    string
+	Properties biproperty.Map
+	Instances  []LinkInstanceSpec
+}
+
+type LinkInstanceSpec struct {
+	Name      string
+	Index     int
+	ID        string
+	AZ        string
+	Address   string
+	Bootstrap bool
+}
+
+// DefaultInstanceSpec matches instance that is used when rendering jobs for create-env
+func DefaultInstanceSpec() InstanceSpec {
+	return InstanceSpec{
+		Index:     0,
+		AZ:        "unknown",
+		Bootstrap: true,
+		Networks:  map[string]NetworkSpec{"default": NetworkSpec{}},
+	}
+}
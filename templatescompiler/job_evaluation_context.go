@@ -17,7 +17,8 @@ type jobEvaluationContext struct {
 	jobProperties        biproperty.Map
 	globalProperties     biproperty.Map
 	deploymentName       string
-	address              string
+	instance             InstanceSpec
+	links                map[string]LinkSpec
 	uuidGen              boshuuid.Generator
 	logger               boshlog.Logger
 	logTag               string
@@ -42,6 +43,9 @@ type RootContext struct {
 	ClusterProperties biproperty.Map  `json:"cluster_properties"` // values from instance group (deployment job) properties
 	JobProperties     *biproperty.Map `json:"job_properties"`     // values from release job (aka template) properties
 	DefaultProperties biproperty.Map  `json:"default_properties"` // values from release's job's spec
+
+	// Usually is accessed with <%= link("db").p("port") %>
+	LinkContexts map[string]linkContext `json:"links,omitempty"`
 }
 
 type jobContext struct {
@@ -54,6 +58,21 @@ type networkContext struct {
 	Gateway string `json:"gateway"`
 }
 
+type linkContext struct {
+	Address    string                `json:"address,omitempty"`
+	Properties biproperty.Map        `json:"properties"`
+	Instances  []linkInstanceContext `json:"instances"`
+}
+
+type linkInstanceContext struct {
+	Name      string `json:"name"`
+	Index     int    `json:"index"`
+	ID        string `json:"id"`
+	AZ        string `json:"az"`
+	Address   string `json:"address"`
+	Bootstrap bool   `json:"bootstrap"`
+}
+
 func NewJobEvaluationContext(
 	releaseJob bireljob.Job,
 	releaseJobProperties *biproperty.Map,
@@ -64,13 +83,41 @@ func NewJobEvaluationContext(
 	uuidGen boshuuid.Generator,
 	logger boshlog.Logger,
 ) bierbrenderer.TemplateEvaluationContext {
+	instance := DefaultInstanceSpec()
+	instance.Address = address
+
 	return jobEvaluationContext{
 		releaseJob:           releaseJob,
 		releaseJobProperties: releaseJobProperties,
 		jobProperties:        jobProperties,
 		globalProperties:     globalProperties,
 		deploymentName:       deploymentName,
-		address:              address,
+		instance:             instance,
+		uuidGen:              uuidGen,
+		logTag:               "jobEvaluationContext",
+		logger:               logger,
+	}
+}
+
+// NewInstanceJobEvaluationContext is used to render job templates for a given instance
+// with only release job properties (similarly to instance group jobs with properties)
+func NewInstanceJobEvaluationContext(
+	releaseJob bireljob.Job,
+	releaseJobProperties biproperty.Map,
+	instance InstanceSpec,
+	links map[string]LinkSpec,
+	deploymentName string,
+	uuidGen boshuuid.Generator,
+	logger boshlog.Logger,
+) bierbrenderer.TemplateEvaluationContext {
+	return jobEvaluationContext{
+		releaseJob:           releaseJob,
+		releaseJobProperties: &releaseJobProperties,
+		jobProperties:        biproperty.Map{},
+		globalProperties:     biproperty.Map{},
+		deploymentName:       deploymentName,
+		instance:             instance,
+		links:                links,
 		uuidGen:              uuidGen,
 		logTag:               "jobEvaluationContext",
 		logger:               logger,
@@ -82,25 +129,26 @@ func (ec jobEvaluationContext) MarshalJSON() ([]byte, error) {
 	var err error
 
 	context := RootContext{
-		Index:             0,
-		AZ:                "unknown",
-		Bootstrap:         true,
+		Index:             ec.instance.Index,
+		ID:                ec.instance.ID,
+		AZ:                ec.instance.AZ,
+		Bootstrap:         ec.instance.Bootstrap,
 		JobContext:        jobContext{Name: ec.releaseJob.Name()},
 		Deployment:        ec.deploymentName,
+		Address:           ec.instance.Address,
 		NetworkContexts:   ec.buildNetworkContexts(),
 		GlobalProperties:  ec.globalProperties,
 		ClusterProperties: ec.jobProperties,
 		JobProperties:     ec.releaseJobProperties,
 		DefaultProperties: defaultProperties,
+		LinkContexts:      ec.buildLinkContexts(),
 	}
 
-	if len(ec.address) > 0 {
-		context.Address = ec.address
-	}
-
-	context.ID, err = ec.uuidGen.Generate()
-	if err != nil {
-		return []byte{}, bosherr.WrapErrorf(err, "Setting job eval context's ID to UUID: %#v", context)
+	if len(context.ID) == 0 {
+		context.ID, err = ec.uuidGen.Generate()
+		if err != nil {
+			return []byte{}, bosherr.WrapErrorf(err, "Setting job eval context's ID to UUID: %#v", context)
+		}
 	}
 
 	ec.logger.Debug(ec.logTag, "Marshalling context %#v", context)
@@ -122,10 +170,37 @@ func (ec jobEvaluationContext) propertyDefaults(properties map[string]bireljob.P
 }
 
 func (ec jobEvaluationContext) buildNetworkContexts() map[string]networkContext {
-	// IP is being returned by agent
-	return map[string]networkContext{
-		"default": networkContext{
-			IP: "",
-		},
+	// Unless specified, IP is being returned by agent
+	result := map[string]networkContext{}
+	for name, network := range ec.instance.Networks {
+		result[name] = networkContext{
+			IP:      network.IP,
+			Netmask: network.Netmask,
+			Gateway: network.Gateway,
+		}
 	}
+	return result
+}
+
+func (ec jobEvaluationContext) buildLinkContexts() map[string]linkContext {
+	if len(ec.links) == 0 {
+		return nil
+	}
+
+	result := map[string]linkContext{}
+	for name, link := range ec.links {
+		linkCtx := linkContext{
+			Address:    link.Address,
+			Properties: link.Properties,
+			Instances:  []linkInstanceContext{},
+		}
+		if linkCtx.Properties == nil {
+			linkCtx.Properties = biproperty.Map{}
+		}
+		for _, inst := range link.Instances {
+			linkCtx.Instances = append(linkCtx.Instances, linkInstanceContext(inst))
+		}
+		result[name] = linkCtx
+	}
+	return result
 }
@@ -264,3 +264,88 @@ var _ = Describe("JobEvaluationContext", func() {
 		})
 	})
 })
+
+var _ = Describe("NewInstanceJobEvaluationContext", func() {
+	It("exposes given instance fields, release job properties and links", func() {
+		releaseJob := boshreljob.NewJob(NewResource("fake-job-name", "", nil))
+		releaseJob.Properties = map[string]boshreljob.PropertyDefinition{
+			"port": boshreljob.PropertyDefinition{Default: 80},
+		}
+
+		instance := InstanceSpec{
+			Index:     2,
+			ID:        "fake-id",
+			AZ:        "z2",
+			Bootstrap: false,
+			Address:   "web-2.internal",
+			Networks: map[string]NetworkSpec{
+				"private": NetworkSpec{IP: "10.0.0.5", Netmask: "255.255.255.0", Gateway: "10.0.0.1"},
+			},
+		}
+
+		links := map[string]LinkSpec{
+			"db": LinkSpec{
+				Address:    "db.internal",
+				Properties: biproperty.Map{"port": 5432},
+				Instances:  []LinkInstanceSpec{{Name: "db", Index: 0, ID: "db-id", AZ: "z1", Address: "10.0.0.10", Bootstrap: true}},
+			},
+		}
+
+		context := NewInstanceJobEvaluationContext(
+			*releaseJob, biproperty.Map{"port": 8080}, instance, links, "fake-deployment-name",
+			fakeuuid.NewFakeGenerator(), boshlog.NewLogger(boshlog.LevelNone))
+
+		generatedJSON, err := context.MarshalJSON()
+		Expect(err).ToNot(HaveOccurred())
+
+		var generatedContext map[string]interface{}
+
+		err = json.Unmarshal(generatedJSON, &generatedContext)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(generatedContext["index"]).To(Equal(2.0))
+		Expect(generatedContext["id"]).To(Equal("fake-id"))
+		Expect(generatedContext["az"]).To(Equal("z2"))
+		Expect(generatedContext["bootstrap"]).To(BeFalse())
+		Expect(generatedContext["address"]).To(Equal("web-2.internal"))
+		Expect(generatedContext["deployment"]).To(Equal("fake-deployment-name"))
+		Expect(generatedContext["networks"]).To(Equal(map[string]interface{}{
+			"private": map[string]interface{}{"ip": "10.0.0.5", "netmask": "255.255.255.0", "gateway": "10.0.0.1"},
+		}))
+		Expect(generatedContext["job_properties"]).To(Equal(map[string]interface{}{"port": 8080.0}))
+		Expect(generatedContext["links"]).To(Equal(map[string]interface{}{
+			"db": map[string]interface{}{
+				"address":    "db.internal",
+				"properties": map[string]interface{}{"port": 5432.0},
+				"instances": []interface{}{
+					map[string]interface{}{
+						"name": "db", "index": 0.0, "id": "db-id", "az": "z1", "address": "10.0.0.10", "bootstrap": true,
+					},
+				},
+			},
+		}))
+	})
+
+	It("generates instance ID if it is not given", func() {
+		uuidGen := fakeuuid.NewFakeGenerator()
+		uuidGen.GeneratedUUID = "fake-uuid"
+
+		context := NewInstanceJobEvaluationContext(
+			*boshreljob.NewJob(NewResource("fake-job-name", "", nil)), biproperty.Map{}, DefaultInstanceSpec(),
+			nil, "fake-deployment-name", uuidGen, boshlog.NewLogger(boshlog.LevelNone))
+
+		generatedJSON, err := context.MarshalJSON()
+		Expect(err).ToNot(HaveOccurred())
+
+		var generatedContext RootContext
+
+		err = json.Unmarshal(generatedJSON, &generatedContext)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(generatedContext.ID).To(Equal("fake-uuid"))
+		Expect(generatedContext.AZ).To(Equal("unknown"))
+		Expect(generatedContext.Bootstrap).To(BeTrue())
+		Expect(generatedContext.NetworkContexts).To(HaveKey("default"))
+		Expect(string(generatedJSON)).ToNot(ContainSubstring(`"links"`))
+	})
+})
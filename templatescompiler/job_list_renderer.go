@@ -46,14 +46,35 @@ func (r *jobListRenderer) Render(
 	r.logger.Debug(r.logTag, "Rendering job list: deploymentName='%s' jobProperties=%#v globalProperties=%#v", deploymentName, jobProperties, globalProperties)
 	renderedJobList := NewRenderedJobList()
 
+	renderedJobs := make([]RenderedJob, len(releaseJobs))
+
+	var tasks []func() error
+
 	// render all the jobs' templates
-	for _, releaseJob := range releaseJobs {
-		renderedJob, err := r.jobRenderer.Render(releaseJob, releaseJobProperties[releaseJob.Name()], jobProperties, globalProperties, deploymentName, address)
-		if err != nil {
-			defer renderedJobList.DeleteSilently()
-			return renderedJobList, bosherr.WrapErrorf(err, "Rendering templates for job '%s/%s'", releaseJob.Name(), releaseJob.Fingerprint())
+	for i, releaseJob := range releaseJobs {
+		i, releaseJob := i, releaseJob
+		tasks = append(tasks, func() error {
+			renderedJob, err := r.jobRenderer.Render(releaseJob, releaseJobProperties[releaseJob.Name()], jobProperties, globalProperties, deploymentName, address)
+			if err != nil {
+				return bosherr.WrapErrorf(err, "Rendering templates for job '%s/%s'", releaseJob.Name(), releaseJob.Fingerprint())
+			}
+			renderedJobs[i] = renderedJob
+			return nil
+		})
+	}
+
+	err := renderConcurrently(tasks)
+
+	// keep jobs in the same order as release jobs
+	for _, renderedJob := range renderedJobs {
+		if renderedJob != nil {
+			renderedJobList.Add(renderedJob)
 		}
-		renderedJobList.Add(renderedJob)
+	}
+
+	if err != nil {
+		defer renderedJobList.DeleteSilently()
+		return renderedJobList, err
 	}
 
 	return renderedJobList, nil
@@ -41,6 +41,7 @@ var _ = Describe("JobListRenderer", func() {
 
 		jobListRenderer JobListRenderer
 
+		expectRender0 *gomock.Call
 		expectRender1 *gomock.Call
 	)
 
@@ -81,7 +82,7 @@ var _ = Describe("JobListRenderer", func() {
 	})
 
 	JustBeforeEach(func() {
-		mockJobRenderer.EXPECT().Render(releaseJobs[0], releaseJobProperties[releaseJobs[0].Name()], jobProperties, globalProperties, deploymentName, address).Return(renderedJobs[0], nil)
+		expectRender0 = mockJobRenderer.EXPECT().Render(releaseJobs[0], releaseJobProperties[releaseJobs[0].Name()], jobProperties, globalProperties, deploymentName, address).Return(renderedJobs[0], nil)
 		expectRender1 = mockJobRenderer.EXPECT().Render(releaseJobs[1], releaseJobProperties[releaseJobs[1].Name()], jobProperties, globalProperties, deploymentName, address).Return(renderedJobs[1], nil)
 	})
 
@@ -108,6 +109,41 @@ var _ = Describe("JobListRenderer", func() {
 				Expect(err.Error()).To(ContainSubstring("fake-render-error"))
 			})
 		})
+
+		Context("when later jobs finish rendering first", func() {
+			var job1Rendered chan struct{}
+
+			JustBeforeEach(func() {
+				job1Rendered = make(chan struct{})
+
+				expectRender0.Do(func(_, _, _, _, _, _ interface{}) { <-job1Rendered })
+				expectRender1.Do(func(_, _, _, _, _, _ interface{}) { close(job1Rendered) })
+			})
+
+			It("renders jobs concurrently and keeps RenderedJobs in the same order as release jobs", func() {
+				renderedJobList, err := jobListRenderer.Render(releaseJobs, releaseJobProperties, jobProperties, globalProperties, deploymentName, address)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(renderedJobList.All()).To(Equal([]RenderedJob{
+					renderedJobs[0],
+					renderedJobs[1],
+				}))
+			})
+
+			Context("when rendering a later job fails", func() {
+				JustBeforeEach(func() {
+					expectRender1.Return(nil, bosherr.Error("fake-render-error"))
+				})
+
+				It("cleans up jobs that finish rendering after the failure", func() {
+					renderedJobs[0].EXPECT().DeleteSilently()
+
+					_, err := jobListRenderer.Render(releaseJobs, releaseJobProperties, jobProperties, globalProperties, deploymentName, address)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Rendering templates for job 'fake-release-job-name-1/'"))
+					Expect(err.Error()).To(ContainSubstring("fake-render-error"))
+				})
+			})
+		})
 	})
 
 })
@@ -3,6 +3,7 @@ package templatescompiler
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 
 	bireljob "github.com/cloudfoundry/bosh-cli/release/job"
 	bierbrenderer "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer"
@@ -11,6 +12,7 @@ import (
 	biproperty "github.com/cloudfoundry/bosh-utils/property"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+	"github.com/cloudfoundry/bosh-utils/work"
 )
 
 type JobRenderer interface {
@@ -32,7 +34,7 @@ func NewJobRenderer(
 	logger boshlog.Logger,
 ) JobRenderer {
 	return &jobRenderer{
-		erbRenderer: erbRenderer,
+		erbRenderer: newLimitedERBRenderer(erbRenderer, runtime.NumCPU()),
 		fs:          fs,
 		uuidGen:     uuidGen,
 		logger:      logger,
@@ -52,19 +54,15 @@ func (r *jobRenderer) Render(releaseJob bireljob.Job, releaseJobProperties *bipr
 
 	renderedJob := NewRenderedJob(releaseJob, destinationPath, r.fs, r.logger)
 
-	for src, dst := range releaseJob.Templates {
-		err := r.renderFile(
-			filepath.Join(sourcePath, "templates", src),
-			filepath.Join(destinationPath, dst),
-			context,
-		)
-		if err != nil {
-			defer renderedJob.DeleteSilently()
-			return nil, bosherr.WrapErrorf(err, "Rendering template src: %s, dst: %s", src, dst)
-		}
+	err = renderJobTemplates(r.erbRenderer, r.fs, releaseJob, sourcePath, destinationPath, context)
+	if err != nil {
+		defer renderedJob.DeleteSilently()
+		return nil, err
 	}
 
-	err = r.renderFile(
+	err = renderFile(
+		r.erbRenderer,
+		r.fs,
 		filepath.Join(sourcePath, "monit"),
 		filepath.Join(destinationPath, "monit"),
 		context,
@@ -77,13 +75,68 @@ func (r *jobRenderer) Render(releaseJob bireljob.Job, releaseJobProperties *bipr
 	return renderedJob, nil
 }
 
-func (r *jobRenderer) renderFile(sourcePath, destinationPath string, context bierbrenderer.TemplateEvaluationContext) error {
-	err := r.fs.MkdirAll(filepath.Dir(destinationPath), os.ModePerm)
+func renderJobTemplates(erbRenderer bierbrenderer.ERBRenderer, fs boshsys.FileSystem, releaseJob bireljob.Job, sourcePath, destinationPath string, context bierbrenderer.TemplateEvaluationContext) error {
+	var tasks []func() error
+
+	for src, dst := range releaseJob.Templates {
+		src, dst := src, dst
+		tasks = append(tasks, func() error {
+			err := renderFile(
+				erbRenderer,
+				fs,
+				filepath.Join(sourcePath, "templates", src),
+				filepath.Join(destinationPath, dst),
+				context,
+			)
+			if err != nil {
+				return bosherr.WrapErrorf(err, "Rendering template src: %s, dst: %s", src, dst)
+			}
+			return nil
+		})
+	}
+
+	return renderConcurrently(tasks)
+}
+
+// renderConcurrently starts all rendering tasks at once;
+// number of templates rendered at the same time is limited by limitedERBRenderer
+func renderConcurrently(tasks []func() error) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	return work.Pool{Count: len(tasks)}.ParallelDo(tasks...)
+}
+
+// limitedERBRenderer is shared by all jobs rendered by a job renderer
+// so that jobs and their templates do not start more than given number
+// of renders (usually Ruby processes) at the same time
+type limitedERBRenderer struct {
+	erbRenderer bierbrenderer.ERBRenderer
+	slots       chan struct{}
+}
+
+func newLimitedERBRenderer(erbRenderer bierbrenderer.ERBRenderer, count int) bierbrenderer.ERBRenderer {
+	return limitedERBRenderer{
+		erbRenderer: erbRenderer,
+		slots:       make(chan struct{}, count),
+	}
+}
+
+func (r limitedERBRenderer) Render(srcPath, dstPath string, context bierbrenderer.TemplateEvaluationContext) error {
+	r.slots <- struct{}{}
+	defer func() { <-r.slots }()
+
+	return r.erbRenderer.Render(srcPath, dstPath, context)
+}
+
+func renderFile(erbRenderer bierbrenderer.ERBRenderer, fs boshsys.FileSystem, sourcePath, destinationPath string, context bierbrenderer.TemplateEvaluationContext) error {
+	err := fs.MkdirAll(filepath.Dir(destinationPath), os.ModePerm)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Creating tempdir '%s'", filepath.Dir(destinationPath))
 	}
 
-	err = r.erbRenderer.Render(sourcePath, destinationPath, context)
+	err = erbRenderer.Render(sourcePath, destinationPath, context)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Rendering template src: %s, dst: %s", sourcePath, destinationPath)
 	}
@@ -1,11 +1,16 @@
 package templatescompiler_test
 
 import (
+	"fmt"
 	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
 
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -17,6 +22,31 @@ import (
 	fakebirender "github.com/cloudfoundry/bosh-cli/templatescompiler/erbrenderer/fakes"
 )
 
+type concurrencyCountingERBRenderer struct {
+	lock       sync.Mutex
+	active     int
+	maxActive  int
+	numRenders int
+}
+
+func (r *concurrencyCountingERBRenderer) Render(srcPath, dstPath string, context bierbrenderer.TemplateEvaluationContext) error {
+	r.lock.Lock()
+	r.active++
+	r.numRenders++
+	if r.active > r.maxActive {
+		r.maxActive = r.active
+	}
+	r.lock.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	r.lock.Lock()
+	r.active--
+	r.lock.Unlock()
+
+	return nil
+}
+
 var _ = Describe("JobRenderer", func() {
 	var (
 		jobRenderer          JobRenderer
@@ -120,5 +150,38 @@ var _ = Describe("JobRenderer", func() {
 				Expect(err.Error()).To(ContainSubstring("fake-template-render-error"))
 			})
 		})
+
+		It("limits number of templates rendered at the same time across all jobs", func() {
+			logger := boshlog.NewLogger(boshlog.LevelNone)
+			countingRenderer := &concurrencyCountingERBRenderer{}
+
+			// Fake file system is not safe for concurrent use
+			jobRenderer = NewJobRenderer(countingRenderer, boshsys.NewOsFileSystem(logger), nil, logger)
+
+			job.Templates = map[string]string{}
+			for i := 0; i < 2*runtime.NumCPU(); i++ {
+				job.Templates[fmt.Sprintf("template-%d.erb", i)] = fmt.Sprintf("config/template-%d", i)
+			}
+
+			jobs := []JobRenderer{jobRenderer, jobRenderer}
+			errs := make(chan error, len(jobs))
+
+			for _, renderer := range jobs {
+				go func(renderer JobRenderer) {
+					renderedJob, err := renderer.Render(*job, &releaseJobProperties, jobProperties, globalProperties, "fake-deployment-name", "1.2.3.4")
+					if err == nil {
+						err = renderedJob.Delete()
+					}
+					errs <- err
+				}(renderer)
+			}
+
+			for range jobs {
+				Expect(<-errs).ToNot(HaveOccurred())
+			}
+
+			Expect(countingRenderer.numRenders).To(Equal(2 * (len(job.Templates) + 1)))
+			Expect(countingRenderer.maxActive).To(BeNumerically("<=", runtime.NumCPU()))
+		})
 	})
 })
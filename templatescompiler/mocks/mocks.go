@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/cloudfoundry/bosh-cli/templatescompiler (interfaces: JobRenderer,JobListRenderer,RenderedJob,RenderedJobList,RenderedJobListArchive,RenderedJobListCompressor)
+// Source: github.com/cloudfoundry/bosh-cli/templatescompiler (interfaces: JobRenderer,InstanceJobRenderer,JobListRenderer,RenderedJob,RenderedJobList,RenderedJobListArchive,RenderedJobListCompressor)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -48,6 +48,41 @@ func (mr *MockJobRendererMockRecorder) Render(arg0, arg1, arg2, arg3, arg4, arg5
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockJobRenderer)(nil).Render), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// MockInstanceJobRenderer is a mock of InstanceJobRenderer interface
+type MockInstanceJobRenderer struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstanceJobRendererMockRecorder
+}
+
+// MockInstanceJobRendererMockRecorder is the mock recorder for MockInstanceJobRenderer
+type MockInstanceJobRendererMockRecorder struct {
+	mock *MockInstanceJobRenderer
+}
+
+// NewMockInstanceJobRenderer creates a new mock instance
+func NewMockInstanceJobRenderer(ctrl *gomock.Controller) *MockInstanceJobRenderer {
+	mock := &MockInstanceJobRenderer{ctrl: ctrl}
+	mock.recorder = &MockInstanceJobRendererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockInstanceJobRenderer) EXPECT() *MockInstanceJobRendererMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method
+func (m *MockInstanceJobRenderer) Render(arg0 job.Job, arg1 string, arg2 property.Map, arg3 templatescompiler.InstanceSpec, arg4 map[string]templatescompiler.LinkSpec, arg5, arg6 string) error {
+	ret := m.ctrl.Call(m, "Render", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Render indicates an expected call of Render
+func (mr *MockInstanceJobRendererMockRecorder) Render(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockInstanceJobRenderer)(nil).Render), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
 // MockJobListRenderer is a mock of JobListRenderer interface
 type MockJobListRenderer struct {
 	ctrl     *gomock.Controller